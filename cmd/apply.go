@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// serverDryRun is the only --dry-run value of the apply command: the resources
+// are sent to the API server, which validates and admits them without
+// persisting them.
+const serverDryRun = "server"
+
+// applyFieldOwner is the field manager of the resources applied by ingress2gateway.
+const applyFieldOwner = "ingress2gateway"
+
+type applyResult struct {
+	kind     string
+	key      string
+	rejected bool
+	reason   string
+}
+
+type ApplyRunner struct {
+	// The path to the input yaml config file. Value assigned via --input-file flag.
+	inputFile string
+
+	// The namespace of the Ingresses read from the cluster. Value assigned via
+	// --namespace/-n flag.
+	namespace string
+
+	// allNamespaces indicates whether the Ingresses of all namespaces are read.
+	// Value assigned via --all-namespaces/-A flag.
+	allNamespaces bool
+
+	// providers indicates which providers are used to convert the Ingresses.
+	// Value assigned via --providers flag.
+	providers []string
+
+	// dryRun is the dry-run mode of the apply. Value assigned via --dry-run flag.
+	dryRun string
+
+	// dryRunDiff indicates whether the changes the apply would make to the
+	// cluster are printed. Value assigned via --dry-run-diff flag.
+	dryRunDiff bool
+
+	// conversionFlags are the flags of print shaping the generated resources, so
+	// that the applied resources are the ones print generates.
+	conversionFlags
+}
+
+// DryRunApply converts the Ingresses and applies the generated resources to
+// the cluster with a server-side dry-run, failing if the API server rejects any
+// of them, e.g. as its CRD is missing or an admission webhook denies it.
+func (ar *ApplyRunner) DryRunApply(cmd *cobra.Command, _ []string) error {
+	// The namespace is defaulted like the one of print.
+	namespace := ar.namespace
+	if ar.allNamespaces {
+		namespace = ""
+	} else if namespace == "" {
+		ns, err := getNamespaceInCurrentContext()
+		if err != nil && ar.inputFile == "" {
+			return fmt.Errorf("failed to initialize namespace filter: %w", err)
+		}
+		namespace = ns
+	}
+
+	gatewayOptions, err := ar.gatewayOptions()
+	if err != nil {
+		return err
+	}
+	gatewayOptions.KubeContext = kubeContext
+	gatewayResources, notificationTablesMap, err := i2gw.ToGatewayAPIResources(cmd.Context(), namespace, ar.inputFile, ar.providers, ar.providerSpecificFlagValues(ar.providers), gatewayOptions)
+	for _, table := range notificationTablesMap {
+		fmt.Fprintln(cmd.OutOrStdout(), table)
+	}
+	if err != nil {
+		return err
+	}
+
+	conf, err := config.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to get client config: %w", err)
+	}
+	cl, err := client.New(conf, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if ar.dryRunDiff {
+		results, err := dryRunDiff(cmd.Context(), cl, gatewayResourcesToObjects(gatewayResources))
+		if err != nil {
+			return err
+		}
+		return printDiffResults(results, cmd.OutOrStdout())
+	}
+	results, err := dryRunApply(cmd.Context(), cl, gatewayResourcesToObjects(gatewayResources))
+	if err != nil {
+		return err
+	}
+	return printApplyResults(results, cmd.OutOrStdout())
+}
+
+// dryRunApply applies every object with a server-side dry-run and returns
+// whether the API server accepted it. The objects are applied as unstructured,
+// keeping the apiVersion they are printed with.
+func dryRunApply(ctx context.Context, cl client.Client, objects []client.Object) ([]applyResult, error) {
+	var results []applyResult
+	for _, obj := range sortObjects(objects) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		u := &unstructured.Unstructured{Object: content}
+		// The status is not applied, and the empty creationTimestamp of the
+		// generated resources would be rejected.
+		unstructured.RemoveNestedField(u.Object, "status")
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+
+		result := applyResult{
+			kind: u.GetKind(),
+			key:  types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}.String(),
+		}
+		if err := cl.Patch(ctx, u, client.Apply, client.DryRunAll, client.FieldOwner(applyFieldOwner), client.ForceOwnership); err != nil {
+			result.rejected = true
+			result.reason = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// printApplyResults prints the results as a table, in the format of the
+// notification tables, and returns an error if a resource was rejected.
+func printApplyResults(results []applyResult, w io.Writer) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No resources to apply")
+		return nil
+	}
+
+	var rejected int
+	table := strings.Builder{}
+	t := tablewriter.NewWriter(&table)
+	t.SetHeader([]string{"Kind", "Resource", "Result", "Reason"})
+	t.SetColWidth(200)
+	t.SetRowLine(true)
+	for _, result := range results {
+		status := "ACCEPTED"
+		if result.rejected {
+			status = "REJECTED"
+			rejected++
+		}
+		t.Append([]string{result.kind, result.key, status, result.reason})
+	}
+	t.Render()
+	fmt.Fprintf(w, "Server-side dry-run:\n%s\n", table.String())
+	if rejected > 0 {
+		return fmt.Errorf("%d generated resources were rejected by the API server", rejected)
+	}
+	return nil
+}
+
+func newApplyCommand() *cobra.Command {
+	ar := &ApplyRunner{}
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Dry-run the apply of the generated Gateway API resources against the cluster",
+		Long:  `Converts the Ingresses, like print with the same conversion flags, and applies the generated Gateway API resources to the cluster of --kubeconfig and --context with a server-side dry-run, so that the API server validates them against its CRDs and admission webhooks without persisting them. Every resource is reported as accepted or rejected, and the command fails if any is rejected.`,
+		RunE:  ar.DryRunApply,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// The diff is computed from a server-side dry-run, which it implies.
+			if ar.dryRunDiff && !cmd.Flags().Changed("dry-run") {
+				ar.dryRun = serverDryRun
+			}
+			if ar.dryRun != serverDryRun {
+				return fmt.Errorf("--dry-run=%s is required, as only server-side dry-runs are supported", serverDryRun)
+			}
+			return ar.conversionFlags.validate()
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&ar.dryRun, "dry-run", "",
+		fmt.Sprintf(`The dry-run mode, which must be "%s": the resources are sent to the API server, which validates and admits them without persisting them. Required unless --dry-run-diff is set, which implies it.`, serverDryRun))
+	cmd.Flags().BoolVar(&ar.dryRunDiff, "dry-run-diff", false,
+		`If present, the changes the apply would make to the cluster are printed instead of the accepted and rejected resources: the fields each resource would create or change, compared to the live resource, including the defaults and mutations of the API server and its admission webhooks. The command fails if any resource would be created or changed.`)
+	cmd.Flags().StringVar(&ar.inputFile, "input-file", "",
+		`Path to the manifest file. When set, the tool will read ingresses from the file instead of reading from the cluster. Supported files are yaml and json.`)
+	cmd.Flags().StringVarP(&ar.namespace, "namespace", "n", "",
+		`If present, the namespace scope for this CLI request.`)
+	cmd.Flags().BoolVarP(&ar.allNamespaces, "all-namespaces", "A", false,
+		`If present, convert the Ingresses across all namespaces. Namespace in current context is ignored even if specified with --namespace.`)
+	cmd.Flags().StringSliceVar(&ar.providers, "providers", []string{},
+		fmt.Sprintf("The providers converting the Ingresses, supported values are %v.", i2gw.GetSupportedProviders()))
+	ar.conversionFlags.addFlags(cmd)
+
+	_ = cmd.MarkFlagRequired("providers")
+	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+	return cmd
+}
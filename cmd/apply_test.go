@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_dryRunApply(t *testing.T) {
+	var dryRuns []bool
+	cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patchOptions := &client.PatchOptions{}
+			patchOptions.ApplyOptions(opts)
+			dryRuns = append(dryRuns, patch.Type() == client.Apply.Type() && len(patchOptions.DryRun) > 0)
+			if obj.GetName() == "rejected" {
+				return fmt.Errorf("admission webhook denied the request")
+			}
+			return nil
+		},
+	}).Build()
+
+	route := func(name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	results, err := dryRunApply(context.Background(), cl, []client.Object{route("accepted"), route("rejected")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []applyResult{
+		{kind: "HTTPRoute", key: "default/accepted"},
+		{kind: "HTTPRoute", key: "default/rejected", rejected: true, reason: "admission webhook denied the request"},
+	}
+	if diff := cmp.Diff(expected, results, cmp.AllowUnexported(applyResult{})); diff != "" {
+		t.Errorf("Unexpected results (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]bool{true, true}, dryRuns); diff != "" {
+		t.Errorf("Unexpected server-side dry-run applies (-want +got):\n%s", diff)
+	}
+
+	var out bytes.Buffer
+	err = printApplyResults(results, &out)
+	if err == nil || err.Error() != "1 generated resources were rejected by the API server" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "REJECTED") {
+		t.Errorf("Expected the rejected resource to be printed, got:\n%s", out.String())
+	}
+}
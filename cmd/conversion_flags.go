@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// conversionFlags are the flags shaping the resources generated from the
+// Ingresses, shared by the commands converting them, so that print, apply and
+// verify generate the same resources from the same flags.
+type conversionFlags struct {
+	// tlsMinVersion is the minimum TLS version set on the HTTPS listeners.
+	// Value assigned via --tls-min-version flag.
+	tlsMinVersion string
+
+	// tlsSecretNamespace is the namespace of the certificate Secrets of the
+	// generated listeners. Value assigned via --tls-secret-namespace flag.
+	tlsSecretNamespace string
+
+	// targetImplementation is the Gateway API implementation the resources are
+	// generated for. Value assigned via --target-implementation flag.
+	targetImplementation string
+
+	// mergeWith is the path to a file with existing Gateways the generated routes
+	// are attached to. Value assigned via --merge-with flag.
+	mergeWith string
+
+	// gatewayClassMapping maps ingress classes or provider names to the
+	// GatewayClass of the generated Gateways. Value assigned via
+	// --gateway-class-mapping flag.
+	gatewayClassMapping map[string]string
+
+	// gatewayClassName is the GatewayClass of the generated Gateways whose
+	// ingress class is not mapped. Value assigned via --gateway-class-name flag.
+	gatewayClassName string
+
+	// listenerProtocols maps listener ports to the protocol of the generated
+	// listeners on them. Value assigned via --listener-protocol flag.
+	listenerProtocols map[string]string
+
+	// portMap maps listener ports to the ports of the generated listeners on
+	// them. Value assigned via --port-map flag.
+	portMap map[string]string
+
+	// httpListenerPolicy sets how the hosts with TLS are served over HTTP.
+	// Value assigned via --http-listener-policy flag.
+	httpListenerPolicy string
+
+	// resourcePrefix is prepended to the names of the generated resources.
+	// Value assigned via --resource-prefix flag.
+	resourcePrefix string
+
+	// renameMap is the path of the file mapping the Ingresses to the names of
+	// the resources generated from them. Value assigned via --rename-map flag.
+	renameMap string
+
+	// emitReferenceGrants indicates whether the generated ReferenceGrants are
+	// printed. Value assigned via --emit-reference-grants flag.
+	emitReferenceGrants bool
+
+	// channel is the release channel of the Gateway API CRDs the resources are
+	// printed for. Value assigned via --channel flag.
+	channel string
+
+	// maxRoutesPerGateway is the maximum number of routes attached to a
+	// generated Gateway. Value assigned via --max-routes-per-gateway flag.
+	maxRoutesPerGateway int
+
+	// Provider specific flags --<provider>-<flag>.
+	providerSpecificFlags map[string]*string
+}
+
+// addFlags registers the conversion flags, including the provider-specific
+// ones, on the command.
+func (cf *conversionFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cf.tlsMinVersion, "tls-min-version", "",
+		`If present, the minimum TLS version (1.0, 1.1, 1.2 or 1.3) of the generated HTTPS listeners, set in the ClientTrafficPolicy of the Gateways for envoy-gateway, not set with a warning for istio, which has no tls.options key for it, and set in the generic tls-min-version key of the listener tls.options otherwise.`)
+
+	cmd.Flags().StringVar(&cf.tlsSecretNamespace, "tls-secret-namespace", "",
+		`If present, the namespace of the certificate Secrets, e.g. a namespace storing all of them: the certificateRefs of the generated listeners reference the Secrets of the same name in it, and ReferenceGrants allowing the Gateways to reference them are generated.`)
+
+	cmd.Flags().StringVar(&cf.targetImplementation, "target-implementation", "",
+		fmt.Sprintf("If present, the Gateway API implementation the resources are generated for, which determines the implementation policies generated, supported values are %v.", i2gw.GetSupportedTargetImplementations()))
+
+	cmd.Flags().StringVar(&cf.mergeWith, "merge-with", "",
+		`If present, the path to a manifest file with existing Gateways. The generated routes are attached to the existing Gateway of the same class, which is then not generated.`)
+
+	cmd.Flags().StringToStringVar(&cf.gatewayClassMapping, "gateway-class-mapping", nil,
+		`If present, comma-separated ingress class or provider name to GatewayClass mappings, e.g. nginx=nginx-gateway,gce=gke-l7, setting the GatewayClass of the generated Gateways.`)
+
+	cmd.Flags().StringVar(&cf.gatewayClassName, "gateway-class-name", "",
+		`If present, the GatewayClass of the generated Gateways whose ingress class has no --gateway-class-mapping.`)
+
+	cmd.Flags().StringToStringVar(&cf.listenerProtocols, "listener-protocol", nil,
+		`If present, comma-separated port to protocol mappings, e.g. 8443=HTTPS,5432=TCP, overriding the protocol inferred for the generated listeners on these ports. Supported protocols are HTTP, HTTPS, TLS, TCP and UDP.`)
+
+	cmd.Flags().StringToStringVar(&cf.portMap, "port-map", nil,
+		`If present, comma-separated port mappings, e.g. 80=8080,443=8443, moving the generated listeners on these ports to the mapped ports, for Gateways served behind another load balancer.`)
+
+	cmd.Flags().StringVar(&cf.httpListenerPolicy, "http-listener-policy", i2gw.HTTPListenerPolicyBoth,
+		fmt.Sprintf(`How the hosts with both an HTTP and an HTTPS listener are served over HTTP: "%s" attaches their routes to both listeners, "%s" redirects their HTTP requests to HTTPS and "%s" removes their HTTP listener.`,
+			i2gw.HTTPListenerPolicyBoth, i2gw.HTTPListenerPolicyRedirect, i2gw.HTTPListenerPolicyHTTPSOnly))
+
+	cmd.Flags().StringVar(&cf.resourcePrefix, "resource-prefix", "",
+		`If present, the prefix of the names of the generated resources, e.g. migrated- for migrated-<name>, to apply them next to existing Gateway API resources. The names too long are truncated and suffixed with a hash.`)
+
+	cmd.Flags().StringVar(&cf.renameMap, "rename-map", "",
+		`If present, a YAML file mapping Ingresses, as namespace/name, to the names of the Gateway and HTTPRoute generated from them, e.g. {"prod/shop": {"gateway": "shop", "httpRoute": "shop-routes"}}. The other resources keep their derived names.`)
+
+	cmd.Flags().BoolVar(&cf.emitReferenceGrants, "emit-reference-grants", true,
+		`If false, the ReferenceGrants allowing the cross-namespace references of the generated routes are not printed, for the users managing them separately. A Warning lists the references each of them would have allowed.`)
+
+	cmd.Flags().StringVar(&cf.channel, "channel", i2gw.ExperimentalChannel,
+		fmt.Sprintf(`The release channel of the Gateway API CRDs of the cluster, "%s" or "%s". It sets the apiVersion of the printed resources, and the conversion fails if resources of kinds missing from the channel, like TCPRoute or TLSRoute for "%s", are generated.`,
+			i2gw.StandardChannel, i2gw.ExperimentalChannel, i2gw.StandardChannel))
+
+	cmd.Flags().IntVar(&cf.maxRoutesPerGateway, "max-routes-per-gateway", 0,
+		`If positive, the generated Gateways with more routes attached are split into Gateways named <name>-1, <name>-2, etc., with at most this number of routes each. The routes sharing a listener are kept on the same Gateway.`)
+
+	cf.providerSpecificFlags = make(map[string]*string)
+	for provider, flags := range i2gw.GetProviderSpecificFlagDefinitions() {
+		for _, flag := range flags {
+			flagName := fmt.Sprintf("%s-%s", provider, flag.Name)
+			cf.providerSpecificFlags[flagName] = cmd.Flags().String(flagName, flag.DefaultValue, fmt.Sprintf("Provider-specific: %s. %s", provider, flag.Description))
+		}
+	}
+}
+
+// validate returns an error if the values of the conversion flags are invalid.
+func (cf *conversionFlags) validate() error {
+	if cf.maxRoutesPerGateway < 0 {
+		return fmt.Errorf("--max-routes-per-gateway must not be negative")
+	}
+	return nil
+}
+
+// gatewayOptions returns the GatewayOptions of the conversion flags, reading the
+// files of --merge-with and --rename-map.
+func (cf *conversionFlags) gatewayOptions() (i2gw.GatewayOptions, error) {
+	var existingGateways []gatewayv1.Gateway
+	if cf.mergeWith != "" {
+		var err error
+		existingGateways, err = common.ReadGatewaysFromFile(cf.mergeWith)
+		if err != nil {
+			return i2gw.GatewayOptions{}, fmt.Errorf("failed to read existing Gateways: %w", err)
+		}
+	}
+
+	var renameMap map[types.NamespacedName]i2gw.ResourceNames
+	if cf.renameMap != "" {
+		var err error
+		renameMap, err = readRenameMap(cf.renameMap)
+		if err != nil {
+			return i2gw.GatewayOptions{}, fmt.Errorf("failed to read the rename map: %w", err)
+		}
+	}
+
+	return i2gw.GatewayOptions{
+		TLSMinVersion:           cf.tlsMinVersion,
+		TLSSecretNamespace:      cf.tlsSecretNamespace,
+		TargetImplementation:    cf.targetImplementation,
+		ExistingGateways:        existingGateways,
+		GatewayClassNames:       cf.gatewayClassMapping,
+		DefaultGatewayClassName: cf.gatewayClassName,
+		ListenerProtocols:       cf.listenerProtocols,
+		PortMap:                 cf.portMap,
+		HTTPListenerPolicy:      cf.httpListenerPolicy,
+		ResourcePrefix:          cf.resourcePrefix,
+		RenameMap:               renameMap,
+		OmitReferenceGrants:     !cf.emitReferenceGrants,
+		Channel:                 cf.channel,
+		MaxRoutesPerGateway:     cf.maxRoutesPerGateway,
+	}, nil
+}
+
+// providerSpecificFlagValues returns the provider specific flags input by the user
+// for the providers. The flags are returned in a map where the key is the provider
+// name and the value is a map of flag name to flag value.
+func (cf *conversionFlags) providerSpecificFlagValues(providers []string) map[string]map[string]string {
+	providerSpecificFlags := make(map[string]map[string]string)
+	for flagName, value := range cf.providerSpecificFlags {
+		provider, found := lo.Find(providers, func(p string) bool { return strings.HasPrefix(flagName, fmt.Sprintf("%s-", p)) })
+		if !found {
+			continue
+		}
+		flagNameWithoutProvider := strings.TrimPrefix(flagName, fmt.Sprintf("%s-", provider))
+		if providerSpecificFlags[provider] == nil {
+			providerSpecificFlags[provider] = make(map[string]string)
+		}
+		providerSpecificFlags[provider][flagNameWithoutProvider] = *value
+	}
+	return providerSpecificFlags
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/spf13/cobra"
+)
+
+func Test_conversionFlags(t *testing.T) {
+	cf := &conversionFlags{}
+	cmd := &cobra.Command{}
+	cf.addFlags(cmd)
+	err := cmd.ParseFlags([]string{
+		"--gateway-class-name", "eg",
+		"--resource-prefix", "migrated-",
+		"--channel", i2gw.StandardChannel,
+		"--emit-reference-grants=false",
+		"--port-map", "80=8080",
+		"--ingress-nginx-controller-service", "ingress-nginx/controller",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the flags: %v", err)
+	}
+
+	gatewayOptions, err := cf.gatewayOptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedOptions := i2gw.GatewayOptions{
+		DefaultGatewayClassName: "eg",
+		ResourcePrefix:          "migrated-",
+		Channel:                 i2gw.StandardChannel,
+		OmitReferenceGrants:     true,
+		PortMap:                 map[string]string{"80": "8080"},
+		HTTPListenerPolicy:      i2gw.HTTPListenerPolicyBoth,
+	}
+	if diff := cmp.Diff(expectedOptions, gatewayOptions); diff != "" {
+		t.Errorf("Unexpected GatewayOptions (-want +got):\n%s", diff)
+	}
+	expectedProviderFlags := map[string]map[string]string{
+		"ingress-nginx": {"controller-service": "ingress-nginx/controller"},
+	}
+	if diff := cmp.Diff(expectedProviderFlags, cf.providerSpecificFlagValues([]string{"ingress-nginx"})); diff != "" {
+		t.Errorf("Unexpected provider-specific flags (-want +got):\n%s", diff)
+	}
+
+	// The commands converting the Ingresses share the conversion flags.
+	for _, command := range []*cobra.Command{newPrintCommand(), newApplyCommand(), newVerifyCommand()} {
+		for _, name := range []string{"channel", "emit-reference-grants", "gateway-class-mapping", "gateway-class-name", "http-listener-policy", "listener-protocol", "max-routes-per-gateway", "merge-with", "port-map", "rename-map", "resource-prefix", "target-implementation", "tls-min-version", "tls-secret-namespace", "ingress-nginx-controller-service"} {
+			if command.Flags().Lookup(name) == nil {
+				t.Errorf("Expected the %s command to have the --%s conversion flag", command.Name(), name)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterScopedKinds are the kinds of the cluster-scoped resources that may be
+// found in input files, which are not assigned the default namespace.
+var clusterScopedKinds = sets.New(
+	"ClusterIssuer",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"GatewayClass",
+	"IngressClass",
+	"KongClusterPlugin",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"StorageClass",
+)
+
+// validateDefaultNamespace returns an error if the default namespace is not a
+// valid namespace name.
+func validateDefaultNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("--default-namespace %q is not a valid namespace: %s", namespace, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// defaultInputNamespace assigns the default namespace to the namespaced objects
+// of the input file without namespace, as kubectl does when applying them, so
+// that the Ingresses and the resources they reference, like their Services, end
+// up in the same namespace. When some objects are assigned the namespace, the
+// objects are written to a temporary file whose name is returned, and which
+// the caller must remove. Otherwise the input file is returned. The assigned
+// objects are returned too.
+func defaultInputNamespace(inputFile, namespace string) (string, []client.Object, error) {
+	stream, err := os.ReadFile(inputFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file %v: %w", inputFile, err)
+	}
+	objects, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), "")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	var defaulted []client.Object
+	for _, obj := range objects {
+		if obj.GetNamespace() != "" || clusterScopedKinds.Has(obj.GetKind()) {
+			continue
+		}
+		obj.SetNamespace(namespace)
+		defaulted = append(defaulted, obj)
+	}
+	if len(defaulted) == 0 {
+		return inputFile, nil, nil
+	}
+
+	f, err := os.CreateTemp("", "ingress2gateway-input-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	for _, obj := range objects {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			os.Remove(f.Name())
+			return "", nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if _, err = fmt.Fprintf(f, "---\n%s", b); err != nil {
+			os.Remove(f.Name())
+			return "", nil, err
+		}
+	}
+	return f.Name(), defaulted, nil
+}
+
+// notifyDefaultedNamespaces dispatches, for every provider, an Info
+// notification listing the input objects assigned the default namespace.
+func notifyDefaultedNamespaces(providers []string, namespace string, defaulted []client.Object) {
+	if len(defaulted) == 0 {
+		return
+	}
+	names := make([]string, 0, len(defaulted))
+	for _, obj := range defaulted {
+		names = append(names, fmt.Sprintf("%s %s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName()))
+	}
+	for _, provider := range providers {
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("the input objects without namespace are assigned the default namespace %s: %s", namespace, strings.Join(names, ", ")),
+			CallingObjects: defaulted,
+		}, provider)
+	}
+}
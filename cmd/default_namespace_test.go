@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const namespacelessInput = `apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: nginx
+spec:
+  controller: k8s.io/ingress-nginx
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: bar
+  namespace: apps
+spec:
+  ingressClassName: nginx
+  defaultBackend:
+    service:
+      name: bar
+      port:
+        number: 80
+`
+
+func Test_defaultInputNamespace(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	if err := os.WriteFile(inputFile, []byte(namespacelessInput), 0o600); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	file, defaulted, err := defaultInputNamespace(inputFile, "staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer os.Remove(file)
+	if file == inputFile {
+		t.Fatalf("Expected a new input file to be written")
+	}
+
+	var gotDefaulted []string
+	for _, obj := range defaulted {
+		gotDefaulted = append(gotDefaulted, obj.GetObjectKind().GroupVersionKind().Kind+" "+obj.GetNamespace()+"/"+obj.GetName())
+	}
+	if diff := cmp.Diff([]string{"Ingress staging/foo", "Service staging/foo"}, gotDefaulted); diff != "" {
+		t.Errorf("Unexpected defaulted objects (-want +got):\n%s", diff)
+	}
+
+	ingresses, err := common.ReadIngressesFromFile(file, "", sets.New("nginx"), nil)
+	if err != nil {
+		t.Fatalf("Expected no error reading the Ingresses, got %v", err)
+	}
+	for _, key := range []types.NamespacedName{{Namespace: "staging", Name: "foo"}, {Namespace: "apps", Name: "bar"}} {
+		if _, ok := ingresses[key]; !ok {
+			t.Errorf("Expected Ingress %s to be read, got %v", key, ingresses)
+		}
+	}
+	services, err := common.ReadServicesFromFile(file, "staging")
+	if err != nil {
+		t.Fatalf("Expected no error reading the Services, got %v", err)
+	}
+	if _, ok := services[types.NamespacedName{Namespace: "staging", Name: "foo"}]; !ok {
+		t.Errorf("Expected Service staging/foo to be read, got %v", services)
+	}
+
+	// The file written is left as is, as all its objects have a namespace.
+	again, defaulted, err := defaultInputNamespace(file, "default")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if again != file || len(defaulted) != 0 {
+		t.Errorf("Expected the input file %s to be kept with no defaulted object, got %s and %d objects", file, again, len(defaulted))
+	}
+}
+
+func Test_validateDefaultNamespace(t *testing.T) {
+	if err := validateDefaultNamespace("default"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	for _, namespace := range []string{"", "Default", "foo.bar"} {
+		if err := validateDefaultNamespace(namespace); err == nil {
+			t.Errorf("Expected an error for namespace %q", namespace)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// diffFriendlyObjects returns the objects as unstructured, without the fields
+// that make `kubectl diff` report changes the apply would not make: the status,
+// which is not applied, the empty creationTimestamp, and the null or empty
+// optional fields, which the API server omits or defaults.
+func diffFriendlyObjects(objects []client.Object) ([]client.Object, error) {
+	diffFriendly := make([]client.Object, 0, len(objects))
+	for _, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		delete(content, "status")
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		pruned, _ := pruneEmptyFields(content).(map[string]any)
+		diffFriendly = append(diffFriendly, &unstructured.Unstructured{Object: pruned})
+	}
+	return diffFriendly, nil
+}
+
+// pruneEmptyFields removes the fields of the maps whose value is null, an empty
+// map or an empty list, once pruned themselves. The list elements are pruned
+// but always kept, as their position and count are meaningful, like an empty
+// match matching every request.
+func pruneEmptyFields(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			field = pruneEmptyFields(field)
+			if isEmptyField(field) {
+				delete(value, key)
+				continue
+			}
+			value[key] = field
+		}
+		return value
+	case []any:
+		for i, element := range value {
+			value[i] = pruneEmptyFields(element)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+func isEmptyField(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(value) == 0
+	case []any:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_diffFriendlyObjects(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+		},
+	}
+	route := &gatewayv1.HTTPRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+			Hostnames:       []gatewayv1.Hostname{"foo.example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")}}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+						Name: "foo", Port: ptr.To(gatewayv1.PortNumber(80)),
+					}}}},
+				},
+				// An empty match matches every request, and must be kept.
+				{Matches: []gatewayv1.HTTPRouteMatch{{}}},
+			},
+		},
+	}
+
+	objects, err := diffFriendlyObjects([]client.Object{gateway, route})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	printer := &printers.YAMLPrinter{}
+	for _, obj := range objects {
+		if err = printer.PrintObj(obj, &out); err != nil {
+			t.Fatalf("Unexpected error printing %s: %v", obj.GetName(), err)
+		}
+	}
+	for _, noise := range []string{"status:", "creationTimestamp", "null", "[]"} {
+		if strings.Contains(out.String(), noise) {
+			t.Errorf("Expected no %q in the diff-friendly output, got:\n%s", noise, out.String())
+		}
+	}
+	if count := strings.Count(out.String(), "{}"); count != 1 {
+		t.Errorf("Expected only the empty match to be printed as {}, got:\n%s", out.String())
+	}
+
+	// The printed resources decode to the generated ones, so an apply of
+	// either makes the same changes.
+	documents := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("Expected 2 printed resources, got:\n%s", out.String())
+	}
+	for i, expected := range []client.Object{gateway, route} {
+		got := expected.DeepCopyObject().(client.Object)
+		if err = yaml.UnmarshalStrict([]byte(documents[i]), got); err != nil {
+			t.Fatalf("Unexpected error decoding %s: %v", expected.GetName(), err)
+		}
+		if diff := cmp.Diff(expected, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Unexpected decoded %s (-want +got):\n%s", expected.GetName(), diff)
+		}
+	}
+}
+
+func Test_pruneEmptyFields(t *testing.T) {
+	value := map[string]any{
+		"metadata": map[string]any{"name": "foo", "labels": map[string]any{}},
+		"spec": map[string]any{
+			"hostnames": []any{},
+			"rules":     []any{map[string]any{"matches": []any{map[string]any{"headers": nil}}, "filters": nil}},
+			"nested":    map[string]any{"empty": map[string]any{"list": []any{}}},
+			"port":      int64(0),
+			"group":     "",
+		},
+	}
+	expected := map[string]any{
+		"metadata": map[string]any{"name": "foo"},
+		"spec": map[string]any{
+			"rules": []any{map[string]any{"matches": []any{map[string]any{}}}},
+			"port":  int64(0),
+			"group": "",
+		},
+	}
+	if diff := cmp.Diff(expected, pruneEmptyFields(runtime.DeepCopyJSON(value))); diff != "" {
+		t.Errorf("Unexpected pruned fields (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// doctorStatus is the result of a readiness check of the doctor command.
+type doctorStatus string
+
+const (
+	doctorOK      doctorStatus = "OK"
+	doctorWarning doctorStatus = "WARN"
+	// doctorFailed is the status of the checks a conversion cannot succeed
+	// without, failing the doctor command.
+	doctorFailed doctorStatus = "FAIL"
+)
+
+// doctorTimeout bounds the requests of the doctor command to the cluster, so
+// that an unreachable cluster is reported instead of hanging.
+const doctorTimeout = 10 * time.Second
+
+// gatewayAPIBundleVersion is the release of the Gateway API CRDs the resources
+// are generated for.
+const gatewayAPIBundleVersion = "v1.0.0"
+
+const (
+	// bundleVersionAnnotation is set on the Gateway API CRDs to their release.
+	bundleVersionAnnotation = "gateway.networking.k8s.io/bundle-version"
+	// channelAnnotation is set on the Gateway API CRDs to their release channel.
+	channelAnnotation = "gateway.networking.k8s.io/channel"
+)
+
+var crdListGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinitionList"}
+
+type doctorCheck struct {
+	name    string
+	status  doctorStatus
+	details string
+}
+
+// runDoctor checks the prerequisites of the conversions of the cluster of the
+// kubeconfig and prints a readiness report, failing if any check failed.
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	restConfig, check := checkKubeconfig()
+	checks := []doctorCheck{check}
+	var flags []string
+	if restConfig != nil {
+		restConfig.Timeout = doctorTimeout
+		var clusterChecks []doctorCheck
+		clusterChecks, flags = diagnoseCluster(cmd.Context(), restConfig)
+		checks = append(checks, clusterChecks...)
+	}
+	return printDoctorReport(checks, flags, cmd.OutOrStdout())
+}
+
+// checkKubeconfig loads the kubeconfig, from --kubeconfig or the standard
+// locations, and returns the client config of its current context, or of the
+// context of --context.
+func checkKubeconfig() (*rest.Config, doctorCheck) {
+	check := doctorCheck{name: "Kubeconfig", status: doctorFailed}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		check.details = fmt.Sprintf("failed to load the kubeconfig: %v", err)
+		return nil, check
+	}
+	contextName := rawConfig.CurrentContext
+	if kubeContext != "" {
+		contextName = kubeContext
+	}
+	if contextName == "" {
+		check.details = "the kubeconfig has no current context, set one with kubectl config use-context or pass --kubeconfig"
+		return nil, check
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		check.details = fmt.Sprintf("the context %s is invalid: %v", contextName, err)
+		return nil, check
+	}
+	check.status = doctorOK
+	check.details = fmt.Sprintf("context %s, server %s", contextName, restConfig.Host)
+	return restConfig, check
+}
+
+// diagnoseCluster checks that the cluster of the client config is reachable,
+// then its Gateway API CRDs and ingress controllers, and returns the flags
+// recommended for its conversions.
+func diagnoseCluster(ctx context.Context, restConfig *rest.Config) ([]doctorCheck, []string) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return []doctorCheck{{name: "Cluster", status: doctorFailed, details: fmt.Sprintf("failed to create discovery client: %v", err)}}, nil
+	}
+	cl, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return []doctorCheck{{name: "Cluster", status: doctorFailed, details: fmt.Sprintf("failed to create client: %v", err)}}, nil
+	}
+	return runClusterChecks(ctx, cl, discoveryClient)
+}
+
+func runClusterChecks(ctx context.Context, cl client.Client, versions discovery.ServerVersionInterface) ([]doctorCheck, []string) {
+	version, err := versions.ServerVersion()
+	if err != nil {
+		return []doctorCheck{{name: "Cluster", status: doctorFailed, details: fmt.Sprintf("the cluster is not reachable: %v", err)}}, nil
+	}
+	checks := []doctorCheck{{name: "Cluster", status: doctorOK, details: fmt.Sprintf("reachable, Kubernetes %s", version.GitVersion)}}
+
+	crdCheck, channel := checkGatewayAPICRDs(ctx, cl)
+	controllersCheck, providers := checkIngressControllers(ctx, cl)
+	var flags []string
+	if channel != "" {
+		flags = append(flags, fmt.Sprintf("--channel %s", channel))
+	}
+	if len(providers) > 0 {
+		flags = append(flags, fmt.Sprintf("--providers %s", strings.Join(providers, ",")))
+	}
+	return append(checks, crdCheck, controllersCheck), flags
+}
+
+// checkGatewayAPICRDs checks that the CRDs of the Gateway API kinds of the
+// standard channel serve the versions the resources are generated with, and
+// returns the recommended channel: experimental if the CRDs of all its kinds
+// are installed too.
+func checkGatewayAPICRDs(ctx context.Context, cl client.Client) (doctorCheck, string) {
+	check := doctorCheck{name: "Gateway API CRDs", status: doctorFailed}
+	crds := &unstructured.UnstructuredList{}
+	crds.SetGroupVersionKind(crdListGVK)
+	if err := cl.List(ctx, crds); err != nil {
+		check.status = doctorWarning
+		check.details = fmt.Sprintf("failed to list the CRDs, the conversions may fail if the Gateway API ones are missing: %v", err)
+		return check, ""
+	}
+
+	served := map[string][]string{}
+	var bundleVersions, channels []string
+	for _, crd := range crds.Items {
+		if group, _, _ := unstructured.NestedString(crd.Object, "spec", "group"); group != gatewayv1.GroupName {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, version := range versions {
+			version, _ := version.(map[string]any)
+			if name, _ := version["name"].(string); name != "" && version["served"] == true {
+				served[kind] = append(served[kind], name)
+			}
+		}
+		if bundleVersion := crd.GetAnnotations()[bundleVersionAnnotation]; bundleVersion != "" && !slices.Contains(bundleVersions, bundleVersion) {
+			bundleVersions = append(bundleVersions, bundleVersion)
+		}
+		if channel := crd.GetAnnotations()[channelAnnotation]; channel != "" && !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	if len(served) == 0 {
+		check.details = fmt.Sprintf("no Gateway API CRD is installed, install the %s ones, e.g. kubectl apply -f https://github.com/kubernetes-sigs/gateway-api/releases/download/%s/standard-install.yaml", gatewayAPIBundleVersion, gatewayAPIBundleVersion)
+		return check, ""
+	}
+
+	// missingKinds returns the kinds of the channel whose CRD does not serve
+	// the version they are generated with.
+	missingKinds := func(channel string, except map[string]string) []string {
+		var missing []string
+		for kind, version := range i2gw.ChannelKinds(channel) {
+			if _, ok := except[kind]; !ok && !slices.Contains(served[kind], version) {
+				missing = append(missing, fmt.Sprintf("%s %s", kind, version))
+			}
+		}
+		sort.Strings(missing)
+		return missing
+	}
+
+	sort.Strings(bundleVersions)
+	sort.Strings(channels)
+	details := []string{fmt.Sprintf("bundle version %s, channel %s, the resources are generated for %s", joinOrUnknown(bundleVersions), joinOrUnknown(channels), gatewayAPIBundleVersion)}
+	if missing := missingKinds(i2gw.StandardChannel, nil); len(missing) > 0 {
+		details = append(details, fmt.Sprintf("the CRDs do not serve %s, the generated resources would be rejected, install the %s CRDs or later", strings.Join(missing, ", "), gatewayAPIBundleVersion))
+		check.details = strings.Join(details, "; ")
+		return check, ""
+	}
+
+	check.status = doctorOK
+	channel := i2gw.ExperimentalChannel
+	if missing := missingKinds(i2gw.ExperimentalChannel, i2gw.ChannelKinds(i2gw.StandardChannel)); len(missing) > 0 {
+		channel = i2gw.StandardChannel
+		details = append(details, fmt.Sprintf("the experimental kinds %s are not served, the conversions generating them fail with --channel %s", strings.Join(missing, ", "), channel))
+	}
+	check.details = strings.Join(details, "; ")
+	return check, channel
+}
+
+// checkIngressControllers reports the ingress classes of the cluster, from its
+// IngressClasses and Ingresses, with their controller and number of Ingresses,
+// and returns the providers converting the Ingresses.
+func checkIngressControllers(ctx context.Context, cl client.Client) (doctorCheck, []string) {
+	check := doctorCheck{name: "Ingress controllers", status: doctorWarning}
+	ingressClassList := &networkingv1.IngressClassList{}
+	if err := cl.List(ctx, ingressClassList); err != nil {
+		check.details = fmt.Sprintf("failed to list the IngressClasses: %v", err)
+		return check, nil
+	}
+	ingressList := &networkingv1.IngressList{}
+	if err := cl.List(ctx, ingressList); err != nil {
+		check.details = fmt.Sprintf("failed to list the Ingresses: %v", err)
+		return check, nil
+	}
+
+	controllers := map[string]string{}
+	for _, ingressClass := range ingressClassList.Items {
+		controllers[ingressClass.Name] = ingressClass.Spec.Controller
+	}
+	ingresses := map[string]int{}
+	for _, ingress := range ingressList.Items {
+		ingressClass := common.GetIngressClass(ingress)
+		ingresses[ingressClass]++
+		if _, ok := controllers[ingressClass]; !ok && ingressClass != "" {
+			controllers[ingressClass] = ""
+		}
+	}
+	if len(ingressList.Items) == 0 {
+		check.details = "no Ingress found, there is nothing to convert"
+		return check, nil
+	}
+
+	supportedProviders := i2gw.GetSupportedProviders()
+	var details, providers []string
+	var unsupported bool
+	for _, ingressClass := range sortedKeys(controllers) {
+		detail := ingressClass
+		if controllers[ingressClass] != "" {
+			detail = fmt.Sprintf("%s (%s)", ingressClass, controllers[ingressClass])
+		}
+		name, known := common.IngressControllerName(ingressClass)
+		switch {
+		case known && slices.Contains(supportedProviders, name):
+			detail = fmt.Sprintf("%s: provider %s", detail, name)
+			if ingresses[ingressClass] > 0 && !slices.Contains(providers, name) {
+				providers = append(providers, name)
+			}
+		case known:
+			detail = fmt.Sprintf("%s: %s, which has no provider", detail, name)
+			unsupported = unsupported || ingresses[ingressClass] > 0
+		default:
+			detail = fmt.Sprintf("%s: unknown controller", detail)
+			unsupported = unsupported || ingresses[ingressClass] > 0
+		}
+		details = append(details, fmt.Sprintf("%s, %d Ingresses", detail, ingresses[ingressClass]))
+	}
+	if ingresses[""] > 0 {
+		details = append(details, fmt.Sprintf("%d Ingresses without ingress class, converted by the providers of their annotations", ingresses[""]))
+	}
+	sort.Strings(providers)
+	if !unsupported {
+		check.status = doctorOK
+	}
+	check.details = strings.Join(details, "; ")
+	return check, providers
+}
+
+func joinOrUnknown(values []string) string {
+	if len(values) == 0 {
+		return "unknown"
+	}
+	return strings.Join(values, ", ")
+}
+
+// sortedKeys returns the keys of the map, sorted.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printDoctorReport prints the checks as a table, in the format of the
+// notification tables, with the recommended flags, and returns an error if a
+// check failed.
+func printDoctorReport(checks []doctorCheck, flags []string, w io.Writer) error {
+	var failed int
+	table := strings.Builder{}
+	t := tablewriter.NewWriter(&table)
+	t.SetHeader([]string{"Check", "Status", "Details"})
+	t.SetColWidth(200)
+	t.SetRowLine(true)
+	for _, check := range checks {
+		if check.status == doctorFailed {
+			failed++
+		}
+		t.Append([]string{check.name, string(check.status), check.details})
+	}
+	t.Render()
+	fmt.Fprintf(w, "Readiness report:\n%s\n", table.String())
+	if len(flags) > 0 {
+		fmt.Fprintf(w, "Recommended flags: %s\n", strings.Join(flags, " "))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d readiness checks failed", failed)
+	}
+	return nil
+}
+
+func newDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "doctor",
+		Short:        "Check the prerequisites of the conversions of the cluster",
+		Long:         `Checks that the kubeconfig is valid, the cluster is reachable, the Gateway API CRDs are installed and serve the versions the resources are generated with, and which ingress controllers the Ingresses belong to. A readiness report is printed, along with the recommended --channel and --providers flags, and the command fails if a check failed.`,
+		RunE:         runDoctor,
+		SilenceUsage: true,
+	}
+}
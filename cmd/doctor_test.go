@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// serverVersion implements discovery.ServerVersionInterface.
+type serverVersion struct {
+	err error
+}
+
+func (s serverVersion) ServerVersion() (*version.Info, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &version.Info{GitVersion: "v1.29.0"}, nil
+}
+
+func gatewayAPICRD(kind, channel string, versions ...string) unstructured.Unstructured {
+	var specVersions []any
+	for _, version := range versions {
+		specVersions = append(specVersions, map[string]any{"name": version, "served": true})
+	}
+	crd := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name": strings.ToLower(kind) + "s.gateway.networking.k8s.io",
+			"annotations": map[string]any{
+				"gateway.networking.k8s.io/bundle-version": "v1.0.0",
+				"gateway.networking.k8s.io/channel":        channel,
+			},
+		},
+		"spec": map[string]any{
+			"group":    "gateway.networking.k8s.io",
+			"names":    map[string]any{"kind": kind},
+			"versions": specVersions,
+		},
+	}}
+	return crd
+}
+
+func Test_runClusterChecks(t *testing.T) {
+	standardCRDs := []unstructured.Unstructured{
+		gatewayAPICRD("GatewayClass", "standard", "v1", "v1beta1"),
+		gatewayAPICRD("Gateway", "standard", "v1", "v1beta1"),
+		gatewayAPICRD("HTTPRoute", "standard", "v1", "v1beta1"),
+		gatewayAPICRD("ReferenceGrant", "standard", "v1beta1"),
+	}
+	experimentalCRDs := append([]unstructured.Unstructured{
+		gatewayAPICRD("GRPCRoute", "experimental", "v1alpha2"),
+		gatewayAPICRD("TLSRoute", "experimental", "v1alpha2"),
+		gatewayAPICRD("TCPRoute", "experimental", "v1alpha2"),
+		gatewayAPICRD("UDPRoute", "experimental", "v1alpha2"),
+		gatewayAPICRD("BackendTLSPolicy", "experimental", "v1alpha2"),
+	}, standardCRDs...)
+	ingress := func(name string, ingressClass *string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       networkingv1.IngressSpec{IngressClassName: ingressClass},
+		}
+	}
+	objects := []client.Object{
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}, Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"}},
+		ingress("foo", ptr.To("nginx")),
+		ingress("bar", ptr.To("nginx")),
+		ingress("baz", nil),
+	}
+
+	testCases := []struct {
+		name           string
+		versionErr     error
+		crds           []unstructured.Unstructured
+		objects        []client.Object
+		expectedChecks []doctorCheck
+		expectedFlags  []string
+	}{
+		{
+			name:       "unreachable cluster",
+			versionErr: fmt.Errorf("connection refused"),
+			expectedChecks: []doctorCheck{
+				{name: "Cluster", status: doctorFailed, details: "the cluster is not reachable: connection refused"},
+			},
+		},
+		{
+			name:    "experimental CRDs",
+			crds:    experimentalCRDs,
+			objects: objects,
+			expectedChecks: []doctorCheck{
+				{name: "Cluster", status: doctorOK, details: "reachable, Kubernetes v1.29.0"},
+				{name: "Gateway API CRDs", status: doctorOK, details: "bundle version v1.0.0, channel experimental, standard, the resources are generated for v1.0.0"},
+				{name: "Ingress controllers", status: doctorOK, details: "nginx (k8s.io/ingress-nginx): provider ingress-nginx, 2 Ingresses; 1 Ingresses without ingress class, converted by the providers of their annotations"},
+			},
+			expectedFlags: []string{"--channel experimental", "--providers ingress-nginx"},
+		},
+		{
+			name:    "standard CRDs",
+			crds:    standardCRDs,
+			objects: append([]client.Object{ingress("traefik", ptr.To("traefik"))}, objects...),
+			expectedChecks: []doctorCheck{
+				{name: "Cluster", status: doctorOK, details: "reachable, Kubernetes v1.29.0"},
+				{name: "Gateway API CRDs", status: doctorOK, details: "bundle version v1.0.0, channel standard, the resources are generated for v1.0.0; " +
+					"the experimental kinds BackendTLSPolicy v1alpha2, GRPCRoute v1alpha2, TCPRoute v1alpha2, TLSRoute v1alpha2, UDPRoute v1alpha2 are not served, the conversions generating them fail with --channel standard"},
+				{name: "Ingress controllers", status: doctorWarning, details: "nginx (k8s.io/ingress-nginx): provider ingress-nginx, 2 Ingresses; traefik: traefik, which has no provider, 1 Ingresses; 1 Ingresses without ingress class, converted by the providers of their annotations"},
+			},
+			expectedFlags: []string{"--channel standard", "--providers ingress-nginx"},
+		},
+		{
+			name: "missing CRDs",
+			crds: standardCRDs[:1],
+			expectedChecks: []doctorCheck{
+				{name: "Cluster", status: doctorOK, details: "reachable, Kubernetes v1.29.0"},
+				{name: "Gateway API CRDs", status: doctorFailed, details: "bundle version v1.0.0, channel standard, the resources are generated for v1.0.0; " +
+					"the CRDs do not serve Gateway v1, HTTPRoute v1, ReferenceGrant v1beta1, the generated resources would be rejected, install the v1.0.0 CRDs or later"},
+				{name: "Ingress controllers", status: doctorWarning, details: "no Ingress found, there is nothing to convert"},
+			},
+		},
+		{
+			name: "no CRDs",
+			expectedChecks: []doctorCheck{
+				{name: "Cluster", status: doctorOK, details: "reachable, Kubernetes v1.29.0"},
+				{name: "Gateway API CRDs", status: doctorFailed, details: "no Gateway API CRD is installed, install the v1.0.0 ones, e.g. kubectl apply -f https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.0.0/standard-install.yaml"},
+				{name: "Ingress controllers", status: doctorWarning, details: "no Ingress found, there is nothing to convert"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithObjects(tc.objects...).WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if crds, ok := list.(*unstructured.UnstructuredList); ok && crds.GroupVersionKind() == crdListGVK {
+						crds.Items = tc.crds
+						return nil
+					}
+					return cl.List(ctx, list, opts...)
+				},
+			}).Build()
+
+			checks, flags := runClusterChecks(context.Background(), cl, serverVersion{err: tc.versionErr})
+			if diff := cmp.Diff(tc.expectedChecks, checks, cmp.AllowUnexported(doctorCheck{})); diff != "" {
+				t.Errorf("Unexpected checks (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedFlags, flags); diff != "" {
+				t.Errorf("Unexpected flags (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_printDoctorReport(t *testing.T) {
+	var out bytes.Buffer
+	err := printDoctorReport([]doctorCheck{
+		{name: "Kubeconfig", status: doctorOK, details: "context kind, server https://127.0.0.1:6443"},
+		{name: "Gateway API CRDs", status: doctorFailed, details: "no Gateway API CRD is installed"},
+	}, []string{"--providers ingress-nginx"}, &out)
+	if err == nil || err.Error() != "1 readiness checks failed" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"Readiness report:", "| Gateway API CRDs | FAIL   | no Gateway API CRD is installed", "Recommended flags: --providers ingress-nginx"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
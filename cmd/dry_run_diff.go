@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// diffIgnoredFields are the fields set by the API server on every write, which
+// are not part of the diff of a dry-run apply.
+var diffIgnoredFields = [][]string{
+	{"status"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "generation"},
+	{"metadata", "uid"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "selfLink"},
+}
+
+type diffResult struct {
+	kind     string
+	key      string
+	created  bool
+	changes  []fieldChange
+	rejected bool
+	reason   string
+}
+
+// fieldChange is a field added (+), removed (-) or changed (~) by the apply.
+type fieldChange struct {
+	op     string
+	path   string
+	before any
+	after  any
+}
+
+// dryRunDiff applies every object with a server-side dry-run, like
+// dryRunApply, and returns the changes between the live object and the object
+// returned by the API server, which includes its defaults and the mutations of
+// the admission webhooks. An object not found in the cluster is created.
+func dryRunDiff(ctx context.Context, cl client.Client, objects []client.Object) ([]diffResult, error) {
+	var results []diffResult
+	for _, obj := range sortObjects(objects) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		u := &unstructured.Unstructured{Object: content}
+		unstructured.RemoveNestedField(u.Object, "status")
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+
+		key := types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}
+		result := diffResult{kind: u.GetKind(), key: key.String()}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(u.GroupVersionKind())
+		if err := cl.Get(ctx, key, live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				result.rejected, result.reason = true, err.Error()
+				results = append(results, result)
+				continue
+			}
+			live, result.created = nil, true
+		}
+
+		if err := cl.Patch(ctx, u, client.Apply, client.DryRunAll, client.FieldOwner(applyFieldOwner), client.ForceOwnership); err != nil {
+			result.rejected, result.reason = true, err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		var before map[string]any
+		if live != nil {
+			before = live.Object
+		}
+		result.changes = diffFields("", withoutIgnoredFields(before), withoutIgnoredFields(u.Object))
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func withoutIgnoredFields(obj map[string]any) map[string]any {
+	if obj == nil {
+		return nil
+	}
+	obj = runtime.DeepCopyJSON(obj)
+	for _, fields := range diffIgnoredFields {
+		unstructured.RemoveNestedField(obj, fields...)
+	}
+	return obj
+}
+
+// diffFields returns the changes between the before and after values, down to
+// their scalar fields. The list elements are compared by index.
+func diffFields(path string, before, after any) []fieldChange {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := map[string]bool{}
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		var changes []fieldChange
+		for _, key := range sortedStrings(keys) {
+			changes = append(changes, diffFields(joinFieldPath(path, key), beforeMap[key], afterMap[key])...)
+		}
+		return changes
+	}
+
+	beforeList, beforeIsList := before.([]any)
+	afterList, afterIsList := after.([]any)
+	if (beforeIsList || before == nil) && (afterIsList || after == nil) && (beforeIsList || afterIsList) {
+		var changes []fieldChange
+		for i := 0; i < len(beforeList) || i < len(afterList); i++ {
+			var beforeElement, afterElement any
+			if i < len(beforeList) {
+				beforeElement = beforeList[i]
+			}
+			if i < len(afterList) {
+				afterElement = afterList[i]
+			}
+			changes = append(changes, diffFields(fmt.Sprintf("%s[%d]", path, i), beforeElement, afterElement)...)
+		}
+		return changes
+	}
+
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		return []fieldChange{{op: "+", path: path, after: after}}
+	case after == nil:
+		return []fieldChange{{op: "-", path: path, before: before}}
+	case equalFieldValues(before, after):
+		return nil
+	default:
+		return []fieldChange{{op: "~", path: path, before: before, after: after}}
+	}
+}
+
+func equalFieldValues(before, after any) bool {
+	if reflect.TypeOf(before).Kind() == reflect.String || reflect.TypeOf(after).Kind() == reflect.String {
+		return before == after
+	}
+	// The numbers may be decoded as integers or floats.
+	return fmt.Sprint(before) == fmt.Sprint(after)
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedStrings(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printDiffResults prints the changes of every resource, and returns an error
+// if a resource would be created or changed, or was rejected, so that the exit
+// code tells whether the cluster is up to date.
+func printDiffResults(results []diffResult, w io.Writer) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No resources to apply")
+		return nil
+	}
+
+	var changed, rejected int
+	fmt.Fprintln(w, "Server-side dry-run diff:")
+	for _, result := range results {
+		switch {
+		case result.rejected:
+			rejected++
+			fmt.Fprintf(w, "%s %s: rejected: %s\n", result.kind, result.key, result.reason)
+			continue
+		case result.created:
+			changed++
+			fmt.Fprintf(w, "%s %s: created\n", result.kind, result.key)
+		case len(result.changes) > 0:
+			changed++
+			fmt.Fprintf(w, "%s %s: changed\n", result.kind, result.key)
+		default:
+			fmt.Fprintf(w, "%s %s: unchanged\n", result.kind, result.key)
+		}
+		for _, change := range result.changes {
+			fmt.Fprintf(w, "  %s\n", formatFieldChange(change))
+		}
+	}
+
+	if rejected > 0 {
+		return fmt.Errorf("%d generated resources were rejected by the API server", rejected)
+	}
+	if changed > 0 {
+		return fmt.Errorf("%d generated resources would be created or changed", changed)
+	}
+	return nil
+}
+
+func formatFieldChange(change fieldChange) string {
+	switch change.op {
+	case "+":
+		return fmt.Sprintf("+ %s: %s", change.path, formatFieldValue(change.after))
+	case "-":
+		return fmt.Sprintf("- %s: %s", change.path, formatFieldValue(change.before))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", change.path, formatFieldValue(change.before), formatFieldValue(change.after))
+	}
+}
+
+func formatFieldValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(encoded))
+}
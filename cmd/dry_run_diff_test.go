@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_dryRunDiff(t *testing.T) {
+	defaultRules := []any{map[string]any{"matches": []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/"}}}}}
+	live := map[string]map[string]any{
+		"changed": {
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": "changed", "namespace": "default", "resourceVersion": "42", "labels": map[string]any{"team": "a"}},
+			"spec":       map[string]any{"hostnames": []any{"old.example.com"}},
+			"status":     map[string]any{"parents": []any{}},
+		},
+		"unchanged": {
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": "unchanged", "namespace": "default", "resourceVersion": "7"},
+			"spec":       map[string]any{"hostnames": []any{"foo.example.com"}, "rules": defaultRules},
+		},
+	}
+	cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(_ context.Context, _ client.WithWatch, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+			content, ok := live[key.Name]
+			if !ok {
+				return apierrors.NewNotFound(schema.GroupResource{Group: gatewayv1.GroupName, Resource: "httproutes"}, key.Name)
+			}
+			obj.(*unstructured.Unstructured).Object = content
+			return nil
+		},
+		Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			if obj.GetName() == "rejected" {
+				return fmt.Errorf("admission webhook denied the request")
+			}
+			// The API server defaults the rules and sets the metadata.
+			u := obj.(*unstructured.Unstructured)
+			_ = unstructured.SetNestedSlice(u.Object, defaultRules, "spec", "rules")
+			u.SetResourceVersion("43")
+			u.SetUID("uid")
+			return nil
+		},
+	}).Build()
+
+	route := func(name string, hostname gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{hostname}},
+		}
+	}
+	results, err := dryRunDiff(context.Background(), cl, []client.Object{
+		route("changed", "new.example.com"),
+		route("created", "foo.example.com"),
+		route("rejected", "foo.example.com"),
+		route("unchanged", "foo.example.com"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	err = printDiffResults(results, &out)
+	if err == nil || err.Error() != "1 generated resources were rejected by the API server" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := `Server-side dry-run diff:
+HTTPRoute default/changed: changed
+  - metadata.labels.team: "a"
+  ~ spec.hostnames[0]: "old.example.com" -> "new.example.com"
+  + spec.rules[0].matches[0].path.type: "PathPrefix"
+  + spec.rules[0].matches[0].path.value: "/"
+HTTPRoute default/created: created
+  + apiVersion: "gateway.networking.k8s.io/v1"
+  + kind: "HTTPRoute"
+  + metadata.name: "created"
+  + metadata.namespace: "default"
+  + spec.hostnames[0]: "foo.example.com"
+  + spec.rules[0].matches[0].path.type: "PathPrefix"
+  + spec.rules[0].matches[0].path.value: "/"
+HTTPRoute default/rejected: rejected: admission webhook denied the request
+HTTPRoute default/unchanged: unchanged
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("Unexpected diff output (-want +got):\n%s", diff)
+	}
+
+	out.Reset()
+	err = printDiffResults([]diffResult{results[3]}, &out)
+	if err != nil {
+		t.Errorf("Expected no error when no resource changes, got %v", err)
+	}
+	results[3].changes = []fieldChange{{op: "~", path: "spec.hostnames[0]", before: "a", after: "b"}}
+	if err = printDiffResults([]diffResult{results[3]}, &out); err == nil || err.Error() != "1 generated resources would be created or changed" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func Test_dryRunDiffFlags(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError bool
+	}{
+		{
+			name: "dry-run-diff implies the server dry-run",
+			args: []string{"--providers", "ingress-nginx", "--dry-run-diff"},
+		},
+		{
+			name: "dry-run-diff with the server dry-run",
+			args: []string{"--providers", "ingress-nginx", "--dry-run=server", "--dry-run-diff"},
+		},
+		{
+			name:          "dry-run-diff with another dry-run",
+			args:          []string{"--providers", "ingress-nginx", "--dry-run=client", "--dry-run-diff"},
+			expectedError: true,
+		},
+		{
+			name:          "no dry-run",
+			args:          []string{"--providers", "ingress-nginx"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newApplyCommand()
+			if err := cmd.ParseFlags(tc.args); err != nil {
+				t.Fatalf("Unexpected error parsing the flags: %v", err)
+			}
+			err := cmd.PreRunE(cmd, nil)
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Expected an error: %t, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	yamlv3 "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// printObjectsExplained prints the objects as YAML documents, with comments
+// above the fields describing the source Ingress fields and annotations that
+// produced them, as recorded in provenance.ProvenanceAggr.
+func printObjectsExplained(objects []client.Object, w io.Writer) error {
+	for i, obj := range objects {
+		ref := provenance.ObjectRef{
+			Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		out, err := explainObject(obj, provenance.ProvenanceAggr.ObjectSources(ref))
+		if err != nil {
+			return fmt.Errorf("failed to explain %s %s: %w", ref.Kind, ref.NamespacedName, err)
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		if _, err = w.Write(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// explainObject returns the YAML serialization of the object, where every field
+// of sources is preceded by a comment listing its sources. The empty field path
+// refers to the object itself, whose sources are written at the top of the
+// document. Field paths that do not exist in the object are ignored.
+func explainObject(obj client.Object, sources map[string][]string) ([]byte, error) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var doc yamlv3.Node
+	if err = yamlv3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	fieldPaths := make([]string, 0, len(sources))
+	for fieldPath := range sources {
+		fieldPaths = append(fieldPaths, fieldPath)
+	}
+	sort.Strings(fieldPaths)
+	for _, fieldPath := range fieldPaths {
+		node := lookupFieldNode(doc.Content[0], fieldPath)
+		if node == nil {
+			continue
+		}
+		var comment []string
+		for _, source := range sources[fieldPath] {
+			comment = append(comment, "# from "+source)
+		}
+		if node.HeadComment != "" {
+			comment = append([]string{node.HeadComment}, comment...)
+		}
+		node.HeadComment = strings.Join(comment, "\n")
+	}
+
+	var buf bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err = encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lookupFieldNode returns the node a comment for the field path, like
+// `spec.rules[0].matches`, is attached to: the key node of a mapping entry, or
+// the first key node of the root or of a sequence item that is a mapping. It
+// returns nil if the field does not exist.
+func lookupFieldNode(root *yamlv3.Node, fieldPath string) *yamlv3.Node {
+	if fieldPath == "" {
+		if root.Kind != yamlv3.MappingNode || len(root.Content) == 0 {
+			return nil
+		}
+		return root.Content[0]
+	}
+	node, commentNode := root, (*yamlv3.Node)(nil)
+	for _, segment := range strings.Split(fieldPath, ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+		if name != "" {
+			key, value := mappingEntry(node, name)
+			if key == nil {
+				return nil
+			}
+			node, commentNode = value, key
+		}
+		if indexes == "" {
+			continue
+		}
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			i, err := strconv.Atoi(index)
+			if err != nil || node.Kind != yamlv3.SequenceNode || i < 0 || i >= len(node.Content) {
+				return nil
+			}
+			node, commentNode = node.Content[i], node.Content[i]
+			if node.Kind == yamlv3.MappingNode && len(node.Content) > 0 {
+				commentNode = node.Content[0]
+			}
+		}
+	}
+	return commentNode
+}
+
+// mappingEntry returns the key and value nodes of the mapping entry with the
+// given name, or nil if there is none.
+func mappingEntry(node *yamlv3.Node, name string) (*yamlv3.Node, *yamlv3.Node) {
+	if node.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
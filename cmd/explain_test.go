@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_explainObject(t *testing.T) {
+	httpRoute := &gatewayv1.HTTPRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"foo.com"},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo"}}}},
+			}},
+		},
+	}
+	sources := map[string][]string{
+		"":                          {"Ingress default/foo"},
+		"spec.hostnames":            {"spec.rules[0].host (Ingress default/foo)"},
+		"spec.rules[0].backendRefs": {"nginx.ingress.kubernetes.io/canary-weight (Ingress default/canary)", "spec.rules[0].http.paths[0].backend (Ingress default/foo)"},
+		"spec.rules[1].backendRefs": {"spec.rules[1].http.paths[0].backend (Ingress default/foo)"},
+	}
+
+	got, err := explainObject(httpRoute, sources)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := `# from Ingress default/foo
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: foo
+  namespace: default
+spec:
+  # from spec.rules[0].host (Ingress default/foo)
+  hostnames:
+    - foo.com
+  rules:
+    - # from nginx.ingress.kubernetes.io/canary-weight (Ingress default/canary)
+      # from spec.rules[0].http.paths[0].backend (Ingress default/foo)
+      backendRefs:
+        - name: foo
+status:
+  parents: null
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Unexpected explained YAML (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func Test_buildKustomization(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "base")
+	overlay := filepath.Join(filepath.Dir(base), "overlay")
+	files := map[string]string{
+		filepath.Join(base, "kustomization.yaml"): "resources:\n- ingress.yaml\n",
+		filepath.Join(base, "ingress.yaml"): `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+`,
+		filepath.Join(overlay, "kustomization.yaml"): "namespace: prod\nnamePrefix: prod-\nresources:\n- ../base\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inputFile, err := buildKustomization(overlay)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer os.Remove(inputFile)
+
+	ingresses, err := common.ReadIngressesFromFile(inputFile, "", sets.New("nginx"), nil)
+	if err != nil {
+		t.Fatalf("Failed to read the built Ingresses: %v", err)
+	}
+	if len(ingresses) != 1 {
+		t.Fatalf("Expected a single Ingress, got %+v", ingresses)
+	}
+	if _, ok := ingresses[types.NamespacedName{Namespace: "prod", Name: "prod-foo"}]; !ok {
+		t.Errorf("Expected Ingress prod/prod-foo, got %+v", ingresses)
+	}
+
+	if _, err = buildKustomization(t.TempDir()); err == nil || !strings.Contains(err.Error(), "kustomize build") {
+		t.Errorf("Expected a kustomize build error, got %v", err)
+	}
+}
+
+func Test_printCommandStrictKustomize(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError bool
+	}{
+		{
+			name: "strict with kustomize",
+			args: []string{"--providers", "ingress-nginx", "--strict", "--kustomize", "overlay"},
+		},
+		{
+			name: "strict with an input file",
+			args: []string{"--providers", "ingress-nginx", "--strict", "--input-file", "ingresses.yaml"},
+		},
+		{
+			name:          "strict with the cluster",
+			args:          []string{"--providers", "ingress-nginx", "--strict"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newPrintCommand()
+			if err := cmd.ParseFlags(tc.args); err != nil {
+				t.Fatalf("Unexpected error parsing the flags: %v", err)
+			}
+			err := cmd.PreRunE(cmd, nil)
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Expected an error: %t, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// outputKinds are the kinds of the generated resources, in the order they are
+// printed.
+var outputKinds = []string{
+	"GatewayClass",
+	"Gateway",
+	"HTTPRoute",
+	"GRPCRoute",
+	"TLSRoute",
+	"TCPRoute",
+	"UDPRoute",
+	"ReferenceGrant",
+	"BackendTLSPolicy",
+	"BackendTrafficPolicy",
+	"ClientTrafficPolicy",
+	"SecurityPolicy",
+}
+
+// normalizeOutputKinds returns the kinds with the case of outputKinds, so that
+// `httproute` can be used for HTTPRoute. It returns an error for unknown kinds.
+func normalizeOutputKinds(kinds []string) ([]string, error) {
+	normalized := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		found := false
+		for _, outputKind := range outputKinds {
+			if strings.EqualFold(kind, outputKind) {
+				normalized = append(normalized, outputKind)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown kind %q for --only-kind, supported kinds are %s", kind, strings.Join(outputKinds, ", "))
+		}
+	}
+	return normalized, nil
+}
+
+// filterGatewayResourcesByKind returns the resources of the given kinds only.
+// The resources are filtered after the whole conversion, so that the references
+// between the kept resources, like parentRefs, are the same as without filter.
+func filterGatewayResourcesByKind(gatewayResources []i2gw.GatewayResources, kinds []string) []i2gw.GatewayResources {
+	keep := map[string]bool{}
+	for _, kind := range kinds {
+		keep[kind] = true
+	}
+
+	filtered := make([]i2gw.GatewayResources, 0, len(gatewayResources))
+	for _, r := range gatewayResources {
+		var f i2gw.GatewayResources
+		if keep["GatewayClass"] {
+			f.GatewayClasses = r.GatewayClasses
+		}
+		if keep["Gateway"] {
+			f.Gateways = r.Gateways
+		}
+		if keep["HTTPRoute"] {
+			f.HTTPRoutes = r.HTTPRoutes
+		}
+		if keep["GRPCRoute"] {
+			f.GRPCRoutes = r.GRPCRoutes
+		}
+		if keep["TLSRoute"] {
+			f.TLSRoutes = r.TLSRoutes
+		}
+		if keep["TCPRoute"] {
+			f.TCPRoutes = r.TCPRoutes
+		}
+		if keep["UDPRoute"] {
+			f.UDPRoutes = r.UDPRoutes
+		}
+		if keep["ReferenceGrant"] {
+			f.ReferenceGrants = r.ReferenceGrants
+		}
+		if keep["BackendTLSPolicy"] {
+			f.BackendTLSPolicies = r.BackendTLSPolicies
+		}
+		for key, policy := range r.ImplementationPolicies {
+			if keep[key.Kind] {
+				if f.ImplementationPolicies == nil {
+					f.ImplementationPolicies = map[i2gw.PolicyKey]unstructured.Unstructured{}
+				}
+				f.ImplementationPolicies[key] = policy
+			}
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
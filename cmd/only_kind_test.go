@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_normalizeOutputKinds(t *testing.T) {
+	testCases := []struct {
+		name          string
+		kinds         []string
+		expectedKinds []string
+		expectedError bool
+	}{
+		{
+			name:          "no kinds",
+			expectedKinds: []string{},
+		},
+		{
+			name:          "kinds with any case",
+			kinds:         []string{"Gateway", "httproute", "REFERENCEGRANT"},
+			expectedKinds: []string{"Gateway", "HTTPRoute", "ReferenceGrant"},
+		},
+		{
+			name:          "unknown kind",
+			kinds:         []string{"Gateway", "Ingress"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kinds, err := normalizeOutputKinds(tc.kinds)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expectedKinds, kinds); diff != "" {
+				t.Errorf("Unexpected kinds (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_filterGatewayResourcesByKind(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "foo"}
+	gatewayResources := []i2gw.GatewayResources{{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			key: {ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			key: {
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "foo"}}},
+				},
+			},
+		},
+		ReferenceGrants: map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+			key: {ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}},
+		},
+	}}
+
+	filtered := filterGatewayResourcesByKind(gatewayResources, []string{"HTTPRoute", "ReferenceGrant"})
+
+	expected := []i2gw.GatewayResources{{
+		HTTPRoutes:      gatewayResources[0].HTTPRoutes,
+		ReferenceGrants: gatewayResources[0].ReferenceGrants,
+	}}
+	if diff := cmp.Diff(expected, filtered); diff != "" {
+		t.Errorf("Unexpected resources (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resourceKey identifies a resource across its versions.
+type resourceKey struct {
+	schema.GroupKind
+	namespace string
+	name      string
+}
+
+func objectResourceKey(obj client.Object) resourceKey {
+	return resourceKey{GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind(), namespace: obj.GetNamespace(), name: obj.GetName()}
+}
+
+// readResourceOrder returns the position of every resource of the manifest
+// file, like a previous output of the print command.
+func readResourceOrder(path string) (map[resourceKey]int, error) {
+	stream, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", path, err)
+	}
+	objs, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+	order := map[resourceKey]int{}
+	for _, obj := range objs {
+		key := objectResourceKey(obj)
+		if _, ok := order[key]; !ok {
+			order[key] = len(order)
+		}
+	}
+	return order, nil
+}
+
+// orderLike orders the objects like the resources of a reference file, so that
+// a new output differs as little as possible from the reference one. The
+// objects with a resource of the same group, kind, namespace and name in the
+// file come first, in its order, followed by the other objects in the order of
+// sortObjects.
+func orderLike(objects []client.Object, order map[resourceKey]int) []client.Object {
+	sorted := sortObjects(objects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iPosition, iOK := order[objectResourceKey(sorted[i])]
+		jPosition, jOK := order[objectResourceKey(sorted[j])]
+		if iOK != jOK {
+			return iOK
+		}
+		return iOK && iPosition < jPosition
+	})
+	return sorted
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_orderLike(t *testing.T) {
+	reference := `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: bar-com
+  namespace: default
+---
+apiVersion: gateway.networking.k8s.io/v1beta1
+kind: Gateway
+metadata:
+  name: nginx
+  namespace: default
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: removed-com
+  namespace: default
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: foo-com
+  namespace: default
+`
+	path := filepath.Join(t.TempDir(), "previous.yaml")
+	if err := os.WriteFile(path, []byte(reference), 0o600); err != nil {
+		t.Fatalf("Failed to write the reference file: %v", err)
+	}
+	order, err := readResourceOrder(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	gateway := func(name string) client.Object {
+		return &gatewayv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	httpRoute := func(name string) client.Object {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	expected := []string{"HTTPRoute/bar-com", "Gateway/nginx", "HTTPRoute/foo-com", "Gateway/internal", "HTTPRoute/baz-com", "HTTPRoute/new-com"}
+
+	// The objects of a kind are generated in any order, which must not change the output.
+	for _, objects := range [][]client.Object{
+		{gateway("nginx"), gateway("internal"), httpRoute("foo-com"), httpRoute("new-com"), httpRoute("bar-com"), httpRoute("baz-com")},
+		{gateway("internal"), gateway("nginx"), httpRoute("baz-com"), httpRoute("bar-com"), httpRoute("new-com"), httpRoute("foo-com")},
+	} {
+		var got []string
+		for _, obj := range orderLike(objects, order) {
+			got = append(got, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+		}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("Unexpected order (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// kustomizationFile is the name of the kustomization written by --emit-kustomization.
+const kustomizationFile = "kustomization.yaml"
+
+// kustomization is the subset of the kustomize Kustomization listing the resources.
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// writeObjectsToDir writes every object to its own file in the output
+// directory, which is created if it does not exist, and a kustomization.yaml
+// referencing all of them if --emit-kustomization is set. With --output-layout,
+// the files are written to the subdirectories of their groupings.
+func (pr *PrintRunner) writeObjectsToDir(objects []client.Object) error {
+	if err := os.MkdirAll(pr.outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	dirs, err := objectDirs(objects, pr.outputLayout)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for i, obj := range objects {
+		data, err := pr.serializeObject(obj)
+		if err != nil {
+			return fmt.Errorf("failed to print %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		if err = os.MkdirAll(filepath.Join(pr.outputDir, dirs[i]), 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory %s: %w", dirs[i], err)
+		}
+		file := filepath.Join(dirs[i], objectFileName(obj, pr.outputFormat))
+		if err = os.WriteFile(filepath.Join(pr.outputDir, file), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		files = append(files, filepath.ToSlash(file))
+	}
+
+	if pr.emitKustomization {
+		if err := writeKustomization(pr.outputDir, files); err != nil {
+			return fmt.Errorf("failed to write %s: %w", kustomizationFile, err)
+		}
+	}
+	return nil
+}
+
+// serializeObject returns the object printed in the output format, annotated
+// with its sources when --explain is set.
+func (pr *PrintRunner) serializeObject(obj client.Object) ([]byte, error) {
+	if pr.explain {
+		ref := provenance.ObjectRef{
+			Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		return explainObject(obj, provenance.ProvenanceAggr.ObjectSources(ref))
+	}
+
+	// The YAML printer prepends a document separator to every object but the
+	// first one it prints, so a new one is used for every file.
+	printer := pr.resourcePrinter
+	if _, ok := printer.(*printers.YAMLPrinter); ok {
+		printer = &printers.YAMLPrinter{}
+	}
+	var buf bytes.Buffer
+	if err := printer.PrintObj(obj, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// objectFileName returns the name of the file an object is written to, e.g.
+// httproute-default-foo.yaml, or gatewayclass-foo.yaml for cluster-scoped objects.
+func objectFileName(obj client.Object, outputFormat string) string {
+	extension := "yaml"
+	if outputFormat == "json" {
+		extension = "json"
+	}
+	parts := []string{strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)}
+	if obj.GetNamespace() != "" {
+		parts = append(parts, obj.GetNamespace())
+	}
+	parts = append(parts, obj.GetName())
+	return fmt.Sprintf("%s.%s", strings.Join(parts, "-"), extension)
+}
+
+// writeKustomization writes a kustomization.yaml listing the files as
+// resources, sorted for stable diffs.
+func writeKustomization(dir string, files []string) error {
+	resources := append([]string{}, files...)
+	sort.Strings(resources)
+	data, err := yaml.Marshal(kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, kustomizationFile), data, 0o644)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_writeObjectsToDir(t *testing.T) {
+	objects := []client.Object{
+		&gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		},
+		&gatewayv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		},
+		&gatewayv1.GatewayClass{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "GatewayClass"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	pr := &PrintRunner{
+		outputFormat:      "yaml",
+		outputDir:         dir,
+		emitKustomization: true,
+		resourcePrinter:   &printers.YAMLPrinter{},
+	}
+	if err := pr.writeObjectsToDir(objects); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read output directory: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	wantFiles := []string{"gateway-default-nginx.yaml", "gatewayclass-nginx.yaml", "httproute-default-foo.yaml", "kustomization.yaml"}
+	if diff := cmp.Diff(wantFiles, files); diff != "" {
+		t.Errorf("Unexpected files (-want +got):\n%s", diff)
+	}
+
+	gateway, err := os.ReadFile(filepath.Join(dir, "gateway-default-nginx.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read Gateway file: %v", err)
+	}
+	wantGateway := `apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  creationTimestamp: null
+  name: nginx
+  namespace: default
+spec:
+  gatewayClassName: ""
+  listeners: null
+status: {}
+`
+	if diff := cmp.Diff(wantGateway, string(gateway)); diff != "" {
+		t.Errorf("Unexpected Gateway file (-want +got):\n%s", diff)
+	}
+
+	kustomization, err := os.ReadFile(filepath.Join(dir, kustomizationFile))
+	if err != nil {
+		t.Fatalf("Failed to read kustomization: %v", err)
+	}
+	wantKustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gateway-default-nginx.yaml
+- gatewayclass-nginx.yaml
+- httproute-default-foo.yaml
+`
+	if diff := cmp.Diff(wantKustomization, string(kustomization)); diff != "" {
+		t.Errorf("Unexpected kustomization (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// classOutputLayout groups the files written to --output-dir by the
+	// GatewayClass of the generated resources.
+	classOutputLayout = "class"
+	// namespaceOutputLayout groups the files written to --output-dir by the
+	// namespace of the generated resources.
+	namespaceOutputLayout = "namespace"
+)
+
+var outputLayouts = []string{classOutputLayout, namespaceOutputLayout}
+
+// validateOutputLayout checks that the --output-layout groupings are known and
+// not repeated.
+func validateOutputLayout(layout []string) error {
+	for i, grouping := range layout {
+		if !slices.Contains(outputLayouts, grouping) {
+			return fmt.Errorf("%q is not a supported output layout, supported values are %v", grouping, outputLayouts)
+		}
+		if slices.Contains(layout[:i], grouping) {
+			return fmt.Errorf("the output layout %q is repeated", grouping)
+		}
+	}
+	return nil
+}
+
+// objectDirs returns the directory, relative to the output directory, every
+// object is written to with the layout, e.g. nginx/default with the class then
+// namespace groupings. A grouping the object has no value for, like the
+// namespace of a GatewayClass or the class of a ReferenceGrant shared by the
+// Gateways of several classes, adds no directory, so the object is written to
+// the directory of the groupings before it.
+func objectDirs(objects []client.Object, layout []string) ([]string, error) {
+	var classes []string
+	if slices.Contains(layout, classOutputLayout) {
+		var err error
+		if classes, err = objectGatewayClasses(objects); err != nil {
+			return nil, err
+		}
+	}
+
+	dirs := make([]string, len(objects))
+	for i, obj := range objects {
+		var parts []string
+		for _, grouping := range layout {
+			var part string
+			switch grouping {
+			case classOutputLayout:
+				part = classes[i]
+			case namespaceOutputLayout:
+				part = obj.GetNamespace()
+			}
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		dirs[i] = filepath.Join(parts...)
+	}
+	return dirs, nil
+}
+
+// objectRef identifies a generated object by its kind, namespace and name.
+type objectRef struct {
+	kind, namespace, name string
+}
+
+// objectGatewayClasses returns the GatewayClass resolved for every object: the
+// GatewayClass itself, the class of a Gateway, the class of the Gateways of the
+// parentRefs of a route, the class of the route or Gateway targeted by a
+// policy, and the class of the objects a ReferenceGrant is from. It is empty
+// when no class or several are resolved.
+func objectGatewayClasses(objects []client.Object) ([]string, error) {
+	contents := make([]map[string]any, len(objects))
+	gatewayClasses := map[objectRef]string{}
+	for i, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		contents[i] = content
+		switch kind := obj.GetObjectKind().GroupVersionKind().Kind; kind {
+		case "GatewayClass":
+			gatewayClasses[objectRef{kind: kind, name: obj.GetName()}] = obj.GetName()
+		case "Gateway":
+			className, _, _ := unstructured.NestedString(content, "spec", "gatewayClassName")
+			gatewayClasses[objectRef{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}] = className
+		}
+	}
+
+	// The routes are resolved from their Gateways, then the policies and
+	// grants from the routes and Gateways.
+	resolve := func(obj client.Object, content map[string]any) []string {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		if className, ok := gatewayClasses[objectRef{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}]; ok {
+			return []string{className}
+		}
+		var refs []objectRef
+		if kind == "ReferenceGrant" {
+			from, _, _ := unstructured.NestedSlice(content, "spec", "from")
+			for _, f := range from {
+				f, _ := f.(map[string]any)
+				fromKind, _ := f["kind"].(string)
+				fromNamespace, _ := f["namespace"].(string)
+				for ref := range gatewayClasses {
+					if ref.kind == fromKind && ref.namespace == fromNamespace {
+						refs = append(refs, ref)
+					}
+				}
+			}
+		}
+		for _, field := range []string{"parentRefs", "targetRefs"} {
+			targets, _, _ := unstructured.NestedSlice(content, "spec", field)
+			for _, target := range targets {
+				refs = append(refs, localObjectRef(target, obj.GetNamespace()))
+			}
+		}
+		if target, ok, _ := unstructured.NestedMap(content, "spec", "targetRef"); ok {
+			refs = append(refs, localObjectRef(target, obj.GetNamespace()))
+		}
+
+		var classNames []string
+		for _, ref := range refs {
+			if className, ok := gatewayClasses[ref]; ok && className != "" && !slices.Contains(classNames, className) {
+				classNames = append(classNames, className)
+			}
+		}
+		return classNames
+	}
+
+	classes := make([]string, len(objects))
+	for pass := 0; pass < 2; pass++ {
+		for i, obj := range objects {
+			if classNames := resolve(obj, contents[i]); len(classNames) == 1 {
+				classes[i] = classNames[0]
+				kind := obj.GetObjectKind().GroupVersionKind().Kind
+				gatewayClasses[objectRef{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}] = classNames[0]
+			}
+		}
+	}
+	return classes, nil
+}
+
+// localObjectRef returns the object referenced by a parentRef or targetRef of
+// an object of the namespace. The kind defaults to the Gateway of parentRefs.
+func localObjectRef(ref any, namespace string) objectRef {
+	fields, _ := ref.(map[string]any)
+	kind, _ := fields["kind"].(string)
+	if kind == "" {
+		kind = "Gateway"
+	}
+	if group, ok := fields["group"].(string); ok && group != gatewayv1.GroupName {
+		// The objects of the other groups, like the Services of a
+		// BackendTLSPolicy, have no class.
+		return objectRef{}
+	}
+	if refNamespace, ok := fields["namespace"].(string); ok && refNamespace != "" {
+		namespace = refNamespace
+	}
+	name, _ := fields["name"].(string)
+	return objectRef{kind: kind, namespace: namespace, name: name}
+}
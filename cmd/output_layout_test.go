@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_writeObjectsToDirLayout(t *testing.T) {
+	gateway := func(namespace, name, className string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(className)},
+		}
+	}
+	route := func(namespace, name string, parentRefs ...gatewayv1.ParentReference) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs}},
+		}
+	}
+	grant := func(namespace, name, fromKind, fromNamespace string) *gatewayv1beta1.ReferenceGrant {
+		return &gatewayv1beta1.ReferenceGrant{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1beta1", Kind: "ReferenceGrant"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: gatewayv1.Kind(fromKind), Namespace: gatewayv1.Namespace(fromNamespace)}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+			},
+		}
+	}
+	nginxNamespace := gatewayv1.Namespace("default")
+	objects := []client.Object{
+		&gatewayv1.GatewayClass{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "GatewayClass"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		},
+		gateway("default", "nginx", "nginx"),
+		gateway("default", "gce", "gce"),
+		route("default", "foo", gatewayv1.ParentReference{Name: "nginx"}),
+		route("apps", "bar", gatewayv1.ParentReference{Name: "nginx", Namespace: &nginxNamespace}),
+		route("default", "baz", gatewayv1.ParentReference{Name: "gce"}),
+		route("default", "both", gatewayv1.ParentReference{Name: "nginx"}, gatewayv1.ParentReference{Name: "gce"}),
+		grant("backends", "from-apps", "HTTPRoute", "apps"),
+		grant("backends", "from-default", "HTTPRoute", "default"),
+	}
+
+	testCases := []struct {
+		name          string
+		layout        []string
+		expectedFiles []string
+	}{
+		{
+			name:   "class",
+			layout: []string{"class"},
+			expectedFiles: []string{
+				"gce/gateway-default-gce.yaml",
+				"gce/httproute-default-baz.yaml",
+				"httproute-default-both.yaml",
+				"kustomization.yaml",
+				"nginx/gateway-default-nginx.yaml",
+				"nginx/gatewayclass-nginx.yaml",
+				"nginx/httproute-apps-bar.yaml",
+				"nginx/httproute-default-foo.yaml",
+				"nginx/referencegrant-backends-from-apps.yaml",
+				"referencegrant-backends-from-default.yaml",
+			},
+		},
+		{
+			name:   "class then namespace",
+			layout: []string{"class", "namespace"},
+			expectedFiles: []string{
+				"backends/referencegrant-backends-from-default.yaml",
+				"default/httproute-default-both.yaml",
+				"gce/default/gateway-default-gce.yaml",
+				"gce/default/httproute-default-baz.yaml",
+				"kustomization.yaml",
+				"nginx/apps/httproute-apps-bar.yaml",
+				"nginx/backends/referencegrant-backends-from-apps.yaml",
+				"nginx/default/gateway-default-nginx.yaml",
+				"nginx/default/httproute-default-foo.yaml",
+				"nginx/gatewayclass-nginx.yaml",
+			},
+		},
+		{
+			name:   "namespace then class",
+			layout: []string{"namespace", "class"},
+			expectedFiles: []string{
+				"apps/nginx/httproute-apps-bar.yaml",
+				"backends/nginx/referencegrant-backends-from-apps.yaml",
+				"backends/referencegrant-backends-from-default.yaml",
+				"default/gce/gateway-default-gce.yaml",
+				"default/gce/httproute-default-baz.yaml",
+				"default/httproute-default-both.yaml",
+				"default/nginx/gateway-default-nginx.yaml",
+				"default/nginx/httproute-default-foo.yaml",
+				"kustomization.yaml",
+				"nginx/gatewayclass-nginx.yaml",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "out")
+			pr := &PrintRunner{
+				outputFormat:      "yaml",
+				outputDir:         dir,
+				outputLayout:      tc.layout,
+				emitKustomization: true,
+				resourcePrinter:   &printers.YAMLPrinter{},
+			}
+			if err := pr.writeObjectsToDir(objects); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var files []string
+			err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil || entry.IsDir() {
+					return err
+				}
+				file, err := filepath.Rel(dir, path)
+				files = append(files, filepath.ToSlash(file))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to read output directory: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Errorf("Unexpected files (-want +got):\n%s", diff)
+			}
+
+			kustomization, err := os.ReadFile(filepath.Join(dir, kustomizationFile))
+			if err != nil {
+				t.Fatalf("Failed to read kustomization: %v", err)
+			}
+			for _, file := range tc.expectedFiles {
+				if file != kustomizationFile && !slices.Contains(strings.Split(string(kustomization), "\n"), "- "+file) {
+					t.Errorf("Expected kustomization to list %s, got:\n%s", file, kustomization)
+				}
+			}
+		})
+	}
+}
+
+func Test_validateOutputLayout(t *testing.T) {
+	for _, layout := range [][]string{nil, {"class"}, {"namespace", "class"}} {
+		if err := validateOutputLayout(layout); err != nil {
+			t.Errorf("Expected layout %v to be valid, got %v", layout, err)
+		}
+	}
+	for _, layout := range [][]string{{"kind"}, {"class", "class"}} {
+		if err := validateOutputLayout(layout); err == nil {
+			t.Errorf("Expected layout %v to be rejected", layout)
+		}
+	}
+}
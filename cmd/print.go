@@ -18,19 +18,30 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
-	"github.com/samber/lo"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	// Call init function for the providers
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/apisix"
+	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/azureappgw"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/gce"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/ingressnginx"
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/istio"
@@ -38,9 +49,19 @@ import (
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/openapi3"
 
 	// Call init for notifications
-	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 )
 
+const (
+	// streamOutputStyle prints every generated resource as a separate document.
+	streamOutputStyle = "stream"
+	// listOutputStyle prints all the generated resources wrapped in a single v1/List.
+	listOutputStyle = "list"
+)
+
+// stdinInputFile is the --input-file value used to read the manifests from stdin.
+const stdinInputFile = "-"
+
 type PrintRunner struct {
 	// outputFormat contains currently set output format. Value assigned via --output/-o flag.
 	// Defaults to YAML.
@@ -49,15 +70,71 @@ type PrintRunner struct {
 	// The path to the input yaml config file. Value assigned via --input-file flag
 	inputFile string
 
+	// kustomize is the directory of a kustomization built to read the
+	// Ingresses from. Value assigned via --kustomize flag.
+	kustomize string
+
+	// since restricts the conversion of the cluster Ingresses to the ones modified
+	// within this duration. Value assigned via --since flag.
+	since time.Duration
+
+	// strict indicates whether the input file must only contain resources read by
+	// the selected providers. Value assigned via --strict flag.
+	strict bool
+
 	// The namespace used to query Gateway API objects. Value assigned via
 	// --namespace/-n flag.
 	// On absence, the current user active namespace is used.
 	namespace string
 
+	// defaultNamespace is the namespace assigned to the namespaced objects of the
+	// input file without namespace. Value assigned via --default-namespace flag.
+	defaultNamespace string
+
 	// allNamespaces indicates whether all namespaces should be used. Value assigned via
 	// --all-namespaces/-A flag.
 	allNamespaces bool
 
+	// outputStyle determines how the generated resources are aggregated when printed.
+	// Value assigned via --output-style flag. Defaults to a stream of documents.
+	outputStyle string
+
+	// explain indicates whether the printed YAML is annotated with comments
+	// describing the source of the generated fields. Value assigned via --explain flag.
+	explain bool
+
+	// annotateUnconverted indicates whether the generated resources are annotated
+	// with the source annotations that could not be converted. Value assigned via
+	// --annotate-unconverted flag.
+	annotateUnconverted bool
+
+	// prunePlan indicates whether a table telling which Ingresses can be deleted
+	// once the generated resources are applied is printed. Value assigned via
+	// --prune-plan flag.
+	prunePlan bool
+
+	// onlyKinds restricts the printed resources to these kinds. Value assigned
+	// via --only-kind flag.
+	onlyKinds []string
+
+	// outputDir is the directory every generated resource is written to, in its
+	// own file, instead of stdout. Value assigned via --output-dir flag.
+	outputDir string
+
+	// outputLayout is the groupings of the files written to outputDir into
+	// subdirectories, like the class then namespace of the resources. Value
+	// assigned via --output-layout flag.
+	outputLayout []string
+
+	// diffFriendly indicates whether the status and the empty optional fields
+	// are omitted from the printed resources, for `kubectl diff`. Value assigned
+	// via --diff-friendly flag.
+	diffFriendly bool
+
+	// emitKustomization indicates whether a kustomization.yaml listing the files
+	// written to outputDir is generated. Value assigned via --emit-kustomization flag.
+	emitKustomization bool
+
 	// resourcePrinter determines how resource objects are printed out
 	resourcePrinter printers.ResourcePrinter
 
@@ -67,8 +144,28 @@ type PrintRunner struct {
 	// providers indicates which providers are used to execute convert action.
 	providers []string
 
-	// Provider specific flags --<provider>-<flag>.
-	providerSpecificFlags map[string]*string
+	// orderLike is the path to a previous output the printed resources are
+	// ordered like. Value assigned via --order-like flag.
+	orderLike string
+
+	// verifySecrets indicates whether the certificate Secrets of the generated
+	// Gateways are checked against the cluster. Value assigned via
+	// --verify-secrets flag.
+	verifySecrets bool
+
+	// readConcurrency is the maximum number of providers reading their
+	// resources, and of Secrets read from the cluster, at the same time. The
+	// conversion itself is not parallelized.
+	// Value assigned via --read-concurrency flag.
+	readConcurrency int
+
+	// progress indicates whether the progress of the conversion is printed on
+	// stderr. Value assigned via --progress flag.
+	progress bool
+
+	// conversionFlags are the flags shaping the generated resources, shared with
+	// the apply and verify commands.
+	conversionFlags
 }
 
 // PrintGatewayAPIObjects performs necessary steps to digest and print
@@ -84,108 +181,279 @@ func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) er
 	if err != nil {
 		return fmt.Errorf("failed to initialize namespace filter: %w", err)
 	}
+	if pr.outputStyle != streamOutputStyle && pr.outputStyle != listOutputStyle {
+		return fmt.Errorf("%s is not a supported output style", pr.outputStyle)
+	}
 
-	gatewayResources, notificationTablesMap, err := i2gw.ToGatewayAPIResources(cmd.Context(), pr.namespaceFilter, pr.inputFile, pr.providers, pr.getProviderSpecificFlags())
+	if pr.kustomize != "" {
+		inputFile, err := buildKustomization(pr.kustomize)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(inputFile)
+		pr.inputFile = inputFile
+	}
+	if pr.inputFile == stdinInputFile {
+		inputFile, err := bufferStdin()
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		defer os.Remove(inputFile)
+		pr.inputFile = inputFile
+	}
+	if pr.inputFile != "" {
+		inputFile, defaulted, err := defaultInputNamespace(pr.inputFile, pr.defaultNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to assign the default namespace: %w", err)
+		}
+		if inputFile != pr.inputFile {
+			defer os.Remove(inputFile)
+			pr.inputFile = inputFile
+		}
+		notifyDefaultedNamespaces(pr.providers, pr.defaultNamespace, defaulted)
+	}
+	if pr.strict {
+		if err = pr.validateInputFile(); err != nil {
+			return fmt.Errorf("failed to validate input file: %w", err)
+		}
+	}
+
+	gatewayOptions, err := pr.gatewayOptions()
 	if err != nil {
 		return err
 	}
 
+	var resourceOrder map[resourceKey]int
+	if pr.orderLike != "" {
+		resourceOrder, err = readResourceOrder(pr.orderLike)
+		if err != nil {
+			return fmt.Errorf("failed to read the resources to order like: %w", err)
+		}
+	}
+
+	var modifiedSince time.Time
+	if pr.since > 0 {
+		if pr.inputFile != "" {
+			fmt.Fprintln(os.Stderr, "Warning: --since is ignored when reading from an input file, as its modification timestamps are not reliable")
+		} else {
+			modifiedSince = time.Now().Add(-pr.since)
+		}
+	}
+
+	if pr.verifySecrets && pr.inputFile != "" {
+		fmt.Fprintln(os.Stderr, "Warning: --verify-secrets is ignored when reading from an input file, as there is no cluster to verify the Secrets against")
+	}
+
+	// The progress is printed by default for the conversions of a cluster, which
+	// may take long, when stderr is a terminal, and only for the steps of many
+	// items, like the conversion of many Ingresses.
+	printer := progressPrinter{out: os.Stderr, terminal: term.IsTerminal(int(os.Stderr.Fd()))}
+	var progress i2gw.ProgressFunc
+	if pr.progress {
+		progress = printer.print
+	} else if !cmd.Flags().Changed("progress") && pr.inputFile == "" && printer.terminal {
+		printer.minTotal = autoProgressMinTotal
+		progress = printer.print
+	}
+
+	gatewayOptions.KubeContext = kubeContext
+	gatewayOptions.ModifiedSince = modifiedSince
+	gatewayOptions.VerifySecrets = pr.verifySecrets
+	gatewayOptions.ReadConcurrency = pr.readConcurrency
+	gatewayOptions.Progress = progress
+	gatewayResources, notificationTablesMap, err := i2gw.ToGatewayAPIResources(cmd.Context(), pr.namespaceFilter, pr.inputFile, pr.providers, pr.getProviderSpecificFlags(), gatewayOptions)
+	if progress != nil {
+		printer.done()
+	}
+	// The notifications are printed even if the conversion failed, as they
+	// often explain the errors.
 	for _, table := range notificationTablesMap {
 		fmt.Println(table)
 	}
+	if err != nil {
+		return err
+	}
 
-	pr.outputResult(gatewayResources)
+	if pr.prunePlan {
+		printPrunePlan(buildPrunePlan(gatewayResourcesToObjects(gatewayResources), notifications.NotificationAggr.Notifications), os.Stdout)
+	}
+	if len(pr.onlyKinds) > 0 {
+		gatewayResources = filterGatewayResourcesByKind(gatewayResources, pr.onlyKinds)
+	}
+	objects := gatewayResourcesToObjects(gatewayResources)
+	if pr.annotateUnconverted {
+		annotateUnconverted(objects)
+	}
+	if resourceOrder != nil {
+		objects = orderLike(objects, resourceOrder)
+	}
+	if pr.diffFriendly {
+		if objects, err = diffFriendlyObjects(objects); err != nil {
+			return err
+		}
+	}
+	if pr.outputDir != "" {
+		return pr.writeObjectsToDir(objects)
+	}
+	pr.outputResult(objects)
 
 	return nil
 }
 
-func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
-	resourceCount := 0
+func (pr *PrintRunner) outputResult(objects []client.Object) {
+	if len(objects) == 0 {
+		msg := "No resources found"
+		if pr.namespaceFilter != "" {
+			msg = fmt.Sprintf("%s in %s namespace", msg, pr.namespaceFilter)
+		}
+		fmt.Println(msg)
+		return
+	}
+
+	if pr.explain {
+		if err := printObjectsExplained(objects, os.Stdout); err != nil {
+			fmt.Printf("# Error printing explained resources: %v\n", err)
+		}
+		return
+	}
+
+	if pr.outputFormat == wideOutputFormat {
+		if err := pr.resourcePrinter.PrintObj(toWideTable(objects), os.Stdout); err != nil {
+			fmt.Printf("# Error printing table: %v\n", err)
+		}
+		return
+	}
+
+	if pr.outputStyle == listOutputStyle {
+		if err := pr.printObjectsAsList(objects, os.Stdout); err != nil {
+			fmt.Printf("# Error printing List: %v\n", err)
+		}
+		return
+	}
+
+	for _, obj := range objects {
+		err := pr.resourcePrinter.PrintObj(obj, os.Stdout)
+		if err != nil {
+			fmt.Printf("# Error printing %s %s: %v\n", obj.GetName(), obj.GetObjectKind().GroupVersionKind().Kind, err)
+		}
+	}
+}
+
+// gatewayResourcesToObjects flattens the given GatewayResources into a single
+// slice of objects, ordered by kind.
+func gatewayResourcesToObjects(gatewayResources []i2gw.GatewayResources) []client.Object {
+	var objects []client.Object
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.GatewayClasses)
 		for _, gatewayClass := range r.GatewayClasses {
 			gatewayClass := gatewayClass
-			err := pr.resourcePrinter.PrintObj(&gatewayClass, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s GatewayClass: %v\n", gatewayClass.Name, err)
-			}
+			objects = append(objects, &gatewayClass)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.Gateways)
 		for _, gateway := range r.Gateways {
 			gateway := gateway
-			err := pr.resourcePrinter.PrintObj(&gateway, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s Gateway: %v\n", gateway.Name, err)
-			}
+			objects = append(objects, &gateway)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.HTTPRoutes)
 		for _, httpRoute := range r.HTTPRoutes {
 			httpRoute := httpRoute
-			err := pr.resourcePrinter.PrintObj(&httpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s HTTPRoute: %v\n", httpRoute.Name, err)
-			}
+			objects = append(objects, &httpRoute)
+		}
+	}
+
+	for _, r := range gatewayResources {
+		for _, grpcRoute := range r.GRPCRoutes {
+			grpcRoute := grpcRoute
+			objects = append(objects, &grpcRoute)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.TLSRoutes)
 		for _, tlsRoute := range r.TLSRoutes {
 			tlsRoute := tlsRoute
-			err := pr.resourcePrinter.PrintObj(&tlsRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s TLSRoute: %v\n", tlsRoute.Name, err)
-			}
+			objects = append(objects, &tlsRoute)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.TCPRoutes)
 		for _, tcpRoute := range r.TCPRoutes {
 			tcpRoute := tcpRoute
-			err := pr.resourcePrinter.PrintObj(&tcpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s TCPRoute: %v\n", tcpRoute.Name, err)
-			}
+			objects = append(objects, &tcpRoute)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.UDPRoutes)
 		for _, udpRoute := range r.UDPRoutes {
 			udpRoute := udpRoute
-			err := pr.resourcePrinter.PrintObj(&udpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s UDPRoute: %v\n", udpRoute.Name, err)
-			}
+			objects = append(objects, &udpRoute)
 		}
 	}
 
 	for _, r := range gatewayResources {
-		resourceCount += len(r.ReferenceGrants)
 		for _, referenceGrant := range r.ReferenceGrants {
 			referenceGrant := referenceGrant
-			err := pr.resourcePrinter.PrintObj(&referenceGrant, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s ReferenceGrant: %v\n", referenceGrant.Name, err)
-			}
+			objects = append(objects, &referenceGrant)
 		}
 	}
 
-	if resourceCount == 0 {
-		msg := "No resources found"
-		if pr.namespaceFilter != "" {
-			msg = fmt.Sprintf("%s in %s namespace", msg, pr.namespaceFilter)
+	for _, r := range gatewayResources {
+		for _, backendTLSPolicy := range r.BackendTLSPolicies {
+			backendTLSPolicy := backendTLSPolicy
+			objects = append(objects, &backendTLSPolicy)
+		}
+	}
+
+	for _, r := range gatewayResources {
+		for _, policy := range r.ImplementationPolicies {
+			policy := policy
+			objects = append(objects, &policy)
 		}
-		fmt.Println(msg)
 	}
+
+	return objects
+}
+
+// sortObjects returns the objects in the order of their kinds, then sorted by
+// namespace and name.
+func sortObjects(objects []client.Object) []client.Object {
+	kindOrder := map[string]int{}
+	for _, obj := range objects {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		if _, ok := kindOrder[kind]; !ok {
+			kindOrder[kind] = len(kindOrder)
+		}
+	}
+	sorted := append([]client.Object{}, objects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iKind, jKind := kindOrder[sorted[i].GetObjectKind().GroupVersionKind().Kind], kindOrder[sorted[j].GetObjectKind().GroupVersionKind().Kind]
+		if iKind != jKind {
+			return iKind < jKind
+		}
+		if sorted[i].GetNamespace() != sorted[j].GetNamespace() {
+			return sorted[i].GetNamespace() < sorted[j].GetNamespace()
+		}
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+	return sorted
+}
+
+// printObjectsAsList wraps all the given objects in a single v1/List and prints
+// it once, instead of printing every object as a separate document.
+func (pr *PrintRunner) printObjectsAsList(objects []client.Object, w io.Writer) error {
+	list := &corev1.List{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "List",
+		},
+	}
+	for _, obj := range objects {
+		list.Items = append(list.Items, runtime.RawExtension{Object: obj})
+	}
+	return pr.resourcePrinter.PrintObj(list, w)
 }
 
 // initializeResourcePrinter assign a specific type of printers.ResourcePrinter
@@ -198,12 +466,41 @@ func (pr *PrintRunner) initializeResourcePrinter() error {
 	case "json":
 		pr.resourcePrinter = &printers.JSONPrinter{}
 		return nil
+	case wideOutputFormat:
+		pr.resourcePrinter = printers.NewTablePrinter(printers.PrintOptions{})
+		return nil
 	default:
 		return fmt.Errorf("%s is not a supported output format", pr.outputFormat)
 	}
 
 }
 
+// bufferStdin copies stdin to a temporary file, as the input file is read
+// separately by every provider. The caller is responsible for removing the file.
+func bufferStdin() (string, error) {
+	f, err := os.CreateTemp("", "ingress2gateway-stdin-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, os.Stdin); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// validateInputFile returns an error if the input file contains resources
+// that are not read by any of the selected providers.
+func (pr *PrintRunner) validateInputFile() error {
+	expectedKinds := sets.New[schema.GroupKind]()
+	for _, provider := range pr.providers {
+		expectedKinds.Insert(i2gw.ProviderResourceKindsByName[i2gw.ProviderName(provider)]...)
+	}
+	return common.ValidateObjectKindsFromFile(pr.inputFile, expectedKinds)
+}
+
 // initializeNamespaceFilter initializes the correct namespace filter for resource processing with these scenarios:
 // 1. If the --all-namespaces flag is used, it processes all resources, regardless of whether they are from the cluster or file.
 // 2. If namespace is specified, it filters resources based on that namespace.
@@ -237,7 +534,7 @@ func (pr *PrintRunner) initializeNamespaceFilter() error {
 func newPrintCommand() *cobra.Command {
 	pr := &PrintRunner{}
 	var printFlags genericclioptions.JSONYamlPrintFlags
-	allowedFormats := printFlags.AllowedFormats()
+	allowedFormats := append(printFlags.AllowedFormats(), wideOutputFormat)
 
 	// printCmd represents the print command. It prints HTTPRoutes and Gateways
 	// generated from Ingress resources.
@@ -250,6 +547,53 @@ func newPrintCommand() *cobra.Command {
 			if openAPIExist && len(pr.providers) != 1 {
 				return fmt.Errorf("openapi3 must be the only provider when specified")
 			}
+			if pr.strict && pr.inputFile == "" && pr.kustomize == "" {
+				return fmt.Errorf("--strict can only be used with --input-file or --kustomize")
+			}
+			if pr.strict && openAPIExist {
+				return fmt.Errorf("--strict is not supported by the openapi3 provider")
+			}
+			if err := validateDefaultNamespace(pr.defaultNamespace); err != nil {
+				return err
+			}
+			if err := pr.conversionFlags.validate(); err != nil {
+				return err
+			}
+			if pr.readConcurrency < 1 {
+				return fmt.Errorf("--read-concurrency must be at least 1")
+			}
+			if pr.since < 0 {
+				return fmt.Errorf("--since must be a positive duration")
+			}
+			if pr.explain && (pr.outputFormat != "yaml" || pr.outputStyle != streamOutputStyle) {
+				return fmt.Errorf("--explain is only supported with the yaml output format and the %s output style", streamOutputStyle)
+			}
+			if pr.outputFormat == wideOutputFormat && (pr.outputStyle != streamOutputStyle || pr.outputDir != "") {
+				return fmt.Errorf("the %s output format is only supported with the %s output style and without --output-dir", wideOutputFormat, streamOutputStyle)
+			}
+			if pr.orderLike != "" && pr.outputFormat == wideOutputFormat {
+				return fmt.Errorf("--order-like is not supported with the %s output format, whose rows are sorted", wideOutputFormat)
+			}
+			if pr.diffFriendly && (pr.explain || pr.outputFormat == wideOutputFormat) {
+				return fmt.Errorf("--diff-friendly is only supported with the yaml and json output formats, without --explain")
+			}
+			if pr.outputDir != "" && pr.outputStyle != streamOutputStyle {
+				return fmt.Errorf("--output-dir is only supported with the %s output style", streamOutputStyle)
+			}
+			if len(pr.outputLayout) > 0 && pr.outputDir == "" {
+				return fmt.Errorf("--output-layout can only be used with --output-dir")
+			}
+			if err := validateOutputLayout(pr.outputLayout); err != nil {
+				return err
+			}
+			if pr.emitKustomization && pr.outputDir == "" {
+				return fmt.Errorf("--emit-kustomization can only be used with --output-dir")
+			}
+			kinds, err := normalizeOutputKinds(pr.onlyKinds)
+			if err != nil {
+				return err
+			}
+			pr.onlyKinds = kinds
 			return nil
 		},
 	}
@@ -257,12 +601,51 @@ func newPrintCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&pr.outputFormat, "output", "o", "yaml",
 		fmt.Sprintf(`Output format. One of: (%s).`, strings.Join(allowedFormats, ", ")))
 
+	cmd.Flags().StringVar(&pr.outputStyle, "output-style", streamOutputStyle,
+		fmt.Sprintf(`Output style. One of: (%s, %s). When set to %s, all the generated resources are wrapped in a single v1/List.`, streamOutputStyle, listOutputStyle, listOutputStyle))
+
+	cmd.Flags().StringVar(&pr.outputDir, "output-dir", "",
+		`If present, the directory every generated resource is written to, in its own file named after its kind, namespace and name, instead of stdout.`)
+
+	cmd.Flags().StringSliceVar(&pr.outputLayout, "output-layout", []string{},
+		fmt.Sprintf(`If present, the files written to --output-dir are grouped into subdirectories, e.g. --output-layout class,namespace writes them to <output-dir>/<gatewayclass>/<namespace>/. Supported values are %v.`, outputLayouts))
+
+	cmd.Flags().BoolVar(&pr.diffFriendly, "diff-friendly", false,
+		`If present, the status, the empty creationTimestamp and the null or empty optional fields are omitted from the printed resources, so that they can be compared with the cluster with kubectl diff -f -.`)
+
+	cmd.Flags().BoolVar(&pr.emitKustomization, "emit-kustomization", false,
+		`If present, a kustomization.yaml listing all the files written to --output-dir is generated, so that they can be applied with kubectl apply -k.`)
+
+	cmd.Flags().BoolVar(&pr.prunePlan, "prune-plan", false,
+		`If present, a table listing every converted Ingress is printed along with the notifications, telling whether it can be deleted once the generated resources are applied: SAFE TO DELETE if it was converted without warnings or errors, REVIEW FIRST if it was converted with some, and KEEP if no resource was generated from it.`)
+
+	cmd.Flags().StringSliceVar(&pr.onlyKinds, "only-kind", []string{},
+		fmt.Sprintf("If present, only the generated resources of these kinds are printed, after the whole conversion. Can be repeated, supported values are %v.", outputKinds))
+
+	cmd.Flags().BoolVar(&pr.explain, "explain", false,
+		`If present, the generated YAML is annotated with comments above the fields, describing the Ingress fields and annotations that produced them.`)
+
+	cmd.Flags().BoolVar(&pr.annotateUnconverted, "annotate-unconverted", false,
+		fmt.Sprintf(`If present, the generated routes are annotated with %s, listing the annotations of their source Ingresses that could not be converted.`, unconvertedAnnotation))
+
 	cmd.Flags().StringVar(&pr.inputFile, "input-file", "",
-		`Path to the manifest file. When set, the tool will read ingresses from the file instead of reading from the cluster. Supported files are yaml and json.`)
+		`Path to the manifest file. When set, the tool will read ingresses from the file instead of reading from the cluster. Supported files are yaml and json. Use "-" to read from stdin.`)
+
+	cmd.Flags().StringVar(&pr.kustomize, "kustomize", "",
+		`If present, the directory of a kustomization built in-process, like with kustomize build, to read the ingresses from instead of the cluster, e.g. an overlay.`)
+
+	cmd.Flags().BoolVar(&pr.strict, "strict", false,
+		`If present, the tool will fail when the input file, or the output of the --kustomize build, contains resources that are not read by the selected providers, instead of skipping them.`)
+
+	cmd.Flags().DurationVar(&pr.since, "since", 0,
+		`If present, only the cluster Ingresses created or modified within this duration, e.g. 24h, are converted, along with the Ingresses sharing a host with them. Ignored when reading from an input file.`)
 
 	cmd.Flags().StringVarP(&pr.namespace, "namespace", "n", "",
 		`If present, the namespace scope for this CLI request.`)
 
+	cmd.Flags().StringVar(&pr.defaultNamespace, "default-namespace", "default",
+		`The namespace assigned to the namespaced objects of the input file without namespace, before --namespace is applied.`)
+
 	cmd.Flags().BoolVarP(&pr.allNamespaces, "all-namespaces", "A", false,
 		`If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even
 if specified with --namespace.`)
@@ -270,43 +653,39 @@ if specified with --namespace.`)
 	cmd.Flags().StringSliceVar(&pr.providers, "providers", []string{},
 		fmt.Sprintf("If present, the tool will try to convert only resources related to the specified providers, supported values are %v.", i2gw.GetSupportedProviders()))
 
-	pr.providerSpecificFlags = make(map[string]*string)
-	for provider, flags := range i2gw.GetProviderSpecificFlagDefinitions() {
-		for _, flag := range flags {
-			flagName := fmt.Sprintf("%s-%s", provider, flag.Name)
-			pr.providerSpecificFlags[flagName] = cmd.Flags().String(flagName, flag.DefaultValue, fmt.Sprintf("Provider-specific: %s. %s", provider, flag.Description))
-		}
-	}
+	cmd.Flags().StringVar(&pr.orderLike, "order-like", "",
+		`If present, the path to a previous output. The printed resources also found in it, by kind, namespace and name, are printed in its order, followed by the other resources, so that the new output differs as little as possible from the previous one.`)
+
+	cmd.Flags().BoolVar(&pr.verifySecrets, "verify-secrets", false,
+		`If present, a Warning is emitted for every certificate Secret of the generated Gateways missing from the cluster. Has no effect, apart from a warning, with --input-file, where the Secrets cannot be verified.`)
+
+	cmd.Flags().IntVar(&pr.readConcurrency, "read-concurrency", 4,
+		`The maximum number of providers reading their resources, and of certificate Secrets read from the cluster, at the same time. The resources read from the cluster are shared between the providers. It does not parallelize the conversion, which runs one Ingress at a time, so the output does not depend on it. With a single provider, it only applies to the Secrets read with --verify-secrets.`)
+
+	cmd.Flags().BoolVar(&pr.progress, "progress", false,
+		`If present, the progress of the reading and the conversion of the resources is printed on stderr. By default, it is only printed when converting the resources of the cluster and stderr is a terminal, for the steps of at least 100 items, like the conversion of at least 100 Ingresses.`)
+
+	pr.conversionFlags.addFlags(cmd)
 
 	_ = cmd.MarkFlagRequired("providers")
 	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+	cmd.MarkFlagsMutuallyExclusive("input-file", "kustomize")
 	return cmd
 }
 
-// getNamespaceInCurrentContext returns the namespace in the current active context of the user.
+// getNamespaceInCurrentContext returns the namespace in the current active context of the user,
+// or in the context of --context.
 func getNamespaceInCurrentContext() (string, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
 	currentNamespace, _, err := kubeConfig.Namespace()
 
 	return currentNamespace, err
 }
 
-// getProviderSpecificFlags returns the provider specific flags input by the user.
-// The flags are returned in a map where the key is the provider name and the value is a map of flag name to flag value.
+// getProviderSpecificFlags returns the provider specific flags input by the user
+// for the providers of the conversion.
 func (pr *PrintRunner) getProviderSpecificFlags() map[string]map[string]string {
-	providerSpecificFlags := make(map[string]map[string]string)
-	for flagName, value := range pr.providerSpecificFlags {
-		provider, found := lo.Find(pr.providers, func(p string) bool { return strings.HasPrefix(flagName, fmt.Sprintf("%s-", p)) })
-		if !found {
-			continue
-		}
-		flagNameWithoutProvider := strings.TrimPrefix(flagName, fmt.Sprintf("%s-", provider))
-		if providerSpecificFlags[provider] == nil {
-			providerSpecificFlags[provider] = make(map[string]string)
-		}
-		providerSpecificFlags[provider][flagNameWithoutProvider] = *value
-	}
-	return providerSpecificFlags
+	return pr.providerSpecificFlagValues(pr.providers)
 }
@@ -17,15 +17,24 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/printers"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func Test_getResourcePrinter(t *testing.T) {
@@ -284,8 +293,8 @@ func Test_getProviderSpecificFlags(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			pr := PrintRunner{
-				providerSpecificFlags: tc.providerSpecificFlags,
-				providers:             tc.providers,
+				conversionFlags: conversionFlags{providerSpecificFlags: tc.providerSpecificFlags},
+				providers:       tc.providers,
 			}
 			actual := pr.getProviderSpecificFlags()
 			if diff := cmp.Diff(tc.expected, actual); diff != "" {
@@ -294,3 +303,211 @@ func Test_getProviderSpecificFlags(t *testing.T) {
 		})
 	}
 }
+
+func Test_printObjectsAsList(t *testing.T) {
+	gateway := gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx"},
+	}
+	gateway.SetGroupVersionKind(common.GatewayGVK)
+	httpRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-example-com", Namespace: "default"},
+	}
+	httpRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+
+	gatewayResources := []i2gw.GatewayResources{{
+		Gateways:   map[types.NamespacedName]gatewayv1.Gateway{{Namespace: "default", Name: "nginx"}: gateway},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "foo-example-com"}: httpRoute},
+	}}
+
+	testCases := []struct {
+		name    string
+		printer printers.ResourcePrinter
+	}{
+		{
+			name:    "YAML format",
+			printer: &printers.YAMLPrinter{},
+		},
+		{
+			name:    "JSON format",
+			printer: &printers.JSONPrinter{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := PrintRunner{resourcePrinter: tc.printer}
+			var out bytes.Buffer
+			if err := pr.printObjectsAsList(gatewayResourcesToObjects(gatewayResources), &out); err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			if strings.Contains(out.String(), "---") {
+				t.Errorf("Expected a single document but got:\n%s", out.String())
+			}
+
+			objs, err := common.ExtractObjectsFromReader(&out, "")
+			if err != nil {
+				t.Fatalf("Failed to decode the printed List: %v", err)
+			}
+			var kinds []string
+			for _, obj := range objs {
+				kinds = append(kinds, obj.GetKind())
+			}
+			if diff := cmp.Diff([]string{"Gateway", "HTTPRoute"}, kinds); diff != "" {
+				t.Errorf("Unexpected List items (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Test_stripPrefixAnnotations verifies that the strip-prefix annotations of the
+// providers are converted to the same URLRewrite filter.
+func Test_stripPrefixAnnotations(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingress := func(name, class, path string, annotations string) string {
+		return fmt.Sprintf(`apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: %[1]s
+  namespace: default
+  annotations:
+%[4]s
+spec:
+  ingressClassName: %[2]s
+  rules:
+  - host: %[1]s.example.com
+    http:
+      paths:
+      - path: %[3]s
+        pathType: Prefix
+        backend:
+          service:
+            name: api
+            port:
+              number: 80
+`, name, class, path, annotations)
+	}
+	input := strings.Join([]string{
+		ingress("nginx", "nginx", "/api(/|$)(.*)", `    nginx.ingress.kubernetes.io/use-regex: "true"
+    nginx.ingress.kubernetes.io/rewrite-target: /$2`),
+		ingress("kong", "kong", "/api", `    konghq.com/strip-path: "true"`),
+		ingress("appgw", "azure-application-gateway", "/api", `    appgw.ingress.kubernetes.io/backend-path-prefix: /`),
+	}, "---\n")
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	if err := os.WriteFile(inputFile, []byte(input), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gatewayResources, _, err := i2gw.ToGatewayAPIResources(context.Background(), "", inputFile, []string{"ingress-nginx", "kong", "azure-appgw"}, nil, i2gw.GatewayOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedPath := &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/api")}
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{
+			Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+			ReplacePrefixMatch: common.PtrTo("/"),
+		}},
+	}}
+	routes := 0
+	for _, resources := range gatewayResources {
+		for key, route := range resources.HTTPRoutes {
+			routes++
+			if len(route.Spec.Rules) != 1 {
+				t.Fatalf("Expected HTTPRoute %s to have 1 rule, got %d", key, len(route.Spec.Rules))
+			}
+			rule := route.Spec.Rules[0]
+			if diff := cmp.Diff(expectedPath, rule.Matches[0].Path); diff != "" {
+				t.Errorf("Unexpected path match of HTTPRoute %s (-want +got):\n%s", key, diff)
+			}
+			if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters of HTTPRoute %s (-want +got):\n%s", key, diff)
+			}
+		}
+	}
+	if routes != 3 {
+		t.Errorf("Expected an HTTPRoute for each provider, got %d", routes)
+	}
+}
+
+// Test_validateInputFile verifies that the Services read by the providers are
+// accepted in strict mode.
+func Test_validateInputFile(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	input := `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  namespace: default
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  namespace: default
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0o600); err != nil {
+		t.Fatalf("Failed to write the input file: %v", err)
+	}
+
+	for _, provider := range []string{"ingress-nginx", "gce", "apisix", "kong", "azure-appgw"} {
+		t.Run(provider, func(t *testing.T) {
+			pr := PrintRunner{inputFile: inputFile, providers: []string{provider}}
+			if err := pr.validateInputFile(); err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		})
+	}
+
+	pr := PrintRunner{inputFile: inputFile, providers: []string{"istio"}}
+	if err := pr.validateInputFile(); err == nil {
+		t.Errorf("Expected an error for a provider reading no Ingresses, got none")
+	}
+}
+
+// Test_classlessIngressProviders verifies that an Ingress without ingress class
+// is converted by a single provider.
+func Test_classlessIngressProviders(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	input := `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+spec:
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+`
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	if err := os.WriteFile(inputFile, []byte(input), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gatewayResources, _, err := i2gw.ToGatewayAPIResources(context.Background(), "", inputFile, []string{"ingress-nginx", "gce"}, nil, i2gw.GatewayOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var routes []string
+	for _, resources := range gatewayResources {
+		for key, route := range resources.HTTPRoutes {
+			routes = append(routes, fmt.Sprintf("%s %s", key, route.Spec.ParentRefs[0].Name))
+		}
+	}
+	if diff := cmp.Diff([]string{"default/foo-foo-com nginx"}, routes); diff != "" {
+		t.Errorf("Unexpected HTTPRoutes (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+)
+
+// autoProgressMinTotal is the number of items, like Ingresses, from which the
+// progress of a step is printed when the progress is printed by default, so
+// that small conversions, which are quick, print nothing.
+const autoProgressMinTotal = 100
+
+// progressPrinter prints the progress of a conversion on a writer, like stderr.
+// On a terminal, every message replaces the previous one, and the last one is
+// cleared once the conversion is done. Elsewhere, every message is printed on
+// its own line. The progress of the steps of fewer than minTotal items is not
+// printed.
+type progressPrinter struct {
+	out      io.Writer
+	terminal bool
+	minTotal int
+}
+
+func (p progressPrinter) print(message string, total int) {
+	if total < p.minTotal {
+		return
+	}
+	if p.terminal {
+		fmt.Fprintf(p.out, "\r\033[K%s", message)
+		return
+	}
+	fmt.Fprintln(p.out, message)
+}
+
+// done clears the last message on a terminal.
+func (p progressPrinter) done() {
+	if p.terminal {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
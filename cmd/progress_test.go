@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_progressPrinter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		terminal bool
+		minTotal int
+		expected string
+	}{
+		{
+			name:     "lines",
+			expected: "Read the resources of 1/2 providers\nRead the resources of 2/2 providers\n",
+		},
+		{
+			name:     "terminal",
+			terminal: true,
+			expected: "\r\033[KRead the resources of 1/2 providers\r\033[KRead the resources of 2/2 providers\r\033[K",
+		},
+		{
+			name:     "below the minimum total",
+			minTotal: autoProgressMinTotal,
+			expected: "",
+		},
+		{
+			name:     "terminal below the minimum total",
+			terminal: true,
+			minTotal: autoProgressMinTotal,
+			expected: "\r\033[K",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printer := progressPrinter{out: &out, terminal: tc.terminal, minTotal: tc.minTotal}
+			printer.print("Read the resources of 1/2 providers", 2)
+			printer.print("Read the resources of 2/2 providers", 2)
+			printer.done()
+			if out.String() != tc.expected {
+				t.Errorf("Expected progress %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"github.com/olekukonko/tablewriter"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pruneStatus tells whether an Ingress can be deleted once the resources
+// generated from it are applied.
+type pruneStatus string
+
+const (
+	// pruneSafe is the status of the Ingresses converted without warnings or
+	// errors.
+	pruneSafe pruneStatus = "SAFE TO DELETE"
+	// pruneReview is the status of the Ingresses converted with warnings or
+	// errors, some of their configuration being dropped or approximated.
+	pruneReview pruneStatus = "REVIEW FIRST"
+	// pruneKeep is the status of the Ingresses no resource was generated from.
+	pruneKeep pruneStatus = "KEEP"
+)
+
+type prunePlanEntry struct {
+	ingress types.NamespacedName
+	status  pruneStatus
+	reason  string
+}
+
+// buildPrunePlan returns the prune status of every converted Ingress, sorted by
+// namespace and name. An Ingress is converted if it is a source of one of the
+// generated objects, and the severities of its notifications tell whether it was
+// converted losslessly. The notifications of the generated objects, like the
+// HTTPRoutes, count for their source Ingresses.
+func buildPrunePlan(objects []client.Object, notificationsByProvider map[string][]notifications.Notification) []prunePlanEntry {
+	ingresses := provenance.ProvenanceAggr.ListIngresses()
+
+	counts := map[types.NamespacedName]map[notifications.MessageType]int{}
+	for _, notifs := range notificationsByProvider {
+		for _, notification := range notifs {
+			for ingress := range notifiedIngresses(notification, ingresses) {
+				if counts[ingress] == nil {
+					counts[ingress] = map[notifications.MessageType]int{}
+				}
+				counts[ingress][notification.Type]++
+			}
+		}
+	}
+
+	plan := make([]prunePlanEntry, 0, len(ingresses))
+	for _, ingress := range ingresses {
+		entry := prunePlanEntry{ingress: ingress}
+		errs, warnings := counts[ingress][notifications.ErrorNotification], counts[ingress][notifications.WarningNotification]
+		switch {
+		case !hasGeneratedObject(objects, ingress):
+			entry.status, entry.reason = pruneKeep, "no resource was generated from it"
+		case errs > 0 || warnings > 0:
+			entry.status, entry.reason = pruneReview, fmt.Sprintf("converted with %s and %s, check the notifications", pluralize(errs, "error"), pluralize(warnings, "warning"))
+		default:
+			entry.status, entry.reason = pruneSafe, "converted without warnings or errors"
+		}
+		plan = append(plan, entry)
+	}
+	return plan
+}
+
+// notifiedIngresses returns the Ingresses among the calling objects of the
+// notification, along with the source Ingresses of the other calling objects.
+func notifiedIngresses(notification notifications.Notification, ingresses []types.NamespacedName) map[types.NamespacedName]bool {
+	notified := map[types.NamespacedName]bool{}
+	for _, obj := range notification.CallingObjects {
+		if _, ok := obj.(*networkingv1.Ingress); ok {
+			notified[types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}] = true
+			continue
+		}
+		ref := provenance.ObjectRef{
+			Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		for _, ingress := range ingresses {
+			if provenance.ProvenanceAggr.HasIngressSource(ref, ingress.Namespace, ingress.Name) {
+				notified[ingress] = true
+			}
+		}
+	}
+	return notified
+}
+
+func hasGeneratedObject(objects []client.Object, ingress types.NamespacedName) bool {
+	for _, obj := range objects {
+		ref := provenance.ObjectRef{
+			Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		if provenance.ProvenanceAggr.HasIngressSource(ref, ingress.Namespace, ingress.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// printPrunePlan prints the prune plan as a table, in the format of the
+// notification tables.
+func printPrunePlan(plan []prunePlanEntry, w io.Writer) {
+	table := strings.Builder{}
+	t := tablewriter.NewWriter(&table)
+	t.SetHeader([]string{"Ingress", "Status", "Reason"})
+	t.SetColWidth(200)
+	t.SetRowLine(true)
+	for _, entry := range plan {
+		t.Append([]string{entry.ingress.String(), string(entry.status), entry.reason})
+	}
+	t.Render()
+	fmt.Fprintf(w, "Prune plan:\n%s\n", table.String())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_buildPrunePlan(t *testing.T) {
+	provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+	provenance.ProvenanceAggr.Ingresses = map[types.NamespacedName]bool{}
+
+	route := func(name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	lossless, lossy, notified := route("lossless"), route("lossy"), route("notified")
+	for _, name := range []string{"lossless", "lossy", "notified", "dropped"} {
+		provenance.ProvenanceAggr.RecordIngress("default", name)
+	}
+	for _, r := range []*gatewayv1.HTTPRoute{lossless, lossy, notified} {
+		ref := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: r.Name}}
+		provenance.ProvenanceAggr.Record(ref, "spec.rules[0]", provenance.IngressSource("default", r.Name, "spec.rules[0]"))
+	}
+
+	lossyIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "lossy", Namespace: "default"}}
+	losslessIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "lossless", Namespace: "default"}}
+	notificationsByProvider := map[string][]notifications.Notification{
+		"ingress-nginx": {
+			{Type: notifications.ErrorNotification, Message: "error", CallingObjects: []client.Object{lossyIngress}},
+			{Type: notifications.WarningNotification, Message: "warning", CallingObjects: []client.Object{lossyIngress, lossyIngress}},
+			{Type: notifications.WarningNotification, Message: "warning", CallingObjects: []client.Object{lossyIngress}},
+			{Type: notifications.InfoNotification, Message: "info", CallingObjects: []client.Object{losslessIngress}},
+			{Type: notifications.WarningNotification, Message: "route warning", CallingObjects: []client.Object{notified}},
+		},
+	}
+
+	expected := []prunePlanEntry{
+		{ingress: types.NamespacedName{Namespace: "default", Name: "dropped"}, status: pruneKeep, reason: "no resource was generated from it"},
+		{ingress: types.NamespacedName{Namespace: "default", Name: "lossless"}, status: pruneSafe, reason: "converted without warnings or errors"},
+		{ingress: types.NamespacedName{Namespace: "default", Name: "lossy"}, status: pruneReview, reason: "converted with 1 error and 2 warnings, check the notifications"},
+		{ingress: types.NamespacedName{Namespace: "default", Name: "notified"}, status: pruneReview, reason: "converted with 0 errors and 1 warning, check the notifications"},
+	}
+	got := buildPrunePlan([]client.Object{lossless, lossy, notified}, notificationsByProvider)
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(prunePlanEntry{})); diff != "" {
+		t.Errorf("Unexpected prune plan (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// readRenameMap reads a rename map file, mapping the namespace/name of the
+// Ingresses to the names of the resources generated from them:
+//
+//	prod/shop:
+//	  gateway: shop
+//	  httpRoute: shop-routes
+func readRenameMap(path string) (map[types.NamespacedName]i2gw.ResourceNames, error) {
+	stream, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", path, err)
+	}
+	var entries map[string]i2gw.ResourceNames
+	if err = yaml.UnmarshalStrict(stream, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse file %v: %w", path, err)
+	}
+	renameMap := make(map[types.NamespacedName]i2gw.ResourceNames, len(entries))
+	for key, names := range entries {
+		namespace, name, ok := strings.Cut(key, "/")
+		if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid Ingress %s in file %v, it must be namespace/name", key, path)
+		}
+		renameMap[types.NamespacedName{Namespace: namespace, Name: name}] = names
+	}
+	return renameMap, nil
+}
@@ -17,25 +17,40 @@ limitations under the License.
 package cmd
 
 import (
+	"flag"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
 )
 
 // kubeconfig indicates kubeconfig file location.
 var kubeconfig string
 
+// kubeContext is the kubeconfig context of the cluster, the current context if
+// empty.
+var kubeContext string
+
+// logLevel is the verbosity of the logs written to stderr.
+var logLevel int
+
 func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "ingress2gateway",
 		Short: "Convert Ingress manifests to Gateway API manifests",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			getKubeconfig()
+			return setLogLevel(logLevel)
 		},
 	}
 
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
 		`The kubeconfig file to use when talking to the cluster. If the flag is not set, a set of standard locations can be searched for an existing kubeconfig file.`)
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "",
+		`The kubeconfig context of the cluster to use. If the flag is not set, the current context of the kubeconfig is used.`)
+	rootCmd.PersistentFlags().IntVarP(&logLevel, "log-level", "v", 0,
+		`The verbosity of the logs written to stderr, to diagnose the conversion: 1 logs the conversion steps, 3 the converted Ingresses and 4 every provider annotation and whether it is converted. The logs are distinct from the notifications, and never written to stdout.`)
 	return rootCmd
 }
 
@@ -45,9 +60,22 @@ func getKubeconfig() {
 	}
 }
 
+// setLogLevel sets the verbosity of klog, which writes to stderr.
+func setLogLevel(level int) error {
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+	if err := klogFlags.Set("logtostderr", "true"); err != nil {
+		return err
+	}
+	return klogFlags.Set("v", strconv.Itoa(level))
+}
+
 func Execute() {
 	rootCmd := newRootCmd()
 	rootCmd.AddCommand(newPrintCommand())
+	rootCmd.AddCommand(newVerifyCommand())
+	rootCmd.AddCommand(newApplyCommand())
+	rootCmd.AddCommand(newDoctorCommand())
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
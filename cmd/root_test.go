@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+func Test_setLogLevel(t *testing.T) {
+	defer func() {
+		if err := setLogLevel(0); err != nil {
+			t.Fatalf("Failed to reset the log level: %v", err)
+		}
+	}()
+
+	if err := setLogLevel(3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !klog.V(3).Enabled() {
+		t.Errorf("Expected level 3 logs to be enabled")
+	}
+	if klog.V(4).Enabled() {
+		t.Errorf("Expected level 4 logs to be disabled")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// unconvertedAnnotation is the annotation listing the source annotations of a
+// generated resource that could not be converted.
+const unconvertedAnnotation = "ingress2gateway.k8s.io/unconverted"
+
+// annotateUnconverted sets the unconvertedAnnotation on the objects with
+// unconverted source annotations recorded in provenance.ProvenanceAggr. The
+// annotations are sorted, so that the value is stable across runs.
+func annotateUnconverted(objects []client.Object) {
+	for _, obj := range objects {
+		ref := provenance.ObjectRef{
+			Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		}
+		unconverted := provenance.ProvenanceAggr.UnconvertedAnnotations(ref)
+		if len(unconverted) == 0 {
+			continue
+		}
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[unconvertedAnnotation] = strings.Join(unconverted, ", ")
+		obj.SetAnnotations(annotations)
+	}
+}
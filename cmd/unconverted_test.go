@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_annotateUnconverted(t *testing.T) {
+	provenance.ProvenanceAggr.Unconverted = map[provenance.ObjectRef][]string{}
+	ref := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+	provenance.ProvenanceAggr.RecordUnconverted(ref, "nginx.ingress.kubernetes.io/enable-cors")
+	provenance.ProvenanceAggr.RecordUnconverted(ref, "nginx.ingress.kubernetes.io/enable-modsecurity")
+
+	foo := &gatewayv1.HTTPRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{"existing": "true"}},
+	}
+	bar := &gatewayv1.HTTPRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+	}
+
+	annotateUnconverted([]client.Object{foo, bar})
+
+	expected := map[string]string{
+		"existing":            "true",
+		unconvertedAnnotation: "nginx.ingress.kubernetes.io/enable-cors, nginx.ingress.kubernetes.io/enable-modsecurity",
+	}
+	if diff := cmp.Diff(expected, foo.Annotations); diff != "" {
+		t.Errorf("Unexpected annotations (-want +got):\n%s", diff)
+	}
+	if bar.Annotations != nil {
+		t.Errorf("Expected no annotations, got %v", bar.Annotations)
+	}
+}
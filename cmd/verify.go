@@ -0,0 +1,436 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// coverageStatus tells whether a Gateway API resource of the conversion is
+// represented in the target directory.
+type coverageStatus string
+
+const (
+	// coverageMissing is the status of the generated resources missing from
+	// the target directory.
+	coverageMissing coverageStatus = "MISSING"
+	// coverageMismatch is the status of the generated resources whose spec is
+	// not represented by the one of the target directory.
+	coverageMismatch coverageStatus = "MISMATCH"
+	// coverageExtra is the status of the Gateway API resources of the target
+	// directory not generated from the source Ingresses. They are reported but
+	// are not gaps, like the resources added by hand.
+	coverageExtra coverageStatus = "EXTRA"
+	// coverageUnconverted is the status of the source Ingresses no generated
+	// resource comes from, e.g. as no provider converts their ingress class.
+	coverageUnconverted coverageStatus = "UNCONVERTED"
+)
+
+type coverageEntry struct {
+	kind   string
+	key    string
+	status coverageStatus
+	reason string
+}
+
+type VerifyRunner struct {
+	// source is the file or directory of the Ingresses and the resources they
+	// reference. Value assigned via --source flag.
+	source string
+
+	// target is the file or directory of the converted Gateway API resources.
+	// Value assigned via --target flag.
+	target string
+
+	// providers indicates which providers are used to convert the source.
+	// Value assigned via --providers flag.
+	providers []string
+
+	// The flags shaping the generated resources, shared with print so that the
+	// source is converted as the target was.
+	conversionFlags
+}
+
+// VerifyCoverage converts the source Ingresses and checks that every generated
+// resource is represented in the target directory, failing if any is missing
+// or differs, or if a source Ingress generates no resource.
+func (vr *VerifyRunner) VerifyCoverage(cmd *cobra.Command, _ []string) error {
+	inputFile, err := concatenateManifests(vr.source)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(inputFile)
+
+	gatewayOptions, err := vr.gatewayOptions()
+	if err != nil {
+		return err
+	}
+	gatewayResources, _, err := i2gw.ToGatewayAPIResources(cmd.Context(), "", inputFile, vr.providers, vr.providerSpecificFlagValues(vr.providers), gatewayOptions)
+	if err != nil {
+		return fmt.Errorf("failed to convert the source: %w", err)
+	}
+	generated := gatewayResourcesToObjects(gatewayResources)
+	ingresses, err := readManifests(vr.source, sets.New(common.IngressGVK.Group, "extensions"))
+	if err != nil {
+		return err
+	}
+	groups := sets.New(gatewayv1.GroupName)
+	for _, obj := range generated {
+		groups.Insert(obj.GetObjectKind().GroupVersionKind().Group)
+	}
+	target, err := readManifests(vr.target, groups)
+	if err != nil {
+		return err
+	}
+
+	entries := append(unconvertedIngresses(ingresses, generated), verifyCoverage(generated, target)...)
+	return printCoverage(entries, cmd.OutOrStdout())
+}
+
+// unconvertedIngresses returns the source Ingresses that are not a source of
+// any generated resource, sorted by namespace and name. The other objects of
+// the source are ignored.
+func unconvertedIngresses(source []*unstructured.Unstructured, generated []client.Object) []coverageEntry {
+	var entries []coverageEntry
+	for _, ingress := range source {
+		if ingress.GetKind() != common.IngressGVK.Kind {
+			continue
+		}
+		converted := false
+		for _, obj := range generated {
+			ref := provenance.ObjectRef{Kind: obj.GetObjectKind().GroupVersionKind().Kind, NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+			if provenance.ProvenanceAggr.HasIngressSource(ref, ingress.GetNamespace(), ingress.GetName()) {
+				converted = true
+				break
+			}
+		}
+		if !converted {
+			entries = append(entries, coverageEntry{kind: ingress.GetKind(), key: objectKey(ingress.GetNamespace(), ingress.GetName()), status: coverageUnconverted, reason: "no resource generated from it by the providers"})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+	return entries
+}
+
+// concatenateManifests writes the YAML and JSON files of the path, or the file
+// itself, to a temporary file whose name is returned, as the conversion reads a
+// single input file. The caller must remove it.
+func concatenateManifests(path string) (string, error) {
+	files, err := manifestFiles(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "ingress2gateway-source-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			os.Remove(f.Name())
+			return "", fmt.Errorf("failed to read file %v: %w", file, err)
+		}
+		if _, err = fmt.Fprintf(f, "---\n%s\n", data); err != nil {
+			os.Remove(f.Name())
+			return "", err
+		}
+	}
+	return f.Name(), nil
+}
+
+// readManifests returns the objects of the groups, like the Gateway API one, of
+// the YAML and JSON files of the path, or of the file itself.
+func readManifests(path string, groups sets.Set[string]) ([]*unstructured.Unstructured, error) {
+	files, err := manifestFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %v: %w", file, err)
+		}
+		fileObjects, err := common.ExtractObjectsFromReader(bytes.NewReader(data), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract objects from %v: %w", file, err)
+		}
+		for _, obj := range fileObjects {
+			if groups.Has(obj.GroupVersionKind().Group) {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	return objects, nil
+}
+
+// manifestFiles returns the YAML and JSON files of the directory and its
+// subdirectories, sorted, or the path itself if it is a file.
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, file)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files of %v: %w", path, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// verifyCoverage returns the generated resources missing from the target or
+// whose spec is not represented by the one of the target resource of the same
+// kind, namespace and name, and the target resources not generated. A spec is
+// represented when every field set by the conversion has the same value in the
+// target, which may set other fields, like the defaults of the API server, and
+// may order its lists differently.
+func verifyCoverage(generated []client.Object, target []*unstructured.Unstructured) []coverageEntry {
+	targetByKey := map[string]*unstructured.Unstructured{}
+	for _, obj := range target {
+		targetByKey[coverageKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = obj
+	}
+
+	var entries []coverageEntry
+	seen := map[string]bool{}
+	for _, obj := range generated {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		key := coverageKey(kind, obj.GetNamespace(), obj.GetName())
+		seen[key] = true
+		entry := coverageEntry{kind: kind, key: objectKey(obj.GetNamespace(), obj.GetName())}
+		targetObj, ok := targetByKey[key]
+		if !ok {
+			entry.status, entry.reason = coverageMissing, "not found in the target"
+			entries = append(entries, entry)
+			continue
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			entry.status, entry.reason = coverageMismatch, fmt.Sprintf("failed to compare: %v", err)
+			entries = append(entries, entry)
+			continue
+		}
+		if path, ok := covers(targetObj.Object["spec"], content["spec"], "spec"); !ok {
+			entry.status, entry.reason = coverageMismatch, fmt.Sprintf("%s differs from the generated one", path)
+			entries = append(entries, entry)
+		}
+	}
+	for _, obj := range target {
+		if !seen[coverageKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())] {
+			entries = append(entries, coverageEntry{kind: obj.GetKind(), key: objectKey(obj.GetNamespace(), obj.GetName()), status: coverageExtra, reason: "not generated from the source"})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].kind != entries[j].kind {
+			return entries[i].kind < entries[j].kind
+		}
+		return entries[i].key < entries[j].key
+	})
+	return entries
+}
+
+func coverageKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s %s", kind, objectKey(namespace, name))
+}
+
+func objectKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// covers returns whether the target value represents the generated one, and
+// otherwise the path of the first field that differs. Every field of a
+// generated map must be represented in the target map, and every element of
+// a generated list by a distinct element of the target list, in any order.
+func covers(target, generated any, path string) (string, bool) {
+	switch generated := generated.(type) {
+	case nil:
+		return "", true
+	case map[string]any:
+		targetMap, ok := target.(map[string]any)
+		if !ok {
+			return path, len(generated) == 0
+		}
+		keys := make([]string, 0, len(generated))
+		for key := range generated {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if diffPath, ok := covers(targetMap[key], generated[key], path+"."+key); !ok {
+				return diffPath, false
+			}
+		}
+		return "", true
+	case []any:
+		targetList, _ := target.([]any)
+		if i, ok := coversList(targetList, generated); !ok {
+			return fmt.Sprintf("%s[%d]", path, i), false
+		}
+		return "", true
+	default:
+		if target == nil {
+			return path, false
+		}
+		if reflect.TypeOf(target).Kind() == reflect.String || reflect.TypeOf(generated).Kind() == reflect.String {
+			return path, target == generated
+		}
+		// The numbers may be decoded as integers or floats.
+		return path, fmt.Sprint(target) == fmt.Sprint(generated)
+	}
+}
+
+// coversList returns whether every generated element is represented by a
+// distinct element of the target list, in any order, and otherwise the index of
+// a generated element left without one. The elements are matched with
+// augmenting paths, so that an element represented by several target elements
+// does not take the only one representing a later element.
+func coversList(target, generated []any) (int, bool) {
+	candidates := make([][]int, len(generated))
+	for i, element := range generated {
+		for j, targetElement := range target {
+			if _, ok := covers(targetElement, element, ""); ok {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+	}
+
+	matchedBy := make([]int, len(target))
+	for j := range matchedBy {
+		matchedBy[j] = -1
+	}
+	var match func(i int, visited []bool) bool
+	match = func(i int, visited []bool) bool {
+		for _, j := range candidates[i] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			if matchedBy[j] < 0 || match(matchedBy[j], visited) {
+				matchedBy[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range generated {
+		if !match(i, make([]bool, len(target))) {
+			return i, false
+		}
+	}
+	return 0, true
+}
+
+// printCoverage prints the coverage as a table, in the format of the
+// notification tables, and returns an error if a source Ingress is not
+// converted or if a generated resource is missing from the target or differs.
+func printCoverage(entries []coverageEntry, w io.Writer) error {
+	var gaps int
+	for _, entry := range entries {
+		if entry.status != coverageExtra {
+			gaps++
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "Every generated resource is represented in the target")
+		return nil
+	}
+
+	table := strings.Builder{}
+	t := tablewriter.NewWriter(&table)
+	t.SetHeader([]string{"Kind", "Resource", "Status", "Reason"})
+	t.SetColWidth(200)
+	t.SetRowLine(true)
+	for _, entry := range entries {
+		t.Append([]string{entry.kind, entry.key, string(entry.status), entry.reason})
+	}
+	t.Render()
+	fmt.Fprintf(w, "Coverage:\n%s\n", table.String())
+	if gaps > 0 {
+		return fmt.Errorf("%d source Ingresses or generated resources are not represented in the target", gaps)
+	}
+	return nil
+}
+
+func newVerifyCommand() *cobra.Command {
+	vr := &VerifyRunner{}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that converted Gateway API resources cover the source Ingresses",
+		Long:  `Converts the Ingresses of --source and checks that every generated Gateway API resource is represented in --target, e.g. the directory of the committed resources. A target resource represents a generated one of the same kind, namespace and name when every field of the generated spec has the same value, in any list order. The source is converted with the conversion flags of print, which must be the ones the target was generated with. The resources missing or differing, and the source Ingresses generating no resource, fail the verification, while the target resources not generated from the source are only reported.`,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return vr.conversionFlags.validate()
+		},
+		RunE:         vr.VerifyCoverage,
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&vr.source, "source", "",
+		`The file or directory of the Ingresses, and the resources they reference like their Services. The YAML and JSON files of a directory and its subdirectories are read.`)
+	cmd.Flags().StringVar(&vr.target, "target", "",
+		`The file or directory of the converted Gateway API resources. The YAML and JSON files of a directory and its subdirectories are read.`)
+	cmd.Flags().StringSliceVar(&vr.providers, "providers", []string{},
+		fmt.Sprintf("The providers converting the source, supported values are %v.", i2gw.GetSupportedProviders()))
+
+	vr.conversionFlags.addFlags(cmd)
+
+	_ = cmd.MarkFlagRequired("source")
+	_ = cmd.MarkFlagRequired("target")
+	_ = cmd.MarkFlagRequired("providers")
+	return cmd
+}
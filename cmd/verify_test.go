@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_verifyCoverage(t *testing.T) {
+	route := func(name string, hostnames ...gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+				Hostnames:       hostnames,
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{Name: "app", Port: ptr.To(gatewayv1.PortNumber(80))},
+					}}},
+				}},
+			},
+		}
+	}
+	targetRoute := func(name string, hostnames ...any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": name, "namespace": "default", "labels": map[string]any{"team": "a"}},
+			"spec": map[string]any{
+				"parentRefs": []any{map[string]any{"name": "nginx", "group": "gateway.networking.k8s.io", "kind": "Gateway"}},
+				"hostnames":  hostnames,
+				"rules": []any{map[string]any{
+					"matches":     []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/"}}},
+					"backendRefs": []any{map[string]any{"name": "app", "port": float64(80), "weight": int64(1)}},
+				}},
+			},
+		}}
+	}
+
+	generated := []client.Object{
+		route("reordered", "a.example.com", "b.example.com"),
+		route("changed", "a.example.com"),
+		route("missing", "a.example.com"),
+	}
+	target := []*unstructured.Unstructured{
+		targetRoute("reordered", "b.example.com", "a.example.com"),
+		targetRoute("changed", "c.example.com"),
+		targetRoute("manual", "a.example.com"),
+	}
+
+	expected := []coverageEntry{
+		{kind: "HTTPRoute", key: "default/changed", status: coverageMismatch, reason: "spec.hostnames[0] differs from the generated one"},
+		{kind: "HTTPRoute", key: "default/manual", status: coverageExtra, reason: "not generated from the source"},
+		{kind: "HTTPRoute", key: "default/missing", status: coverageMissing, reason: "not found in the target"},
+	}
+	got := verifyCoverage(generated, target)
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(coverageEntry{})); diff != "" {
+		t.Errorf("Unexpected coverage (-want +got):\n%s", diff)
+	}
+}
+
+func Test_covers(t *testing.T) {
+	testCases := []struct {
+		name      string
+		target    any
+		generated any
+		expected  string
+		ok        bool
+	}{{
+		name:      "list in another order",
+		target:    []any{"b", "a"},
+		generated: []any{"a", "b"},
+		ok:        true,
+	}, {
+		name: "list whose first element is represented by several target elements",
+		target: []any{
+			map[string]any{"name": "a", "port": float64(80)},
+			map[string]any{"name": "a"},
+		},
+		generated: []any{
+			map[string]any{"name": "a"},
+			map[string]any{"name": "a", "port": int64(80)},
+		},
+		ok: true,
+	}, {
+		name:      "list element without a distinct target element",
+		target:    []any{map[string]any{"name": "a", "port": float64(80)}},
+		generated: []any{map[string]any{"name": "a"}, map[string]any{"name": "a", "port": int64(80)}},
+		expected:  "spec[1]",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ok := covers(tc.target, tc.generated, "spec")
+			if ok != tc.ok {
+				t.Fatalf("Expected covers to return %v, got %v", tc.ok, ok)
+			}
+			if !ok && path != tc.expected {
+				t.Errorf("Expected the differing path %s, got %s", tc.expected, path)
+			}
+		})
+	}
+}
+
+func Test_VerifyCoverage(t *testing.T) {
+	source := `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: foo.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: foo
+            port:
+              number: 80
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: bar
+  namespace: default
+spec:
+  ingressClassName: kong
+  rules:
+  - host: bar.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: bar
+            port:
+              number: 80
+`
+	dir := t.TempDir()
+	sourceFile := filepath.Join(dir, "ingresses.yaml")
+	if err := os.WriteFile(sourceFile, []byte(source), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	targetDir := filepath.Join(dir, "gatewayapi")
+	if err := os.Mkdir(targetDir, 0o700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cmd := newVerifyCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"--source", sourceFile, "--target", targetDir, "--providers", "ingress-nginx", "--resource-prefix", "migrated-"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("Expected the verification to fail")
+	}
+	// The Ingress of the kong class is not converted by the ingress-nginx
+	// provider, and the generated resources are named with the prefix.
+	for _, expected := range []string{"default/bar", "UNCONVERTED", "default/migrated-foo-foo-com", "MISSING"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected the coverage to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "default/foo ") {
+		t.Errorf("Expected the converted Ingress not to be reported, got:\n%s", out.String())
+	}
+}
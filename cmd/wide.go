@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// wideOutputFormat prints a table with a row per generated resource, instead
+// of the resources.
+const wideOutputFormat = "wide"
+
+// noneCell is the value of the cells without value, like in kubectl tables.
+const noneCell = "<none>"
+
+var wideColumns = []metav1.TableColumnDefinition{
+	{Name: "NAMESPACE", Type: "string"},
+	{Name: "SOURCE-INGRESS", Type: "string", Description: "The Ingresses the resource was generated from."},
+	{Name: "KIND", Type: "string"},
+	{Name: "NAME", Type: "string"},
+	{Name: "HOSTNAMES", Type: "string", Description: "The hostnames of the listeners of a Gateway, or of a route, * for any."},
+	{Name: "GATEWAY", Type: "string", Description: "The Gateways a route is attached to."},
+}
+
+// toWideTable returns a table with a row per object, in the order of the
+// kinds of the objects, then sorted by namespace and name.
+func toWideTable(objects []client.Object) *metav1.Table {
+	sorted := sortObjects(objects)
+	ingresses := provenance.ProvenanceAggr.ListIngresses()
+	table := &metav1.Table{
+		TypeMeta:          metav1.TypeMeta{APIVersion: metav1.SchemeGroupVersion.String(), Kind: "Table"},
+		ColumnDefinitions: wideColumns,
+	}
+	for _, obj := range sorted {
+		hostnames, gateways := wideHostnamesAndGateways(obj)
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: []interface{}{
+			obj.GetNamespace(),
+			joinCell(sourceIngresses(obj, ingresses)),
+			obj.GetObjectKind().GroupVersionKind().Kind,
+			obj.GetName(),
+			joinCell(hostnames),
+			joinCell(gateways),
+		}})
+	}
+	return table
+}
+
+// sourceIngresses returns the Ingresses the object was generated from, with
+// their namespace if it differs from the namespace of the object.
+func sourceIngresses(obj client.Object, ingresses []types.NamespacedName) []string {
+	ref := provenance.ObjectRef{
+		Kind:           obj.GetObjectKind().GroupVersionKind().Kind,
+		NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+	}
+	var sources []string
+	for _, ingress := range ingresses {
+		if !provenance.ProvenanceAggr.HasIngressSource(ref, ingress.Namespace, ingress.Name) {
+			continue
+		}
+		if ingress.Namespace == obj.GetNamespace() {
+			sources = append(sources, ingress.Name)
+		} else {
+			sources = append(sources, ingress.String())
+		}
+	}
+	return sources
+}
+
+// wideHostnamesAndGateways returns the hostnames of the listeners of a Gateway,
+// or of a route along with the Gateways of its parentRefs.
+func wideHostnamesAndGateways(obj client.Object) ([]string, []string) {
+	var hostnames []gatewayv1.Hostname
+	var parentRefs []gatewayv1.ParentReference
+	switch o := obj.(type) {
+	case *gatewayv1.Gateway:
+		var listenerHostnames []string
+		for _, listener := range o.Spec.Listeners {
+			hostname := "*"
+			if listener.Hostname != nil && *listener.Hostname != "" {
+				hostname = string(*listener.Hostname)
+			}
+			if !slices.Contains(listenerHostnames, hostname) {
+				listenerHostnames = append(listenerHostnames, hostname)
+			}
+		}
+		return listenerHostnames, nil
+	case *gatewayv1.HTTPRoute:
+		hostnames, parentRefs = anyHostname(o.Spec.Hostnames), o.Spec.ParentRefs
+	case *gatewayv1alpha2.GRPCRoute:
+		hostnames, parentRefs = anyHostname(o.Spec.Hostnames), o.Spec.ParentRefs
+	case *gatewayv1alpha2.TLSRoute:
+		hostnames, parentRefs = anyHostname(o.Spec.Hostnames), o.Spec.ParentRefs
+	case *gatewayv1alpha2.TCPRoute:
+		parentRefs = o.Spec.ParentRefs
+	case *gatewayv1alpha2.UDPRoute:
+		parentRefs = o.Spec.ParentRefs
+	default:
+		return nil, nil
+	}
+
+	routeHostnames := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		routeHostnames = append(routeHostnames, string(hostname))
+	}
+	var gateways []string
+	for _, parentRef := range parentRefs {
+		gateway := string(parentRef.Name)
+		if parentRef.Namespace != nil && string(*parentRef.Namespace) != obj.GetNamespace() {
+			gateway = fmt.Sprintf("%s/%s", *parentRef.Namespace, parentRef.Name)
+		}
+		if !slices.Contains(gateways, gateway) {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return routeHostnames, gateways
+}
+
+// anyHostname returns the hostnames of a route, or * if it has none, as it then
+// matches any hostname.
+func anyHostname(hostnames []gatewayv1.Hostname) []gatewayv1.Hostname {
+	if len(hostnames) == 0 {
+		return []gatewayv1.Hostname{"*"}
+	}
+	return hostnames
+}
+
+func joinCell(values []string) string {
+	if len(values) == 0 {
+		return noneCell
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_toWideTable(t *testing.T) {
+	provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+	provenance.ProvenanceAggr.Ingresses = map[types.NamespacedName]bool{}
+	provenance.ProvenanceAggr.RecordIngress("default", "foo")
+	provenance.ProvenanceAggr.RecordIngress("default", "bar")
+	provenance.ProvenanceAggr.RecordIngress("apps", "baz")
+
+	record := func(kind, namespace, name string, ingresses ...types.NamespacedName) {
+		ref := provenance.ObjectRef{Kind: kind, NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		for _, ingress := range ingresses {
+			provenance.ProvenanceAggr.Record(ref, "", provenance.IngressSource(ingress.Namespace, ingress.Name, ""))
+		}
+	}
+	foo, bar, baz := types.NamespacedName{Namespace: "default", Name: "foo"}, types.NamespacedName{Namespace: "default", Name: "bar"}, types.NamespacedName{Namespace: "apps", Name: "baz"}
+	record("Gateway", "default", "nginx", foo, bar, baz)
+	record("HTTPRoute", "default", "foo-com", foo, bar)
+	record("TCPRoute", "apps", "baz", baz)
+
+	objects := []client.Object{
+		&gatewayv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+			Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+				{Name: "foo-com-http", Hostname: ptr.To(gatewayv1.Hostname("foo.com"))},
+				{Name: "foo-com-https", Hostname: ptr.To(gatewayv1.Hostname("foo.com"))},
+				{Name: "tcp"},
+			}},
+		},
+		&gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-com", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}, {Name: "nginx"}}},
+				Hostnames:       []gatewayv1.Hostname{"foo.com"},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: "any-host", Namespace: "default"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+			},
+		},
+		&gatewayv1alpha2.TCPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1alpha2", Kind: "TCPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "apps"},
+			Spec: gatewayv1alpha2.TCPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", Namespace: ptr.To(gatewayv1.Namespace("default"))}}},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := printers.NewTablePrinter(printers.PrintOptions{}).PrintObj(toWideTable(objects), &out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := `NAMESPACE   SOURCE-INGRESS     KIND        NAME       HOSTNAMES   GATEWAY
+default     apps/baz,bar,foo   Gateway     nginx      foo.com,*   <none>
+default     <none>             HTTPRoute   any-host   *           nginx
+default     bar,foo            HTTPRoute   foo-com    foo.com     nginx
+apps        baz                TCPRoute    baz        <none>      default/nginx
+`
+	if out.String() != want {
+		t.Errorf("Expected table\n%s\ngot\n%s", want, out.String())
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// routeKindsByProtocol lists, in the order they are set, the route kinds the
+// listeners of each protocol accept.
+var routeKindsByProtocol = map[gatewayv1.ProtocolType][]gatewayv1.Kind{
+	gatewayv1.HTTPProtocolType:  {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.HTTPSProtocolType: {"HTTPRoute", "GRPCRoute"},
+	gatewayv1.TLSProtocolType:   {"TLSRoute", "TCPRoute"},
+	gatewayv1.TCPProtocolType:   {"TCPRoute"},
+	gatewayv1.UDPProtocolType:   {"UDPRoute"},
+}
+
+// attachedRoute is the part of a route deciding the listeners it attaches to.
+type attachedRoute struct {
+	kind       gatewayv1.Kind
+	key        types.NamespacedName
+	namespace  string
+	hostnames  []gatewayv1.Hostname
+	parentRefs []gatewayv1.ParentReference
+}
+
+// setAllowedRouteKinds sets the allowedRoutes.kinds of the listeners of the
+// generated Gateways to the kinds of the routes, of any provider, attached to
+// them, so that the Gateways only accept the kinds of routes generated for
+// them. A route attaches to the listeners its parentRefs name, or to all the
+// listeners of its Gateway on its port and hostnames, provided their protocol
+// supports its kind. The listeners with no route, and the ones whose kinds are
+// already set, are left unchanged.
+func setAllowedRouteKinds(gatewayResourcesByProvider map[ProviderName]GatewayResources) {
+	var routes []attachedRoute
+	for _, gatewayResources := range gatewayResourcesByProvider {
+		routes = append(routes, attachedRoutes(gatewayResources)...)
+	}
+
+	for _, gatewayResources := range gatewayResourcesByProvider {
+		for key, gateway := range gatewayResources.Gateways {
+			for i, listener := range gateway.Spec.Listeners {
+				if listener.AllowedRoutes != nil && len(listener.AllowedRoutes.Kinds) > 0 {
+					continue
+				}
+				kinds := listenerRouteKinds(key, listener, routes)
+				if len(kinds) == 0 {
+					continue
+				}
+				if listener.AllowedRoutes == nil {
+					gateway.Spec.Listeners[i].AllowedRoutes = &gatewayv1.AllowedRoutes{}
+				}
+				gateway.Spec.Listeners[i].AllowedRoutes.Kinds = kinds
+			}
+			gatewayResources.Gateways[key] = gateway
+		}
+	}
+}
+
+func attachedRoutes(gatewayResources GatewayResources) []attachedRoute {
+	var routes []attachedRoute
+	for key, route := range gatewayResources.HTTPRoutes {
+		routes = append(routes, attachedRoute{kind: "HTTPRoute", key: key, namespace: route.Namespace, hostnames: route.Spec.Hostnames, parentRefs: route.Spec.ParentRefs})
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		routes = append(routes, attachedRoute{kind: "GRPCRoute", key: key, namespace: route.Namespace, hostnames: route.Spec.Hostnames, parentRefs: route.Spec.ParentRefs})
+	}
+	for key, route := range gatewayResources.TLSRoutes {
+		routes = append(routes, attachedRoute{kind: "TLSRoute", key: key, namespace: route.Namespace, hostnames: route.Spec.Hostnames, parentRefs: route.Spec.ParentRefs})
+	}
+	for key, route := range gatewayResources.TCPRoutes {
+		routes = append(routes, attachedRoute{kind: "TCPRoute", key: key, namespace: route.Namespace, parentRefs: route.Spec.ParentRefs})
+	}
+	for key, route := range gatewayResources.UDPRoutes {
+		routes = append(routes, attachedRoute{kind: "UDPRoute", key: key, namespace: route.Namespace, parentRefs: route.Spec.ParentRefs})
+	}
+	return routes
+}
+
+// listenerRouteKinds returns the kinds of the routes attached to the listener
+// of the Gateway, in the order of routeKindsByProtocol.
+func listenerRouteKinds(gatewayKey types.NamespacedName, listener gatewayv1.Listener, routes []attachedRoute) []gatewayv1.RouteGroupKind {
+	var kinds []gatewayv1.RouteGroupKind
+	for _, kind := range routeKindsByProtocol[listener.Protocol] {
+		for _, route := range routes {
+			if route.kind == kind && routeAttaches(route, gatewayKey, listener) {
+				kinds = append(kinds, gatewayv1.RouteGroupKind{Group: ptr.To(gatewayv1.Group(gatewayv1.GroupName)), Kind: kind})
+				break
+			}
+		}
+	}
+	return kinds
+}
+
+// routeAttaches returns whether the route attaches to the listener of the Gateway.
+func routeAttaches(route attachedRoute, gatewayKey types.NamespacedName, listener gatewayv1.Listener) bool {
+	for _, parentRef := range route.parentRefs {
+		if !refersToGateway(parentRef, route.namespace, gatewayKey) {
+			continue
+		}
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			continue
+		}
+		if hostnamesIntersect(listener.Hostname, route.hostnames) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostnamesIntersect returns whether a request may match both the listener
+// hostname and one of the route hostnames. A route without hostnames matches
+// all of them.
+func hostnamesIntersect(listenerHostname *gatewayv1.Hostname, routeHostnames []gatewayv1.Hostname) bool {
+	if len(routeHostnames) == 0 || listenerHostname == nil || *listenerHostname == "" {
+		return true
+	}
+	for _, routeHostname := range routeHostnames {
+		if hostnameMatches(listenerHostname, string(routeHostname)) {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(string(routeHostname), "*"); ok && strings.HasSuffix(string(*listenerHostname), suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_setAllowedRouteKinds(t *testing.T) {
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	parentRef := gatewayv1.ParentReference{Name: "nginx"}
+	httpRoutes := map[types.NamespacedName]gatewayv1.HTTPRoute{
+		{Namespace: "default", Name: "foo"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}},
+				Hostnames:       []gatewayv1.Hostname{"foo.example.com"},
+			},
+		},
+		{Namespace: "other", Name: "bar"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "bar"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+				Hostnames:       []gatewayv1.Hostname{"bar.example.com"},
+			},
+		},
+	}
+	grpcRoutes := map[types.NamespacedName]gatewayv1alpha2.GRPCRoute{
+		{Namespace: "default", Name: "grpc"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "grpc"},
+			Spec: gatewayv1alpha2.GRPCRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptr.To(gatewayv1.SectionName("http"))}}},
+				Hostnames:       []gatewayv1.Hostname{"*.example.com"},
+			},
+		},
+	}
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"provider-a": {
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				gatewayKey: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						GatewayClassName: "nginx",
+						Listeners: []gatewayv1.Listener{
+							{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+							{Name: "foo-https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, Hostname: ptr.To(gatewayv1.Hostname("foo.example.com"))},
+							{Name: "baz-https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, Hostname: ptr.To(gatewayv1.Hostname("baz.example.com"))},
+							{Name: "tls", Port: 8443, Protocol: gatewayv1.TLSProtocolType},
+						},
+					},
+				},
+			},
+			HTTPRoutes: httpRoutes,
+		},
+		"provider-b": {GRPCRoutes: grpcRoutes},
+	}
+
+	setAllowedRouteKinds(gatewayResourcesByProvider)
+
+	routeKinds := func(kinds ...gatewayv1.Kind) *gatewayv1.AllowedRoutes {
+		allowedRoutes := &gatewayv1.AllowedRoutes{}
+		for _, kind := range kinds {
+			allowedRoutes.Kinds = append(allowedRoutes.Kinds, gatewayv1.RouteGroupKind{Group: ptr.To(gatewayv1.Group(gatewayv1.GroupName)), Kind: kind})
+		}
+		return allowedRoutes
+	}
+	expected := map[gatewayv1.SectionName]*gatewayv1.AllowedRoutes{
+		"http":      routeKinds("HTTPRoute", "GRPCRoute"),
+		"foo-https": routeKinds("HTTPRoute"),
+		"baz-https": nil,
+		"tls":       nil,
+	}
+	got := map[gatewayv1.SectionName]*gatewayv1.AllowedRoutes{}
+	for _, listener := range gatewayResourcesByProvider["provider-a"].Gateways[gatewayKey].Spec.Listeners {
+		got[listener.Name] = listener.AllowedRoutes
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Unexpected allowed routes of the listeners (-want +got):\n%s", diff)
+	}
+}
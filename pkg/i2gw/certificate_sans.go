@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// readCertificateSANs returns the DNS names of the certificates of the
+// certificate Secrets of the generated Gateways, read from the input file, or
+// else from the cluster if cl is set. The Secrets which cannot be read, or
+// without a parsable certificate, are skipped, as their names are only used to
+// group the listeners.
+func readCertificateSANs(ctx context.Context, cl client.Client, inputFile string, gatewayResourcesByProvider map[ProviderName]GatewayResources, concurrency int) (map[types.NamespacedName][]string, error) {
+	refs := map[types.NamespacedName]bool{}
+	for _, gatewayResources := range gatewayResourcesByProvider {
+		for _, gateway := range gatewayResources.Gateways {
+			for _, ref := range listenerSecretRefs(gateway) {
+				refs[ref] = true
+			}
+		}
+	}
+	sans := map[types.NamespacedName][]string{}
+	if len(refs) == 0 {
+		return sans, nil
+	}
+
+	if inputFile != "" {
+		secrets, err := readSecretsFromFile(inputFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			key := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+			if !refs[key] {
+				continue
+			}
+			if names := certificateDNSNames(secret); len(names) > 0 {
+				sans[key] = names
+			}
+		}
+		return sans, nil
+	}
+	if cl == nil {
+		return sans, nil
+	}
+
+	keys := make([]types.NamespacedName, 0, len(refs))
+	for ref := range refs {
+		keys = append(keys, ref)
+	}
+	names := make([][]string, len(keys))
+	forEachIndex(len(keys), concurrency, func(i int) {
+		secret := &corev1.Secret{}
+		if err := cl.Get(ctx, keys[i], secret); err == nil {
+			names[i] = certificateDNSNames(*secret)
+		}
+	})
+	for i, key := range keys {
+		if len(names[i]) > 0 {
+			sans[key] = names[i]
+		}
+	}
+	return sans, nil
+}
+
+// readSecretsFromFile returns the Secrets of the manifest file, including the
+// ones of its Lists.
+func readSecretsFromFile(filename string) ([]corev1.Secret, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var secrets []corev1.Secret
+	addSecret := func(obj runtime.Object) error {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetAPIVersion() != "v1" || u.GetKind() != "Secret" {
+			return nil
+		}
+		var secret corev1.Secret
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &secret); err != nil {
+			return fmt.Errorf("failed to read Secret %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+		}
+		secrets = append(secrets, secret)
+		return nil
+	}
+	decoder := kubeyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		u := &unstructured.Unstructured{Object: content}
+		if u.IsList() {
+			if err := u.EachListItem(addSecret); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := addSecret(u); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+// certificateDNSNames returns the DNS names of the first certificate of the
+// tls.crt of the Secret, if any.
+func certificateDNSNames(secret corev1.Secret) []string {
+	data := secret.Data[corev1.TLSCertKey]
+	if len(data) == 0 && secret.StringData != nil {
+		data = []byte(secret.StringData[corev1.TLSCertKey])
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return certificate.DNSNames
+}
+
+// groupListenersByCertificateSANs adds a wildcard listener to the Gateways with
+// several HTTPS listeners whose hostnames are all covered by the same wildcard
+// DNS name of their certificates, like a.example.com and b.example.com by a
+// certificate for *.example.com, on the same port and with the same TLS
+// settings and allowed routes. The listeners are then grouped by the hostnames
+// the certificates actually cover, rather than only the hosts declared by the
+// Ingresses, and the specific listeners are collapsed into the wildcard one by
+// collapseWildcardListeners. An Info notification describes every grouping.
+func groupListenersByCertificateSANs(gatewayResourcesByProvider map[ProviderName]GatewayResources, sans map[types.NamespacedName][]string) {
+	if len(sans) == 0 {
+		return
+	}
+	for _, providerName := range sortedProviderNames(gatewayResourcesByProvider) {
+		gatewayResources := gatewayResourcesByProvider[providerName]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+		for key := range gatewayResources.Gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			gateway := gatewayResources.Gateways[key]
+			type group struct {
+				wildcard  string
+				listeners []gatewayv1.Listener
+			}
+			var groups []*group
+			for _, listener := range gateway.Spec.Listeners {
+				wildcard := coveringWildcardSAN(gateway, listener, sans)
+				if wildcard == "" {
+					continue
+				}
+				i := slices.IndexFunc(groups, func(g *group) bool {
+					first := g.listeners[0]
+					return g.wildcard == wildcard && first.Port == listener.Port && first.Protocol == listener.Protocol &&
+						equality.Semantic.DeepEqual(first.TLS, listener.TLS) && equality.Semantic.DeepEqual(first.AllowedRoutes, listener.AllowedRoutes)
+				})
+				if i < 0 {
+					groups = append(groups, &group{wildcard: wildcard})
+					i = len(groups) - 1
+				}
+				groups[i].listeners = append(groups[i].listeners, listener)
+			}
+
+			for _, g := range groups {
+				first := g.listeners[0]
+				if len(g.listeners) < 2 || hasListener(gateway, g.wildcard, first.Port) {
+					continue
+				}
+				hostname := gatewayv1.Hostname(g.wildcard)
+				wildcardListener := *first.DeepCopy()
+				wildcardListener.Name = gatewayv1.SectionName(fmt.Sprintf("wildcard-%s-%s", strings.ReplaceAll(strings.TrimPrefix(g.wildcard, "*."), ".", "-"), strings.ToLower(string(first.Protocol))))
+				wildcardListener.Hostname = &hostname
+				gateway.Spec.Listeners = append(gateway.Spec.Listeners, wildcardListener)
+
+				names := make([]string, 0, len(g.listeners))
+				for _, listener := range g.listeners {
+					names = append(names, string(listener.Name))
+				}
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.InfoNotification,
+					Message:        fmt.Sprintf("the listeners %s of Gateway %s are grouped under the wildcard listener %s, as their certificates cover %s", strings.Join(names, ", "), key, wildcardListener.Name, g.wildcard),
+					CallingObjects: []client.Object{&gateway},
+				}, string(providerName))
+			}
+			gatewayResources.Gateways[key] = gateway
+		}
+	}
+}
+
+// coveringWildcardSAN returns the wildcard DNS name shared by all the
+// certificates of the TLS listener which covers its hostname, if any. The
+// certificates without known DNS names cover nothing.
+func coveringWildcardSAN(gateway gatewayv1.Gateway, listener gatewayv1.Listener, sans map[types.NamespacedName][]string) string {
+	if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 || listener.Hostname == nil || strings.HasPrefix(string(*listener.Hostname), "*") {
+		return ""
+	}
+	listenerGateway := gateway
+	listenerGateway.Spec.Listeners = []gatewayv1.Listener{listener}
+	refs := listenerSecretRefs(listenerGateway)
+	if len(refs) != len(listener.TLS.CertificateRefs) {
+		return ""
+	}
+	var wildcards []string
+	for _, name := range sans[refs[0]] {
+		hostname := gatewayv1.Hostname(name)
+		if strings.HasPrefix(name, "*.") && hostnameMatches(&hostname, string(*listener.Hostname)) && !strings.Contains(strings.TrimSuffix(string(*listener.Hostname), name[1:]), ".") {
+			wildcards = append(wildcards, name)
+		}
+	}
+	for _, wildcard := range wildcards {
+		coveredByAll := true
+		for _, ref := range refs[1:] {
+			if !slices.Contains(sans[ref], wildcard) {
+				coveredByAll = false
+				break
+			}
+		}
+		if coveredByAll {
+			return wildcard
+		}
+	}
+	return ""
+}
+
+// hasListener returns whether the Gateway has a listener for the hostname on the port.
+func hasListener(gateway gatewayv1.Gateway, hostname string, port gatewayv1.PortNumber) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname != nil && string(*listener.Hostname) == hostname && listener.Port == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// selfSignedCertificate returns a PEM certificate for the DNS names.
+func selfSignedCertificate(t *testing.T, dnsNames ...string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_readCertificateSANs(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: wildcard-example-com
+  namespace: default
+type: kubernetes.io/tls
+data:
+  tls.crt: %s
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: unreferenced
+  namespace: default
+type: kubernetes.io/tls
+data:
+  tls.crt: %s
+`, base64.StdEncoding.EncodeToString(selfSignedCertificate(t, "*.example.com", "example.com")), base64.StdEncoding.EncodeToString(selfSignedCertificate(t, "other.com")))
+	if err := os.WriteFile(inputFile, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"test-provider": {
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "nginx"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{{
+						Name:     "a-example-com-https",
+						Hostname: ptr.To(gatewayv1.Hostname("a.example.com")),
+						Port:     443,
+						Protocol: gatewayv1.HTTPSProtocolType,
+						TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "wildcard-example-com"}, {Name: "missing"}}},
+					}}},
+				},
+			},
+		},
+	}
+
+	sans, err := readCertificateSANs(context.Background(), nil, inputFile, gatewayResourcesByProvider, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[types.NamespacedName][]string{{Namespace: "default", Name: "wildcard-example-com"}: {"*.example.com", "example.com"}}
+	if diff := cmp.Diff(expected, sans); diff != "" {
+		t.Errorf("Unexpected certificate SANs (-want +got):\n%s", diff)
+	}
+}
+
+func Test_groupListenersByCertificateSANs(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	tls := func(secretName string) *gatewayv1.GatewayTLSConfig {
+		return &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: gatewayv1.ObjectName(secretName)}}}
+	}
+	listener := func(hostname string, tls *gatewayv1.GatewayTLSConfig) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("%s-https", hostname)),
+			Hostname: ptr.To(gatewayv1.Hostname(hostname)),
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+			TLS:      tls,
+		}
+	}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"test-provider": {
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				gatewayKey: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+						// The Ingresses declare the hosts a and b with the same
+						// wildcard certificate.
+						listener("a.example.com", tls("wildcard")),
+						listener("b.example.com", tls("wildcard")),
+						// The wildcard certificate only covers a single label, so
+						// the host is not grouped, but it is then collapsed, as
+						// the wildcard listener serves it with the same certificate.
+						listener("a.b.example.com", tls("wildcard")),
+						listener("c.example.com", tls("c")),
+					}},
+				},
+			},
+		},
+	}
+	sans := map[types.NamespacedName][]string{
+		{Namespace: "default", Name: "wildcard"}: {"*.example.com"},
+		{Namespace: "default", Name: "c"}:        {"c.example.com"},
+	}
+
+	groupListenersByCertificateSANs(gatewayResourcesByProvider, sans)
+	collapseWildcardListeners(gatewayResourcesByProvider)
+
+	var got []string
+	for _, listener := range gatewayResourcesByProvider["test-provider"].Gateways[gatewayKey].Spec.Listeners {
+		got = append(got, fmt.Sprintf("%s %s %s", listener.Name, *listener.Hostname, listener.TLS.CertificateRefs[0].Name))
+	}
+	expected := []string{
+		"c.example.com-https c.example.com c",
+		"wildcard-example-com-https *.example.com wildcard",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications["test-provider"]
+	expectedMessage := "the listeners a.example.com-https, b.example.com-https of Gateway default/nginx are grouped under the wildcard listener wildcard-example-com-https, as their certificates cover *.example.com"
+	if len(gotNotifications) != 2 || gotNotifications[0].Type != notifications.InfoNotification || gotNotifications[0].Message != expectedMessage {
+		t.Errorf("Expected the Info notification %q, got %+v", expectedMessage, gotNotifications)
+	}
+}
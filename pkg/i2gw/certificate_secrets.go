@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerSecretRefs returns the Secrets referenced by the TLS certificates of
+// the Gateway listeners, sorted by namespace and name.
+func listenerSecretRefs(gateway gatewayv1.Gateway) []types.NamespacedName {
+	refs := map[types.NamespacedName]bool{}
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Secret") {
+				continue
+			}
+			key := types.NamespacedName{Namespace: gateway.Namespace, Name: string(ref.Name)}
+			if ref.Namespace != nil {
+				key.Namespace = string(*ref.Namespace)
+			}
+			refs[key] = true
+		}
+	}
+	sorted := make([]types.NamespacedName, 0, len(refs))
+	for ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	return sorted
+}
+
+// notifyUnverifiedSecrets emits an Info notification for every generated Gateway
+// referencing certificate Secrets when the resources are read from a file, as
+// the Secrets are then often not part of the file and there is no cluster to
+// check them against. The certificateRefs are generated anyway.
+func notifyUnverifiedSecrets(gatewayResourcesByProvider map[ProviderName]GatewayResources) {
+	for providerName, gatewayResources := range gatewayResourcesByProvider {
+		for _, gateway := range gatewayResources.Gateways {
+			gateway := gateway
+			refs := listenerSecretRefs(gateway)
+			if len(refs) == 0 {
+				continue
+			}
+			names := make([]string, 0, len(refs))
+			for _, ref := range refs {
+				names = append(names, ref.String())
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("the existence of the certificate Secrets %s of Gateway %s/%s could not be verified, as the resources were read from a file: create them before applying the Gateway", strings.Join(names, ", "), gateway.Namespace, gateway.Name),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+	}
+}
+
+// verifySecrets emits a Warning notification for every certificate Secret of the
+// generated Gateways missing from the cluster, as the listeners referencing it
+// would not be programmed. The Secrets are read with at most concurrency reads
+// at the same time, and the notifications are then emitted in the order of the
+// providers, Gateways and listeners, whatever the order of the reads.
+func verifySecrets(ctx context.Context, cl client.Client, gatewayResourcesByProvider map[ProviderName]GatewayResources, concurrency int, progress ProgressFunc) {
+	type secretCheck struct {
+		providerName ProviderName
+		gateway      gatewayv1.Gateway
+		ref          types.NamespacedName
+		err          error
+	}
+	var checks []secretCheck
+	for _, providerName := range sortedProviderNames(gatewayResourcesByProvider) {
+		gateways := gatewayResourcesByProvider[providerName].Gateways
+		keys := make([]types.NamespacedName, 0, len(gateways))
+		for key := range gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			for _, ref := range listenerSecretRefs(gateways[key]) {
+				checks = append(checks, secretCheck{providerName: providerName, gateway: gateways[key], ref: ref})
+			}
+		}
+	}
+
+	verified := newProgressCounter(progress, len(checks), "Verified %d/%d certificate Secrets")
+	forEachIndex(len(checks), concurrency, func(i int) {
+		checks[i].err = cl.Get(ctx, checks[i].ref, &corev1.Secret{})
+		verified.complete()
+	})
+
+	for _, check := range checks {
+		if check.err == nil {
+			continue
+		}
+		gateway := check.gateway
+		message := fmt.Sprintf("the certificate Secret %s of Gateway %s/%s does not exist in the cluster", check.ref, gateway.Namespace, gateway.Name)
+		if !apierrors.IsNotFound(check.err) {
+			message = fmt.Sprintf("the certificate Secret %s of Gateway %s/%s could not be verified: %v", check.ref, gateway.Namespace, gateway.Name, check.err)
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        message,
+			CallingObjects: []client.Object{&gateway},
+		}, string(check.providerName))
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func certificateSecretsTestResources() map[ProviderName]GatewayResources {
+	return map[ProviderName]GatewayResources{
+		"test-provider": {
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "nginx"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: gatewayv1.GatewaySpec{
+						Listeners: []gatewayv1.Listener{
+							{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+							{
+								Name:     "foo-https",
+								Port:     443,
+								Protocol: gatewayv1.HTTPSProtocolType,
+								TLS: &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{
+									{Name: "foo"},
+									{Name: "shared", Namespace: ptr.To(gatewayv1.Namespace("certs"))},
+								}},
+							},
+							{
+								Name:     "bar-https",
+								Port:     443,
+								Protocol: gatewayv1.HTTPSProtocolType,
+								TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "foo"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_listenerSecretRefs(t *testing.T) {
+	gateway := certificateSecretsTestResources()["test-provider"].Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}]
+	expected := []types.NamespacedName{{Namespace: "certs", Name: "shared"}, {Namespace: "default", Name: "foo"}}
+	if diff := cmp.Diff(expected, listenerSecretRefs(gateway)); diff != "" {
+		t.Errorf("Unexpected Secret references (-want +got):\n%s", diff)
+	}
+}
+
+func Test_notifyUnverifiedSecrets(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+	notifyUnverifiedSecrets(certificateSecretsTestResources())
+
+	notifs := notifications.NotificationAggr.Notifications["test-provider"]
+	if len(notifs) != 1 || notifs[0].Type != notifications.InfoNotification || !strings.Contains(notifs[0].Message, "certs/shared, default/foo") {
+		t.Errorf("Expected one Info notification listing the Secrets, got %+v", notifs)
+	}
+}
+
+func Test_verifySecrets(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	cl := fake.NewClientBuilder().WithObjects(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}).Build()
+
+	verifySecrets(context.Background(), cl, certificateSecretsTestResources(), 2, nil)
+
+	notifs := notifications.NotificationAggr.Notifications["test-provider"]
+	if len(notifs) != 1 {
+		t.Fatalf("Expected 1 notification, got %+v", notifs)
+	}
+	if notifs[0].Type != notifications.WarningNotification || !strings.Contains(notifs[0].Message, "certs/shared of Gateway default/nginx does not exist") {
+		t.Errorf("Expected a Warning about the missing Secret, got %s %q", notifs[0].Type, notifs[0].Message)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// StandardChannel is the release channel of the Gateway API CRDs with the
+	// stable kinds only.
+	StandardChannel = "standard"
+	// ExperimentalChannel is the release channel of the Gateway API CRDs with
+	// the stable and the experimental kinds, like TCPRoute and TLSRoute.
+	ExperimentalChannel = "experimental"
+)
+
+// supportedChannels are the values of the --channel flag.
+var supportedChannels = []string{StandardChannel, ExperimentalChannel}
+
+// apiVersionsByChannel are the versions of the Gateway API kinds printed for
+// each channel of the Gateway API CRDs generated for, v1.0.0. A kind missing
+// from a channel cannot be printed for it.
+var apiVersionsByChannel = map[string]map[string]string{
+	StandardChannel: {
+		"GatewayClass":   "v1",
+		"Gateway":        "v1",
+		"HTTPRoute":      "v1",
+		"ReferenceGrant": "v1beta1",
+	},
+	ExperimentalChannel: {
+		"GatewayClass":     "v1",
+		"Gateway":          "v1",
+		"HTTPRoute":        "v1",
+		"ReferenceGrant":   "v1beta1",
+		"GRPCRoute":        "v1alpha2",
+		"TLSRoute":         "v1alpha2",
+		"TCPRoute":         "v1alpha2",
+		"UDPRoute":         "v1alpha2",
+		"BackendTLSPolicy": "v1alpha2",
+	},
+}
+
+// ChannelKinds returns the Gateway API kinds of the channel with the version
+// they are printed with, like v1 for HTTPRoute, or nil for an unknown channel.
+func ChannelKinds(channel string) map[string]string {
+	versions, ok := apiVersionsByChannel[channel]
+	if !ok {
+		return nil
+	}
+	kinds := make(map[string]string, len(versions))
+	for kind, version := range versions {
+		kinds[kind] = version
+	}
+	return kinds
+}
+
+// channelAPIVersion returns the apiVersion the kind is printed with for the
+// channel, the experimental one if empty, or false if the channel does not
+// include the kind.
+func channelAPIVersion(channel, kind string) (string, bool) {
+	if channel == "" {
+		channel = ExperimentalChannel
+	}
+	version, ok := apiVersionsByChannel[channel][kind]
+	if !ok {
+		return "", false
+	}
+	return schema.GroupVersion{Group: gatewayv1.GroupName, Version: version}.String(), true
+}
+
+// setChannelAPIVersions sets the apiVersion of the generated resources to the
+// one of their kind in the channel, so that they apply cleanly to the CRDs of
+// that channel. An error is returned for every kind of resource the channel
+// does not include, listing the resources of that kind.
+func setChannelAPIVersions(gatewayResourcesByProvider map[ProviderName]GatewayResources, channel string) field.ErrorList {
+	unsupported := map[string][]string{}
+	for _, gatewayResources := range gatewayResourcesByProvider {
+		setKindAPIVersion(gatewayResources.GatewayClasses, "GatewayClass", channel, unsupported)
+		setKindAPIVersion(gatewayResources.Gateways, "Gateway", channel, unsupported)
+		setKindAPIVersion(gatewayResources.HTTPRoutes, "HTTPRoute", channel, unsupported)
+		setKindAPIVersion(gatewayResources.GRPCRoutes, "GRPCRoute", channel, unsupported)
+		setKindAPIVersion(gatewayResources.TLSRoutes, "TLSRoute", channel, unsupported)
+		setKindAPIVersion(gatewayResources.TCPRoutes, "TCPRoute", channel, unsupported)
+		setKindAPIVersion(gatewayResources.UDPRoutes, "UDPRoute", channel, unsupported)
+		setKindAPIVersion(gatewayResources.ReferenceGrants, "ReferenceGrant", channel, unsupported)
+		setKindAPIVersion(gatewayResources.BackendTLSPolicies, "BackendTLSPolicy", channel, unsupported)
+	}
+
+	kinds := make([]string, 0, len(unsupported))
+	for kind := range unsupported {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var errs field.ErrorList
+	for _, kind := range kinds {
+		names := unsupported[kind]
+		sort.Strings(names)
+		errs = append(errs, field.Invalid(field.NewPath(kind), strings.Join(names, ", "), fmt.Sprintf("the %s channel of the Gateway API CRDs has no %s, use --channel %s to print them", channel, kind, ExperimentalChannel)))
+	}
+	return errs
+}
+
+// setKindAPIVersion sets the apiVersion of the resources of the kind, or adds
+// their names to unsupported if the channel does not include the kind.
+func setKindAPIVersion[T any, PT interface {
+	*T
+	client.Object
+}](resources map[types.NamespacedName]T, kind, channel string, unsupported map[string][]string) {
+	if len(resources) == 0 {
+		return
+	}
+	apiVersion, ok := channelAPIVersion(channel, kind)
+	for key, resource := range resources {
+		if !ok {
+			unsupported[kind] = append(unsupported[kind], key.String())
+			continue
+		}
+		PT(&resource).GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+		resources[key] = resource
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_channelAPIVersion(t *testing.T) {
+	testCases := []struct {
+		channel            string
+		kind               string
+		expectedAPIVersion string
+		expectedOK         bool
+	}{
+		{channel: StandardChannel, kind: "HTTPRoute", expectedAPIVersion: "gateway.networking.k8s.io/v1", expectedOK: true},
+		{channel: StandardChannel, kind: "ReferenceGrant", expectedAPIVersion: "gateway.networking.k8s.io/v1beta1", expectedOK: true},
+		{channel: StandardChannel, kind: "TCPRoute"},
+		{channel: StandardChannel, kind: "TLSRoute"},
+		{channel: ExperimentalChannel, kind: "TCPRoute", expectedAPIVersion: "gateway.networking.k8s.io/v1alpha2", expectedOK: true},
+		{channel: ExperimentalChannel, kind: "TLSRoute", expectedAPIVersion: "gateway.networking.k8s.io/v1alpha2", expectedOK: true},
+		{channel: "", kind: "TLSRoute", expectedAPIVersion: "gateway.networking.k8s.io/v1alpha2", expectedOK: true},
+		{channel: ExperimentalChannel, kind: "Gateway", expectedAPIVersion: "gateway.networking.k8s.io/v1", expectedOK: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.channel+"/"+tc.kind, func(t *testing.T) {
+			apiVersion, ok := channelAPIVersion(tc.channel, tc.kind)
+			if apiVersion != tc.expectedAPIVersion || ok != tc.expectedOK {
+				t.Errorf("Expected %q, %v, got %q, %v", tc.expectedAPIVersion, tc.expectedOK, apiVersion, ok)
+			}
+		})
+	}
+}
+
+func Test_setChannelAPIVersions(t *testing.T) {
+	gatewayResources := func() map[ProviderName]GatewayResources {
+		return map[ProviderName]GatewayResources{
+			"test-provider": {
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "default", Name: "kong"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"}},
+				},
+				TCPRoutes: map[types.NamespacedName]gatewayv1alpha2.TCPRoute{
+					{Namespace: "default", Name: "foo"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+				},
+				TLSRoutes: map[types.NamespacedName]gatewayv1alpha2.TLSRoute{
+					{Namespace: "default", Name: "bar"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}},
+				},
+			},
+		}
+	}
+
+	t.Run("experimental channel", func(t *testing.T) {
+		resources := gatewayResources()
+		if errs := setChannelAPIVersions(resources, ExperimentalChannel); len(errs) != 0 {
+			t.Fatalf("Expected no errors, got %+v", errs)
+		}
+		tcpRoute := resources["test-provider"].TCPRoutes[types.NamespacedName{Namespace: "default", Name: "foo"}]
+		if tcpRoute.APIVersion != "gateway.networking.k8s.io/v1alpha2" || tcpRoute.Kind != "TCPRoute" {
+			t.Errorf("Expected a gateway.networking.k8s.io/v1alpha2 TCPRoute, got %+v", tcpRoute.TypeMeta)
+		}
+		gateway := resources["test-provider"].Gateways[types.NamespacedName{Namespace: "default", Name: "kong"}]
+		if gateway.APIVersion != "gateway.networking.k8s.io/v1" || gateway.Kind != "Gateway" {
+			t.Errorf("Expected a gateway.networking.k8s.io/v1 Gateway, got %+v", gateway.TypeMeta)
+		}
+	})
+
+	t.Run("standard channel", func(t *testing.T) {
+		errs := setChannelAPIVersions(gatewayResources(), StandardChannel)
+		if len(errs) != 2 {
+			t.Fatalf("Expected an error for the TCPRoutes and the TLSRoutes, got %+v", errs)
+		}
+		if !strings.Contains(errs[0].Error(), "TCPRoute") || !strings.Contains(errs[0].Error(), "default/foo") {
+			t.Errorf("Expected an error listing TCPRoute default/foo, got %v", errs[0])
+		}
+		if !strings.Contains(errs[1].Error(), "TLSRoute") || !strings.Contains(errs[1].Error(), "default/bar") {
+			t.Errorf("Expected an error listing TLSRoute default/bar, got %v", errs[1])
+		}
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cachingClient is a client.Client sharing the objects it reads between its
+// callers, so that the resources read by several providers, like the Services
+// or the Secrets, are fetched once from the cluster even when the providers
+// read them concurrently. Every caller gets its own copy of the objects, and
+// the errors are cached too.
+type cachingClient struct {
+	client.Client
+	mutex sync.Mutex
+	calls map[string]*cachedCall
+}
+
+// cachedCall is a read of an object, performed once.
+type cachedCall struct {
+	once sync.Once
+	obj  runtime.Object
+	err  error
+}
+
+func newCachingClient(cl client.Client) client.Client {
+	return &cachingClient{Client: cl, calls: map[string]*cachedCall{}}
+}
+
+func (c *cachingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	callKey := fmt.Sprintf("get %T %s %s", obj, obj.GetObjectKind().GroupVersionKind(), key)
+	return c.cached(callKey, obj, func(fresh runtime.Object) error {
+		return c.Client.Get(ctx, key, fresh.(client.Object), opts...)
+	})
+}
+
+func (c *cachingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	callKey := fmt.Sprintf("list %T %s %s %v %v %d %s", list, list.GetObjectKind().GroupVersionKind(), listOpts.Namespace, listOpts.LabelSelector, listOpts.FieldSelector, listOpts.Limit, listOpts.Continue)
+	return c.cached(callKey, list, func(fresh runtime.Object) error {
+		return c.Client.List(ctx, fresh.(client.ObjectList), opts...)
+	})
+}
+
+// cached performs the read of the key once, into a copy of obj, and copies its
+// result into obj.
+func (c *cachingClient) cached(key string, obj runtime.Object, read func(runtime.Object) error) error {
+	c.mutex.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		call = &cachedCall{}
+		c.calls[key] = call
+	}
+	c.mutex.Unlock()
+
+	call.once.Do(func() {
+		call.obj = obj.DeepCopyObject()
+		call.err = read(call.obj)
+	})
+	if call.err != nil {
+		return call.err
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(call.obj.DeepCopyObject()).Elem())
+	return nil
+}
+
+// forEachProvider calls f for every provider, with at most concurrency calls
+// running at the same time, or one if concurrency is below 1. It returns the
+// error of the first provider by name whose call failed, so that the error
+// does not depend on the scheduling of the calls.
+func forEachProvider(providerByName map[ProviderName]Provider, concurrency int, f func(ProviderName, Provider) error) error {
+	names := sortedProviderNames(providerByName)
+	errs := make([]error, len(names))
+	forEachIndex(len(names), concurrency, func(i int) {
+		errs[i] = f(names[i], providerByName[names[i]])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachIndex calls f for every index below n, with at most concurrency calls
+// running at the same time, or one if concurrency is below 1.
+func forEachIndex(n, concurrency int, f func(int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// sortedProviderNames returns the provider names of the map, sorted.
+func sortedProviderNames[V any](byProvider map[ProviderName]V) []ProviderName {
+	names := make([]ProviderName, 0, len(byProvider))
+	for name := range byProvider {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
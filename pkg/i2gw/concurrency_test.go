@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingClient counts the reads reaching the wrapped client.
+type countingClient struct {
+	client.Client
+	gets, lists atomic.Int32
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets.Add(1)
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *countingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.lists.Add(1)
+	return c.Client.List(ctx, list, opts...)
+}
+
+func Test_cachingClient(t *testing.T) {
+	counting := &countingClient{Client: fake.NewClientBuilder().WithObjects(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}},
+	).Build()}
+	cl := newCachingClient(counting)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			services := &corev1.ServiceList{}
+			if err := cl.List(ctx, services); err != nil {
+				t.Errorf("Unexpected error listing the Services: %v", err)
+				return
+			}
+			if len(services.Items) != 2 {
+				t.Errorf("Expected 2 Services, got %d", len(services.Items))
+			}
+			services.Items[0].Name = "modified"
+		}()
+	}
+	wg.Wait()
+	if got := counting.lists.Load(); got != 1 {
+		t.Errorf("Expected the Services to be listed once, got %d lists", got)
+	}
+
+	services := &corev1.ServiceList{}
+	if err := cl.List(ctx, services, client.InNamespace("other")); err != nil {
+		t.Fatalf("Unexpected error listing the Services: %v", err)
+	}
+	if got := counting.lists.Load(); got != 2 || len(services.Items) != 0 {
+		t.Errorf("Expected a list of the other namespace with no Service, got %d lists and %d Services", got, len(services.Items))
+	}
+
+	for i := 0; i < 2; i++ {
+		secret := &corev1.Secret{}
+		err := cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "missing"}, secret)
+		if err == nil {
+			t.Errorf("Expected an error getting a missing Secret")
+		}
+		service := &corev1.Service{}
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "foo"}, service); err != nil || service.Name != "foo" {
+			t.Errorf("Expected Service default/foo, got %q and error %v", service.Name, err)
+		}
+	}
+	if got := counting.gets.Load(); got != 2 {
+		t.Errorf("Expected 2 gets, got %d", got)
+	}
+}
+
+func Test_forEachIndex(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	var visited [10]atomic.Bool
+	forEachIndex(len(visited), 3, func(i int) {
+		current := running.Add(1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		visited[i].Store(true)
+		running.Add(-1)
+	})
+
+	if got := maxRunning.Load(); got > 3 {
+		t.Errorf("Expected at most 3 calls at the same time, got %d", got)
+	}
+	for i := range visited {
+		if !visited[i].Load() {
+			t.Errorf("Expected index %d to be visited", i)
+		}
+	}
+}
+
+func Test_forEachProvider(t *testing.T) {
+	providerByName := map[ProviderName]Provider{"a": nil, "b": nil, "c": nil, "d": nil}
+	for i := 0; i < 10; i++ {
+		err := forEachProvider(providerByName, 4, func(name ProviderName, _ Provider) error {
+			if name == "b" || name == "d" {
+				return errors.New(string(name))
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "b" {
+			t.Fatalf("Expected the error of provider b, got %v", err)
+		}
+	}
+}
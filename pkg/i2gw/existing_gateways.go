@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// attachToExistingGateways replaces the generated Gateways with the matching
+// existing ones. A generated Gateway matches an existing Gateway with the same
+// GatewayClass, preferring the ones in the same namespace, then the ones with
+// listeners covering most of the hostnames of the routes attached to the
+// generated Gateway. The routes are attached to the existing Gateway, to the
+// listeners equivalent to the ones their sectionNames refer to, or to all its
+// listeners if there is none, and the generated Gateway is dropped. When no
+// existing Gateway matches, the generated Gateway is kept and an Info
+// notification is emitted.
+func attachToExistingGateways(gatewayResources *GatewayResources, existingGateways []gatewayv1.Gateway, providerName ProviderName) {
+	for key, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		hostnames := routeHostnames(gatewayResources, key, gateway)
+		existing := findExistingGateway(gateway, hostnames, existingGateways)
+		if existing == nil {
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("no existing Gateway of class %s was found, Gateway %s/%s is generated", gateway.Spec.GatewayClassName, gateway.Namespace, gateway.Name),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+			continue
+		}
+
+		existingKey := types.NamespacedName{Namespace: existing.Namespace, Name: existing.Name}
+		removedSectionNames := reparentRoutes(gatewayResources, key, existingKey, listenerNames(gateway, *existing))
+		delete(gatewayResources.Gateways, key)
+		if policy, ok := gatewayResources.GatewayPolicies[key]; ok {
+			delete(gatewayResources.GatewayPolicies, key)
+			if !policy.IsEmpty() {
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.WarningNotification,
+					Message:        fmt.Sprintf("the %s of Gateway %s/%s were not converted for the existing Gateway %s, configure them with your Gateway implementation if it does not have them yet", strings.Join(policy.settings(), ", "), gateway.Namespace, gateway.Name, existingKey),
+					CallingObjects: []client.Object{&gateway},
+				}, string(providerName))
+			}
+		}
+
+		message := fmt.Sprintf("the routes of Gateway %s/%s are attached to the existing Gateway %s", gateway.Namespace, gateway.Name, existingKey)
+		if uncovered := uncoveredHostnames(hostnames, *existing); len(uncovered) > 0 {
+			message = fmt.Sprintf("%s, which has no listener for the hostnames %s", message, strings.Join(uncovered, ", "))
+		}
+		if len(removedSectionNames) > 0 {
+			message = fmt.Sprintf("%s, the sectionName of the routes attached to the listeners %s is removed as it has no listener of the same port, protocol and hostname", message, strings.Join(removedSectionNames, ", "))
+		}
+		if existing.Namespace != gateway.Namespace {
+			message = fmt.Sprintf("%s, make sure its listeners allow routes from namespace %s", message, gateway.Namespace)
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        message,
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+	}
+}
+
+func findExistingGateway(gateway gatewayv1.Gateway, hostnames []string, existingGateways []gatewayv1.Gateway) *gatewayv1.Gateway {
+	var candidates []*gatewayv1.Gateway
+	for i := range existingGateways {
+		if existingGateways[i].Spec.GatewayClassName == gateway.Spec.GatewayClassName {
+			candidates = append(candidates, &existingGateways[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iSameNamespace, jSameNamespace := candidates[i].Namespace == gateway.Namespace, candidates[j].Namespace == gateway.Namespace
+		if iSameNamespace != jSameNamespace {
+			return iSameNamespace
+		}
+		iUncovered, jUncovered := len(uncoveredHostnames(hostnames, *candidates[i])), len(uncoveredHostnames(hostnames, *candidates[j]))
+		if iUncovered != jUncovered {
+			return iUncovered < jUncovered
+		}
+		return types.NamespacedName{Namespace: candidates[i].Namespace, Name: candidates[i].Name}.String() <
+			types.NamespacedName{Namespace: candidates[j].Namespace, Name: candidates[j].Name}.String()
+	})
+	return candidates[0]
+}
+
+// routeHostnames returns the hostnames served by the routes attached to the
+// Gateway: the hostnames of the routes, or, for the routes without hostnames,
+// the hostnames of the listeners they attach to.
+func routeHostnames(gatewayResources *GatewayResources, key types.NamespacedName, gateway gatewayv1.Gateway) []string {
+	hostnames := sets.New[string]()
+	for _, route := range attachedRoutes(*gatewayResources) {
+		if len(route.hostnames) > 0 {
+			for _, parentRef := range route.parentRefs {
+				if refersToGateway(parentRef, route.namespace, key) {
+					for _, hostname := range route.hostnames {
+						hostnames.Insert(string(hostname))
+					}
+					break
+				}
+			}
+			continue
+		}
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.Hostname != nil && *listener.Hostname != "" && routeAttaches(route, key, listener) {
+				hostnames.Insert(string(*listener.Hostname))
+			}
+		}
+	}
+	return sets.List(hostnames)
+}
+
+// uncoveredHostnames returns the hostnames which are not matched by any
+// listener of the existing Gateway.
+func uncoveredHostnames(hostnames []string, existing gatewayv1.Gateway) []string {
+	var uncovered []string
+	for _, hostname := range hostnames {
+		var covered bool
+		for _, existingListener := range existing.Spec.Listeners {
+			if hostnameMatches(existingListener.Hostname, hostname) {
+				covered = true
+				break
+			}
+		}
+		if !covered && !slices.Contains(uncovered, hostname) {
+			uncovered = append(uncovered, hostname)
+		}
+	}
+	return uncovered
+}
+
+// listenerNames maps the names of the listeners of the generated Gateway to the
+// names of the equivalent listeners of the existing Gateway, of the same port
+// and protocol, with the same hostname or else a hostname matching it. The
+// listeners without equivalent are not mapped.
+func listenerNames(gateway, existing gatewayv1.Gateway) map[gatewayv1.SectionName]gatewayv1.SectionName {
+	names := map[gatewayv1.SectionName]gatewayv1.SectionName{}
+	for _, listener := range gateway.Spec.Listeners {
+		var match *gatewayv1.Listener
+		for i, existingListener := range existing.Spec.Listeners {
+			if existingListener.Port != listener.Port || existingListener.Protocol != listener.Protocol {
+				continue
+			}
+			if ptr.Deref(existingListener.Hostname, "") == ptr.Deref(listener.Hostname, "") {
+				match = &existing.Spec.Listeners[i]
+				break
+			}
+			if match == nil && listener.Hostname != nil && hostnameMatches(existingListener.Hostname, string(*listener.Hostname)) {
+				match = &existing.Spec.Listeners[i]
+			}
+		}
+		if match != nil {
+			names[listener.Name] = match.Name
+		}
+	}
+	return names
+}
+
+// hostnameMatches returns whether the hostname is matched by the listener hostname,
+// which may be empty or a wildcard.
+func hostnameMatches(listenerHostname *gatewayv1.Hostname, hostname string) bool {
+	if listenerHostname == nil || *listenerHostname == "" {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(string(*listenerHostname), "*"); ok {
+		return strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix)
+	}
+	return string(*listenerHostname) == hostname
+}
+
+// reparentRoutes replaces the parentRefs to the Gateway from with parentRefs to
+// the Gateway to. If sectionNames is not nil, the sectionNames of the parentRefs
+// are replaced with the names of the listeners they map to, and removed if they
+// map to none, whose names are returned, sorted.
+func reparentRoutes(gatewayResources *GatewayResources, from, to types.NamespacedName, sectionNames map[gatewayv1.SectionName]gatewayv1.SectionName) []string {
+	removed := sets.New[string]()
+	for key, route := range gatewayResources.HTTPRoutes {
+		remapSectionNames(route.Spec.ParentRefs, route.Namespace, from, sectionNames, removed)
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+		gatewayResources.HTTPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		remapSectionNames(route.Spec.ParentRefs, route.Namespace, from, sectionNames, removed)
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+		gatewayResources.GRPCRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TLSRoutes {
+		remapSectionNames(route.Spec.ParentRefs, route.Namespace, from, sectionNames, removed)
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+		gatewayResources.TLSRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TCPRoutes {
+		remapSectionNames(route.Spec.ParentRefs, route.Namespace, from, sectionNames, removed)
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+		gatewayResources.TCPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.UDPRoutes {
+		remapSectionNames(route.Spec.ParentRefs, route.Namespace, from, sectionNames, removed)
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+		gatewayResources.UDPRoutes[key] = route
+	}
+	return sets.List(removed)
+}
+
+func reparent(parentRefs []gatewayv1.ParentReference, routeNamespace string, from, to types.NamespacedName) {
+	for i, parentRef := range parentRefs {
+		if !refersToGateway(parentRef, routeNamespace, from) {
+			continue
+		}
+		parentRefs[i].Name = gatewayv1.ObjectName(to.Name)
+		parentRefs[i].Namespace = nil
+		if to.Namespace != routeNamespace {
+			ns := gatewayv1.Namespace(to.Namespace)
+			parentRefs[i].Namespace = &ns
+		}
+	}
+}
+
+// remapSectionNames replaces the sectionNames of the parentRefs to the Gateway
+// with the names they map to, or removes them, adding them to removed, if they
+// map to none. Nothing is changed if sectionNames is nil.
+func remapSectionNames(parentRefs []gatewayv1.ParentReference, routeNamespace string, gateway types.NamespacedName, sectionNames map[gatewayv1.SectionName]gatewayv1.SectionName, removed sets.Set[string]) {
+	if sectionNames == nil {
+		return
+	}
+	for i, parentRef := range parentRefs {
+		if parentRef.SectionName == nil || !refersToGateway(parentRef, routeNamespace, gateway) {
+			continue
+		}
+		if name, ok := sectionNames[*parentRef.SectionName]; ok {
+			parentRefs[i].SectionName = &name
+		} else {
+			removed.Insert(string(*parentRef.SectionName))
+			parentRefs[i].SectionName = nil
+		}
+	}
+}
+
+// refersToGateway returns whether the parentRef of a route of the namespace
+// references the Gateway.
+func refersToGateway(parentRef gatewayv1.ParentReference, routeNamespace string, gateway types.NamespacedName) bool {
+	parentGateway, ok := parentRefGateway(parentRef, routeNamespace)
+	return ok && parentGateway == gateway
+}
+
+// parentRefGateway returns the Gateway referenced by the parentRef of a route
+// of the namespace, or false if the parent is not a Gateway.
+func parentRefGateway(parentRef gatewayv1.ParentReference, routeNamespace string) (types.NamespacedName, bool) {
+	if parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName {
+		return types.NamespacedName{}, false
+	}
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return types.NamespacedName{}, false
+	}
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}, true
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_attachToExistingGateways(t *testing.T) {
+	hostname := func(h string) *gatewayv1.Hostname {
+		return ptr.To(gatewayv1.Hostname(h))
+	}
+	existingGateway := func(namespace, name, class string, hostnames ...string) gatewayv1.Gateway {
+		gateway := gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(class)},
+		}
+		for i, h := range hostnames {
+			gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{Name: gatewayv1.SectionName(fmt.Sprintf("listener-%d", i)), Hostname: hostname(h)})
+		}
+		return gateway
+	}
+
+	testCases := []struct {
+		name               string
+		listenerHostname   *gatewayv1.Hostname
+		routeHostnames     []gatewayv1.Hostname
+		sectionName        *gatewayv1.SectionName
+		existingGateways   []gatewayv1.Gateway
+		expectedGateways   []types.NamespacedName
+		expectedParentRefs []gatewayv1.ParentReference
+	}{
+		{
+			name:               "no matching class",
+			existingGateways:   []gatewayv1.Gateway{existingGateway("default", "istio", "istio")},
+			expectedGateways:   []types.NamespacedName{{Namespace: "default", Name: "nginx"}},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}},
+		},
+		{
+			name:               "existing gateway in the same namespace",
+			existingGateways:   []gatewayv1.Gateway{existingGateway("default", "main", "nginx")},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "main"}},
+		},
+		{
+			name: "existing gateway in another namespace",
+			existingGateways: []gatewayv1.Gateway{
+				existingGateway("infra", "other-hosts", "nginx", "other.com"),
+				existingGateway("infra", "shared", "nginx", "*.example.com"),
+			},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "shared", Namespace: ptr.To(gatewayv1.Namespace("infra"))}},
+		},
+		{
+			name:             "existing gateway covering the route hostnames",
+			listenerHostname: ptr.To(gatewayv1.Hostname("")),
+			routeHostnames:   []gatewayv1.Hostname{"foo.example.com"},
+			existingGateways: []gatewayv1.Gateway{
+				existingGateway("infra", "other-hosts", "nginx", "other.com"),
+				existingGateway("infra", "shared", "nginx", "*.example.com"),
+			},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "shared", Namespace: ptr.To(gatewayv1.Namespace("infra"))}},
+		},
+		{
+			name:               "sectionName of an equivalent listener",
+			sectionName:        ptr.To(gatewayv1.SectionName("foo-example-com-http")),
+			existingGateways:   []gatewayv1.Gateway{existingGateway("default", "main", "nginx", "bar.example.com", "*.example.com", "foo.example.com")},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "main", SectionName: ptr.To(gatewayv1.SectionName("listener-2"))}},
+		},
+		{
+			name:               "sectionName of a matching listener",
+			sectionName:        ptr.To(gatewayv1.SectionName("foo-example-com-http")),
+			existingGateways:   []gatewayv1.Gateway{existingGateway("default", "main", "nginx", "bar.example.com", "*.example.com")},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "main", SectionName: ptr.To(gatewayv1.SectionName("listener-1"))}},
+		},
+		{
+			name:               "sectionName without equivalent listener",
+			sectionName:        ptr.To(gatewayv1.SectionName("foo-example-com-http")),
+			existingGateways:   []gatewayv1.Gateway{existingGateway("default", "main", "nginx", "bar.example.com")},
+			expectedParentRefs: []gatewayv1.ParentReference{{Name: "main"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+			listenerHostname := hostname("foo.example.com")
+			if tc.listenerHostname != nil {
+				listenerHostname = tc.listenerHostname
+			}
+			routeKey := types.NamespacedName{Namespace: "default", Name: "route"}
+			gatewayResources := GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					gatewayKey: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+						Spec: gatewayv1.GatewaySpec{
+							GatewayClassName: "nginx",
+							Listeners:        []gatewayv1.Listener{{Name: "foo-example-com-http", Hostname: listenerHostname}},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					routeKey: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: tc.sectionName}},
+							},
+							Hostnames: tc.routeHostnames,
+						},
+					},
+				},
+			}
+
+			attachToExistingGateways(&gatewayResources, tc.existingGateways, "test-provider")
+
+			var gotGateways []types.NamespacedName
+			for key := range gatewayResources.Gateways {
+				gotGateways = append(gotGateways, key)
+			}
+			if diff := cmp.Diff(tc.expectedGateways, gotGateways); diff != "" {
+				t.Errorf("Unexpected Gateways (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedParentRefs, gatewayResources.HTTPRoutes[routeKey].Spec.ParentRefs); diff != "" {
+				t.Errorf("Unexpected parentRefs (-want +got):\n%s", diff)
+			}
+			if got := len(notifications.NotificationAggr.Notifications["test-provider"]); got != 1 {
+				t.Errorf("Expected 1 notification, got %d", got)
+			}
+		})
+	}
+}
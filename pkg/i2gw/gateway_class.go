@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// setGatewayClassNames sets the GatewayClass of the generated Gateways, which
+// is the ingress class they were generated from, to the class it is mapped to.
+// The mapping keys are ingress classes or provider names, the ingress class
+// taking precedence, so that the Ingresses of a controller can be mapped
+// regardless of their class name. Gateways whose class has no mapping get the
+// default class, if any, and an Info notification is emitted.
+func setGatewayClassNames(gatewayResources *GatewayResources, classNames map[string]string, defaultClassName string, providerName ProviderName) {
+	for key, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		ingressClass := string(gateway.Spec.GatewayClassName)
+		className, ok := classNames[ingressClass]
+		if !ok {
+			className, ok = classNames[string(providerName)]
+		}
+		if !ok {
+			message := fmt.Sprintf("no GatewayClass is mapped to the ingress class %s, Gateway %s/%s keeps the class %s", ingressClass, gateway.Namespace, gateway.Name, ingressClass)
+			if defaultClassName != "" {
+				className = defaultClassName
+				message = fmt.Sprintf("no GatewayClass is mapped to the ingress class %s, Gateway %s/%s uses the default class %s", ingressClass, gateway.Namespace, gateway.Name, defaultClassName)
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        message,
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+			if className == "" {
+				continue
+			}
+		}
+		gateway.Spec.GatewayClassName = gatewayv1.ObjectName(className)
+		gatewayResources.Gateways[key] = gateway
+	}
+}
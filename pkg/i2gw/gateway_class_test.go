@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_setGatewayClassNames(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		ingressClass          string
+		classNames            map[string]string
+		defaultClassName      string
+		expectedClassName     gatewayv1.ObjectName
+		expectedNotifications int
+	}{
+		{
+			name:              "mapped ingress class",
+			ingressClass:      "nginx",
+			classNames:        map[string]string{"nginx": "nginx-gateway", "gce": "gke-l7"},
+			defaultClassName:  "default",
+			expectedClassName: "nginx-gateway",
+		},
+		{
+			name:              "mapped provider name",
+			ingressClass:      "internal",
+			classNames:        map[string]string{"test-provider": "provider-gateway"},
+			expectedClassName: "provider-gateway",
+		},
+		{
+			name:              "ingress class takes precedence over provider name",
+			ingressClass:      "nginx",
+			classNames:        map[string]string{"nginx": "nginx-gateway", "test-provider": "provider-gateway"},
+			expectedClassName: "nginx-gateway",
+		},
+		{
+			name:                  "unmapped ingress class uses the default",
+			ingressClass:          "internal",
+			classNames:            map[string]string{"nginx": "nginx-gateway"},
+			defaultClassName:      "default",
+			expectedClassName:     "default",
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "unmapped ingress class without default",
+			ingressClass:          "internal",
+			classNames:            map[string]string{"nginx": "nginx-gateway"},
+			expectedClassName:     "internal",
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			key := types.NamespacedName{Namespace: "default", Name: tc.ingressClass}
+			gatewayResources := GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					key: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+						Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(tc.ingressClass)},
+					},
+				},
+			}
+
+			applyGatewayOptions(&gatewayResources, GatewayOptions{GatewayClassNames: tc.classNames, DefaultGatewayClassName: tc.defaultClassName}, "test-provider")
+
+			if got := gatewayResources.Gateways[key].Spec.GatewayClassName; got != tc.expectedClassName {
+				t.Errorf("Expected GatewayClass %s, got %s", tc.expectedClassName, got)
+			}
+			if got := len(notifications.NotificationAggr.Notifications["test-provider"]); got != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %d", tc.expectedNotifications, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// gatewayOwner is the provider whose Gateway holds a namespace and name.
+type gatewayOwner struct {
+	providerName ProviderName
+	className    gatewayv1.ObjectName
+}
+
+// mergeProviderGateways merges the Gateways generated by different providers
+// with the same namespace, name and GatewayClass into the Gateway of the first
+// provider by name, so that a single Gateway with the listeners of all of them
+// is printed. Gateways with the same namespace and name but different
+// GatewayClasses are kept distinct: the Gateway of the later provider is renamed
+// `<name>-<class>`, and the parentRefs of its routes are updated. The providers
+// are returned sorted by name.
+//
+// The overlapping hostnames of the listeners of the distinct Gateways are then
+// reported by warnConflictingListeners.
+func mergeProviderGateways(gatewayResourcesByProvider map[ProviderName]GatewayResources) ([]ProviderName, field.ErrorList) {
+	providerNames := make([]ProviderName, 0, len(gatewayResourcesByProvider))
+	for name := range gatewayResourcesByProvider {
+		providerNames = append(providerNames, name)
+	}
+	sort.Slice(providerNames, func(i, j int) bool { return providerNames[i] < providerNames[j] })
+
+	var errs field.ErrorList
+	owners := map[types.NamespacedName]gatewayOwner{}
+	for _, providerName := range providerNames {
+		gatewayResources := gatewayResourcesByProvider[providerName]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+		for key := range gatewayResources.Gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			gateway := gatewayResources.Gateways[key]
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = gatewayOwner{providerName: providerName, className: gateway.Spec.GatewayClassName}
+				continue
+			}
+			if owner.className == gateway.Spec.GatewayClassName {
+				ownerResources := gatewayResourcesByProvider[owner.providerName]
+				merged := ownerResources.Gateways[key]
+				errs = append(errs, mergeListeners(&merged, gateway, owner.providerName, providerName)...)
+				ownerResources.Gateways[key] = merged
+				delete(gatewayResources.Gateways, key)
+				if policy, ok := gatewayResources.GatewayPolicies[key]; ok {
+					if ownerResources.GatewayPolicies == nil {
+						ownerResources.GatewayPolicies = map[types.NamespacedName]GatewayPolicy{}
+					}
+					ownerResources.GatewayPolicies[key] = ownerResources.GatewayPolicies[key].merge(policy)
+					delete(gatewayResources.GatewayPolicies, key)
+				}
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.InfoNotification,
+					Message:        fmt.Sprintf("Gateway %s of class %s was also generated by provider %s, their listeners are merged into a single Gateway", key, gateway.Spec.GatewayClassName, owner.providerName),
+					CallingObjects: []client.Object{&merged},
+				}, string(providerName))
+				continue
+			}
+
+			renamed := types.NamespacedName{Namespace: key.Namespace, Name: fmt.Sprintf("%s-%s", key.Name, gateway.Spec.GatewayClassName)}
+			for i := 2; ; i++ {
+				if _, taken := owners[renamed]; !taken {
+					break
+				}
+				renamed.Name = fmt.Sprintf("%s-%s-%d", key.Name, gateway.Spec.GatewayClassName, i)
+			}
+			gateway.Name = renamed.Name
+			delete(gatewayResources.Gateways, key)
+			gatewayResources.Gateways[renamed] = gateway
+			reparentRoutes(&gatewayResources, key, renamed, nil)
+			gatewayResources.GatewayPolicies = renameObjectKeys(gatewayResources.GatewayPolicies, map[types.NamespacedName]string{key: renamed.Name})
+			owners[renamed] = gatewayOwner{providerName: providerName, className: gateway.Spec.GatewayClassName}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("Gateway %s was also generated by provider %s with class %s, it is renamed %s to keep the Gateways of class %s distinct", key, owner.providerName, owner.className, renamed, gateway.Spec.GatewayClassName),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+		gatewayResourcesByProvider[providerName] = gatewayResources
+	}
+	return providerNames, errs
+}
+
+// mergeListeners appends the listeners of the Gateway to the merged Gateway.
+// Identical listeners are only kept once, and a Warning notification is emitted
+// for listeners of the same name with different settings, the listener of the
+// merged Gateway being kept.
+func mergeListeners(merged *gatewayv1.Gateway, gateway gatewayv1.Gateway, ownerName, providerName ProviderName) field.ErrorList {
+	for _, listener := range gateway.Spec.Listeners {
+		i := listenerIndex(merged.Spec.Listeners, listener.Name)
+		if i < 0 {
+			merged.Spec.Listeners = append(merged.Spec.Listeners, listener)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(merged.Spec.Listeners[i], listener) {
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        fmt.Sprintf("listener %s of Gateway %s/%s differs from the one generated by provider %s, which is kept", listener.Name, merged.Namespace, merged.Name, ownerName),
+				CallingObjects: []client.Object{merged},
+			}, string(providerName))
+		}
+	}
+	// 64 is the maximum number of listeners a Gateway can have
+	if len(merged.Spec.Listeners) > 64 {
+		fieldPath := field.NewPath(fmt.Sprintf("%s/%s", merged.Namespace, merged.Name)).Child("spec").Child("listeners")
+		return field.ErrorList{field.Invalid(fieldPath, merged.Name, "error while merging gateway listeners: a gateway cannot have more than 64 listeners")}
+	}
+	return nil
+}
+
+func listenerIndex(listeners []gatewayv1.Listener, name gatewayv1.SectionName) int {
+	for i, listener := range listeners {
+		if listener.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_mergeProviderGateways(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	listener := func(hostname string) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(hostname + "-http"),
+			Hostname: ptr.To(gatewayv1.Hostname(hostname)),
+			Port:     80,
+			Protocol: gatewayv1.HTTPProtocolType,
+		}
+	}
+	resources := func(name, className string, listeners ...gatewayv1.Listener) GatewayResources {
+		key := types.NamespacedName{Namespace: "default", Name: name}
+		return GatewayResources{
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				key: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+					Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(className), Listeners: listeners},
+				},
+			},
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				{Namespace: "default", Name: "route"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(name)}}},
+					},
+				},
+			},
+		}
+	}
+
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"ingress-nginx": resources("nginx", "nginx", listener("example.com")),
+		"gce":           resources("gce", "gce", listener("example.com")),
+		// Same name and class as the ingress-nginx Gateway.
+		"istio": resources("nginx", "nginx", listener("example.com"), listener("foo.com")),
+		// Same name as the ingress-nginx Gateway, but another class.
+		"openapi3": resources("nginx", "envoy", listener("bar.com")),
+	}
+
+	providerNames, errs := mergeProviderGateways(gatewayResourcesByProvider)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if diff := cmp.Diff([]ProviderName{"gce", "ingress-nginx", "istio", "openapi3"}, providerNames); diff != "" {
+		t.Errorf("Unexpected provider names (-want +got):\n%s", diff)
+	}
+
+	gatewayNames := map[ProviderName][]string{}
+	for providerName, gatewayResources := range gatewayResourcesByProvider {
+		for key, gateway := range gatewayResources.Gateways {
+			gatewayNames[providerName] = append(gatewayNames[providerName], key.String())
+			var listeners []string
+			for _, l := range gateway.Spec.Listeners {
+				listeners = append(listeners, string(l.Name))
+			}
+			expectedListeners := map[string][]string{
+				"default/gce":         {"example.com-http"},
+				"default/nginx":       {"example.com-http", "foo.com-http"},
+				"default/nginx-envoy": {"bar.com-http"},
+			}[key.String()]
+			if diff := cmp.Diff(expectedListeners, listeners); diff != "" {
+				t.Errorf("Unexpected listeners of Gateway %s (-want +got):\n%s", key, diff)
+			}
+		}
+	}
+	expectedGatewayNames := map[ProviderName][]string{
+		"gce":           {"default/gce"},
+		"ingress-nginx": {"default/nginx"},
+		"openapi3":      {"default/nginx-envoy"},
+	}
+	if diff := cmp.Diff(expectedGatewayNames, gatewayNames); diff != "" {
+		t.Errorf("Unexpected Gateways (-want +got):\n%s", diff)
+	}
+
+	route := gatewayResourcesByProvider["openapi3"].HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "route"}]
+	if got := route.Spec.ParentRefs[0].Name; got != "nginx-envoy" {
+		t.Errorf("Expected the openapi3 route to be attached to the renamed Gateway, got %s", got)
+	}
+	route = gatewayResourcesByProvider["istio"].HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "route"}]
+	if got := route.Spec.ParentRefs[0].Name; got != "nginx" {
+		t.Errorf("Expected the istio route to be attached to the merged Gateway, got %s", got)
+	}
+
+	// The distinct Gateways of different classes claiming the same hostname are
+	// reported.
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	warnConflictingListeners(gatewayResourcesByProvider)
+	if got := len(notifications.NotificationAggr.Notifications["gce"]); got != 1 {
+		t.Errorf("Expected 1 overlapping hostname notification, got %+v", notifications.NotificationAggr.Notifications["gce"])
+	}
+}
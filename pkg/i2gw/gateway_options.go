@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayOptions contains the settings applied to the Gateways generated by every provider.
+type GatewayOptions struct {
+	// TLSMinVersion is the minimum TLS version of the HTTPS listeners, e.g. 1.2.
+	TLSMinVersion string
+	// TargetImplementation is the Gateway API implementation the resources are
+	// generated for. It determines the implementation-specific listener tls.options.
+	TargetImplementation string
+	// ExistingGateways are the Gateways the generated routes are attached to,
+	// instead of the generated Gateways, when they match.
+	ExistingGateways []gatewayv1.Gateway
+	// GatewayClassNames maps ingress classes or provider names to the
+	// GatewayClass of the Gateways generated for them.
+	GatewayClassNames map[string]string
+	// DefaultGatewayClassName is the GatewayClass of the generated Gateways
+	// whose ingress class has no mapping in GatewayClassNames.
+	DefaultGatewayClassName string
+	// ListenerProtocols maps listener ports to the protocol of the generated
+	// listeners on them, overriding the inferred protocols.
+	ListenerProtocols map[string]string
+	// PortMap maps listener ports to the ports the generated listeners on them
+	// are moved to, e.g. 80 to 8080. The route parentRefs and the redirects to
+	// the same host follow the listeners.
+	PortMap map[string]string
+	// KubeContext is the kubeconfig context of the cluster the resources are
+	// read from, the current context if empty.
+	KubeContext string
+	// ModifiedSince, if set, restricts the conversion of the cluster Ingresses
+	// to the ones created or modified since then. It has no effect when reading
+	// from a file.
+	ModifiedSince time.Time
+	// VerifySecrets reports the certificate Secrets of the generated Gateways
+	// missing from the cluster. It has no effect when reading from a file.
+	VerifySecrets bool
+	// HTTPListenerPolicy sets how the hosts with TLS are served over HTTP, one
+	// of HTTPListenerPolicyBoth, the default, HTTPListenerPolicyRedirect or
+	// HTTPListenerPolicyHTTPSOnly.
+	HTTPListenerPolicy string
+	// ResourcePrefix is prepended to the names of the generated resources,
+	// except the GatewayClasses.
+	ResourcePrefix string
+	// RenameMap maps the Ingresses to the names of the Gateways and HTTPRoutes
+	// generated from them, overriding the names derived by the providers. The
+	// ResourcePrefix is prepended to the new names.
+	RenameMap map[types.NamespacedName]ResourceNames
+	// ReadConcurrency is the maximum number of providers reading their
+	// resources, and of Secrets read from the cluster, at the same time. Below
+	// 1, they are read one at a time. The Ingresses are converted one at a time
+	// whatever the concurrency.
+	ReadConcurrency int
+	// TLSSecretNamespace, if set, is the namespace of the certificate Secrets
+	// of the generated listeners, which reference the Secret of the same name
+	// in it, with a ReferenceGrant allowing the Gateways of every namespace to
+	// reference it.
+	TLSSecretNamespace string
+	// OmitReferenceGrants removes the generated ReferenceGrants, the missing
+	// grants being reported with a Warning notification.
+	OmitReferenceGrants bool
+	// Channel is the release channel of the Gateway API CRDs the resources are
+	// printed for, StandardChannel or ExperimentalChannel, the default. It sets
+	// the apiVersion of the resources, and the experimental kinds, like TCPRoute
+	// and TLSRoute, cannot be printed for the standard channel.
+	Channel string
+	// MaxRoutesPerGateway is the maximum number of routes attached to a
+	// generated Gateway, the Gateways with more routes being split. No limit
+	// if 0.
+	MaxRoutesPerGateway int
+	// Progress, if set, is called with the progress of the reading and the
+	// conversion of the resources, and of the verification of the Secrets.
+	Progress ProgressFunc
+	// MetricsRegisterer, if set, is the Prometheus registerer of the metrics of
+	// the conversions: the conversions by provider, the notifications by provider
+	// and severity, and the conversion durations. The metrics are registered once,
+	// and updated by every conversion with the same registerer.
+	MetricsRegisterer prometheus.Registerer
+	// OwnerReference, if set, is the owner of all the generated resources, e.g.
+	// the custom resource of an operator embedding the conversion, so that they
+	// are garbage collected with it. The owner references and finalizers of the
+	// source resources are never copied to the generated ones.
+	OwnerReference *metav1.OwnerReference
+	// OwnerNamespace is the namespace of the OwnerReference, empty for a
+	// cluster-scoped owner. As Kubernetes only resolves a namespaced owner in
+	// the namespace of its dependents, only the generated resources of this
+	// namespace are owned, and a Warning is emitted for the others, like the
+	// cluster-scoped GatewayClasses.
+	OwnerNamespace string
+}
+
+// Validate returns an error if the options are not supported.
+func (o GatewayOptions) Validate() error {
+	if o.TLSMinVersion != "" && !slices.Contains(supportedTLSVersions, o.TLSMinVersion) {
+		return fmt.Errorf("%s is not a supported TLS version, supported values are %v", o.TLSMinVersion, supportedTLSVersions)
+	}
+	if o.TargetImplementation != "" && !slices.Contains(GetSupportedTargetImplementations(), o.TargetImplementation) {
+		return fmt.Errorf("%s is not a supported target implementation, supported values are %v", o.TargetImplementation, GetSupportedTargetImplementations())
+	}
+	for class, className := range o.GatewayClassNames {
+		if class == "" || className == "" {
+			return fmt.Errorf("invalid GatewayClass mapping %s=%s, both the ingress class and the GatewayClass must be set", class, className)
+		}
+	}
+	for port, protocol := range o.ListenerProtocols {
+		if _, _, err := parseListenerProtocol(port, protocol); err != nil {
+			return err
+		}
+	}
+	if _, err := parsePortMap(o.PortMap); err != nil {
+		return err
+	}
+	if o.HTTPListenerPolicy != "" && !slices.Contains(supportedHTTPListenerPolicies, o.HTTPListenerPolicy) {
+		return fmt.Errorf("%s is not a supported HTTP listener policy, supported values are %v", o.HTTPListenerPolicy, supportedHTTPListenerPolicies)
+	}
+	if o.Channel != "" && !slices.Contains(supportedChannels, o.Channel) {
+		return fmt.Errorf("%s is not a supported channel, supported values are %v", o.Channel, supportedChannels)
+	}
+	if o.MaxRoutesPerGateway < 0 {
+		return fmt.Errorf("the maximum number of routes per Gateway must not be negative, got %d", o.MaxRoutesPerGateway)
+	}
+	if o.ReadConcurrency < 0 {
+		return fmt.Errorf("the read concurrency must not be negative, got %d", o.ReadConcurrency)
+	}
+	if o.ResourcePrefix != "" {
+		if err := validateResourcePrefix(o.ResourcePrefix); err != nil {
+			return err
+		}
+	}
+	if o.TLSSecretNamespace != "" {
+		if err := validateTLSSecretNamespace(o.TLSSecretNamespace); err != nil {
+			return err
+		}
+	}
+	if err := validateRenameMap(o.RenameMap); err != nil {
+		return err
+	}
+	if o.OwnerReference != nil {
+		if err := validateOwnerReference(*o.OwnerReference); err != nil {
+			return err
+		}
+	}
+	if o.OwnerNamespace != "" {
+		if o.OwnerReference == nil {
+			return fmt.Errorf("the owner namespace %s is set without an owner reference", o.OwnerNamespace)
+		}
+		if err := validateOwnerNamespace(o.OwnerNamespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSupportedTargetImplementations returns the names of all the supported target implementations.
+func GetSupportedTargetImplementations() []string {
+	return []string{EnvoyGatewayImplementation, IstioImplementation}
+}
+
+// applyGatewayOptions sets the options on the Gateways generated by the provider.
+func applyGatewayOptions(gatewayResources *GatewayResources, options GatewayOptions, providerName ProviderName) field.ErrorList {
+	var errs field.ErrorList
+	// The GatewayClass is set first, as the existing Gateways are matched on it.
+	if len(options.GatewayClassNames) > 0 || options.DefaultGatewayClassName != "" {
+		setGatewayClassNames(gatewayResources, options.GatewayClassNames, options.DefaultGatewayClassName, providerName)
+	}
+	if len(options.ListenerProtocols) > 0 {
+		setListenerProtocols(gatewayResources, options.ListenerProtocols, providerName)
+	}
+	// The HTTP listener policy is applied to the generated listeners, before
+	// the routes are attached to the existing Gateways.
+	if options.HTTPListenerPolicy != "" {
+		applyHTTPListenerPolicy(gatewayResources, options.HTTPListenerPolicy, providerName)
+	}
+	// The resources are renamed before the names are prefixed, and before the
+	// routes are attached to the existing Gateways.
+	if len(options.RenameMap) > 0 {
+		errs = append(errs, renameResources(gatewayResources, options.RenameMap)...)
+	}
+	// The ports are remapped once the protocols are set by the original ports,
+	// and the HTTP to HTTPS redirects are generated.
+	if len(options.PortMap) > 0 {
+		remapListenerPorts(gatewayResources, options.PortMap, providerName)
+	}
+	// The certificate Secrets are moved before the names are prefixed, so that
+	// the ReferenceGrants of the Secrets are prefixed like the other ones.
+	if options.TLSSecretNamespace != "" {
+		setTLSSecretNamespace(gatewayResources, options.TLSSecretNamespace, providerName)
+	}
+	// The names are prefixed before the routes are attached to the existing
+	// Gateways, which keep their names. As all the providers use the same
+	// prefix, their Gateways of the same name are still merged.
+	if options.ResourcePrefix != "" {
+		prefixResourceNames(gatewayResources, options.ResourcePrefix)
+	}
+	if len(options.ExistingGateways) > 0 {
+		attachToExistingGateways(gatewayResources, options.ExistingGateways, providerName)
+	}
+	if options.TLSMinVersion != "" {
+		setTLSMinVersion(gatewayResources, options.TLSMinVersion, options.TargetImplementation, providerName)
+	}
+	// The ReferenceGrants are removed last, so that the notifications name
+	// them as they would have been printed.
+	if options.OmitReferenceGrants {
+		omitReferenceGrants(gatewayResources, providerName)
+	}
+	return errs
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// routeKindOrder is the order the routes of a split Gateway are distributed in.
+var routeKindOrder = []gatewayv1.Kind{"HTTPRoute", "GRPCRoute", "TLSRoute", "TCPRoute", "UDPRoute"}
+
+// providerRoute is a route generated by a provider.
+type providerRoute struct {
+	attachedRoute
+	providerName ProviderName
+}
+
+// splitGateways splits the generated Gateways with more than maxRoutes routes
+// attached into Gateways named `<name>-1`, `<name>-2`, etc., with at most
+// maxRoutes routes each, as some implementations do not cope with hundreds of
+// routes on a Gateway. It runs once the Gateways of all the providers are
+// merged, so the routes of every provider are counted.
+//
+// The routes attached to the same listener are kept on the same Gateway, as
+// the requests for a hostname and port can only reach one of them, and every
+// Gateway only gets the listeners of its routes, the listeners without routes
+// staying on the first Gateway. The routes are distributed in the order of
+// their kind and name. An Info notification describes every split, and a
+// Warning is emitted for the Gateways left over the limit by routes sharing
+// their listeners, which are not split if all their routes do.
+func splitGateways(gatewayResourcesByProvider map[ProviderName]GatewayResources, maxRoutes int) {
+	if maxRoutes < 1 {
+		return
+	}
+
+	providerNames := sortedProviderNames(gatewayResourcesByProvider)
+	var routes []providerRoute
+	taken := map[types.NamespacedName]bool{}
+	for _, providerName := range providerNames {
+		for _, route := range attachedRoutes(gatewayResourcesByProvider[providerName]) {
+			routes = append(routes, providerRoute{attachedRoute: route, providerName: providerName})
+		}
+		for key := range gatewayResourcesByProvider[providerName].Gateways {
+			taken[key] = true
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].kind != routes[j].kind {
+			return slices.Index(routeKindOrder, routes[i].kind) < slices.Index(routeKindOrder, routes[j].kind)
+		}
+		if routes[i].key != routes[j].key {
+			return routes[i].key.String() < routes[j].key.String()
+		}
+		return routes[i].providerName < routes[j].providerName
+	})
+
+	for _, providerName := range providerNames {
+		gatewayResources := gatewayResourcesByProvider[providerName]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+		for key := range gatewayResources.Gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			var gatewayRoutes []providerRoute
+			for _, route := range routes {
+				if slices.ContainsFunc(route.parentRefs, func(parentRef gatewayv1.ParentReference) bool {
+					return refersToGateway(parentRef, route.namespace, key)
+				}) {
+					gatewayRoutes = append(gatewayRoutes, route)
+				}
+			}
+			if len(gatewayRoutes) > maxRoutes {
+				splitGateway(gatewayResourcesByProvider, providerName, key, gatewayRoutes, maxRoutes, taken)
+			}
+		}
+	}
+}
+
+// gatewaySplit is a Gateway split from a Gateway, with the indexes of its
+// routes and listeners.
+type gatewaySplit struct {
+	routes    []int
+	listeners []int
+}
+
+func splitGateway(gatewayResourcesByProvider map[ProviderName]GatewayResources, providerName ProviderName, key types.NamespacedName, routes []providerRoute, maxRoutes int, taken map[types.NamespacedName]bool) {
+	gatewayResources := gatewayResourcesByProvider[providerName]
+	gateway := gatewayResources.Gateways[key]
+	splits := distributeRoutes(gateway, key, routes, maxRoutes)
+	if len(splits) == 1 {
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("Gateway %s has %d routes, over the maximum of %d, but is not split as they are all attached to the same listeners", key, len(routes), maxRoutes),
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+		return
+	}
+
+	var names, oversized []string
+	gatewayRef := provenance.ObjectRef{Kind: "Gateway", NamespacedName: key}
+	for i, split := range splits {
+		splitKey := types.NamespacedName{Namespace: key.Namespace, Name: fmt.Sprintf("%s-%d", key.Name, i+1)}
+		for suffix := 2; taken[splitKey]; suffix++ {
+			splitKey.Name = fmt.Sprintf("%s-%d-%d", key.Name, i+1, suffix)
+		}
+		taken[splitKey] = true
+
+		splitGateway := *gateway.DeepCopy()
+		splitGateway.Name = splitKey.Name
+		splitGateway.Spec.Listeners = nil
+		for _, l := range split.listeners {
+			splitGateway.Spec.Listeners = append(splitGateway.Spec.Listeners, gateway.Spec.Listeners[l])
+		}
+		gatewayResources.Gateways[splitKey] = splitGateway
+		if policy, ok := gatewayResources.GatewayPolicies[key]; ok {
+			gatewayResources.GatewayPolicies[splitKey] = policy
+		}
+
+		splitRef := provenance.ObjectRef{Kind: "Gateway", NamespacedName: splitKey}
+		for _, source := range provenance.ProvenanceAggr.ObjectSources(gatewayRef)[""] {
+			provenance.ProvenanceAggr.Record(splitRef, "", source)
+		}
+		for _, r := range split.routes {
+			route := routes[r]
+			reparentRoute(gatewayResourcesByProvider[route.providerName], route.kind, route.key, key, splitKey)
+		}
+		names = append(names, fmt.Sprintf("%s (%d routes)", splitKey, len(split.routes)))
+		if len(split.routes) > maxRoutes {
+			oversized = append(oversized, splitKey.String())
+		}
+	}
+	delete(gatewayResources.Gateways, key)
+	delete(gatewayResources.GatewayPolicies, key)
+	provenance.ProvenanceAggr.Delete(gatewayRef)
+
+	notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+		Type:           notifications.InfoNotification,
+		Message:        fmt.Sprintf("Gateway %s has %d routes, over the maximum of %d, it is split into Gateways %s, each with the listeners of its routes", key, len(routes), maxRoutes, strings.Join(names, ", ")),
+		CallingObjects: []client.Object{&gateway},
+	}, string(providerName))
+	if len(oversized) > 0 {
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("Gateways %s still have more than %d routes, as their routes are attached to the same listeners and cannot be split", strings.Join(oversized, ", "), maxRoutes),
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+	}
+}
+
+// distributeRoutes groups the routes of the Gateway attached to the same
+// listeners, and distributes the groups, in the order of their first route, on
+// as few Gateways of at most maxRoutes routes as keeps that order. A group of
+// more than maxRoutes routes gets a Gateway of its own.
+func distributeRoutes(gateway gatewayv1.Gateway, key types.NamespacedName, routes []providerRoute, maxRoutes int) []gatewaySplit {
+	// groups is a union-find of the routes sharing a listener.
+	groups := make([]int, len(routes))
+	for i := range groups {
+		groups[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if groups[i] != i {
+			groups[i] = find(groups[i])
+		}
+		return groups[i]
+	}
+	routeListeners := make([][]int, len(routes))
+	listenerRoute := map[int]int{}
+	for r, route := range routes {
+		for l, listener := range gateway.Spec.Listeners {
+			if !slices.Contains(routeKindsByProtocol[listener.Protocol], route.kind) || !routeAttaches(route.attachedRoute, key, listener) {
+				continue
+			}
+			routeListeners[r] = append(routeListeners[r], l)
+			if other, ok := listenerRoute[l]; ok {
+				a, b := find(other), find(r)
+				groups[max(a, b)] = min(a, b)
+			} else {
+				listenerRoute[l] = r
+			}
+		}
+	}
+
+	var order []int
+	members := map[int][]int{}
+	for r := range routes {
+		group := find(r)
+		if _, ok := members[group]; !ok {
+			order = append(order, group)
+		}
+		members[group] = append(members[group], r)
+	}
+
+	var splits []gatewaySplit
+	for _, group := range order {
+		if len(splits) == 0 || len(splits[len(splits)-1].routes)+len(members[group]) > maxRoutes {
+			splits = append(splits, gatewaySplit{})
+		}
+		split := &splits[len(splits)-1]
+		split.routes = append(split.routes, members[group]...)
+	}
+	for i := range splits {
+		listeners := map[int]bool{}
+		for _, r := range splits[i].routes {
+			for _, l := range routeListeners[r] {
+				listeners[l] = true
+			}
+		}
+		for l := range gateway.Spec.Listeners {
+			if _, ok := listenerRoute[l]; listeners[l] || (i == 0 && !ok) {
+				splits[i].listeners = append(splits[i].listeners, l)
+			}
+		}
+	}
+	return splits
+}
+
+// reparentRoute replaces the parentRefs to the Gateway from of the route with
+// parentRefs to the Gateway to.
+func reparentRoute(gatewayResources GatewayResources, kind gatewayv1.Kind, key, from, to types.NamespacedName) {
+	switch kind {
+	case "HTTPRoute":
+		route := gatewayResources.HTTPRoutes[key]
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+	case "GRPCRoute":
+		route := gatewayResources.GRPCRoutes[key]
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+	case "TLSRoute":
+		route := gatewayResources.TLSRoutes[key]
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+	case "TCPRoute":
+		route := gatewayResources.TCPRoutes[key]
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+	case "UDPRoute":
+		route := gatewayResources.UDPRoutes[key]
+		reparent(route.Spec.ParentRefs, route.Namespace, from, to)
+	}
+}
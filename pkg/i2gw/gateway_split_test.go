@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_splitGateways(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	hosts := []string{"a.example.com", "b.example.com", "c.example.com"}
+
+	gateway := gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx"},
+	}
+	httpRoutes := map[types.NamespacedName]gatewayv1.HTTPRoute{}
+	for _, host := range hosts {
+		gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(host + "-http"),
+			Hostname: ptr.To(gatewayv1.Hostname(host)),
+			Port:     80,
+			Protocol: gatewayv1.HTTPProtocolType,
+		})
+		// Two routes per host, which share its listener.
+		for _, path := range []string{"api", "web"} {
+			key := types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("%s-%s", host, path)}
+			httpRoutes[key] = gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}},
+					Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(host)},
+				},
+			}
+		}
+	}
+	gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{Name: "unused-https", Hostname: ptr.To(gatewayv1.Hostname("d.example.com")), Port: 443, Protocol: gatewayv1.HTTPSProtocolType})
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"provider-a": {Gateways: map[types.NamespacedName]gatewayv1.Gateway{gatewayKey: gateway}},
+		"provider-b": {HTTPRoutes: httpRoutes},
+	}
+
+	splitGateways(gatewayResourcesByProvider, 4)
+
+	gateways := gatewayResourcesByProvider["provider-a"].Gateways
+	if _, ok := gateways[gatewayKey]; ok {
+		t.Errorf("Expected Gateway %s to be replaced by the split Gateways", gatewayKey)
+	}
+	gotListeners := map[string][]gatewayv1.SectionName{}
+	for key, gateway := range gateways {
+		for _, listener := range gateway.Spec.Listeners {
+			gotListeners[key.Name] = append(gotListeners[key.Name], listener.Name)
+		}
+	}
+	expectedListeners := map[string][]gatewayv1.SectionName{
+		"nginx-1": {"a.example.com-http", "b.example.com-http", "unused-https"},
+		"nginx-2": {"c.example.com-http"},
+	}
+	if diff := cmp.Diff(expectedListeners, gotListeners); diff != "" {
+		t.Errorf("Unexpected listeners of the split Gateways (-want +got):\n%s", diff)
+	}
+
+	expectedParents := map[string]gatewayv1.ObjectName{
+		"a.example.com-api": "nginx-1",
+		"a.example.com-web": "nginx-1",
+		"b.example.com-api": "nginx-1",
+		"b.example.com-web": "nginx-1",
+		"c.example.com-api": "nginx-2",
+		"c.example.com-web": "nginx-2",
+	}
+	gotParents := map[string]gatewayv1.ObjectName{}
+	for key, route := range gatewayResourcesByProvider["provider-b"].HTTPRoutes {
+		gotParents[key.Name] = route.Spec.ParentRefs[0].Name
+	}
+	if diff := cmp.Diff(expectedParents, gotParents); diff != "" {
+		t.Errorf("Unexpected parents of the routes (-want +got):\n%s", diff)
+	}
+
+	notifs := notifications.NotificationAggr.Notifications["provider-a"]
+	if len(notifs) != 1 || notifs[0].Type != notifications.InfoNotification {
+		t.Errorf("Expected a single Info notification describing the split, got %+v", notifs)
+	}
+}
+
+func Test_splitGateways_sharedListener(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	httpRoutes := map[types.NamespacedName]gatewayv1.HTTPRoute{}
+	for i := 0; i < 3; i++ {
+		key := types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("route-%d", i)}
+		httpRoutes[key] = gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx"}}}},
+		}
+	}
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{
+		"provider-a": {
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{gatewayKey: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+				Spec:       gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}}},
+			}},
+			HTTPRoutes: httpRoutes,
+		},
+	}
+
+	splitGateways(gatewayResourcesByProvider, 2)
+
+	gateways := gatewayResourcesByProvider["provider-a"].Gateways
+	if _, ok := gateways[gatewayKey]; len(gateways) != 1 || !ok {
+		t.Errorf("Expected Gateway %s to be kept, got %+v", gatewayKey, gateways)
+	}
+	notifs := notifications.NotificationAggr.Notifications["provider-a"]
+	if len(notifs) != 1 || notifs[0].Type != notifications.WarningNotification {
+		t.Errorf("Expected a single Warning notification, got %+v", notifs)
+	}
+}
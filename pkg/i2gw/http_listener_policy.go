@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// HTTPListenerPolicyBoth serves the hosts with TLS over both HTTP and
+	// HTTPS, like Ingress controllers do without redirect annotations.
+	HTTPListenerPolicyBoth = "both"
+	// HTTPListenerPolicyRedirect redirects the HTTP requests of the hosts with
+	// TLS to HTTPS.
+	HTTPListenerPolicyRedirect = "redirect"
+	// HTTPListenerPolicyHTTPSOnly serves the hosts with TLS over HTTPS only.
+	HTTPListenerPolicyHTTPSOnly = "https-only"
+)
+
+// supportedHTTPListenerPolicies are the values of the --http-listener-policy
+// flag.
+var supportedHTTPListenerPolicies = []string{HTTPListenerPolicyBoth, HTTPListenerPolicyRedirect, HTTPListenerPolicyHTTPSOnly}
+
+// hostListeners are the HTTP and HTTPS listeners of a Gateway for the same
+// hostname.
+type hostListeners struct {
+	hostname string
+	http     gatewayv1.SectionName
+	https    gatewayv1.SectionName
+}
+
+// applyHTTPListenerPolicy sets how the hosts served by both an HTTP and an
+// HTTPS listener of a generated Gateway are served over HTTP.
+//
+// With HTTPListenerPolicyBoth, the HTTPRoutes attached to the Gateway without
+// sectionName apply to both listeners, so nothing changes. Otherwise, the
+// HTTPRoutes of the host attached without sectionName are attached to the
+// HTTPS listener by name. With HTTPListenerPolicyRedirect, an HTTPRoute named
+// `<route>-http-redirect`, attached to the HTTP listener, redirects the
+// requests to HTTPS with a 301. With HTTPListenerPolicyHTTPSOnly, the HTTP
+// listener is removed, unless a route still references it by name. An Info
+// notification is emitted for every host.
+func applyHTTPListenerPolicy(gatewayResources *GatewayResources, policy string, providerName ProviderName) {
+	if policy == "" || policy == HTTPListenerPolicyBoth {
+		return
+	}
+
+	gatewayKeys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+	for key := range gatewayResources.Gateways {
+		gatewayKeys = append(gatewayKeys, key)
+	}
+	sort.Slice(gatewayKeys, func(i, j int) bool { return gatewayKeys[i].String() < gatewayKeys[j].String() })
+	routeKeys := make([]types.NamespacedName, 0, len(gatewayResources.HTTPRoutes))
+	for key := range gatewayResources.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	sort.Slice(routeKeys, func(i, j int) bool { return routeKeys[i].String() < routeKeys[j].String() })
+
+	for _, gatewayKey := range gatewayKeys {
+		gateway := gatewayResources.Gateways[gatewayKey]
+		for _, host := range dualSchemeHosts(gateway) {
+			var attached []types.NamespacedName
+			for _, routeKey := range routeKeys {
+				route := gatewayResources.HTTPRoutes[routeKey]
+				if !servesHostname(route, host.hostname) {
+					continue
+				}
+				var changed bool
+				for i, parentRef := range route.Spec.ParentRefs {
+					if parentRef.SectionName != nil || parentRef.Port != nil || !refersToGateway(parentRef, route.Namespace, gatewayKey) {
+						continue
+					}
+					route.Spec.ParentRefs[i].SectionName = ptr.To(host.https)
+					changed = true
+				}
+				if !changed {
+					continue
+				}
+				gatewayResources.HTTPRoutes[routeKey] = route
+				attached = append(attached, routeKey)
+			}
+			if len(attached) == 0 {
+				continue
+			}
+			attachedNames := make([]string, 0, len(attached))
+			for _, routeKey := range attached {
+				attachedNames = append(attachedNames, routeKey.String())
+			}
+
+			message := fmt.Sprintf("the HTTPRoutes %s of host %q were attached to the HTTPS listener %s of Gateway %s only", strings.Join(attachedNames, ", "), host.hostname, host.https, gatewayKey)
+			switch policy {
+			case HTTPListenerPolicyRedirect:
+				for _, routeKey := range attached {
+					redirectKey := addHTTPRedirectRoute(gatewayResources, gatewayResources.HTTPRoutes[routeKey], gatewayKey, host.http)
+					message += fmt.Sprintf(", HTTPRoute %s redirects the requests of the HTTP listener %s to HTTPS", redirectKey, host.http)
+				}
+			case HTTPListenerPolicyHTTPSOnly:
+				if isListenerReferenced(gatewayResources, gatewayKey, host.http) {
+					message += fmt.Sprintf(", the HTTP listener %s is kept, as routes reference it", host.http)
+					break
+				}
+				for i, listener := range gateway.Spec.Listeners {
+					if listener.Name == host.http {
+						gateway.Spec.Listeners = append(gateway.Spec.Listeners[:i], gateway.Spec.Listeners[i+1:]...)
+						break
+					}
+				}
+				gatewayResources.Gateways[gatewayKey] = gateway
+				message += fmt.Sprintf(", the HTTP listener %s was removed", host.http)
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("%s, as set by --http-listener-policy %s", message, policy),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+	}
+}
+
+// dualSchemeHosts returns the hostnames of the Gateway served by both an HTTP
+// and an HTTPS listener.
+func dualSchemeHosts(gateway gatewayv1.Gateway) []hostListeners {
+	httpListeners := map[string]gatewayv1.SectionName{}
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Protocol == gatewayv1.HTTPProtocolType {
+			httpListeners[listenerHostname(listener)] = listener.Name
+		}
+	}
+	var hosts []hostListeners
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		hostname := listenerHostname(listener)
+		if httpListener, ok := httpListeners[hostname]; ok {
+			hosts = append(hosts, hostListeners{hostname: hostname, http: httpListener, https: listener.Name})
+		}
+	}
+	return hosts
+}
+
+func listenerHostname(listener gatewayv1.Listener) string {
+	if listener.Hostname == nil {
+		return ""
+	}
+	return string(*listener.Hostname)
+}
+
+// servesHostname returns whether the HTTPRoute serves the hostname only. A
+// route without hostnames serves the listeners without hostname.
+func servesHostname(route gatewayv1.HTTPRoute, hostname string) bool {
+	if len(route.Spec.Hostnames) == 0 {
+		return hostname == ""
+	}
+	return len(route.Spec.Hostnames) == 1 && string(route.Spec.Hostnames[0]) == hostname
+}
+
+// isListenerReferenced returns whether a route references the listener of the
+// Gateway by name.
+func isListenerReferenced(gatewayResources *GatewayResources, gatewayKey types.NamespacedName, listener gatewayv1.SectionName) bool {
+	var parentRefs [][]gatewayv1.ParentReference
+	var namespaces []string
+	for _, route := range gatewayResources.HTTPRoutes {
+		parentRefs, namespaces = append(parentRefs, route.Spec.ParentRefs), append(namespaces, route.Namespace)
+	}
+	for _, route := range gatewayResources.GRPCRoutes {
+		parentRefs, namespaces = append(parentRefs, route.Spec.ParentRefs), append(namespaces, route.Namespace)
+	}
+	for i, refs := range parentRefs {
+		for _, parentRef := range refs {
+			if parentRef.SectionName != nil && *parentRef.SectionName == listener && refersToGateway(parentRef, namespaces[i], gatewayKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// httpRedirectRouteSuffix is the suffix of the name of the HTTPRoutes added by
+// addHTTPRedirectRoute to the one of the route.
+const httpRedirectRouteSuffix = "-http-redirect"
+
+// addHTTPRedirectRoute adds an HTTPRoute redirecting the requests of the
+// route hostnames on the HTTP listener to HTTPS, and returns its key.
+func addHTTPRedirectRoute(gatewayResources *GatewayResources, route gatewayv1.HTTPRoute, gatewayKey types.NamespacedName, listener gatewayv1.SectionName) types.NamespacedName {
+	parentRef := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: ptr.To(listener)}
+	if gatewayKey.Namespace != route.Namespace {
+		parentRef.Namespace = ptr.To(gatewayv1.Namespace(gatewayKey.Namespace))
+	}
+	redirectKey := types.NamespacedName{Namespace: route.Namespace, Name: route.Name + httpRedirectRouteSuffix}
+	redirectRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: redirectKey.Namespace, Name: redirectKey.Name},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}},
+			Hostnames:       route.Spec.Hostnames,
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						Scheme:     ptr.To("https"),
+						StatusCode: ptr.To(301),
+					},
+				}},
+			}},
+		},
+		Status: gatewayv1.HTTPRouteStatus{
+			RouteStatus: gatewayv1.RouteStatus{
+				Parents: []gatewayv1.RouteParentStatus{},
+			},
+		},
+	}
+	redirectRoute.SetGroupVersionKind(route.GroupVersionKind())
+	gatewayResources.HTTPRoutes[redirectKey] = redirectRoute
+
+	routeRef := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: route.Namespace, Name: route.Name}}
+	redirectRef := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: redirectKey}
+	for _, source := range provenance.ProvenanceAggr.ObjectSources(routeRef)[""] {
+		provenance.ProvenanceAggr.Record(redirectRef, "", source)
+	}
+	return redirectKey
+}
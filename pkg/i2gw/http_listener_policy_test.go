@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_applyHTTPListenerPolicy(t *testing.T) {
+	tls := &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "example-cert"}}}
+	listeners := []gatewayv1.Listener{
+		{Name: "example-com-http", Hostname: ptr.To(gatewayv1.Hostname("example.com")), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+		{Name: "example-com-https", Hostname: ptr.To(gatewayv1.Hostname("example.com")), Port: 443, Protocol: gatewayv1.HTTPSProtocolType, TLS: tls},
+		{Name: "example-net-http", Hostname: ptr.To(gatewayv1.Hostname("example.net")), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+	}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	route := func(name, hostname string, sectionName *gatewayv1.SectionName) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: sectionName}}},
+				Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(hostname)},
+			},
+		}
+	}
+	redirectRoute := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-example-com-http-redirect"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptr.To(gatewayv1.SectionName("example-com-http"))}}},
+			Hostnames:       []gatewayv1.Hostname{"example.com"},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+				}},
+			}},
+		},
+		Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{}}},
+	}
+
+	testCases := []struct {
+		name                  string
+		policy                string
+		routes                []gatewayv1.HTTPRoute
+		expectedListeners     []gatewayv1.Listener
+		expectedRoutes        []gatewayv1.HTTPRoute
+		expectedNotifications int
+	}{
+		{
+			name:              "both",
+			policy:            HTTPListenerPolicyBoth,
+			routes:            []gatewayv1.HTTPRoute{route("foo-example-com", "example.com", nil)},
+			expectedListeners: listeners,
+			expectedRoutes:    []gatewayv1.HTTPRoute{route("foo-example-com", "example.com", nil)},
+		},
+		{
+			name:              "redirect",
+			policy:            HTTPListenerPolicyRedirect,
+			routes:            []gatewayv1.HTTPRoute{route("foo-example-com", "example.com", nil), route("foo-example-net", "example.net", nil)},
+			expectedListeners: listeners,
+			expectedRoutes: []gatewayv1.HTTPRoute{
+				route("foo-example-com", "example.com", ptr.To(gatewayv1.SectionName("example-com-https"))),
+				redirectRoute,
+				route("foo-example-net", "example.net", nil),
+			},
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "https only",
+			policy:                HTTPListenerPolicyHTTPSOnly,
+			routes:                []gatewayv1.HTTPRoute{route("foo-example-com", "example.com", nil)},
+			expectedListeners:     listeners[1:],
+			expectedRoutes:        []gatewayv1.HTTPRoute{route("foo-example-com", "example.com", ptr.To(gatewayv1.SectionName("example-com-https")))},
+			expectedNotifications: 1,
+		},
+		{
+			name:   "https only with a route referencing the HTTP listener",
+			policy: HTTPListenerPolicyHTTPSOnly,
+			routes: []gatewayv1.HTTPRoute{
+				route("foo-example-com", "example.com", nil),
+				route("bar-example-com", "example.com", ptr.To(gatewayv1.SectionName("example-com-http"))),
+			},
+			expectedListeners: listeners,
+			expectedRoutes: []gatewayv1.HTTPRoute{
+				route("bar-example-com", "example.com", ptr.To(gatewayv1.SectionName("example-com-http"))),
+				route("foo-example-com", "example.com", ptr.To(gatewayv1.SectionName("example-com-https"))),
+			},
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			gatewayResources := GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					gatewayKey: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+						Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: append([]gatewayv1.Listener{}, listeners...)},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{},
+			}
+			for _, route := range tc.routes {
+				gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+			}
+
+			applyGatewayOptions(&gatewayResources, GatewayOptions{HTTPListenerPolicy: tc.policy}, "test-provider")
+
+			if diff := cmp.Diff(tc.expectedListeners, gatewayResources.Gateways[gatewayKey].Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+			}
+			expectedRoutes := map[types.NamespacedName]gatewayv1.HTTPRoute{}
+			for _, route := range tc.expectedRoutes {
+				expectedRoutes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+			}
+			if diff := cmp.Diff(expectedRoutes, gatewayResources.HTTPRoutes); diff != "" {
+				t.Errorf("Unexpected HTTPRoutes (-want +got):\n%s", diff)
+			}
+			if got := notifications.NotificationAggr.Notifications["test-provider"]; len(got) != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %+v", tc.expectedNotifications, got)
+			}
+		})
+	}
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"time"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -31,11 +34,21 @@ import (
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
-func ToGatewayAPIResources(ctx context.Context, namespace string, inputFile string, providers []string, providerSpecificFlags map[string]map[string]string) ([]GatewayResources, map[string]string, error) {
+func ToGatewayAPIResources(ctx context.Context, namespace string, inputFile string, providers []string, providerSpecificFlags map[string]map[string]string, gatewayOptions GatewayOptions) ([]GatewayResources, map[string]string, error) {
+	if err := gatewayOptions.Validate(); err != nil {
+		return nil, nil, err
+	}
+	metrics, err := newConversionMetrics(gatewayOptions.MetricsRegisterer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register the conversion metrics: %w", err)
+	}
+	defer metrics.observeDuration(time.Now())
+	defer metrics.observeNotifications(notificationCounts())
+
 	var clusterClient client.Client
 
 	if inputFile == "" {
-		conf, err := config.GetConfig()
+		conf, err := config.GetConfigWithContext(gatewayOptions.KubeContext)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get client config: %w", err)
 		}
@@ -45,60 +58,117 @@ func ToGatewayAPIResources(ctx context.Context, namespace string, inputFile stri
 			return nil, nil, fmt.Errorf("failed to create client: %w", err)
 		}
 		clusterClient = client.NewNamespacedClient(cl, namespace)
+		if !gatewayOptions.ModifiedSince.IsZero() {
+			clusterClient = newModifiedSinceClient(clusterClient, gatewayOptions.ModifiedSince)
+		}
+		clusterClient = newCachingClient(clusterClient)
 	}
 
 	providerByName, err := constructProviders(&ProviderConf{
 		Client:                clusterClient,
 		Namespace:             namespace,
 		ProviderSpecificFlags: providerSpecificFlags,
+		Providers:             providers,
+		Progress:              gatewayOptions.Progress,
 	}, providers)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if inputFile != "" {
-		if err = readProviderResourcesFromFile(ctx, providerByName, inputFile); err != nil {
+		klog.V(1).Infof("Reading the resources of providers %v from file %s", providers, inputFile)
+		if err = readProviderResourcesFromFile(ctx, providerByName, inputFile, gatewayOptions.ReadConcurrency, gatewayOptions.Progress); err != nil {
 			return nil, nil, err
 		}
 	} else {
-		if err = readProviderResourcesFromCluster(ctx, providerByName); err != nil {
+		klog.V(1).Infof("Reading the resources of providers %v from the cluster", providers)
+		if err = readProviderResourcesFromCluster(ctx, providerByName, gatewayOptions.ReadConcurrency, gatewayOptions.Progress); err != nil {
 			return nil, nil, err
 		}
 	}
 
 	var (
-		gatewayResources []GatewayResources
-		errs             field.ErrorList
+		gatewayResources           []GatewayResources
+		gatewayResourcesByProvider = map[ProviderName]GatewayResources{}
+		errs                       field.ErrorList
 	)
-	for _, provider := range providerByName {
+	// The providers are converted one at a time, in the order of their names, so
+	// that the notifications and the provenance of the generated resources are
+	// deterministic. The conversion only works on the resources read beforehand.
+	// The providers report the progress of the conversion of their Ingresses.
+	for _, name := range sortedProviderNames(providerByName) {
+		provider := providerByName[name]
+		klog.V(1).Infof("Converting the resources of provider %s", name)
 		providerGatewayResources, conversionErrs := provider.ToGatewayAPI()
 		errs = append(errs, conversionErrs...)
-		gatewayResources = append(gatewayResources, providerGatewayResources)
+		klog.V(1).Infof("Provider %s generated %d Gateways, %d HTTPRoutes and %d GRPCRoutes, with %d errors", name, len(providerGatewayResources.Gateways), len(providerGatewayResources.HTTPRoutes), len(providerGatewayResources.GRPCRoutes), len(conversionErrs))
+		errs = append(errs, applyGatewayOptions(&providerGatewayResources, gatewayOptions, name)...)
+		gatewayResourcesByProvider[name] = providerGatewayResources
+		metrics.observeConversion(name)
+	}
+	providerNames, mergeErrs := mergeProviderGateways(gatewayResourcesByProvider)
+	errs = append(errs, mergeErrs...)
+	// The certificates of the Secrets are only read from the cluster when the
+	// Secrets are verified, reading Secrets requiring their own permissions.
+	var secretsClient client.Client
+	if gatewayOptions.VerifySecrets {
+		secretsClient = clusterClient
+	}
+	certificateSANs, err := readCertificateSANs(ctx, secretsClient, inputFile, gatewayResourcesByProvider, gatewayOptions.ReadConcurrency)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the certificates of the Secrets: %w", err)
+	}
+	groupListenersByCertificateSANs(gatewayResourcesByProvider, certificateSANs)
+	normalizeListenerNames(gatewayResourcesByProvider)
+	collapseWildcardListeners(gatewayResourcesByProvider)
+	splitGateways(gatewayResourcesByProvider, gatewayOptions.MaxRoutesPerGateway)
+	errs = append(errs, setChannelAPIVersions(gatewayResourcesByProvider, gatewayOptions.Channel)...)
+	setAllowedRouteKinds(gatewayResourcesByProvider)
+	generateImplementationPolicies(gatewayResourcesByProvider, gatewayOptions.TargetImplementation)
+	warnConflictingListeners(gatewayResourcesByProvider)
+	if gatewayOptions.OwnerReference != nil {
+		setOwnerReferences(gatewayResourcesByProvider, *gatewayOptions.OwnerReference, gatewayOptions.OwnerNamespace)
+	}
+	if inputFile != "" {
+		notifyUnverifiedSecrets(gatewayResourcesByProvider)
+	} else if gatewayOptions.VerifySecrets {
+		verifySecrets(ctx, clusterClient, gatewayResourcesByProvider, gatewayOptions.ReadConcurrency, gatewayOptions.Progress)
 	}
 	notificationTablesMap := notifications.NotificationAggr.CreateNotificationTables()
 	if len(errs) > 0 {
 		return nil, notificationTablesMap, aggregatedErrs(errs)
 	}
+	for _, name := range providerNames {
+		gatewayResources = append(gatewayResources, gatewayResourcesByProvider[name])
+	}
 
 	return gatewayResources, notificationTablesMap, nil
 }
 
-func readProviderResourcesFromFile(ctx context.Context, providerByName map[ProviderName]Provider, inputFile string) error {
-	for name, provider := range providerByName {
+// readProviderResourcesFromFile reads the resources of the providers from the
+// file, with at most concurrency providers reading at the same time.
+func readProviderResourcesFromFile(ctx context.Context, providerByName map[ProviderName]Provider, inputFile string, concurrency int, progress ProgressFunc) error {
+	read := newProgressCounter(progress, len(providerByName), "Read the resources of %d/%d providers")
+	return forEachProvider(providerByName, concurrency, func(name ProviderName, provider Provider) error {
 		if err := provider.ReadResourcesFromFile(ctx, inputFile); err != nil {
 			return fmt.Errorf("failed to read %s resources from file: %w", name, err)
 		}
-	}
-	return nil
+		read.complete()
+		return nil
+	})
 }
 
-func readProviderResourcesFromCluster(ctx context.Context, providerByName map[ProviderName]Provider) error {
-	for name, provider := range providerByName {
+// readProviderResourcesFromCluster reads the resources of the providers from
+// the cluster, with at most concurrency providers reading at the same time.
+func readProviderResourcesFromCluster(ctx context.Context, providerByName map[ProviderName]Provider, concurrency int, progress ProgressFunc) error {
+	read := newProgressCounter(progress, len(providerByName), "Read the resources of %d/%d providers from the cluster")
+	return forEachProvider(providerByName, concurrency, func(name ProviderName, provider Provider) error {
 		if err := provider.ReadResourcesFromCluster(ctx); err != nil {
 			return fmt.Errorf("failed to read %s resources from the cluster: %w", name, err)
 		}
-	}
-	return nil
+		read.complete()
+		return nil
+	})
 }
 
 // constructProviders constructs a map of concrete Provider implementations
@@ -138,11 +208,13 @@ func GetSupportedProviders() []string {
 
 // MergeGatewayResources accept multiple GatewayResources and create a unique Resource struct
 // built as follows:
-//   - GatewayClasses, *Routes, and ReferenceGrants are grouped into the same maps
+//   - GatewayClasses, *Routes, ReferenceGrants, BackendTLSPolicies and the
+//     traffic and implementation policies are grouped into the same maps
 //   - Gateways may have the same NamespaceName even if they come from different
 //     ingresses, as they have a their GatewayClass' name as name. For this reason,
 //     if there are mutiple gateways named the same, their listeners are merged into
-//     a unique Gateway.
+//     a unique Gateway. Gateways named the same with different GatewayClasses
+//     cannot be merged, and an error is returned for them.
 //
 // This behavior is likely to change after https://github.com/kubernetes-sigs/gateway-api/pull/1863 takes place.
 func MergeGatewayResources(gatewayResources ...GatewayResources) (GatewayResources, field.ErrorList) {
@@ -150,10 +222,17 @@ func MergeGatewayResources(gatewayResources ...GatewayResources) (GatewayResourc
 		Gateways:        make(map[types.NamespacedName]gatewayv1.Gateway),
 		GatewayClasses:  make(map[types.NamespacedName]gatewayv1.GatewayClass),
 		HTTPRoutes:      make(map[types.NamespacedName]gatewayv1.HTTPRoute),
+		GRPCRoutes:      make(map[types.NamespacedName]gatewayv1alpha2.GRPCRoute),
 		TLSRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TLSRoute),
 		TCPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
 		UDPRoutes:       make(map[types.NamespacedName]gatewayv1alpha2.UDPRoute),
 		ReferenceGrants: make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
+
+		BackendTLSPolicies: make(map[types.NamespacedName]gatewayv1alpha2.BackendTLSPolicy),
+
+		TrafficPolicies:        make(map[types.NamespacedName]TrafficPolicy),
+		GatewayPolicies:        make(map[types.NamespacedName]GatewayPolicy),
+		ImplementationPolicies: make(map[PolicyKey]unstructured.Unstructured),
 	}
 	var errs field.ErrorList
 	mergedGatewayResources.Gateways, errs = mergeGateways(gatewayResources)
@@ -163,10 +242,17 @@ func MergeGatewayResources(gatewayResources ...GatewayResources) (GatewayResourc
 	for _, gr := range gatewayResources {
 		maps.Copy(mergedGatewayResources.GatewayClasses, gr.GatewayClasses)
 		maps.Copy(mergedGatewayResources.HTTPRoutes, gr.HTTPRoutes)
+		maps.Copy(mergedGatewayResources.GRPCRoutes, gr.GRPCRoutes)
 		maps.Copy(mergedGatewayResources.TLSRoutes, gr.TLSRoutes)
 		maps.Copy(mergedGatewayResources.TCPRoutes, gr.TCPRoutes)
 		maps.Copy(mergedGatewayResources.UDPRoutes, gr.UDPRoutes)
 		maps.Copy(mergedGatewayResources.ReferenceGrants, gr.ReferenceGrants)
+		maps.Copy(mergedGatewayResources.BackendTLSPolicies, gr.BackendTLSPolicies)
+		maps.Copy(mergedGatewayResources.TrafficPolicies, gr.TrafficPolicies)
+		for key, policy := range gr.GatewayPolicies {
+			mergedGatewayResources.GatewayPolicies[key] = mergedGatewayResources.GatewayPolicies[key].merge(policy)
+		}
+		maps.Copy(mergedGatewayResources.ImplementationPolicies, gr.ImplementationPolicies)
 	}
 	return mergedGatewayResources, errs
 }
@@ -179,6 +265,11 @@ func mergeGateways(gatewaResources []GatewayResources) (map[types.NamespacedName
 		for _, g := range gr.Gateways {
 			nn := types.NamespacedName{Namespace: g.Namespace, Name: g.Name}
 			if existingGateway, ok := newGateways[nn]; ok {
+				if existingGateway.Spec.GatewayClassName != g.Spec.GatewayClassName {
+					fieldPath := field.NewPath(fmt.Sprintf("%s/%s", nn.Namespace, nn.Name)).Child("spec").Child("gatewayClassName")
+					errs = append(errs, field.Invalid(fieldPath, g.Spec.GatewayClassName, fmt.Sprintf("error while merging gateways: the gateway has class %s in other resources", existingGateway.Spec.GatewayClassName)))
+					continue
+				}
 				g.Spec.Listeners = append(g.Spec.Listeners, existingGateway.Spec.Listeners...)
 				g.Spec.Addresses = append(g.Spec.Addresses, existingGateway.Spec.Addresses...)
 			}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -93,3 +94,41 @@ func Test_GetSupportedProviders(t *testing.T) {
 		}
 	})
 }
+
+func Test_RegisterProvider(t *testing.T) {
+	name := ProviderName("external-provider")
+	constructor := func(conf *ProviderConf) Provider { return nil }
+	t.Cleanup(func() {
+		delete(ProviderConstructorByName, name)
+		delete(ProviderResourceKindsByName, name)
+	})
+
+	ingressKind := schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}
+	RegisterProvider(name, constructor, ingressKind)
+	if _, ok := ProviderConstructorByName[name]; !ok {
+		t.Errorf("Expected provider %s to be registered", name)
+	}
+	if kinds := ProviderResourceKindsByName[name]; len(kinds) != 1 || kinds[0] != ingressKind {
+		t.Errorf("Expected provider %s to read %v, got %v", name, ingressKind, kinds)
+	}
+
+	testCases := []struct {
+		name         string
+		providerName ProviderName
+		constructor  ProviderConstructor
+	}{
+		{name: "empty name", providerName: "", constructor: constructor},
+		{name: "nil constructor", providerName: "other-provider", constructor: nil},
+		{name: "already registered", providerName: name, constructor: constructor},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected RegisterProvider to panic")
+				}
+			}()
+			RegisterProvider(tc.providerName, tc.constructor)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerKey identifies the hostname and port a listener claims. An empty
+// hostname matches all hostnames.
+type listenerKey struct {
+	hostname string
+	port     gatewayv1.PortNumber
+}
+
+// listenerClaim is a Gateway with a listener for a listenerKey.
+type listenerClaim struct {
+	gateway      *gatewayv1.Gateway
+	providerName ProviderName
+}
+
+// warnConflictingListeners emits a Warning notification for every hostname and
+// port claimed by listeners of different Gateways, possibly generated by different
+// providers, as the Gateway receiving the requests then depends on the
+// implementation. Conflicts within a single Gateway are left to the
+// implementation's listener validation.
+func warnConflictingListeners(gatewayResourcesByProvider map[ProviderName]GatewayResources) {
+	claims := map[listenerKey]map[types.NamespacedName]listenerClaim{}
+	for providerName, gatewayResources := range gatewayResourcesByProvider {
+		for gatewayKey := range gatewayResources.Gateways {
+			gateway := gatewayResources.Gateways[gatewayKey]
+			for _, listener := range gateway.Spec.Listeners {
+				key := listenerKey{port: listener.Port}
+				if listener.Hostname != nil {
+					key.hostname = string(*listener.Hostname)
+				}
+				if claims[key] == nil {
+					claims[key] = map[types.NamespacedName]listenerClaim{}
+				}
+				claims[key][gatewayKey] = listenerClaim{gateway: &gateway, providerName: providerName}
+			}
+		}
+	}
+
+	keys := make([]listenerKey, 0, len(claims))
+	for key, gateways := range claims {
+		if len(gateways) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].port != keys[j].port {
+			return keys[i].port < keys[j].port
+		}
+		return keys[i].hostname < keys[j].hostname
+	})
+
+	for _, key := range keys {
+		var names []string
+		var objects []client.Object
+		providerNames := map[ProviderName]bool{}
+		for gatewayKey, claim := range claims[key] {
+			names = append(names, gatewayKey.String())
+			objects = append(objects, claim.gateway)
+			providerNames[claim.providerName] = true
+		}
+		sort.Strings(names)
+		sort.Slice(objects, func(i, j int) bool {
+			return client.ObjectKeyFromObject(objects[i]).String() < client.ObjectKeyFromObject(objects[j]).String()
+		})
+
+		hostname := key.hostname
+		if hostname == "" {
+			hostname = "any hostname"
+		}
+		message := fmt.Sprintf("%s on port %d is claimed by listeners of several Gateways (%s), which of them receives the requests depends on the implementation", hostname, key.port, strings.Join(names, ", "))
+		for providerName := range providerNames {
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        message,
+				CallingObjects: objects,
+			}, string(providerName))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_warnConflictingListeners(t *testing.T) {
+	gateway := func(name string, listeners ...gatewayv1.Listener) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(name), Listeners: listeners},
+		}
+	}
+	listener := func(hostname string, port gatewayv1.PortNumber) gatewayv1.Listener {
+		l := gatewayv1.Listener{Name: "listener", Port: port, Protocol: gatewayv1.HTTPProtocolType}
+		if hostname != "" {
+			l.Hostname = ptr.To(gatewayv1.Hostname(hostname))
+		}
+		return l
+	}
+	resources := func(gateways ...gatewayv1.Gateway) GatewayResources {
+		r := GatewayResources{Gateways: map[types.NamespacedName]gatewayv1.Gateway{}}
+		for _, gw := range gateways {
+			r.Gateways[types.NamespacedName{Namespace: gw.Namespace, Name: gw.Name}] = gw
+		}
+		return r
+	}
+
+	testCases := []struct {
+		name                  string
+		gatewayResources      map[ProviderName]GatewayResources
+		expectedNotifications map[ProviderName][]string
+	}{
+		{
+			name: "no conflict",
+			gatewayResources: map[ProviderName]GatewayResources{
+				"provider-a": resources(
+					gateway("nginx", listener("example.com", 443)),
+					gateway("internal", listener("example.com", 80), listener("internal.example.com", 443)),
+				),
+			},
+		},
+		{
+			name: "same hostname and port in different Gateways of a provider",
+			gatewayResources: map[ProviderName]GatewayResources{
+				"provider-a": resources(
+					gateway("nginx", listener("example.com", 443), listener("foo.com", 443)),
+					gateway("internal", listener("example.com", 443)),
+				),
+			},
+			expectedNotifications: map[ProviderName][]string{
+				"provider-a": {"example.com on port 443 is claimed by listeners of several Gateways (default/internal, default/nginx)"},
+			},
+		},
+		{
+			name: "listeners without hostname across providers",
+			gatewayResources: map[ProviderName]GatewayResources{
+				"provider-a": resources(gateway("nginx", listener("", 80))),
+				"provider-b": resources(gateway("gce", listener("", 80))),
+			},
+			expectedNotifications: map[ProviderName][]string{
+				"provider-a": {"any hostname on port 80 is claimed by listeners of several Gateways (default/gce, default/nginx)"},
+				"provider-b": {"any hostname on port 80 is claimed by listeners of several Gateways (default/gce, default/nginx)"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+			warnConflictingListeners(tc.gatewayResources)
+
+			for providerName := range tc.gatewayResources {
+				notifs := notifications.NotificationAggr.Notifications[string(providerName)]
+				expected := tc.expectedNotifications[providerName]
+				if len(notifs) != len(expected) {
+					t.Fatalf("Expected %d notifications for %s, got %+v", len(expected), providerName, notifs)
+				}
+				for i, n := range notifs {
+					if n.Type != notifications.WarningNotification || !strings.HasPrefix(n.Message, expected[i]) {
+						t.Errorf("Expected a Warning starting with %q, got %s %q", expected[i], n.Type, n.Message)
+					}
+					if len(n.CallingObjects) != 2 {
+						t.Errorf("Expected the 2 conflicting Gateways as calling objects, got %d", len(n.CallingObjects))
+					}
+				}
+			}
+		})
+	}
+}
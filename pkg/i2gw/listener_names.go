@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// invalidListenerNameCharacters matches the characters which are not allowed
+// in a DNS label.
+var invalidListenerNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// normalizeListenerNames renames the listeners of the Gateways whose names are
+// not valid DNS labels, like the names generated from long hostnames, e.g.
+// <hostname>-https, and updates the sectionName of the routes referencing them,
+// of any provider. The names are lower-cased, their invalid characters replaced
+// with '-', and the ones longer than 63 characters are truncated and suffixed
+// with a hash of the name, so that they stay unique and are the same on every
+// run. An Info notification is emitted for every Gateway with renamed
+// listeners.
+func normalizeListenerNames(gatewayResourcesByProvider map[ProviderName]GatewayResources) {
+	for _, providerName := range sortedProviderNames(gatewayResourcesByProvider) {
+		gatewayResources := gatewayResourcesByProvider[providerName]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+		for key := range gatewayResources.Gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			gateway := gatewayResources.Gateways[key]
+			names := sets.New[gatewayv1.SectionName]()
+			for _, listener := range gateway.Spec.Listeners {
+				names.Insert(listener.Name)
+			}
+			renames := map[gatewayv1.SectionName]gatewayv1.SectionName{}
+			var renamed []string
+			for i, listener := range gateway.Spec.Listeners {
+				if len(validation.IsDNS1123Label(string(listener.Name))) == 0 {
+					continue
+				}
+				name := validListenerName(listener.Name, names)
+				names.Insert(name)
+				renames[listener.Name] = name
+				gateway.Spec.Listeners[i].Name = name
+				renamed = append(renamed, fmt.Sprintf("%s to %s", listener.Name, name))
+			}
+			if len(renames) == 0 {
+				continue
+			}
+			gatewayResources.Gateways[key] = gateway
+			for _, routeResources := range gatewayResourcesByProvider {
+				renameSectionNames(routeResources, key, renames)
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("the listeners of Gateway %s whose names are not valid DNS labels were renamed: %s", key, strings.Join(renamed, ", ")),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+	}
+}
+
+// validListenerName returns the DNS label the listener name is normalized to,
+// distinct from the names of the other listeners of its Gateway.
+func validListenerName(name gatewayv1.SectionName, names sets.Set[gatewayv1.SectionName]) gatewayv1.SectionName {
+	normalized := strings.Trim(invalidListenerNameCharacters.ReplaceAllString(strings.ToLower(string(name)), "-"), "-")
+	if normalized == "" {
+		normalized = "listener"
+	}
+	if len(normalized) <= validation.DNS1123LabelMaxLength && !names.Has(gatewayv1.SectionName(normalized)) {
+		return gatewayv1.SectionName(normalized)
+	}
+	// The hash is the one of the original name, as distinct names may be
+	// normalized to the same one.
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return gatewayv1.SectionName(strings.TrimRight(normalized[:min(len(normalized), validation.DNS1123LabelMaxLength-len(suffix))], "-") + suffix)
+}
+
+// renameSectionNames replaces the renamed listeners of the Gateway in the
+// parentRefs of the routes.
+func renameSectionNames(gatewayResources GatewayResources, gatewayKey types.NamespacedName, renames map[gatewayv1.SectionName]gatewayv1.SectionName) {
+	for _, route := range gatewayResources.HTTPRoutes {
+		renameParentRefSectionNames(route.Spec.ParentRefs, route.Namespace, gatewayKey, renames)
+	}
+	for _, route := range gatewayResources.GRPCRoutes {
+		renameParentRefSectionNames(route.Spec.ParentRefs, route.Namespace, gatewayKey, renames)
+	}
+	for _, route := range gatewayResources.TLSRoutes {
+		renameParentRefSectionNames(route.Spec.ParentRefs, route.Namespace, gatewayKey, renames)
+	}
+	for _, route := range gatewayResources.TCPRoutes {
+		renameParentRefSectionNames(route.Spec.ParentRefs, route.Namespace, gatewayKey, renames)
+	}
+	for _, route := range gatewayResources.UDPRoutes {
+		renameParentRefSectionNames(route.Spec.ParentRefs, route.Namespace, gatewayKey, renames)
+	}
+}
+
+func renameParentRefSectionNames(parentRefs []gatewayv1.ParentReference, routeNamespace string, gatewayKey types.NamespacedName, renames map[gatewayv1.SectionName]gatewayv1.SectionName) {
+	for i, parentRef := range parentRefs {
+		if parentRef.SectionName == nil || !refersToGateway(parentRef, routeNamespace, gatewayKey) {
+			continue
+		}
+		if name, ok := renames[*parentRef.SectionName]; ok {
+			parentRefs[i].SectionName = &name
+		}
+	}
+}
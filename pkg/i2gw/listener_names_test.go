@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_normalizeListenerNames(t *testing.T) {
+	// The hostnames only differ after the 63 characters of a listener name.
+	longPrefix := strings.Repeat("a", 30) + "." + strings.Repeat("b", 30) + "."
+	hostnames := []string{longPrefix + "first.example.com", longPrefix + "second.example.com"}
+	newResources := func() map[ProviderName]GatewayResources {
+		var listeners []gatewayv1.Listener
+		for _, hostname := range hostnames {
+			name := strings.ReplaceAll(hostname, ".", "-")
+			listeners = append(listeners,
+				gatewayv1.Listener{Name: gatewayv1.SectionName(name + "-http"), Hostname: ptr.To(gatewayv1.Hostname(hostname)), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				gatewayv1.Listener{Name: gatewayv1.SectionName(name + "-https"), Hostname: ptr.To(gatewayv1.Hostname(hostname)), Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			)
+		}
+		listeners = append(listeners, gatewayv1.Listener{Name: "example-com-http", Hostname: ptr.To(gatewayv1.Hostname("example.com")), Port: 80, Protocol: gatewayv1.HTTPProtocolType})
+		return map[ProviderName]GatewayResources{
+			"gateway-provider": {
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "default", Name: "nginx"}: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+						Spec:       gatewayv1.GatewaySpec{Listeners: listeners},
+					},
+				},
+			},
+			// The routes of another provider referencing the merged Gateway.
+			"route-provider": {
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: "default", Name: "second"}: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "second"},
+						Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+							{Name: "nginx", SectionName: ptr.To(gatewayv1.SectionName(strings.ReplaceAll(hostnames[1], ".", "-") + "-https"))},
+						}}},
+					},
+				},
+			},
+		}
+	}
+
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	gatewayResourcesByProvider := newResources()
+	normalizeListenerNames(gatewayResourcesByProvider)
+
+	listeners := gatewayResourcesByProvider["gateway-provider"].Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.Listeners
+	names := map[gatewayv1.SectionName]bool{}
+	for _, listener := range listeners {
+		if errs := validation.IsDNS1123Label(string(listener.Name)); len(errs) > 0 {
+			t.Errorf("Listener name %s is not a valid DNS label: %v", listener.Name, errs)
+		}
+		if names[listener.Name] {
+			t.Errorf("Listener name %s is not unique", listener.Name)
+		}
+		names[listener.Name] = true
+	}
+	if listeners[4].Name != "example-com-http" {
+		t.Errorf("Expected the valid listener name to be kept, got %s", listeners[4].Name)
+	}
+
+	sectionName := *gatewayResourcesByProvider["route-provider"].HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "second"}].Spec.ParentRefs[0].SectionName
+	if sectionName != listeners[3].Name {
+		t.Errorf("Expected the sectionName to reference the renamed listener %s, got %s", listeners[3].Name, sectionName)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications["gateway-provider"]
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.InfoNotification {
+		t.Errorf("Expected a single Info notification, got %+v", gotNotifications)
+	}
+
+	// The names are the same on every run.
+	rerun := newResources()
+	normalizeListenerNames(rerun)
+	for i, listener := range rerun["gateway-provider"].Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.Listeners {
+		if listener.Name != listeners[i].Name {
+			t.Errorf("Expected listener name %s on every run, got %s", listeners[i].Name, listener.Name)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// supportedListenerProtocols are the protocols of the --listener-protocol flag.
+var supportedListenerProtocols = []gatewayv1.ProtocolType{
+	gatewayv1.HTTPProtocolType,
+	gatewayv1.HTTPSProtocolType,
+	gatewayv1.TLSProtocolType,
+	gatewayv1.TCPProtocolType,
+	gatewayv1.UDPProtocolType,
+}
+
+// parseListenerProtocol parses a port and protocol of the --listener-protocol
+// flag. The protocol is case-insensitive.
+func parseListenerProtocol(port, protocol string) (gatewayv1.PortNumber, gatewayv1.ProtocolType, error) {
+	number, err := strconv.Atoi(port)
+	if err != nil || number < 1 || number > 65535 {
+		return 0, "", fmt.Errorf("invalid listener protocol %s=%s, %s is not a valid port number", port, protocol, port)
+	}
+	for _, supported := range supportedListenerProtocols {
+		if strings.EqualFold(protocol, string(supported)) {
+			return gatewayv1.PortNumber(number), supported, nil
+		}
+	}
+	return 0, "", fmt.Errorf("invalid listener protocol %s=%s, supported protocols are %v", port, protocol, supportedListenerProtocols)
+}
+
+// setListenerProtocols overrides the protocol of the listeners of the generated
+// Gateways on the given ports. As Gateway API requires TLS settings for the HTTPS
+// and TLS protocols only, the TLS settings of the listeners switched to another
+// protocol are removed, and a Warning notification is emitted for the listeners
+// switched to HTTPS or TLS without TLS settings.
+func setListenerProtocols(gatewayResources *GatewayResources, listenerProtocols map[string]string, providerName ProviderName) {
+	protocolByPort := map[gatewayv1.PortNumber]gatewayv1.ProtocolType{}
+	for port, protocol := range listenerProtocols {
+		// The options are validated before the conversion.
+		number, parsed, err := parseListenerProtocol(port, protocol)
+		if err == nil {
+			protocolByPort[number] = parsed
+		}
+	}
+
+	for gatewayKey, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		var changed bool
+		for i, listener := range gateway.Spec.Listeners {
+			protocol, ok := protocolByPort[listener.Port]
+			if !ok || protocol == listener.Protocol {
+				continue
+			}
+			gateway.Spec.Listeners[i].Protocol = protocol
+			changed = true
+			message := fmt.Sprintf("the protocol of listener %s of Gateway %s/%s was overridden from %s to %s", listener.Name, gateway.Namespace, gateway.Name, listener.Protocol, protocol)
+			mType := notifications.InfoNotification
+			switch {
+			case listener.TLS != nil && protocol != gatewayv1.HTTPSProtocolType && protocol != gatewayv1.TLSProtocolType:
+				gateway.Spec.Listeners[i].TLS = nil
+				message += ", its TLS settings were removed"
+				mType = notifications.WarningNotification
+			case listener.TLS == nil && (protocol == gatewayv1.HTTPSProtocolType || protocol == gatewayv1.TLSProtocolType):
+				message += ", but it has no TLS settings: add them to the listener"
+				mType = notifications.WarningNotification
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           mType,
+				Message:        message,
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+		if changed {
+			gatewayResources.Gateways[gatewayKey] = gateway
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_setListenerProtocols(t *testing.T) {
+	tls := &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "example-com"}}}
+	listeners := []gatewayv1.Listener{
+		{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+		{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443, TLS: tls},
+		{Name: "tcp-8443", Protocol: gatewayv1.TCPProtocolType, Port: 8443},
+		{Name: "tls-9443", Protocol: gatewayv1.TLSProtocolType, Port: 9443, TLS: tls},
+	}
+
+	testCases := []struct {
+		name                  string
+		listenerProtocols     map[string]string
+		expectedListeners     []gatewayv1.Listener
+		expectedNotifications []notifications.MessageType
+	}{
+		{
+			name:              "protocols already set",
+			listenerProtocols: map[string]string{"80": "HTTP", "443": "https"},
+			expectedListeners: listeners,
+		},
+		{
+			name:              "custom HTTPS port without TLS",
+			listenerProtocols: map[string]string{"8443": "https"},
+			expectedListeners: []gatewayv1.Listener{
+				listeners[0],
+				listeners[1],
+				{Name: "tcp-8443", Protocol: gatewayv1.HTTPSProtocolType, Port: 8443},
+				listeners[3],
+			},
+			expectedNotifications: []notifications.MessageType{notifications.WarningNotification},
+		},
+		{
+			name:              "TLS removed",
+			listenerProtocols: map[string]string{"9443": "TCP"},
+			expectedListeners: []gatewayv1.Listener{
+				listeners[0],
+				listeners[1],
+				listeners[2],
+				{Name: "tls-9443", Protocol: gatewayv1.TCPProtocolType, Port: 9443},
+			},
+			expectedNotifications: []notifications.MessageType{notifications.WarningNotification},
+		},
+		{
+			name:              "TLS kept",
+			listenerProtocols: map[string]string{"443": "TLS"},
+			expectedListeners: []gatewayv1.Listener{
+				listeners[0],
+				{Name: "https", Protocol: gatewayv1.TLSProtocolType, Port: 443, TLS: tls},
+				listeners[2],
+				listeners[3],
+			},
+			expectedNotifications: []notifications.MessageType{notifications.InfoNotification},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			key := types.NamespacedName{Namespace: "default", Name: "gateway"}
+			gatewayResources := GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					key: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway"},
+						Spec:       gatewayv1.GatewaySpec{Listeners: append([]gatewayv1.Listener{}, listeners...)},
+					},
+				},
+			}
+
+			applyGatewayOptions(&gatewayResources, GatewayOptions{ListenerProtocols: tc.listenerProtocols}, "test-provider")
+
+			if diff := cmp.Diff(tc.expectedListeners, gatewayResources.Gateways[key].Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+			}
+			var gotNotifications []notifications.MessageType
+			for _, notification := range notifications.NotificationAggr.Notifications["test-provider"] {
+				gotNotifications = append(gotNotifications, notification.Type)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, gotNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// conversionMetrics are the Prometheus metrics of the conversions, for the
+// programs embedding the conversion and running it continuously, like
+// operators. The CLI does not register them.
+type conversionMetrics struct {
+	conversions   *prometheus.CounterVec
+	notifications *prometheus.CounterVec
+	duration      prometheus.Histogram
+}
+
+// newConversionMetrics registers the conversion metrics with the registerer,
+// or reuses the ones registered by a previous conversion. It returns nil if
+// there is no registerer.
+func newConversionMetrics(registerer prometheus.Registerer) (*conversionMetrics, error) {
+	if registerer == nil {
+		return nil, nil
+	}
+	conversions, err := registerCollector(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress2gateway_conversions_total",
+		Help: "Number of conversions of the resources of a provider to Gateway API resources.",
+	}, []string{"provider"}))
+	if err != nil {
+		return nil, err
+	}
+	notificationCounter, err := registerCollector(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingress2gateway_notifications_total",
+		Help: "Number of notifications emitted by the conversions, by provider and severity.",
+	}, []string{"provider", "severity"}))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := registerCollector(registerer, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ingress2gateway_conversion_duration_seconds",
+		Help:    "Duration of the conversions, from the reading of the resources to the generated Gateway API resources.",
+		Buckets: prometheus.DefBuckets,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &conversionMetrics{conversions: conversions, notifications: notificationCounter, duration: duration}, nil
+}
+
+// registerCollector registers the collector, or returns the one of the same
+// name already registered.
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	return collector, err
+}
+
+// observeConversion counts the conversion of the resources of the provider.
+func (m *conversionMetrics) observeConversion(providerName ProviderName) {
+	if m == nil {
+		return
+	}
+	m.conversions.WithLabelValues(string(providerName)).Inc()
+}
+
+// observeNotifications counts the notifications of every provider dispatched
+// after the first counts ones, which were dispatched before the conversion.
+func (m *conversionMetrics) observeNotifications(counts map[string]int) {
+	if m == nil {
+		return
+	}
+	for provider, providerNotifications := range notifications.NotificationAggr.Notifications {
+		for _, notification := range providerNotifications[min(counts[provider], len(providerNotifications)):] {
+			m.notifications.WithLabelValues(provider, string(notification.Type)).Inc()
+		}
+	}
+}
+
+// observeDuration records the duration of the conversion started at start.
+func (m *conversionMetrics) observeDuration(start time.Time) {
+	if m == nil {
+		return
+	}
+	m.duration.Observe(time.Since(start).Seconds())
+}
+
+// notificationCounts returns the number of notifications of every provider.
+func notificationCounts() map[string]int {
+	counts := map[string]int{}
+	for provider, providerNotifications := range notifications.NotificationAggr.Notifications {
+		counts[provider] = len(providerNotifications)
+	}
+	return counts
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// notifyingProvider is a Provider converting no resources, with an Info
+// notification.
+type notifyingProvider struct{}
+
+func (notifyingProvider) ReadResourcesFromCluster(context.Context) error { return nil }
+
+func (notifyingProvider) ReadResourcesFromFile(context.Context, string) error { return nil }
+
+func (notifyingProvider) ToGatewayAPI() (GatewayResources, field.ErrorList) {
+	notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+		Type:           notifications.InfoNotification,
+		Message:        "converted",
+		CallingObjects: []client.Object{},
+	}, "metrics-provider")
+	return GatewayResources{}, nil
+}
+
+func Test_conversionMetrics(t *testing.T) {
+	ProviderConstructorByName["metrics-provider"] = func(*ProviderConf) Provider { return notifyingProvider{} }
+	defer delete(ProviderConstructorByName, "metrics-provider")
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	if err := os.WriteFile(inputFile, nil, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	registry := prometheus.NewRegistry()
+	// The metrics registered by the first conversion are updated by the second.
+	for i := 0; i < 2; i++ {
+		if _, _, err := ToGatewayAPIResources(context.Background(), "", inputFile, []string{"metrics-provider"}, nil, GatewayOptions{MetricsRegisterer: registry}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	metrics, err := newConversionMetrics(registry)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.conversions.WithLabelValues("metrics-provider")); got != 2 {
+		t.Errorf("Expected 2 conversions, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.notifications.WithLabelValues("metrics-provider", string(notifications.InfoNotification))); got != 2 {
+		t.Errorf("Expected 2 Info notifications, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.duration); got != 1 {
+		t.Errorf("Expected the conversion duration histogram, got %d metrics", got)
+	}
+}
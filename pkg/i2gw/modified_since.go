@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// modifiedSinceClient is a client.Client that only lists the Ingresses modified
+// since a point in time, so that repeated conversions of large clusters can focus
+// on the Ingresses changed since the previous run. Other resources are listed
+// unfiltered, as they are needed to convert the Ingresses.
+type modifiedSinceClient struct {
+	client.Client
+	since time.Time
+}
+
+func newModifiedSinceClient(cl client.Client, since time.Time) client.Client {
+	return &modifiedSinceClient{Client: cl, since: since}
+}
+
+func (c *modifiedSinceClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if ingressList, ok := list.(*networkingv1.IngressList); ok {
+		ingressList.Items = filterIngressesModifiedSince(ingressList.Items, c.since)
+	}
+	return nil
+}
+
+// filterIngressesModifiedSince returns the Ingresses modified since the given time,
+// along with the Ingresses sharing a host with them in the same namespace: the
+// routes of a host are generated from all of its Ingresses, and converting only
+// the modified ones would produce incomplete routes.
+func filterIngressesModifiedSince(ingresses []networkingv1.Ingress, since time.Time) []networkingv1.Ingress {
+	type namespacedHost struct{ namespace, host string }
+	modifiedHosts := map[namespacedHost]bool{}
+	for _, ingress := range ingresses {
+		if lastModified(&ingress).Before(since) {
+			continue
+		}
+		for _, host := range ingressHosts(ingress) {
+			modifiedHosts[namespacedHost{ingress.Namespace, host}] = true
+		}
+	}
+
+	var filtered []networkingv1.Ingress
+	for _, ingress := range ingresses {
+		for _, host := range ingressHosts(ingress) {
+			if modifiedHosts[namespacedHost{ingress.Namespace, host}] {
+				filtered = append(filtered, ingress)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// lastModified returns the last time the object was created or updated, according
+// to its managed fields. Status updates are ignored, as they do not affect the
+// conversion.
+func lastModified(obj metav1.Object) time.Time {
+	modified := obj.GetCreationTimestamp().Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource == "status" || entry.Time == nil {
+			continue
+		}
+		if entry.Time.After(modified) {
+			modified = entry.Time.Time
+		}
+	}
+	return modified
+}
+
+// ingressHosts returns the hosts of the Ingress rules, the empty host standing
+// for the rules without host and the default backend.
+func ingressHosts(ingress networkingv1.Ingress) []string {
+	var hosts []string
+	if ingress.Spec.DefaultBackend != nil || len(ingress.Spec.Rules) == 0 {
+		hosts = append(hosts, "")
+	}
+	for _, rule := range ingress.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_filterIngressesModifiedSince(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(since.Add(-time.Hour))
+	after := metav1.NewTime(since.Add(time.Hour))
+
+	ingress := func(namespace, name, host string, created metav1.Time, managedFields ...metav1.ManagedFieldsEntry) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: created, ManagedFields: managedFields},
+			Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: host}}},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		ingresses     []networkingv1.Ingress
+		expectedNames []string
+	}{
+		{
+			name: "created before and after",
+			ingresses: []networkingv1.Ingress{
+				ingress("default", "old", "old.example.com", before),
+				ingress("default", "new", "new.example.com", after),
+			},
+			expectedNames: []string{"new"},
+		},
+		{
+			name: "updated after creation",
+			ingresses: []networkingv1.Ingress{
+				ingress("default", "updated", "updated.example.com", before, metav1.ManagedFieldsEntry{Manager: "kubectl", Time: &after}),
+			},
+			expectedNames: []string{"updated"},
+		},
+		{
+			name: "status updates are ignored",
+			ingresses: []networkingv1.Ingress{
+				ingress("default", "status", "status.example.com", before, metav1.ManagedFieldsEntry{Manager: "nginx-ingress-controller", Subresource: "status", Time: &after}),
+			},
+		},
+		{
+			name: "unmodified Ingresses sharing a host with a modified one are kept",
+			ingresses: []networkingv1.Ingress{
+				ingress("default", "production", "echo.example.com", before),
+				ingress("default", "canary", "echo.example.com", after),
+				ingress("other", "production", "echo.example.com", before),
+			},
+			expectedNames: []string{"production", "canary"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotNames []string
+			for _, ingress := range filterIngressesModifiedSince(tc.ingresses, since) {
+				gotNames = append(gotNames, ingress.Name)
+			}
+			if diff := cmp.Diff(tc.expectedNames, gotNames); diff != "" {
+				t.Errorf("Unexpected Ingresses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_modifiedSinceClient(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	objects := []runtime.Object{
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "old", CreationTimestamp: metav1.NewTime(since.Add(-time.Hour))},
+			Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "old.example.com"}}},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new", CreationTimestamp: metav1.NewTime(since.Add(time.Minute))},
+			Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "new.example.com"}}},
+		},
+	}
+	cl := newModifiedSinceClient(fake.NewClientBuilder().WithRuntimeObjects(objects...).Build(), since)
+
+	var ingressList networkingv1.IngressList
+	if err := cl.List(context.Background(), &ingressList); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if len(ingressList.Items) != 1 || ingressList.Items[0].Name != "new" {
+		t.Errorf("Expected only Ingress default/new to be listed, got %+v", ingressList.Items)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateOwnerReference returns an error if the owner reference misses one of
+// the fields identifying the owner.
+func validateOwnerReference(owner metav1.OwnerReference) error {
+	if owner.APIVersion == "" || owner.Kind == "" || owner.Name == "" || owner.UID == "" {
+		return fmt.Errorf("invalid owner reference %s/%s %s, the apiVersion, kind, name and uid must be set", owner.APIVersion, owner.Kind, owner.Name)
+	}
+	return nil
+}
+
+// validateOwnerNamespace returns an error if the namespace of the owner is not
+// a valid namespace name.
+func validateOwnerNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid owner namespace %s: %s", namespace, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// setOwnerReferences sets the owner reference as the only owner of the
+// generated resources of the owner namespace, replacing the owner references
+// the providers may have copied from their source resources. As Kubernetes only
+// resolves a namespaced owner in the namespace of its dependents, the resources
+// of the other namespaces, and the cluster-scoped GatewayClasses, keep no owner
+// and a Warning notification is emitted for each of them. A cluster-scoped
+// owner, of an empty namespace, owns all the generated resources.
+func setOwnerReferences(gatewayResourcesByProvider map[ProviderName]GatewayResources, owner metav1.OwnerReference, ownerNamespace string) {
+	for providerName, gatewayResources := range gatewayResourcesByProvider {
+		setObjectsOwnerReference(gatewayResources.GatewayClasses, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.Gateways, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.HTTPRoutes, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.GRPCRoutes, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.TLSRoutes, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.TCPRoutes, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.UDPRoutes, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.ReferenceGrants, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.BackendTLSPolicies, owner, ownerNamespace, providerName)
+		setObjectsOwnerReference(gatewayResources.ImplementationPolicies, owner, ownerNamespace, providerName)
+	}
+}
+
+func setObjectsOwnerReference[K comparable, T any, PT interface {
+	*T
+	client.Object
+}](objects map[K]T, owner metav1.OwnerReference, ownerNamespace string, providerName ProviderName) {
+	for key, object := range objects {
+		obj := PT(&object)
+		if ownerNamespace != "" && obj.GetNamespace() != ownerNamespace {
+			name, scope := client.ObjectKeyFromObject(obj).String(), fmt.Sprintf("is in namespace %s", obj.GetNamespace())
+			if obj.GetNamespace() == "" {
+				name, scope = obj.GetName(), "is cluster-scoped"
+			}
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        fmt.Sprintf("%s %s %s while its owner %s %s is in namespace %s, it is generated without an owner and is not garbage collected with it", obj.GetObjectKind().GroupVersionKind().Kind, name, scope, owner.Kind, owner.Name, ownerNamespace),
+				CallingObjects: []client.Object{obj},
+			}, string(providerName))
+			continue
+		}
+		obj.SetOwnerReferences([]metav1.OwnerReference{owner})
+		objects[key] = object
+	}
+}
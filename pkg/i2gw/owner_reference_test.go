@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// staticProvider is a Provider converting to fixed resources.
+type staticProvider struct {
+	resources GatewayResources
+}
+
+func (staticProvider) ReadResourcesFromCluster(context.Context) error { return nil }
+
+func (staticProvider) ReadResourcesFromFile(context.Context, string) error { return nil }
+
+func (p staticProvider) ToGatewayAPI() (GatewayResources, field.ErrorList) {
+	return p.resources, nil
+}
+
+func Test_setOwnerReferences(t *testing.T) {
+	sourceOwner := metav1.OwnerReference{APIVersion: "networking.istio.io/v1beta1", Kind: "Gateway", Name: "istio", UID: "source-uid"}
+	ProviderConstructorByName["static-provider"] = func(*ProviderConf) Provider {
+		return staticProvider{resources: GatewayResources{
+			GatewayClasses: map[types.NamespacedName]gatewayv1.GatewayClass{
+				{Name: "example"}: {
+					ObjectMeta: metav1.ObjectMeta{Name: "example"},
+					Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/controller"},
+				},
+			},
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "gateway"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway", OwnerReferences: []metav1.OwnerReference{sourceOwner}},
+					Spec: gatewayv1.GatewaySpec{GatewayClassName: "example", Listeners: []gatewayv1.Listener{
+						{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+					}},
+				},
+			},
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				{Namespace: "default", Name: "route"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+					Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}}},
+				},
+				{Namespace: "other", Name: "route"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "route"},
+					Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway", Namespace: ptr.To(gatewayv1.Namespace("default"))}}}},
+				},
+			},
+		}}
+	}
+	defer delete(ProviderConstructorByName, "static-provider")
+
+	inputFile := filepath.Join(t.TempDir(), "input.yaml")
+	if err := os.WriteFile(inputFile, nil, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	convert := func(owner *metav1.OwnerReference, ownerNamespace string) GatewayResources {
+		t.Helper()
+		notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+		gatewayResources, _, err := ToGatewayAPIResources(context.Background(), "", inputFile, []string{"static-provider"}, nil, GatewayOptions{OwnerReference: owner, OwnerNamespace: ownerNamespace})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(gatewayResources) != 1 {
+			t.Fatalf("Expected the resources of 1 provider, got %d", len(gatewayResources))
+		}
+		return gatewayResources[0]
+	}
+
+	owner := metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Migration", Name: "migration", UID: "owner-uid", Controller: ptr.To(true)}
+	ownerCount := func(gatewayResources GatewayResources) map[string]int {
+		return map[string]int{
+			"GatewayClass example":    len(gatewayResources.GatewayClasses[types.NamespacedName{Name: "example"}].OwnerReferences),
+			"Gateway default/gateway": len(gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: "gateway"}].OwnerReferences),
+			"HTTPRoute default/route": len(gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "route"}].OwnerReferences),
+			"HTTPRoute other/route":   len(gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "other", Name: "route"}].OwnerReferences),
+		}
+	}
+
+	t.Run("namespaced owner", func(t *testing.T) {
+		gatewayResources := convert(&owner, "default")
+		expected := []metav1.OwnerReference{owner}
+		gateway := gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: "gateway"}]
+		if diff := cmp.Diff(expected, gateway.OwnerReferences); diff != "" {
+			t.Errorf("Unexpected owner references of the Gateway (-want +got):\n%s", diff)
+		}
+		route := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "route"}]
+		if diff := cmp.Diff(expected, route.OwnerReferences); diff != "" {
+			t.Errorf("Unexpected owner references of the HTTPRoute (-want +got):\n%s", diff)
+		}
+		// The GatewayClass is cluster-scoped and the other route in another
+		// namespace, where the owner cannot be resolved.
+		expectedCount := map[string]int{"GatewayClass example": 0, "Gateway default/gateway": 1, "HTTPRoute default/route": 1, "HTTPRoute other/route": 0}
+		if diff := cmp.Diff(expectedCount, ownerCount(gatewayResources)); diff != "" {
+			t.Errorf("Unexpected number of owner references (-want +got):\n%s", diff)
+		}
+		var warnings []string
+		for _, notification := range notifications.NotificationAggr.Notifications["static-provider"] {
+			if notification.Type == notifications.WarningNotification {
+				warnings = append(warnings, notification.Message)
+			}
+		}
+		expectedWarnings := []string{
+			"GatewayClass example is cluster-scoped while its owner Migration migration is in namespace default, it is generated without an owner and is not garbage collected with it",
+			"HTTPRoute other/route is in namespace other while its owner Migration migration is in namespace default, it is generated without an owner and is not garbage collected with it",
+		}
+		if diff := cmp.Diff(expectedWarnings, warnings); diff != "" {
+			t.Errorf("Unexpected warnings (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("cluster-scoped owner", func(t *testing.T) {
+		gatewayResources := convert(&owner, "")
+		expectedCount := map[string]int{"GatewayClass example": 1, "Gateway default/gateway": 1, "HTTPRoute default/route": 1, "HTTPRoute other/route": 1}
+		if diff := cmp.Diff(expectedCount, ownerCount(gatewayResources)); diff != "" {
+			t.Errorf("Unexpected number of owner references (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("no owner", func(t *testing.T) {
+		gatewayResources := convert(nil, "")
+		route := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "route"}]
+		if len(route.OwnerReferences) != 0 {
+			t.Errorf("Expected no owner references, got %v", route.OwnerReferences)
+		}
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+		err := GatewayOptions{OwnerReference: &metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Migration", Name: "migration"}}.Validate()
+		if err == nil {
+			t.Errorf("Expected an error for the owner reference without uid")
+		}
+		if err = (GatewayOptions{OwnerNamespace: "default"}).Validate(); err == nil {
+			t.Errorf("Expected an error for the owner namespace without owner reference")
+		}
+		if err = (GatewayOptions{OwnerReference: &owner, OwnerNamespace: "Default"}).Validate(); err == nil {
+			t.Errorf("Expected an error for the invalid owner namespace")
+		}
+	})
+}
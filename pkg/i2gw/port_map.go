@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// schemePorts are the well-known ports of the redirect schemes, which the
+// redirects without port are sent to.
+var schemePorts = map[string]gatewayv1.PortNumber{
+	"http":  80,
+	"https": 443,
+}
+
+// parsePortMap parses the port mappings of the --port-map flag, returning an
+// error for the invalid port numbers and the ports mapped to the same port.
+func parsePortMap(portMap map[string]string) (map[gatewayv1.PortNumber]gatewayv1.PortNumber, error) {
+	parsePort := func(from, to, port string) (gatewayv1.PortNumber, error) {
+		number, err := strconv.Atoi(port)
+		if err != nil || number < 1 || number > 65535 {
+			return 0, fmt.Errorf("invalid port mapping %s=%s, %s is not a valid port number", from, to, port)
+		}
+		return gatewayv1.PortNumber(number), nil
+	}
+
+	froms := make([]string, 0, len(portMap))
+	for from := range portMap {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	ports := map[gatewayv1.PortNumber]gatewayv1.PortNumber{}
+	mappedFrom := map[gatewayv1.PortNumber]gatewayv1.PortNumber{}
+	for _, from := range froms {
+		to := portMap[from]
+		fromPort, err := parsePort(from, to, from)
+		if err != nil {
+			return nil, err
+		}
+		toPort, err := parsePort(from, to, to)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := mappedFrom[toPort]; ok {
+			return nil, fmt.Errorf("invalid port mapping %s=%s, port %d is already mapped to %d", from, to, other, toPort)
+		}
+		ports[fromPort] = toPort
+		mappedFrom[toPort] = fromPort
+	}
+	return ports, nil
+}
+
+// remapListenerPorts moves the listeners of the generated Gateways on the
+// mapped ports to their new ports, e.g. to serve the Gateways behind another
+// load balancer. The ports of the route parentRefs follow the listeners, as do
+// the ports of the redirects to the same host, including the redirects without
+// port to the well-known port of their scheme, like the HTTP to HTTPS
+// redirects. The redirects to other hosts are left untouched.
+func remapListenerPorts(gatewayResources *GatewayResources, portMap map[string]string, providerName ProviderName) {
+	// The options are validated before the conversion.
+	ports, err := parsePortMap(portMap)
+	if err != nil {
+		return
+	}
+
+	for gatewayKey, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		var remapped []string
+		for i, listener := range gateway.Spec.Listeners {
+			if port, ok := ports[listener.Port]; ok {
+				gateway.Spec.Listeners[i].Port = port
+				remapped = append(remapped, fmt.Sprintf("%s from %d to %d", listener.Name, listener.Port, port))
+			}
+		}
+		if len(remapped) == 0 {
+			continue
+		}
+		gatewayResources.Gateways[gatewayKey] = gateway
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("the listeners of Gateway %s/%s were moved to the mapped ports: %s", gateway.Namespace, gateway.Name, strings.Join(remapped, ", ")),
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+	}
+
+	for key, route := range gatewayResources.HTTPRoutes {
+		remapParentRefPorts(route.Spec.ParentRefs, ports)
+		for i := range route.Spec.Rules {
+			for j := range route.Spec.Rules[i].Filters {
+				remapRedirectPort(route.Spec.Rules[i].Filters[j].RequestRedirect, ports)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		remapParentRefPorts(route.Spec.ParentRefs, ports)
+		gatewayResources.GRPCRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TLSRoutes {
+		remapParentRefPorts(route.Spec.ParentRefs, ports)
+		gatewayResources.TLSRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TCPRoutes {
+		remapParentRefPorts(route.Spec.ParentRefs, ports)
+		gatewayResources.TCPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.UDPRoutes {
+		remapParentRefPorts(route.Spec.ParentRefs, ports)
+		gatewayResources.UDPRoutes[key] = route
+	}
+}
+
+func remapParentRefPorts(parentRefs []gatewayv1.ParentReference, ports map[gatewayv1.PortNumber]gatewayv1.PortNumber) {
+	for i, parentRef := range parentRefs {
+		if parentRef.Port == nil {
+			continue
+		}
+		if port, ok := ports[*parentRef.Port]; ok {
+			parentRefs[i].Port = &port
+		}
+	}
+}
+
+// remapRedirectPort remaps the port of a redirect to the same host, setting the
+// port of the redirects to the well-known port of their scheme.
+func remapRedirectPort(redirect *gatewayv1.HTTPRequestRedirectFilter, ports map[gatewayv1.PortNumber]gatewayv1.PortNumber) {
+	if redirect == nil || redirect.Hostname != nil {
+		return
+	}
+	port := redirect.Port
+	if port == nil && redirect.Scheme != nil {
+		if schemePort, ok := schemePorts[strings.ToLower(*redirect.Scheme)]; ok {
+			port = &schemePort
+		}
+	}
+	if port == nil {
+		return
+	}
+	if remapped, ok := ports[*port]; ok {
+		redirect.Port = &remapped
+	}
+}
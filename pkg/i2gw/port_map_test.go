@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_remapListenerPorts(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	redirectKey := types.NamespacedName{Namespace: "default", Name: "foo-redirect"}
+	externalKey := types.NamespacedName{Namespace: "default", Name: "foo-external"}
+	route := func(name string, port *gatewayv1.PortNumber, redirect *gatewayv1.HTTPRequestRedirectFilter) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", Port: port}}},
+				Rules: []gatewayv1.HTTPRouteRule{{Filters: []gatewayv1.HTTPRouteFilter{{
+					Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: redirect,
+				}}}},
+			},
+		}
+	}
+	gateway := func(httpPort, httpsPort gatewayv1.PortNumber) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+			Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{
+				{Name: "foo-example-com-http", Hostname: ptr.To(gatewayv1.Hostname("foo.example.com")), Protocol: gatewayv1.HTTPProtocolType, Port: httpPort},
+				{Name: "foo-example-com-https", Hostname: ptr.To(gatewayv1.Hostname("foo.example.com")), Protocol: gatewayv1.HTTPSProtocolType, Port: httpsPort},
+			}},
+		}
+	}
+
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{gatewayKey: gateway(80, 443)},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			redirectKey: route("foo-redirect", ptr.To(gatewayv1.PortNumber(80)), &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)}),
+			externalKey: route("foo-external", nil, &gatewayv1.HTTPRequestRedirectFilter{Hostname: ptr.To(gatewayv1.PreciseHostname("bar.example.com")), Port: ptr.To(gatewayv1.PortNumber(443))}),
+		},
+	}
+	options := GatewayOptions{PortMap: map[string]string{"80": "8080", "443": "8443"}}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	applyGatewayOptions(&gatewayResources, options, "test-provider")
+
+	expected := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{gatewayKey: gateway(8080, 8443)},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			redirectKey: route("foo-redirect", ptr.To(gatewayv1.PortNumber(8080)), &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), Port: ptr.To(gatewayv1.PortNumber(8443)), StatusCode: ptr.To(301)}),
+			externalKey: route("foo-external", nil, &gatewayv1.HTTPRequestRedirectFilter{Hostname: ptr.To(gatewayv1.PreciseHostname("bar.example.com")), Port: ptr.To(gatewayv1.PortNumber(443))}),
+		},
+	}
+	if diff := cmp.Diff(expected, gatewayResources); diff != "" {
+		t.Errorf("Unexpected resources (-want +got):\n%s", diff)
+	}
+	if got := len(notifications.NotificationAggr.Notifications["test-provider"]); got != 1 {
+		t.Errorf("Expected 1 notification, got %d", got)
+	}
+}
+
+func Test_parsePortMap(t *testing.T) {
+	testCases := []struct {
+		name        string
+		portMap     map[string]string
+		expectedErr bool
+	}{
+		{name: "valid", portMap: map[string]string{"80": "8080", "443": "8443"}},
+		{name: "swapped ports", portMap: map[string]string{"80": "443", "443": "80"}},
+		{name: "invalid source port", portMap: map[string]string{"http": "8080"}, expectedErr: true},
+		{name: "out of range port", portMap: map[string]string{"443": "65536"}, expectedErr: true},
+		{name: "zero port", portMap: map[string]string{"80": "0"}, expectedErr: true},
+		{name: "ports mapped to the same port", portMap: map[string]string{"80": "8080", "8000": "8080"}, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parsePortMap(tc.portMap)
+			if (err != nil) != tc.expectedErr {
+				t.Errorf("Expected error %t, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ProgressFunc is called with a message for every step of a conversion, like
+// the number of providers converted so far, and the total number of items of
+// the step, so that long conversions can be followed.
+type ProgressFunc func(message string, total int)
+
+// progressCounter reports the number of completed items of a step, which may
+// complete concurrently. When there are many items, only every hundredth of
+// them is reported, along with the last one.
+type progressCounter struct {
+	mutex    sync.Mutex
+	progress ProgressFunc
+	format   string
+	done     int
+	total    int
+}
+
+// newProgressCounter returns a counter of the total items of a step. The
+// format gets the number of completed items and the total. A nil progress
+// func disables the reports.
+func newProgressCounter(progress ProgressFunc, total int, format string) *progressCounter {
+	return &progressCounter{progress: progress, format: format, total: total}
+}
+
+// Counter returns a func to call for every completed item of the total items
+// of a step, like the Ingresses converted by a provider, reporting their number
+// as the progress counters of the conversion do. The format gets the number of
+// completed items and the total. It reports nothing if the func is nil.
+func (p ProgressFunc) Counter(total int, format string) func() {
+	counter := newProgressCounter(p, total, format)
+	return func() {
+		counter.complete()
+	}
+}
+
+// complete counts a completed item, with the extra formatted arguments.
+func (c *progressCounter) complete(args ...any) {
+	if c.progress == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.done++
+	if c.done == c.total || c.done%max(1, c.total/100) == 0 {
+		c.progress(fmt.Sprintf(c.format, append([]any{c.done, c.total}, args...)...), c.total)
+	}
+}
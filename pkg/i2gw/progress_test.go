@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+)
+
+func Test_progressCounter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		total         int
+		expectedCount int
+		expectedFirst string
+		expectedLast  string
+	}{
+		{
+			name:          "every item",
+			total:         3,
+			expectedCount: 3,
+			expectedFirst: "Verified 1/3 Secrets",
+			expectedLast:  "Verified 3/3 Secrets",
+		},
+		{
+			name:          "every hundredth item",
+			total:         250,
+			expectedCount: 125,
+			expectedFirst: "Verified 2/250 Secrets",
+			expectedLast:  "Verified 250/250 Secrets",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var messages []string
+			counter := newProgressCounter(func(message string, _ int) { messages = append(messages, message) }, tc.total, "Verified %d/%d Secrets")
+			for i := 0; i < tc.total; i++ {
+				counter.complete()
+			}
+			if len(messages) != tc.expectedCount {
+				t.Fatalf("Expected %d progress messages, got %d", tc.expectedCount, len(messages))
+			}
+			if messages[0] != tc.expectedFirst || messages[len(messages)-1] != tc.expectedLast {
+				t.Errorf("Expected progress messages from %q to %q, got %q to %q", tc.expectedFirst, tc.expectedLast, messages[0], messages[len(messages)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func init() {
+	ProvenanceAggr = ProvenanceAggregator{Sources: map[ObjectRef]map[string][]string{}, Unconverted: map[ObjectRef][]string{}, Ingresses: map[types.NamespacedName]bool{}}
+}
+
+// ObjectRef identifies a generated Gateway API object.
+type ObjectRef struct {
+	Kind string
+	types.NamespacedName
+}
+
+// ProvenanceAggregator tracks which source fields and annotations produced the
+// fields of the generated objects, and which source annotations of the
+// generated objects could not be converted, along with the Ingresses that were
+// converted.
+//
+// The fields are identified by their path in the serialized object, like
+// `spec.rules[0].backendRefs`, and the empty path refers to the object itself.
+type ProvenanceAggregator struct {
+	mutex       sync.Mutex
+	Sources     map[ObjectRef]map[string][]string
+	Unconverted map[ObjectRef][]string
+	Ingresses   map[types.NamespacedName]bool
+}
+
+var ProvenanceAggr ProvenanceAggregator
+
+// Record records that the field of the object was produced by the source.
+// Recording the same source twice for a field has no effect.
+func (pa *ProvenanceAggregator) Record(object ObjectRef, fieldPath string, source string) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	if pa.Sources[object] == nil {
+		pa.Sources[object] = map[string][]string{}
+	}
+	for _, s := range pa.Sources[object][fieldPath] {
+		if s == source {
+			return
+		}
+	}
+	pa.Sources[object][fieldPath] = append(pa.Sources[object][fieldPath], source)
+}
+
+// Move moves the sources recorded for the field of the object, and for all its
+// subfields, to another field, possibly of another object. It is meant to be
+// used when a field is moved during the conversion, like an HTTPRoute rule
+// converted to a GRPCRoute rule.
+func (pa *ProvenanceAggregator) Move(from ObjectRef, fromPath string, to ObjectRef, toPath string) {
+	if from == to && fromPath == toPath {
+		return
+	}
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	for fieldPath, sources := range pa.Sources[from] {
+		subPath, ok := subfieldPath(fieldPath, fromPath)
+		if !ok {
+			continue
+		}
+		delete(pa.Sources[from], fieldPath)
+		if pa.Sources[to] == nil {
+			pa.Sources[to] = map[string][]string{}
+		}
+		pa.Sources[to][toPath+subPath] = append(pa.Sources[to][toPath+subPath], sources...)
+	}
+}
+
+// Rename moves all the sources and unconverted annotations recorded for the
+// objects to their new references. The objects are renamed at once, so that an
+// object may be renamed to the former reference of another renamed object.
+func (pa *ProvenanceAggregator) Rename(renames map[ObjectRef]ObjectRef) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	sources := map[ObjectRef]map[string][]string{}
+	unconverted := map[ObjectRef][]string{}
+	for from, to := range renames {
+		if s, ok := pa.Sources[from]; ok {
+			sources[to] = s
+			delete(pa.Sources, from)
+		}
+		if u, ok := pa.Unconverted[from]; ok {
+			unconverted[to] = u
+			delete(pa.Unconverted, from)
+		}
+	}
+	for to, s := range sources {
+		pa.Sources[to] = s
+	}
+	for to, u := range unconverted {
+		if pa.Unconverted == nil {
+			pa.Unconverted = map[ObjectRef][]string{}
+		}
+		pa.Unconverted[to] = u
+	}
+}
+
+// Delete removes all the sources and unconverted annotations recorded for the
+// object.
+func (pa *ProvenanceAggregator) Delete(object ObjectRef) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	delete(pa.Sources, object)
+	delete(pa.Unconverted, object)
+}
+
+// RecordUnconverted records that the annotation of a source of the object could
+// not be converted. Recording the same annotation twice has no effect.
+func (pa *ProvenanceAggregator) RecordUnconverted(object ObjectRef, annotation string) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	if pa.Unconverted == nil {
+		pa.Unconverted = map[ObjectRef][]string{}
+	}
+	for _, a := range pa.Unconverted[object] {
+		if a == annotation {
+			return
+		}
+	}
+	pa.Unconverted[object] = append(pa.Unconverted[object], annotation)
+}
+
+// UnconvertedAnnotations returns the sorted unconverted annotations recorded for
+// the object.
+func (pa *ProvenanceAggregator) UnconvertedAnnotations(object ObjectRef) []string {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	annotations := append([]string{}, pa.Unconverted[object]...)
+	sort.Strings(annotations)
+	return annotations
+}
+
+// RecordIngress records that the Ingress was converted.
+func (pa *ProvenanceAggregator) RecordIngress(namespace, name string) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	if pa.Ingresses == nil {
+		pa.Ingresses = map[types.NamespacedName]bool{}
+	}
+	pa.Ingresses[types.NamespacedName{Namespace: namespace, Name: name}] = true
+}
+
+// ListIngresses returns the converted Ingresses, sorted by namespace and name.
+func (pa *ProvenanceAggregator) ListIngresses() []types.NamespacedName {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	ingresses := make([]types.NamespacedName, 0, len(pa.Ingresses))
+	for ingress := range pa.Ingresses {
+		ingresses = append(ingresses, ingress)
+	}
+	sort.Slice(ingresses, func(i, j int) bool {
+		return ingresses[i].String() < ingresses[j].String()
+	})
+	return ingresses
+}
+
+// HasIngressSource returns whether the Ingress, or one of its fields or
+// annotations, is a source of the object or of one of its fields.
+func (pa *ProvenanceAggregator) HasIngressSource(object ObjectRef, namespace, name string) bool {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	ingress := IngressSource(namespace, name, "")
+	for _, sources := range pa.Sources[object] {
+		for _, source := range sources {
+			if source == ingress || strings.HasSuffix(source, " ("+ingress+")") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ObjectSources returns the sources recorded for the fields of the object,
+// keyed by field path.
+func (pa *ProvenanceAggregator) ObjectSources(object ObjectRef) map[string][]string {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	sources := map[string][]string{}
+	for fieldPath, s := range pa.Sources[object] {
+		sources[fieldPath] = append([]string{}, s...)
+		sort.Strings(sources[fieldPath])
+	}
+	return sources
+}
+
+// subfieldPath returns the remainder of fieldPath if it is the parent path or one
+// of its subfields.
+func subfieldPath(fieldPath, parent string) (string, bool) {
+	if fieldPath == parent {
+		return "", true
+	}
+	if parent == "" {
+		return "", false
+	}
+	subPath, ok := strings.CutPrefix(fieldPath, parent)
+	if !ok || (!strings.HasPrefix(subPath, ".") && !strings.HasPrefix(subPath, "[")) {
+		return "", false
+	}
+	return subPath, true
+}
+
+// IngressSource describes a field or annotation of an Ingress as a source. If
+// from is empty, the source is the Ingress itself.
+func IngressSource(namespace, name, from string) string {
+	if from == "" {
+		return fmt.Sprintf("Ingress %s/%s", namespace, name)
+	}
+	return fmt.Sprintf("%s (Ingress %s/%s)", from, namespace, name)
+}
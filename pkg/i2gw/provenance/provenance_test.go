@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestProvenanceAggregatorMove(t *testing.T) {
+	httpRoute := ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+	grpcRoute := ObjectRef{Kind: "GRPCRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+
+	aggr := ProvenanceAggregator{Sources: map[ObjectRef]map[string][]string{}}
+	aggr.Record(httpRoute, "", "Ingress default/foo")
+	aggr.Record(httpRoute, "spec.rules[1]", "rule")
+	aggr.Record(httpRoute, "spec.rules[1].matches", "matches")
+	aggr.Record(httpRoute, "spec.rules[1].matches", "matches")
+	aggr.Record(httpRoute, "spec.rules[10].matches", "other matches")
+
+	aggr.Move(httpRoute, "spec.rules[1]", grpcRoute, "spec.rules[0]")
+
+	expectedHTTPRouteSources := map[string][]string{
+		"":                       {"Ingress default/foo"},
+		"spec.rules[10].matches": {"other matches"},
+	}
+	if diff := cmp.Diff(expectedHTTPRouteSources, aggr.ObjectSources(httpRoute)); diff != "" {
+		t.Errorf("Unexpected HTTPRoute sources (-want +got):\n%s", diff)
+	}
+	expectedGRPCRouteSources := map[string][]string{
+		"spec.rules[0]":         {"rule"},
+		"spec.rules[0].matches": {"matches"},
+	}
+	if diff := cmp.Diff(expectedGRPCRouteSources, aggr.ObjectSources(grpcRoute)); diff != "" {
+		t.Errorf("Unexpected GRPCRoute sources (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvenanceAggregatorRename(t *testing.T) {
+	foo := ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+	bar := ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"}}
+	prefixedBar := ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "prefixed-bar"}}
+
+	aggr := ProvenanceAggregator{Sources: map[ObjectRef]map[string][]string{}}
+	aggr.Record(foo, "", "Ingress default/foo")
+	aggr.Record(foo, "spec.rules[0]", "rule")
+	aggr.Record(bar, "", "Ingress default/bar")
+	aggr.RecordUnconverted(foo, "nginx.ingress.kubernetes.io/enable-cors")
+
+	// foo is renamed to the former name of bar.
+	aggr.Rename(map[ObjectRef]ObjectRef{foo: bar, bar: prefixedBar})
+
+	if diff := cmp.Diff(map[string][]string{}, aggr.ObjectSources(foo)); diff != "" {
+		t.Errorf("Unexpected foo sources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string][]string{"": {"Ingress default/foo"}, "spec.rules[0]": {"rule"}}, aggr.ObjectSources(bar)); diff != "" {
+		t.Errorf("Unexpected bar sources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string][]string{"": {"Ingress default/bar"}}, aggr.ObjectSources(prefixedBar)); diff != "" {
+		t.Errorf("Unexpected prefixed-bar sources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"nginx.ingress.kubernetes.io/enable-cors"}, aggr.UnconvertedAnnotations(bar)); diff != "" {
+		t.Errorf("Unexpected bar unconverted annotations (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvenanceAggregatorUnconverted(t *testing.T) {
+	httpRoute := ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+	grpcRoute := ObjectRef{Kind: "GRPCRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+
+	aggr := ProvenanceAggregator{Sources: map[ObjectRef]map[string][]string{}}
+	aggr.Record(httpRoute, "", IngressSource("default", "foo", ""))
+	aggr.Record(grpcRoute, "spec.rules[0].matches", IngressSource("default", "bar", "spec.rules[0].http.paths[0]"))
+	aggr.RecordUnconverted(httpRoute, "b")
+	aggr.RecordUnconverted(httpRoute, "a")
+	aggr.RecordUnconverted(httpRoute, "b")
+
+	if diff := cmp.Diff([]string{"a", "b"}, aggr.UnconvertedAnnotations(httpRoute)); diff != "" {
+		t.Errorf("Unexpected HTTPRoute unconverted annotations (-want +got):\n%s", diff)
+	}
+	if got := aggr.UnconvertedAnnotations(grpcRoute); len(got) != 0 {
+		t.Errorf("Expected no GRPCRoute unconverted annotations, got %v", got)
+	}
+
+	for _, tc := range []struct {
+		object   ObjectRef
+		name     string
+		expected bool
+	}{
+		{object: httpRoute, name: "foo", expected: true},
+		{object: httpRoute, name: "bar", expected: false},
+		{object: grpcRoute, name: "bar", expected: true},
+		{object: grpcRoute, name: "ba", expected: false},
+	} {
+		if got := aggr.HasIngressSource(tc.object, "default", tc.name); got != tc.expected {
+			t.Errorf("HasIngressSource(%s, default/%s) = %t, want %t", tc.object.Kind, tc.name, got, tc.expected)
+		}
+	}
+
+	aggr.Delete(httpRoute)
+	if got := aggr.UnconvertedAnnotations(httpRoute); len(got) != 0 {
+		t.Errorf("Expected no unconverted annotations after Delete, got %v", got)
+	}
+}
+
+func TestProvenanceAggregatorIngresses(t *testing.T) {
+	aggr := ProvenanceAggregator{}
+	aggr.RecordIngress("default", "foo")
+	aggr.RecordIngress("apps", "foo")
+	aggr.RecordIngress("default", "bar")
+	aggr.RecordIngress("default", "foo")
+
+	expected := []types.NamespacedName{
+		{Namespace: "apps", Name: "foo"},
+		{Namespace: "default", Name: "bar"},
+		{Namespace: "default", Name: "foo"},
+	}
+	if diff := cmp.Diff(expected, aggr.ListIngresses()); diff != "" {
+		t.Errorf("Unexpected Ingresses (-want +got):\n%s", diff)
+	}
+}
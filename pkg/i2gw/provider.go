@@ -18,9 +18,12 @@ package i2gw
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,9 +34,40 @@ import (
 
 // ProviderConstructorByName is a map of ProviderConstructor functions by a
 // provider name. Different Provider implementations should add their construction
-// func at startup.
+// func at startup, preferably with RegisterProvider.
 var ProviderConstructorByName = map[ProviderName]ProviderConstructor{}
 
+// ProviderResourceKindsByName is a map of the resource kinds every provider reads
+// from input files by a provider name. Different Provider implementations should
+// add their kinds at startup. The kinds are used to reject unexpected resources
+// when the input file is read in strict mode.
+var ProviderResourceKindsByName = map[ProviderName][]schema.GroupKind{}
+
+// RegisterProvider registers the constructor of a Provider under the name, along
+// with the resource kinds the provider reads from input files. Registered providers
+// can be selected with the --providers flag of the print command.
+//
+// Built-in providers register themselves from their init function. Providers living
+// in external packages can do the same, or call RegisterProvider explicitly, as long
+// as it happens before cmd.Execute or ToGatewayAPIResources is called.
+// RegisterProvider is not thread-safe, and panics if the name is empty, the
+// constructor is nil, or a provider is already registered under the name.
+func RegisterProvider(name ProviderName, constructor ProviderConstructor, resourceKinds ...schema.GroupKind) {
+	if name == "" {
+		panic("i2gw: RegisterProvider called with an empty provider name")
+	}
+	if constructor == nil {
+		panic(fmt.Sprintf("i2gw: RegisterProvider called with a nil constructor for provider %s", name))
+	}
+	if _, ok := ProviderConstructorByName[name]; ok {
+		panic(fmt.Sprintf("i2gw: RegisterProvider called twice for provider %s", name))
+	}
+	ProviderConstructorByName[name] = constructor
+	if len(resourceKinds) > 0 {
+		ProviderResourceKindsByName[name] = resourceKinds
+	}
+}
+
 // ProviderName is a string alias that stores the concrete Provider name.
 type ProviderName string
 
@@ -47,16 +81,39 @@ type ProviderConf struct {
 	Client                client.Client
 	Namespace             string
 	ProviderSpecificFlags map[string]map[string]string
+	// Providers are the names of the providers of the conversion, which share
+	// the Ingresses without ingress class so that each is converted once.
+	Providers []string
+	// Progress, if set, is called with the number of Ingresses the provider
+	// converted so far.
+	Progress ProgressFunc
 }
 
 // The Provider interface specifies the required functionality which needs to be
 // implemented by every concrete Ingress/Gateway-API provider, in order for it to
 // be used.
+//
+// A Provider is constructed once per run with the ProviderConf. Either
+// ReadResourcesFromCluster or ReadResourcesFromFile is then called, once, to let
+// the provider read and store the resources it needs, and ToGatewayAPI is called
+// to convert them. The provider reports what could not be converted exactly
+// through the notifications package, and returns the resources it failed to
+// convert as errors.
 type Provider interface {
 	CustomResourceReader
 	ResourceConverter
 }
 
+// AnnotationSupporter can optionally be implemented by a Provider to list the
+// annotations it converts, so that callers can tell which annotations of their
+// resources are handled.
+type AnnotationSupporter interface {
+
+	// SupportedAnnotations returns the keys of the annotations converted by the
+	// Provider.
+	SupportedAnnotations() []string
+}
+
 type CustomResourceReader interface {
 
 	// ReadResourcesFromCluster reads custom resources associated with
@@ -86,6 +143,9 @@ type ImplementationSpecificHTTPPathTypeMatchConverter func(*gatewayv1.HTTPPathMa
 // implementation-specific fields of the ingress API.
 type ProviderImplementationSpecificOptions struct {
 	ToImplementationSpecificHTTPPathTypeMatch ImplementationSpecificHTTPPathTypeMatchConverter
+	// Progress, if set, is called with the number of Ingresses converted, the
+	// Progress of the ProviderConf.
+	Progress ProgressFunc
 }
 
 // GatewayResources contains all Gateway-API objects.
@@ -94,11 +154,35 @@ type GatewayResources struct {
 	GatewayClasses map[types.NamespacedName]gatewayv1.GatewayClass
 
 	HTTPRoutes map[types.NamespacedName]gatewayv1.HTTPRoute
+	GRPCRoutes map[types.NamespacedName]gatewayv1alpha2.GRPCRoute
 	TLSRoutes  map[types.NamespacedName]gatewayv1alpha2.TLSRoute
 	TCPRoutes  map[types.NamespacedName]gatewayv1alpha2.TCPRoute
 	UDPRoutes  map[types.NamespacedName]gatewayv1alpha2.UDPRoute
 
 	ReferenceGrants map[types.NamespacedName]gatewayv1beta1.ReferenceGrant
+
+	BackendTLSPolicies map[types.NamespacedName]gatewayv1alpha2.BackendTLSPolicy
+
+	// TrafficPolicies are the settings of the traffic of the HTTPRoutes and GRPCRoutes
+	// that Gateway API has no equivalent for, keyed by route. They are converted
+	// to the ImplementationPolicies of the target implementation.
+	TrafficPolicies map[types.NamespacedName]TrafficPolicy
+
+	// GatewayPolicies are the settings of the traffic of the Gateways that
+	// Gateway API has no equivalent for, keyed by Gateway. They are converted
+	// to the ImplementationPolicies of the target implementation.
+	GatewayPolicies map[types.NamespacedName]GatewayPolicy
+
+	// ImplementationPolicies are the policies of the Gateway API implementation
+	// the resources are generated for, like the BackendTrafficPolicies of Envoy
+	// Gateway, keyed by kind and name.
+	ImplementationPolicies map[PolicyKey]unstructured.Unstructured
+}
+
+// PolicyKey identifies an implementation-specific policy.
+type PolicyKey struct {
+	Kind string
+	types.NamespacedName
 }
 
 // FeatureParser is a function that reads the Ingresses, and applies
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -29,7 +30,7 @@ const Name = "apisix"
 const ApisixIngressClass = "apisix"
 
 func init() {
-	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProvider(Name, NewProvider, common.IngressGVK.GroupKind(), common.ServiceGVK.GroupKind())
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -44,7 +45,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:        newResourcesStorage(),
 		resourceReader: newResourceReader(conf),
-		converter:      newConverter(),
+		converter:      newConverter(conf),
 	}
 }
 
@@ -18,6 +18,7 @@ package apisix
 
 import (
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -30,13 +31,14 @@ type converter struct {
 }
 
 // newConverter returns an apisix converter instance.
-func newConverter() *converter {
+func newConverter(conf *i2gw.ProviderConf) *converter {
 	return &converter{
 		featureParsers: []i2gw.FeatureParser{
 			httpToHTTPSFeature,
 		},
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			// The list of the implementationSpecific ingress fields options comes here.
+			Progress: conf.Progress,
 		},
 	}
 }
@@ -46,6 +48,11 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 	for _, ing := range storage.Ingresses {
 		ingressList = append(ingressList, *ing)
 	}
+
+	for _, notification := range common.ResolveNamedServicePorts(ingressList, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
 	// Convert plain ingress resources to gateway resources, ignoring all
 	// provider-specific features.
 	gatewayResources, errs := common.ToGateway(ingressList, c.implementationSpecificOptions)
@@ -60,5 +67,21 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		errs = append(errs, parseErrs...)
 	}
 
+	for _, notification := range common.ResolveCrossNamespaceBackends(&gatewayResources, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.ResolveMixedIngressControllers(&gatewayResources, ingressList, Name) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.CopyCertManagerAnnotations(&gatewayResources, ingressList) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
 	return gatewayResources, errs
 }
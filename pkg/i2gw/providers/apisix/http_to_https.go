@@ -17,6 +17,8 @@ limitations under the License.
 package apisix
 
 import (
+	"fmt"
+
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -42,6 +44,7 @@ func httpToHTTPSFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw
 					errs = append(errs, field.NotFound(field.NewPath("HTTPRoute"), key))
 				}
 
+				ingress := rule.Ingress
 				for i, rule := range httpRoute.Spec.Rules {
 					rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
 						Type: gatewayv1.HTTPRouteFilterRequestRedirect,
@@ -51,6 +54,7 @@ func httpToHTTPSFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw
 						},
 					})
 					httpRoute.Spec.Rules[i] = rule
+					common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, httpToHTTPSAnnotation)
 				}
 			}
 		}
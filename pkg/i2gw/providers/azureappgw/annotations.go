@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import "fmt"
+
+const (
+	annotationPrefix = "appgw.ingress.kubernetes.io"
+
+	backendPathPrefixKey   = "backend-path-prefix"
+	backendProtocolKey     = "backend-protocol"
+	cookieBasedAffinityKey = "cookie-based-affinity"
+	requestTimeoutKey      = "request-timeout"
+	sslRedirectKey         = "ssl-redirect"
+
+	healthProbeHostnameKey           = "health-probe-hostname"
+	healthProbePortKey               = "health-probe-port"
+	healthProbePathKey               = "health-probe-path"
+	healthProbeStatusCodesKey        = "health-probe-status-codes"
+	healthProbeIntervalKey           = "health-probe-interval"
+	healthProbeTimeoutKey            = "health-probe-timeout"
+	healthProbeUnhealthyThresholdKey = "health-probe-unhealthy-threshold"
+)
+
+// convertedAnnotationKeys are the suffixes of the annotations converted to
+// Gateway API resources.
+var convertedAnnotationKeys = []string{
+	backendPathPrefixKey,
+	requestTimeoutKey,
+	sslRedirectKey,
+}
+
+// supportedAnnotations returns the keys of the annotations converted to Gateway
+// API resources.
+func supportedAnnotations() []string {
+	annotations := make([]string, 0, len(convertedAnnotationKeys))
+	for _, key := range convertedAnnotationKeys {
+		annotations = append(annotations, appGwAnnotation(key))
+	}
+	return annotations
+}
+
+func appGwAnnotation(suffix string) string {
+	return fmt.Sprintf("%s/%s", annotationPrefix, suffix)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// The Name of the provider.
+const Name = "azure-appgw"
+
+const (
+	// AppGwIngressClass is the name of the IngressClass created by the Application
+	// Gateway Ingress Controller, used as the class of the generated Gateways.
+	AppGwIngressClass = "azure-application-gateway"
+	// AppGwIngressClassAnnotation is the kubernetes.io/ingress.class annotation
+	// value of the Ingresses handled by the Application Gateway Ingress Controller.
+	AppGwIngressClassAnnotation = "azure/application-gateway"
+)
+
+func init() {
+	i2gw.RegisterProvider(Name, NewProvider, common.IngressGVK.GroupKind(), common.ServiceGVK.GroupKind())
+}
+
+// Provider implements the i2gw.Provider interface.
+type Provider struct {
+	storage        *storage
+	resourceReader *resourceReader
+	converter      *converter
+}
+
+// NewProvider constructs and returns the azure-appgw implementation of i2gw.Provider.
+func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
+	return &Provider{
+		storage:        newResourcesStorage(),
+		resourceReader: newResourceReader(conf),
+		converter:      newConverter(conf),
+	}
+}
+
+// ToGatewayAPI converts stored Application Gateway Ingress Controller API
+// entities to i2gw.GatewayResources including the AGIC specific features.
+func (p *Provider) ToGatewayAPI() (i2gw.GatewayResources, field.ErrorList) {
+	return p.converter.convert(p.storage)
+}
+
+// SupportedAnnotations returns the Application Gateway Ingress Controller
+// annotations converted to Gateway API resources.
+func (p *Provider) SupportedAnnotations() []string {
+	return supportedAnnotations()
+}
+
+func (p *Provider) ReadResourcesFromCluster(ctx context.Context) error {
+	storage, err := p.resourceReader.readResourcesFromCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read resources from cluster: %w", err)
+	}
+
+	p.storage = storage
+	return nil
+}
+
+func (p *Provider) ReadResourcesFromFile(_ context.Context, filename string) error {
+	storage, err := p.resourceReader.readResourcesFromFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read resources from file: %w", err)
+	}
+
+	p.storage = storage
+	return nil
+}
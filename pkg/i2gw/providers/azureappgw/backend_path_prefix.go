@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// backendPathPrefixFeature converts the `appgw.ingress.kubernetes.io/backend-path-prefix`
+// annotation, which rewrites the path of the Ingress to the given prefix before
+// the request is forwarded to the backend, to a URLRewrite filter. The prefix
+// matched by PathPrefix rules is replaced with it, as is the full path of Exact
+// rules. The regular expression paths are not rewritten.
+func backendPathPrefixFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			prefix := ingress.Annotations[appGwAnnotation(backendPathPrefixKey)]
+			if prefix == "" || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			if !strings.HasPrefix(prefix, "/") {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%s %q is not an absolute path, the path is not rewritten in HTTPRoute %s/%s", appGwAnnotation(backendPathPrefixKey), prefix, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+			rewrite := common.PathPrefixRewrite{Replacement: prefix}
+			for _, i := range ruleIndexes(httpRoute, rule.IngressRule.HTTP.Paths) {
+				if !rewrite.Apply(&httpRoute.Spec.Rules[i]) {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s %q is not converted for the regular expression paths, whose matched prefix is not known, in HTTPRoute %s/%s", appGwAnnotation(backendPathPrefixKey), prefix, httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, appGwAnnotation(backendPathPrefixKey))
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_backendPathPrefixFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		pathType             networkingv1.PathType
+		expectedFilters      []gatewayv1.HTTPRouteFilter
+		expectedNotification bool
+	}{
+		{
+			name:     "no annotation",
+			pathType: networkingv1.PathTypePrefix,
+		},
+		{
+			name:        "prefix path",
+			annotations: map[string]string{"appgw.ingress.kubernetes.io/backend-path-prefix": "/test/"},
+			pathType:    networkingv1.PathTypePrefix,
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+					Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To("/test/")},
+				},
+			}},
+		},
+		{
+			name:        "exact path",
+			annotations: map[string]string{"appgw.ingress.kubernetes.io/backend-path-prefix": "/test/"},
+			pathType:    networkingv1.PathTypeExact,
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+					Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/test/")},
+				},
+			}},
+		},
+		{
+			name:                 "relative prefix",
+			annotations:          map[string]string{"appgw.ingress.kubernetes.io/backend-path-prefix": "test"},
+			pathType:             networkingv1.PathTypePrefix,
+			expectedNotification: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{testIngress("foo", tc.annotations, false, testPath("/hello", tc.pathType))}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = backendPathPrefixFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+			gotNotification := len(notifications.NotificationAggr.Notifications[Name]) > 0
+			if gotNotification != tc.expectedNotification {
+				t.Errorf("Expected notification: %v, got %+v", tc.expectedNotification, notifications.NotificationAggr.Notifications[Name])
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// converter implements the ToGatewayAPI function of i2gw.ResourceConverter interface.
+type converter struct {
+	featureParsers                []i2gw.FeatureParser
+	implementationSpecificOptions i2gw.ProviderImplementationSpecificOptions
+}
+
+// newConverter returns an azure-appgw converter instance.
+func newConverter(conf *i2gw.ProviderConf) *converter {
+	return &converter{
+		featureParsers: []i2gw.FeatureParser{
+			backendPathPrefixFeature,
+			healthProbeFeature,
+			requestTimeoutFeature,
+			sslRedirectFeature,
+			unsupportedAnnotationsFeature,
+		},
+		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
+			ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			Progress: conf.Progress,
+		},
+	}
+}
+
+func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.ErrorList) {
+	ingressList := []networkingv1.Ingress{}
+	for _, ing := range storage.Ingresses {
+		ingress := *ing
+		// The ingress class annotation value is not a valid Gateway name, so the
+		// Ingresses using it are converted as if they used the AGIC IngressClass.
+		if common.GetIngressClass(ingress) == AppGwIngressClassAnnotation {
+			ingress.Spec.IngressClassName = common.PtrTo(AppGwIngressClass)
+		}
+		ingressList = append(ingressList, ingress)
+	}
+
+	for _, notification := range common.ResolveNamedServicePorts(ingressList, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	// Convert plain ingress resources to gateway resources, ignoring all
+	// provider-specific features.
+	gatewayResources, errs := common.ToGateway(ingressList, c.implementationSpecificOptions)
+	if len(errs) > 0 {
+		return i2gw.GatewayResources{}, errs
+	}
+
+	for _, parseFeatureFunc := range c.featureParsers {
+		// Apply the feature parsing function to the gateway resources, one by one.
+		parseErrs := parseFeatureFunc(ingressList, &gatewayResources)
+		// Append the parsing errors to the error list.
+		errs = append(errs, parseErrs...)
+	}
+
+	for _, notification := range common.ResolveCrossNamespaceBackends(&gatewayResources, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.ResolveMixedIngressControllers(&gatewayResources, ingressList, Name) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.CopyCertManagerAnnotations(&gatewayResources, ingressList) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	common.RecordUnconvertedAnnotations(&gatewayResources, ingressList, annotationPrefix+"/", supportedAnnotations())
+
+	return gatewayResources, errs
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// testIngress returns an Ingress of the AGIC IngressClass for the host foo.com,
+// with a path to the Service foo for every given path.
+func testIngress(name string, annotations map[string]string, tls bool, paths ...networkingv1.HTTPIngressPath) networkingv1.Ingress {
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(AppGwIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+				},
+			}},
+		},
+	}
+	if tls {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-cert"}}
+	}
+	return ingress
+}
+
+func testPath(path string, pathType networkingv1.PathType) networkingv1.HTTPIngressPath {
+	return networkingv1.HTTPIngressPath{
+		Path:     path,
+		PathType: ptr.To(pathType),
+		Backend: networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: "foo",
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
+		},
+	}
+}
+
+func Test_convert(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingress := testIngress("foo", nil, false, testPath("/v1/*", networkingv1.PathTypeImplementationSpecific), testPath("/health", networkingv1.PathTypeImplementationSpecific))
+	// Ingresses using the ingress class annotation are converted as if they used
+	// the AGIC IngressClass, as the annotation value is not a valid Gateway name.
+	ingress.Spec.IngressClassName = nil
+	ingress.Annotations = map[string]string{networkingv1beta1.AnnotationIngressClass: AppGwIngressClassAnnotation}
+
+	storage := newResourcesStorage()
+	storage.Ingresses[types.NamespacedName{Namespace: "default", Name: "foo"}] = &ingress
+	gatewayResources, errs := newConverter(&i2gw.ProviderConf{}).convert(storage)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	gateway, ok := gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: AppGwIngressClass}]
+	if !ok {
+		t.Fatalf("Expected Gateway default/%s, got %+v", AppGwIngressClass, gatewayResources.Gateways)
+	}
+	if gateway.Spec.GatewayClassName != AppGwIngressClass {
+		t.Errorf("Expected GatewayClass %s, got %s", AppGwIngressClass, gateway.Spec.GatewayClassName)
+	}
+
+	httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+	expectedMatches := map[string]gatewayv1.PathMatchType{"/v1": gatewayv1.PathMatchPathPrefix, "/health": gatewayv1.PathMatchExact}
+	if len(httpRoute.Spec.Rules) != len(expectedMatches) {
+		t.Fatalf("Expected %d rules, got %+v", len(expectedMatches), httpRoute.Spec.Rules)
+	}
+	for _, rule := range httpRoute.Spec.Rules {
+		path := rule.Matches[0].Path
+		if expectedType, ok := expectedMatches[*path.Value]; !ok || *path.Type != expectedType {
+			t.Errorf("Unexpected path match %s %s", *path.Type, *path.Value)
+		}
+	}
+}
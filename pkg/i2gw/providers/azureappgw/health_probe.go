@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// healthProbeSettings are the health probe annotation suffixes, along with the
+// field of the Application Gateway for Containers HealthCheckPolicy
+// spec.default they correspond to.
+var healthProbeSettings = []struct {
+	key   string
+	field string
+}{
+	{healthProbeHostnameKey, "http.host"},
+	{healthProbePortKey, "port"},
+	{healthProbePathKey, "http.path"},
+	{healthProbeStatusCodesKey, "http.match.statusCodes"},
+	{healthProbeIntervalKey, "interval"},
+	{healthProbeTimeoutKey, "timeout"},
+	{healthProbeUnhealthyThresholdKey, "unhealthyThreshold"},
+}
+
+// healthProbeFeature emits a Warning notification for every Ingress
+// configuring the Application Gateway health probe of its backends.
+//
+// Gateway API has no health checks, so the notification lists the probe
+// settings along with the fields of an Application Gateway for Containers
+// HealthCheckPolicy targeting the backend Services, which configures them.
+func healthProbeFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		var settings []string
+		for _, setting := range healthProbeSettings {
+			if value, ok := ingress.Annotations[appGwAnnotation(setting.key)]; ok {
+				settings = append(settings, fmt.Sprintf("%s: %s (%s)", appGwAnnotation(setting.key), value, setting.field))
+			}
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("the health probe is not converted, as Gateway API has no health checks, create an alb.networking.azure.io/v1 HealthCheckPolicy targeting the backend Services with %s", strings.Join(settings, ", ")), ingress)
+	}
+	return nil
+}
+
+// isHealthProbeKey returns whether the annotation suffix configures the health
+// probe.
+func isHealthProbeKey(key string) bool {
+	for _, setting := range healthProbeSettings {
+		if setting.key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func Test_healthProbeFeature(t *testing.T) {
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		expectedMessage string
+	}{
+		{
+			name:        "no health probe",
+			annotations: map[string]string{"appgw.ingress.kubernetes.io/request-timeout": "30"},
+		},
+		{
+			name: "health probe",
+			annotations: map[string]string{
+				"appgw.ingress.kubernetes.io/health-probe-path":         "/healthz",
+				"appgw.ingress.kubernetes.io/health-probe-interval":     "15",
+				"appgw.ingress.kubernetes.io/health-probe-status-codes": "200-399",
+			},
+			expectedMessage: "the health probe is not converted, as Gateway API has no health checks, create an alb.networking.azure.io/v1 HealthCheckPolicy targeting the backend Services with appgw.ingress.kubernetes.io/health-probe-path: /healthz (http.path), appgw.ingress.kubernetes.io/health-probe-status-codes: 200-399 (http.match.statusCodes), appgw.ingress.kubernetes.io/health-probe-interval: 15 (interval)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{testIngress("foo", tc.annotations, false, testPath("/", networkingv1.PathTypePrefix))}
+
+			if errs := healthProbeFeature(ingresses, &i2gw.GatewayResources{}); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedMessage == "" {
+				if len(notifs) != 0 {
+					t.Errorf("Expected no notifications, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Type != notifications.WarningNotification || notifs[0].Message != tc.expectedMessage {
+				t.Errorf("Expected a single Warning %q, got %+v", tc.expectedMessage, notifs)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// implementationSpecificHTTPPathTypeMatch maps the Implementation Specific
+// HTTP path and type to the corresponding Gateway HTTP ones.
+//
+// Application Gateway path rules match a path exactly, unless it ends with the
+// `/*` wildcard, so an Ingress path with type `ImplementationSpecific` will:
+// - Translate to equivalent Gateway Prefix path but dropping `/*`, if `/*` exists
+// - Translate to equivalent Exact path otherwise.
+func implementationSpecificHTTPPathTypeMatch(path *gatewayv1.HTTPPathMatch) {
+	pathType, value := implementationSpecificPath(*path.Value)
+	path.Type = &pathType
+	path.Value = common.PtrTo(value)
+}
+
+// implementationSpecificPath returns the Gateway path type and value an
+// ImplementationSpecific Ingress path is interpreted as by Application Gateway.
+func implementationSpecificPath(path string) (gatewayv1.PathMatchType, string) {
+	if path == "/*" {
+		return gatewayv1.PathMatchPathPrefix, "/"
+	}
+	if !strings.HasSuffix(path, "/*") {
+		return gatewayv1.PathMatchExact, path
+	}
+	return gatewayv1.PathMatchPathPrefix, strings.TrimSuffix(path, "/*")
+}
+
+// ruleIndexes returns the indexes of the HTTPRoute rules generated from the
+// Ingress paths.
+func ruleIndexes(httpRoute gatewayv1.HTTPRoute, paths []networkingv1.HTTPIngressPath) []int {
+	var indexes []int
+	for i, rule := range httpRoute.Spec.Rules {
+		for _, path := range paths {
+			if ruleMatchesPath(rule, path) {
+				indexes = append(indexes, i)
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+func ruleMatchesPath(rule gatewayv1.HTTPRouteRule, path networkingv1.HTTPIngressPath) bool {
+	value := path.Path
+	if path.PathType != nil && *path.PathType == networkingv1.PathTypeImplementationSpecific {
+		_, value = implementationSpecificPath(path.Path)
+	}
+	for _, match := range rule.Matches {
+		if match.Path != nil && match.Path.Value != nil && *match.Path.Value == value {
+			return true
+		}
+	}
+	return false
+}
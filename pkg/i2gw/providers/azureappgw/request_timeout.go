@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// requestTimeoutFeature converts the `appgw.ingress.kubernetes.io/request-timeout`
+// annotation, the number of seconds after which Application Gateway fails the
+// request if no response was received, to the request timeout of the HTTPRoute
+// rules generated from the Ingress paths.
+func requestTimeoutFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			timeout := ingress.Annotations[appGwAnnotation(requestTimeoutKey)]
+			if timeout == "" || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			seconds, err := strconv.Atoi(timeout)
+			if err != nil || seconds <= 0 {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%s %q is not a positive number of seconds, no timeout is set in HTTPRoute %s/%s", appGwAnnotation(requestTimeoutKey), timeout, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+			for _, i := range ruleIndexes(httpRoute, rule.IngressRule.HTTP.Paths) {
+				httpRoute.Spec.Rules[i].Timeouts = &gatewayv1.HTTPRouteTimeouts{
+					Request: common.PtrTo(gatewayv1.Duration(fmt.Sprintf("%ds", seconds))),
+				}
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].timeouts", i), &ingress, appGwAnnotation(requestTimeoutKey))
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_requestTimeoutFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		timeout              string
+		expectedTimeouts     *gatewayv1.HTTPRouteTimeouts
+		expectedNotification bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:             "timeout in seconds",
+			timeout:          "30",
+			expectedTimeouts: &gatewayv1.HTTPRouteTimeouts{Request: ptr.To(gatewayv1.Duration("30s"))},
+		},
+		{
+			name:                 "invalid timeout",
+			timeout:              "30s",
+			expectedNotification: true,
+		},
+		{
+			name:                 "zero timeout",
+			timeout:              "0",
+			expectedNotification: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			var annotations map[string]string
+			if tc.timeout != "" {
+				annotations = map[string]string{"appgw.ingress.kubernetes.io/request-timeout": tc.timeout}
+			}
+			ingresses := []networkingv1.Ingress{testIngress("foo", annotations, false, testPath("/", networkingv1.PathTypePrefix))}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = requestTimeoutFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedTimeouts, rule.Timeouts); diff != "" {
+				t.Errorf("Unexpected timeouts (-want +got):\n%s", diff)
+			}
+			gotNotification := len(notifications.NotificationAggr.Notifications[Name]) > 0
+			if gotNotification != tc.expectedNotification {
+				t.Errorf("Expected notification: %v, got %+v", tc.expectedNotification, notifications.NotificationAggr.Notifications[Name])
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// sslRedirectFeature converts the `appgw.ingress.kubernetes.io/ssl-redirect`
+// annotation, which redirects the HTTP requests of the Ingress hosts to HTTPS.
+//
+// The HTTPRoute of the host is attached to the HTTPS listener only, and an
+// HTTPRoute named `<route>-ssl-redirect` is attached to the HTTP listener to
+// redirect all the requests of the host to HTTPS with a 301. As the redirect
+// applies to the whole host, a Warning notification is emitted when other
+// Ingresses sharing it do not set the annotation. A host without TLS
+// configured has no HTTPS listener, so only an Error notification is emitted.
+func sslRedirectFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || len(httpRoute.Spec.ParentRefs) != 1 {
+			continue
+		}
+
+		var redirected []*networkingv1.Ingress
+		var notRedirected []string
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if ingress.Annotations[appGwAnnotation(sslRedirectKey)] == "true" {
+				redirected = append(redirected, &ingress)
+			} else {
+				notRedirected = append(notRedirected, fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name))
+			}
+		}
+		if len(redirected) == 0 {
+			continue
+		}
+
+		gatewayKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(httpRoute.Spec.ParentRefs[0].Name)}
+		httpListener, httpsListener := hostListeners(gatewayResources.Gateways[gatewayKey], rg.Host)
+		if httpListener == "" || httpsListener == "" {
+			notify(notifications.ErrorNotification, fmt.Sprintf("%s requires TLS to be configured for the host %q, no redirect is generated for HTTPRoute %s/%s", appGwAnnotation(sslRedirectKey), rg.Host, httpRoute.Namespace, httpRoute.Name), redirected[0])
+			continue
+		}
+
+		httpRoute.Spec.ParentRefs[0].SectionName = common.PtrTo(httpsListener)
+		gatewayResources.HTTPRoutes[key] = httpRoute
+
+		redirectKey := types.NamespacedName{Namespace: key.Namespace, Name: fmt.Sprintf("%s-ssl-redirect", key.Name)}
+		redirectRoute := gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: redirectKey.Namespace, Name: redirectKey.Name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: common.PtrTo(httpListener)}},
+				},
+				Hostnames: httpRoute.Spec.Hostnames,
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+						RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+							Scheme:     common.PtrTo("https"),
+							StatusCode: common.PtrTo(301),
+						},
+					}},
+				}},
+			},
+			Status: gatewayv1.HTTPRouteStatus{
+				RouteStatus: gatewayv1.RouteStatus{
+					Parents: []gatewayv1.RouteParentStatus{},
+				},
+			},
+		}
+		redirectRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+		gatewayResources.HTTPRoutes[redirectKey] = redirectRoute
+		for _, ingress := range redirected {
+			common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, redirectKey, "", ingress, appGwAnnotation(sslRedirectKey))
+			common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, "spec.parentRefs", ingress, appGwAnnotation(sslRedirectKey))
+		}
+
+		if len(notRedirected) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s redirects all the HTTP requests of the host %q to HTTPS, including the ones of the Ingresses %s, which do not set it", appGwAnnotation(sslRedirectKey), rg.Host, strings.Join(notRedirected, ", ")), redirected[0])
+		}
+	}
+	return nil
+}
+
+// hostListeners returns the names of the HTTP and HTTPS listeners of the
+// Gateway for the hostname, or empty names if there are none.
+func hostListeners(gateway gatewayv1.Gateway, hostname string) (gatewayv1.SectionName, gatewayv1.SectionName) {
+	var httpListener, httpsListener gatewayv1.SectionName
+	for _, listener := range gateway.Spec.Listeners {
+		listenerHostname := ""
+		if listener.Hostname != nil {
+			listenerHostname = string(*listener.Hostname)
+		}
+		if listenerHostname != hostname {
+			continue
+		}
+		switch listener.Protocol {
+		case gatewayv1.HTTPProtocolType:
+			httpListener = listener.Name
+		case gatewayv1.HTTPSProtocolType:
+			httpsListener = listener.Name
+		}
+	}
+	return httpListener, httpsListener
+}
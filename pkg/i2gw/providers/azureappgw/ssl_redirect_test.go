@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_sslRedirectFeature(t *testing.T) {
+	redirect := map[string]string{"appgw.ingress.kubernetes.io/ssl-redirect": "true"}
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		expectedSectionName   *gatewayv1.SectionName
+		expectedRedirect      bool
+		expectedNotifications int
+	}{
+		{
+			name:      "no annotation",
+			ingresses: []networkingv1.Ingress{testIngress("foo", nil, true, testPath("/", networkingv1.PathTypePrefix))},
+		},
+		{
+			name:                "ssl redirect",
+			ingresses:           []networkingv1.Ingress{testIngress("foo", redirect, true, testPath("/", networkingv1.PathTypePrefix))},
+			expectedSectionName: ptr.To(gatewayv1.SectionName("foo-com-https")),
+			expectedRedirect:    true,
+		},
+		{
+			name: "host shared with an Ingress without ssl redirect",
+			ingresses: []networkingv1.Ingress{
+				testIngress("foo", redirect, true, testPath("/", networkingv1.PathTypePrefix)),
+				testIngress("bar", nil, true, testPath("/bar", networkingv1.PathTypePrefix)),
+			},
+			expectedSectionName:   ptr.To(gatewayv1.SectionName("foo-com-https")),
+			expectedRedirect:      true,
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "no TLS",
+			ingresses:             []networkingv1.Ingress{testIngress("foo", redirect, false, testPath("/", networkingv1.PathTypePrefix))},
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+			gatewayResources, errs := common.ToGateway(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = sslRedirectFeature(tc.ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+			if diff := cmp.Diff(tc.expectedSectionName, httpRoute.Spec.ParentRefs[0].SectionName); diff != "" {
+				t.Errorf("Unexpected sectionName of the HTTPRoute (-want +got):\n%s", diff)
+			}
+
+			redirectRoute, ok := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com-ssl-redirect"}]
+			if ok != tc.expectedRedirect {
+				t.Fatalf("Expected redirect HTTPRoute: %v, got %+v", tc.expectedRedirect, gatewayResources.HTTPRoutes)
+			}
+			if ok {
+				expectedSpec := gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: AppGwIngressClass, SectionName: ptr.To(gatewayv1.SectionName("foo-com-http"))}},
+					},
+					Hostnames: []gatewayv1.Hostname{"foo.com"},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						Filters: []gatewayv1.HTTPRouteFilter{{
+							Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+							RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+						}},
+					}},
+				}
+				if diff := cmp.Diff(expectedSpec, redirectRoute.Spec); diff != "" {
+					t.Errorf("Unexpected redirect HTTPRoute (-want +got):\n%s", diff)
+				}
+			}
+
+			if got := len(notifications.NotificationAggr.Notifications[Name]); got != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %+v", tc.expectedNotifications, notifications.NotificationAggr.Notifications[Name])
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// unsupportedAnnotationsFeature emits notifications for the Application Gateway
+// annotations that are not converted:
+//   - `cookie-based-affinity` cannot be expressed, as the Gateway API version
+//     the resources are generated for has no session persistence, so a Warning
+//     notification is emitted.
+//   - `backend-protocol: https` requires a BackendTLSPolicy validating the
+//     backend certificate, whose CA is configured in Application Gateway and
+//     not in the cluster, so a Warning notification is emitted.
+//   - The health probe annotations are reported by healthProbeFeature.
+//   - The other annotations are listed in a single Warning notification.
+func unsupportedAnnotationsFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		if ingress.Annotations[appGwAnnotation(cookieBasedAffinityKey)] == "true" {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s is not supported, as Gateway API has no session persistence, the requests of a client may be sent to different backends", appGwAnnotation(cookieBasedAffinityKey)), ingress)
+		}
+		if strings.EqualFold(ingress.Annotations[appGwAnnotation(backendProtocolKey)], "https") {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s https is not converted, create a BackendTLSPolicy with the CA of the backends certificates for TLS to the backends", appGwAnnotation(backendProtocolKey)), ingress)
+		}
+
+		var unsupported []string
+		for annotation := range ingress.Annotations {
+			key, ok := strings.CutPrefix(annotation, annotationPrefix+"/")
+			if !ok || slices.Contains(convertedAnnotationKeys, key) || key == cookieBasedAffinityKey || key == backendProtocolKey || isHealthProbeKey(key) {
+				continue
+			}
+			unsupported = append(unsupported, annotation)
+		}
+		if len(unsupported) == 0 {
+			continue
+		}
+		sort.Strings(unsupported)
+		notify(notifications.WarningNotification, fmt.Sprintf("unsupported annotations were not converted: %s", strings.Join(unsupported, ", ")), ingress)
+	}
+	return nil
+}
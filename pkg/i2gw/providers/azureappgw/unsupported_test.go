@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureappgw
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func Test_unsupportedAnnotationsFeature(t *testing.T) {
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedMessages []string
+	}{
+		{
+			name: "converted annotations",
+			annotations: map[string]string{
+				"appgw.ingress.kubernetes.io/backend-path-prefix": "/test/",
+				"appgw.ingress.kubernetes.io/backend-protocol":    "http",
+				"appgw.ingress.kubernetes.io/ssl-redirect":        "true",
+				"kubernetes.io/ingress.class":                     AppGwIngressClassAnnotation,
+			},
+		},
+		{
+			name: "unsupported annotations",
+			annotations: map[string]string{
+				"appgw.ingress.kubernetes.io/cookie-based-affinity": "true",
+				"appgw.ingress.kubernetes.io/backend-protocol":      "https",
+				"appgw.ingress.kubernetes.io/health-probe-path":     "/healthz",
+				"appgw.ingress.kubernetes.io/connection-draining":   "true",
+			},
+			expectedMessages: []string{
+				"appgw.ingress.kubernetes.io/cookie-based-affinity is not supported, as Gateway API has no session persistence, the requests of a client may be sent to different backends",
+				"appgw.ingress.kubernetes.io/backend-protocol https is not converted, create a BackendTLSPolicy with the CA of the backends certificates for TLS to the backends",
+				"unsupported annotations were not converted: appgw.ingress.kubernetes.io/connection-draining",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{testIngress("foo", tc.annotations, false, testPath("/", networkingv1.PathTypePrefix))}
+
+			if errs := unsupportedAnnotationsFeature(ingresses, &i2gw.GatewayResources{}); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if len(notifs) != len(tc.expectedMessages) {
+				t.Fatalf("Expected %d notifications, got %+v", len(tc.expectedMessages), notifs)
+			}
+			for i, n := range notifs {
+				if n.Type != notifications.WarningNotification || n.Message != tc.expectedMessages[i] {
+					t.Errorf("Expected Warning %q, got %s %q", tc.expectedMessages[i], n.Type, n.Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// ResolveCrossNamespaceBackends makes the Service backendRefs of the HTTPRoutes and
+// GRPCRoutes usable across namespaces.
+//
+// An Ingress can only reference Services of its own namespace, but an ExternalName
+// Service may alias a Service of another namespace, like
+// `my-service.other.svc.cluster.local`. Such backendRefs are replaced with a
+// reference to the aliased Service, and an Info notification is returned for each.
+// Chains of such ExternalName Services are followed. A backendRef whose Service
+// resolves to an ExternalName Service for a host outside the cluster is kept, with
+// a Warning notification, as most implementations need their own resource to route
+// to external hosts. A Warning notification is also returned for the backendRefs
+// to headless Services, which are kept, as the implementations handle them
+// differently, some routing to the Pods of their endpoints directly.
+// A ReferenceGrant is then added for every backendRef targeting a Service in another
+// namespace than its route, as Gateway API requires one for cross-namespace
+// references.
+func ResolveCrossNamespaceBackends(gatewayResources *i2gw.GatewayResources, services map[types.NamespacedName]*corev1.Service) []notifications.Notification {
+	var notifs []notifications.Notification
+	for key := range gatewayResources.HTTPRoutes {
+		httpRoute := gatewayResources.HTTPRoutes[key]
+		for i := range httpRoute.Spec.Rules {
+			for j := range httpRoute.Spec.Rules[i].BackendRefs {
+				notifs = append(notifs, resolveCrossNamespaceBackendRef(gatewayResources, HTTPRouteGVK.Kind, &httpRoute, &httpRoute.Spec.Rules[i].BackendRefs[j].BackendRef, services)...)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	for key := range gatewayResources.GRPCRoutes {
+		grpcRoute := gatewayResources.GRPCRoutes[key]
+		for i := range grpcRoute.Spec.Rules {
+			for j := range grpcRoute.Spec.Rules[i].BackendRefs {
+				notifs = append(notifs, resolveCrossNamespaceBackendRef(gatewayResources, GRPCRouteGVK.Kind, &grpcRoute, &grpcRoute.Spec.Rules[i].BackendRefs[j].BackendRef, services)...)
+			}
+		}
+		gatewayResources.GRPCRoutes[key] = grpcRoute
+	}
+	return notifs
+}
+
+func resolveCrossNamespaceBackendRef(gatewayResources *i2gw.GatewayResources, routeKind string, route client.Object, backendRef *gatewayv1.BackendRef, services map[types.NamespacedName]*corev1.Service) []notifications.Notification {
+	if !isServiceBackendRef(backendRef.BackendObjectReference) {
+		return nil
+	}
+	var notifs []notifications.Notification
+	serviceKey := types.NamespacedName{Namespace: route.GetNamespace(), Name: string(backendRef.Name)}
+	if backendRef.Namespace != nil {
+		serviceKey.Namespace = string(*backendRef.Namespace)
+	}
+	target, externalHost := resolveExternalNameService(serviceKey, services)
+	if target != serviceKey {
+		backendRef.Name = gatewayv1.ObjectName(target.Name)
+		backendRef.Namespace = nil
+		if target.Namespace != route.GetNamespace() {
+			backendRef.Namespace = PtrTo(gatewayv1.Namespace(target.Namespace))
+		}
+		notifs = append(notifs, notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("ExternalName Service %s was replaced with the Service %s it aliases in the backendRefs of %s %s/%s", serviceKey, target, routeKind, route.GetNamespace(), route.GetName()),
+			CallingObjects: []client.Object{route},
+		})
+		serviceKey = target
+	}
+	if externalHost != "" {
+		notifs = append(notifs, notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("the backendRef of %s %s/%s to ExternalName Service %s resolves to the external host %s, which most Gateway API implementations do not route to through a Service backendRef. Replace it with the equivalent of your implementation, like an Istio ServiceEntry or an Envoy Gateway Backend", routeKind, route.GetNamespace(), route.GetName(), serviceKey, externalHost),
+			CallingObjects: []client.Object{route},
+		})
+	}
+	if service, ok := services[serviceKey]; ok && service.Spec.ClusterIP == corev1.ClusterIPNone {
+		notifs = append(notifs, notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("the backendRef of %s %s/%s targets the headless Service %s (clusterIP: None). It is kept, but Gateway API implementations may handle headless backends differently, e.g. route to the addresses of its endpoints directly or reject it: check how yours does", routeKind, route.GetNamespace(), route.GetName(), serviceKey),
+			CallingObjects: []client.Object{route},
+		})
+	}
+	if serviceKey.Namespace != route.GetNamespace() {
+		AddServiceReferenceGrant(gatewayResources, routeKind, route.GetNamespace(), serviceKey)
+	}
+	return notifs
+}
+
+// maxExternalNameAliases bounds the chains of ExternalName Services followed by
+// resolveExternalNameService, which also stops on loops.
+const maxExternalNameAliases = 8
+
+// resolveExternalNameService follows the chain of ExternalName Services aliasing
+// cluster-local Services from the Service, and returns the last Service of the
+// chain. If that Service is an ExternalName Service for a host outside the
+// cluster, the host is returned too.
+func resolveExternalNameService(serviceKey types.NamespacedName, services map[types.NamespacedName]*corev1.Service) (types.NamespacedName, string) {
+	visited := map[types.NamespacedName]bool{serviceKey: true}
+	for i := 0; i < maxExternalNameAliases; i++ {
+		service, ok := services[serviceKey]
+		if !ok || service.Spec.Type != corev1.ServiceTypeExternalName {
+			return serviceKey, ""
+		}
+		target, ok := ClusterLocalService(service.Spec.ExternalName)
+		if !ok {
+			return serviceKey, strings.TrimSuffix(service.Spec.ExternalName, ".")
+		}
+		if visited[target] {
+			return serviceKey, ""
+		}
+		visited[target] = true
+		serviceKey = target
+	}
+	return serviceKey, ""
+}
+
+func isServiceBackendRef(ref gatewayv1.BackendObjectReference) bool {
+	return (ref.Group == nil || *ref.Group == "") && (ref.Kind == nil || *ref.Kind == "Service")
+}
+
+// ClusterLocalService returns the Service named by a cluster-local DNS name, like
+// `my-service.my-namespace.svc.cluster.local`, and whether the name is one.
+func ClusterLocalService(dnsName string) (types.NamespacedName, bool) {
+	labels := strings.Split(strings.TrimSuffix(dnsName, "."), ".")
+	if !(len(labels) == 3 && labels[2] == "svc") &&
+		!(len(labels) == 5 && labels[2] == "svc" && labels[3] == "cluster" && labels[4] == "local") {
+		return types.NamespacedName{}, false
+	}
+	if len(validation.IsDNS1035Label(labels[0])) > 0 || len(validation.IsDNS1123Label(labels[1])) > 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: labels[1], Name: labels[0]}, true
+}
+
+// AddServiceReferenceGrant allows the routes of the given kind in the namespace to
+// reference the Service. ReferenceGrants are keyed by namespace and name, so adding
+// the same grant twice has no effect.
+func AddServiceReferenceGrant(gatewayResources *i2gw.GatewayResources, fromKind, fromNamespace string, service types.NamespacedName) {
+	name := fmt.Sprintf("from-%s-to-service-%s", fromNamespace, service.Name)
+	if fromKind != HTTPRouteGVK.Kind {
+		name = fmt.Sprintf("from-%s-%s-to-service-%s", fromNamespace, strings.ToLower(fromKind), service.Name)
+	}
+	from := gatewayv1beta1.ReferenceGrantFrom{
+		Group:     gatewayv1.Group(HTTPRouteGVK.Group),
+		Kind:      gatewayv1.Kind(fromKind),
+		Namespace: gatewayv1.Namespace(fromNamespace),
+	}
+	i2gw.AddReferenceGrant(gatewayResources, from, "Service", service, name)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestResolveCrossNamespaceBackends(t *testing.T) {
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "a", Name: "cross"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "cross"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "web.b.svc.cluster.local"},
+		},
+		{Namespace: "a", Name: "external"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "external"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "api.example.com"},
+		},
+		{Namespace: "a", Name: "chained"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "chained"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "external.a.svc"},
+		},
+		{Namespace: "a", Name: "loop"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "loop"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "loop.a.svc.cluster.local"},
+		},
+		{Namespace: "a", Name: "headless"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "headless"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: corev1.ClusterIPNone},
+		},
+		{Namespace: "a", Name: "local"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "local"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		},
+	}
+
+	testCases := []struct {
+		name                    string
+		service                 string
+		expectedBackendRef      gatewayv1.BackendObjectReference
+		expectedReferenceGrants []types.NamespacedName
+		expectedNotifications   int
+	}{
+		{
+			name:    "ExternalName aliasing a Service in another namespace",
+			service: "cross",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name:      "web",
+				Namespace: PtrTo(gatewayv1.Namespace("b")),
+				Port:      PtrTo(gatewayv1.PortNumber(80)),
+			},
+			expectedReferenceGrants: []types.NamespacedName{{Namespace: "b", Name: "from-a-to-service-web"}},
+			expectedNotifications:   1,
+		},
+		{
+			name:    "ExternalName aliasing an external host",
+			service: "external",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "external",
+				Port: PtrTo(gatewayv1.PortNumber(80)),
+			},
+			expectedNotifications: 1,
+		},
+		{
+			name:    "ExternalName aliasing an ExternalName for an external host",
+			service: "chained",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "external",
+				Port: PtrTo(gatewayv1.PortNumber(80)),
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name:    "ExternalName aliasing itself",
+			service: "loop",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "loop",
+				Port: PtrTo(gatewayv1.PortNumber(80)),
+			},
+		},
+		{
+			name:    "headless Service",
+			service: "headless",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "headless",
+				Port: PtrTo(gatewayv1.PortNumber(80)),
+			},
+			expectedNotifications: 1,
+		},
+		{
+			name:    "Service in the same namespace",
+			service: "local",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "local",
+				Port: PtrTo(gatewayv1.PortNumber(80)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// Two Ingresses referencing the same backend, to check the ReferenceGrant is only generated once.
+			var ingresses []networkingv1.Ingress
+			for _, name := range []string{"first", "second"} {
+				ingresses = append(ingresses, networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: name},
+					Spec: networkingv1.IngressSpec{
+						IngressClassName: PtrTo("nginx"),
+						Rules: []networkingv1.IngressRule{{
+							Host: name + ".example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{{
+										Path:     "/",
+										PathType: PtrTo(networkingv1.PathTypePrefix),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: tc.service,
+												Port: networkingv1.ServiceBackendPort{Number: 80},
+											},
+										},
+									}},
+								},
+							},
+						}},
+					},
+				})
+			}
+
+			gatewayResources, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			notifs := ResolveCrossNamespaceBackends(&gatewayResources, services)
+			if len(notifs) != 2*tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %+v", 2*tc.expectedNotifications, notifs)
+			}
+
+			for _, httpRoute := range gatewayResources.HTTPRoutes {
+				got := httpRoute.Spec.Rules[0].BackendRefs[0].BackendObjectReference
+				if diff := cmp.Diff(tc.expectedBackendRef, got); diff != "" {
+					t.Errorf("Unexpected backendRef of HTTPRoute %s (-want +got):\n%s", httpRoute.Name, diff)
+				}
+			}
+
+			if len(gatewayResources.ReferenceGrants) != len(tc.expectedReferenceGrants) {
+				t.Fatalf("Expected %d ReferenceGrants, got %+v", len(tc.expectedReferenceGrants), gatewayResources.ReferenceGrants)
+			}
+			for _, key := range tc.expectedReferenceGrants {
+				referenceGrant, ok := gatewayResources.ReferenceGrants[key]
+				if !ok {
+					t.Fatalf("Expected ReferenceGrant %s, got %+v", key, gatewayResources.ReferenceGrants)
+				}
+				expectedSpec := gatewayv1beta1.ReferenceGrantSpec{
+					From: []gatewayv1beta1.ReferenceGrantFrom{{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute", Namespace: "a"}},
+					To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service", Name: PtrTo(gatewayv1.ObjectName("web"))}},
+				}
+				if diff := cmp.Diff(expectedSpec, referenceGrant.Spec); diff != "" {
+					t.Errorf("Unexpected ReferenceGrant spec (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
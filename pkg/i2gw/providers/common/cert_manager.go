@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// certManagerAnnotations are the cert-manager annotations configuring the
+// Certificates issued for the TLS Secrets of an Ingress, which cert-manager
+// also reads on Gateways.
+var certManagerAnnotations = []string{
+	"cert-manager.io/issuer",
+	"cert-manager.io/cluster-issuer",
+	"cert-manager.io/issuer-kind",
+	"cert-manager.io/issuer-group",
+	"cert-manager.io/common-name",
+	"cert-manager.io/duration",
+	"cert-manager.io/renew-before",
+	"cert-manager.io/usages",
+	"cert-manager.io/revision-history-limit",
+	"cert-manager.io/private-key-algorithm",
+	"cert-manager.io/private-key-encoding",
+	"cert-manager.io/private-key-size",
+	"cert-manager.io/private-key-rotation-policy",
+}
+
+// CopyCertManagerAnnotations sets the cert-manager annotations of the Ingresses
+// with TLS on the Gateways generated from them, so that cert-manager keeps
+// issuing the certificates of their HTTPS listeners, and returns an Info
+// notification for every such Gateway, as cert-manager only reads Gateways
+// when its Gateway API support is enabled. When the Ingresses of a Gateway set
+// different values for the same annotation, the value of the first Ingress is
+// kept, and a Warning notification is returned.
+func CopyCertManagerAnnotations(gatewayResources *i2gw.GatewayResources, ingresses []networkingv1.Ingress) []notifications.Notification {
+	var notifs []notifications.Notification
+	keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+	for key := range gatewayResources.Gateways {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, key := range keys {
+		gateway := gatewayResources.Gateways[key]
+		gatewayRef := provenance.ObjectRef{Kind: GatewayGVK.Kind, NamespacedName: key}
+		annotations := map[string]string{}
+		sources := map[string]*networkingv1.Ingress{}
+		var objs []client.Object
+		for i := range ingresses {
+			ingress := &ingresses[i]
+			if len(ingress.Spec.TLS) == 0 || !provenance.ProvenanceAggr.HasIngressSource(gatewayRef, ingress.Namespace, ingress.Name) {
+				continue
+			}
+			var copied bool
+			for _, annotation := range certManagerAnnotations {
+				value, ok := ingress.Annotations[annotation]
+				if !ok {
+					continue
+				}
+				if source, ok := sources[annotation]; ok {
+					if annotations[annotation] != value {
+						notifs = append(notifs, notifications.Notification{
+							Type:           notifications.WarningNotification,
+							Message:        fmt.Sprintf("the %s annotation %q of the Ingress is not set on Gateway %s, which has the value %q of Ingress %s/%s", annotation, value, key, annotations[annotation], source.Namespace, source.Name),
+							CallingObjects: []client.Object{ingress},
+						})
+					}
+					continue
+				}
+				annotations[annotation] = value
+				sources[annotation] = ingress
+				copied = true
+				RecordIngressProvenance(GatewayGVK.Kind, key, "metadata.annotations", ingress, annotation)
+			}
+			if copied {
+				objs = append(objs, ingress)
+			}
+		}
+		if len(annotations) == 0 {
+			continue
+		}
+
+		if gateway.Annotations == nil {
+			gateway.Annotations = map[string]string{}
+		}
+		var copiedAnnotations []string
+		for _, annotation := range certManagerAnnotations {
+			if value, ok := annotations[annotation]; ok {
+				gateway.Annotations[annotation] = value
+				copiedAnnotations = append(copiedAnnotations, annotation)
+			}
+		}
+		gatewayResources.Gateways[key] = gateway
+		notifs = append(notifs, notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("the cert-manager annotations %s are set on Gateway %s, for cert-manager to issue the certificates of its HTTPS listeners, which requires the Gateway API support of cert-manager to be enabled, e.g. with --enable-gateway-api", strings.Join(copiedAnnotations, ", "), key),
+			CallingObjects: objs,
+		})
+	}
+	return notifs
+}
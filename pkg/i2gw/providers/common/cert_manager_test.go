@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestCopyCertManagerAnnotations(t *testing.T) {
+	provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+	newIngress := func(name, host string, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To("nginx"),
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: name + "-tls"}},
+				Rules: []networkingv1.IngressRule{{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: name,
+								Port: networkingv1.ServiceBackendPort{Number: 80},
+							}},
+						}},
+					}},
+				}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{
+		newIngress("bar", "bar.com", map[string]string{
+			"cert-manager.io/cluster-issuer": "letsencrypt",
+			"cert-manager.io/duration":       "2160h",
+			"cert-manager.io/unknown":        "ignored",
+		}),
+		newIngress("foo", "foo.com", map[string]string{"cert-manager.io/cluster-issuer": "internal-ca"}),
+	}
+
+	gatewayResources, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	notifs := CopyCertManagerAnnotations(&gatewayResources, ingresses)
+
+	expectedAnnotations := map[string]string{
+		"cert-manager.io/cluster-issuer": "letsencrypt",
+		"cert-manager.io/duration":       "2160h",
+	}
+	gateway := gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}]
+	if diff := cmp.Diff(expectedAnnotations, gateway.Annotations); diff != "" {
+		t.Errorf("Unexpected Gateway annotations (-want +got):\n%s", diff)
+	}
+
+	var messages []string
+	for _, notification := range notifs {
+		messages = append(messages, string(notification.Type)+": "+notification.Message)
+	}
+	expectedMessages := []string{
+		`WARNING: the cert-manager.io/cluster-issuer annotation "internal-ca" of the Ingress is not set on Gateway default/nginx, which has the value "letsencrypt" of Ingress default/bar`,
+		"INFO: the cert-manager annotations cert-manager.io/cluster-issuer, cert-manager.io/duration are set on Gateway default/nginx, for cert-manager to issue the certificates of its HTTPS listeners, which requires the Gateway API support of cert-manager to be enabled, e.g. with --enable-gateway-api",
+	}
+	if diff := cmp.Diff(expectedMessages, messages); diff != "" {
+		t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+	}
+}
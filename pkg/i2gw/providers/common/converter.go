@@ -18,14 +18,17 @@ package common
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -35,8 +38,12 @@ func ToGateway(ingresses []networkingv1.Ingress, options i2gw.ProviderImplementa
 	aggregator := ingressAggregator{ruleGroups: map[ruleGroupKey]*ingressRuleGroup{}}
 
 	var errs field.ErrorList
+	converted := options.Progress.Counter(len(ingresses), "Converted %d/%d Ingresses")
 	for _, ingress := range ingresses {
+		klog.V(3).Infof("Converting Ingress %s/%s of ingress class %q", ingress.Namespace, ingress.Name, GetIngressClass(ingress))
 		aggregator.addIngress(ingress)
+		provenance.ProvenanceAggr.RecordIngress(ingress.Namespace, ingress.Name)
+		converted()
 	}
 	if len(errs) > 0 {
 		return i2gw.GatewayResources{}, errs
@@ -66,6 +73,18 @@ func ToGateway(ingresses []networkingv1.Ingress, options i2gw.ProviderImplementa
 }
 
 var (
+	IngressGVK = schema.GroupVersionKind{
+		Group:   "networking.k8s.io",
+		Version: "v1",
+		Kind:    "Ingress",
+	}
+
+	ServiceGVK = schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Service",
+	}
+
 	GatewayGVK = schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1",
@@ -78,6 +97,12 @@ var (
 		Kind:    "HTTPRoute",
 	}
 
+	GRPCRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "GRPCRoute",
+	}
+
 	TLSRouteGVK = schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1alpha2",
@@ -95,6 +120,12 @@ var (
 		Version: "v1beta1",
 		Kind:    "ReferenceGrant",
 	}
+
+	BackendTLSPolicyGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "BackendTLSPolicy",
+	}
 )
 
 type ruleGroupKey string
@@ -117,6 +148,9 @@ type ingressRuleGroup struct {
 
 type ingressRule struct {
 	rule networkingv1.IngressRule
+	// ingressName and ruleIdx identify the rule in its source Ingress.
+	ingressName string
+	ruleIdx     int
 }
 
 type ingressDefaultBackend struct {
@@ -135,8 +169,8 @@ type ingressPath struct {
 
 func (a *ingressAggregator) addIngress(ingress networkingv1.Ingress) {
 	ingressClass := GetIngressClass(ingress)
-	for _, rule := range ingress.Spec.Rules {
-		a.addIngressRule(ingress.Namespace, ingress.Name, ingressClass, rule, ingress.Spec)
+	for i, rule := range ingress.Spec.Rules {
+		a.addIngressRule(ingress.Namespace, ingress.Name, ingressClass, i, rule, ingress.Spec)
 	}
 	if ingress.Spec.DefaultBackend != nil {
 		a.defaultBackends = append(a.defaultBackends, ingressDefaultBackend{
@@ -148,7 +182,7 @@ func (a *ingressAggregator) addIngress(ingress networkingv1.Ingress) {
 	}
 }
 
-func (a *ingressAggregator) addIngressRule(namespace, name, ingressClass string, rule networkingv1.IngressRule, iSpec networkingv1.IngressSpec) {
+func (a *ingressAggregator) addIngressRule(namespace, name, ingressClass string, ruleIdx int, rule networkingv1.IngressRule, iSpec networkingv1.IngressSpec) {
 	rgKey := ruleGroupKey(fmt.Sprintf("%s/%s/%s", namespace, ingressClass, rule.Host))
 	rg, ok := a.ruleGroups[rgKey]
 	if !ok {
@@ -160,10 +194,12 @@ func (a *ingressAggregator) addIngressRule(namespace, name, ingressClass string,
 		}
 		a.ruleGroups[rgKey] = rg
 	}
-	if len(iSpec.TLS) > 0 {
-		rg.tls = append(rg.tls, iSpec.TLS...)
+	for _, tls := range iSpec.TLS {
+		if TLSCoversHost(tls, rule.Host) && !slices.ContainsFunc(rg.tls, func(existing networkingv1.IngressTLS) bool { return existing.SecretName == tls.SecretName }) {
+			rg.tls = append(rg.tls, tls)
+		}
 	}
-	rg.rules = append(rg.rules, ingressRule{rule: rule})
+	rg.rules = append(rg.rules, ingressRule{rule: rule, ingressName: name, ruleIdx: ruleIdx})
 }
 
 func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImplementationSpecificOptions) ([]gatewayv1.HTTPRoute, []gatewayv1.Gateway, field.ErrorList) {
@@ -171,7 +207,15 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 	var errors field.ErrorList
 	listenersByNamespacedGateway := map[string][]gatewayv1.Listener{}
 
-	for _, rg := range a.ruleGroups {
+	// The rule groups are sorted, for the listeners to be generated in a
+	// stable order.
+	rgKeys := make([]ruleGroupKey, 0, len(a.ruleGroups))
+	for rgKey := range a.ruleGroups {
+		rgKeys = append(rgKeys, rgKey)
+	}
+	slices.Sort(rgKeys)
+	for _, rgKey := range rgKeys {
+		rg := a.ruleGroups[rgKey]
 		listener := gatewayv1.Listener{}
 		if rg.host != "" {
 			listener.Hostname = (*gatewayv1.Hostname)(&rg.host)
@@ -187,6 +231,15 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 		}
 		gwKey := fmt.Sprintf("%s/%s", rg.namespace, rg.ingressClass)
 		listenersByNamespacedGateway[gwKey] = append(listenersByNamespacedGateway[gwKey], listener)
+		for _, rule := range rg.rules {
+			provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: GatewayGVK.Kind, NamespacedName: types.NamespacedName{Namespace: rg.namespace, Name: rg.ingressClass}}, "", provenance.IngressSource(rg.namespace, rule.ingressName, ""))
+		}
+		// Rules without http only attach a TLS certificate to their host, which
+		// only needs the listeners, and an HTTPRoute without rules would match
+		// all the requests.
+		if !rg.hasHTTPRules() {
+			continue
+		}
 		httpRoute, errs := rg.toHTTPRoute(options)
 		httpRoutes = append(httpRoutes, httpRoute)
 		errors = append(errors, errs...)
@@ -221,6 +274,9 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: *backendRef}},
 			})
 		}
+		routeRef := provenance.ObjectRef{Kind: HTTPRouteGVK.Kind, NamespacedName: types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}}
+		provenance.ProvenanceAggr.Record(routeRef, "", provenance.IngressSource(db.namespace, db.name, ""))
+		provenance.ProvenanceAggr.Record(routeRef, "spec.rules[0].backendRefs", provenance.IngressSource(db.namespace, db.name, "spec.defaultBackend"))
 
 		httpRoutes = append(httpRoutes, httpRoute)
 	}
@@ -256,14 +312,14 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 				Name:     gatewayv1.SectionName(fmt.Sprintf("%shttp", listenerNamePrefix)),
 				Hostname: listener.Hostname,
 				Port:     80,
-				Protocol: gatewayv1.HTTPProtocolType,
+				Protocol: InferListenerProtocol(nil, false),
 			})
 			if listener.TLS != nil {
 				gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
 					Name:     gatewayv1.SectionName(fmt.Sprintf("%shttps", listenerNamePrefix)),
 					Hostname: listener.Hostname,
 					Port:     443,
-					Protocol: gatewayv1.HTTPSProtocolType,
+					Protocol: InferListenerProtocol(listener.TLS, false),
 					TLS:      listener.TLS,
 				})
 			}
@@ -278,6 +334,38 @@ func (a *ingressAggregator) toHTTPRoutesAndGateways(options i2gw.ProviderImpleme
 	return httpRoutes, gateways, errors
 }
 
+// TLSCoversHost returns whether the TLS section of an Ingress applies to the
+// host of a rule, so that other hosts of the Ingress, served over HTTP only,
+// get no HTTPS listener. A TLS section without hosts, or a rule without host,
+// keeps applying. Wildcard TLS hosts, like `*.example.com`, cover a single
+// DNS label.
+func TLSCoversHost(tls networkingv1.IngressTLS, host string) bool {
+	if host == "" || len(tls.Hosts) == 0 {
+		return true
+	}
+	for _, tlsHost := range tls.Hosts {
+		if tlsHost == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(tlsHost, "*"); ok {
+			if label, ok := strings.CutSuffix(host, suffix); ok && label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasHTTPRules returns whether any rule of the group has an http block.
+func (rg *ingressRuleGroup) hasHTTPRules() bool {
+	for _, rule := range rg.rules {
+		if rule.rule.HTTP != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (rg *ingressRuleGroup) toHTTPRoute(options i2gw.ProviderImplementationSpecificOptions) (gatewayv1.HTTPRoute, field.ErrorList) {
 	ingressPathsByMatchKey := groupIngressPathsByMatchKey(rg.rules)
 	httpRoute := gatewayv1.HTTPRoute{
@@ -297,8 +385,15 @@ func (rg *ingressRuleGroup) toHTTPRoute(options i2gw.ProviderImplementationSpeci
 	if rg.ingressClass != "" {
 		httpRoute.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(rg.ingressClass)}}
 	}
+	routeRef := provenance.ObjectRef{Kind: HTTPRouteGVK.Kind, NamespacedName: types.NamespacedName{Namespace: httpRoute.Namespace, Name: httpRoute.Name}}
+	for _, rule := range rg.rules {
+		provenance.ProvenanceAggr.Record(routeRef, "", provenance.IngressSource(rg.namespace, rule.ingressName, ""))
+	}
 	if rg.host != "" {
 		httpRoute.Spec.Hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(rg.host)}
+		for _, rule := range rg.rules {
+			provenance.ProvenanceAggr.Record(routeRef, "spec.hostnames", provenance.IngressSource(rg.namespace, rule.ingressName, fmt.Sprintf("spec.rules[%d].host", rule.ruleIdx)))
+		}
 	}
 
 	var errors field.ErrorList
@@ -319,6 +414,14 @@ func (rg *ingressRuleGroup) toHTTPRoute(options i2gw.ProviderImplementationSpeci
 		errors = append(errors, errs...)
 		hrRule.BackendRefs = backendRefs
 
+		rulePath := fmt.Sprintf("spec.rules[%d]", len(httpRoute.Spec.Rules))
+		for _, p := range paths {
+			rule := rg.rules[p.ruleIdx]
+			ingressPath := fmt.Sprintf("spec.rules[%d].http.paths[%d]", rule.ruleIdx, p.pathIdx)
+			provenance.ProvenanceAggr.Record(routeRef, rulePath+".matches", provenance.IngressSource(rg.namespace, rule.ingressName, ingressPath))
+			provenance.ProvenanceAggr.Record(routeRef, rulePath+".backendRefs", provenance.IngressSource(rg.namespace, rule.ingressName, ingressPath+".backend"))
+		}
+
 		httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, hrRule)
 	}
 
@@ -387,7 +490,7 @@ func toBackendRef(ib networkingv1.IngressBackend, path *field.Path) (*gatewayv1.
 	if ib.Service != nil {
 		if ib.Service.Port.Name != "" {
 			fieldPath := path.Child("service", "port")
-			return nil, field.Invalid(fieldPath, "name", fmt.Sprintf("named ports not supported: %s", ib.Service.Port.Name))
+			return nil, field.Invalid(fieldPath, "name", fmt.Sprintf("named port %s could not be resolved to a port number", ib.Service.Port.Name))
 		}
 		return &gatewayv1.BackendRef{
 			BackendObjectReference: gatewayv1.BackendObjectReference{
@@ -211,6 +211,306 @@ func Test_ingresses2GatewaysAndHttpRoutes(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "same host with and without TLS",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "dual", Namespace: "test"},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{{
+						Hosts:      []string{"example.com"},
+						SecretName: "example-cert",
+					}},
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/secure",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "secure",
+											Port: networkingv1.ServiceBackendPort{Number: 443},
+										},
+									},
+								}},
+							},
+						},
+					}, {
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/plain",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "plain",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}, {
+						Host: "example.net",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "plain",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+					IngressClassName: PtrTo("dual"),
+				},
+			}},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "test", Name: "dual"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "dual", Namespace: "test"},
+						Spec: gatewayv1.GatewaySpec{
+							GatewayClassName: "dual",
+							Listeners: []gatewayv1.Listener{{
+								Name:     "example-com-http",
+								Port:     80,
+								Protocol: gatewayv1.HTTPProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+							}, {
+								Name:     "example-com-https",
+								Port:     443,
+								Protocol: gatewayv1.HTTPSProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+								TLS: &gatewayv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayv1.SecretObjectReference{{
+										Name: "example-cert",
+									}},
+								},
+							}, {
+								Name:     "example-net-http",
+								Port:     80,
+								Protocol: gatewayv1.HTTPProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.net")),
+							}},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: "test", Name: "dual-example-com"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "dual-example-com", Namespace: "test"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{
+									Name: "dual",
+								}},
+							},
+							Hostnames: []gatewayv1.Hostname{"example.com"},
+							Rules: []gatewayv1.HTTPRouteRule{{
+								Matches: []gatewayv1.HTTPRouteMatch{{
+									Path: &gatewayv1.HTTPPathMatch{
+										Type:  &gPathPrefix,
+										Value: PtrTo("/secure"),
+									},
+								}},
+								BackendRefs: []gatewayv1.HTTPBackendRef{{
+									BackendRef: gatewayv1.BackendRef{
+										BackendObjectReference: gatewayv1.BackendObjectReference{
+											Name: "secure",
+											Port: PtrTo(gatewayv1.PortNumber(443)),
+										},
+									},
+								}},
+							}, {
+								Matches: []gatewayv1.HTTPRouteMatch{{
+									Path: &gatewayv1.HTTPPathMatch{
+										Type:  &gPathPrefix,
+										Value: PtrTo("/plain"),
+									},
+								}},
+								BackendRefs: []gatewayv1.HTTPBackendRef{{
+									BackendRef: gatewayv1.BackendRef{
+										BackendObjectReference: gatewayv1.BackendObjectReference{
+											Name: "plain",
+											Port: PtrTo(gatewayv1.PortNumber(80)),
+										},
+									},
+								}},
+							}},
+						},
+					},
+					{Namespace: "test", Name: "dual-example-net"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "dual-example-net", Namespace: "test"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{
+									Name: "dual",
+								}},
+							},
+							Hostnames: []gatewayv1.Hostname{"example.net"},
+							Rules: []gatewayv1.HTTPRouteRule{{
+								Matches: []gatewayv1.HTTPRouteMatch{{
+									Path: &gatewayv1.HTTPPathMatch{
+										Type:  &gPathPrefix,
+										Value: PtrTo("/"),
+									},
+								}},
+								BackendRefs: []gatewayv1.HTTPBackendRef{{
+									BackendRef: gatewayv1.BackendRef{
+										BackendObjectReference: gatewayv1.BackendObjectReference{
+											Name: "plain",
+											Port: PtrTo(gatewayv1.PortNumber(80)),
+										},
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
+		{
+			name: "ingress with TLS-only host rule",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls-only", Namespace: "test"},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{{
+						Hosts:      []string{"example.com"},
+						SecretName: "example-cert",
+					}},
+					Rules:            []networkingv1.IngressRule{{Host: "example.com"}},
+					IngressClassName: PtrTo("with-tls"),
+				},
+			}},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "test", Name: "with-tls"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "with-tls", Namespace: "test"},
+						Spec: gatewayv1.GatewaySpec{
+							GatewayClassName: "with-tls",
+							Listeners: []gatewayv1.Listener{{
+								Name:     "example-com-http",
+								Port:     80,
+								Protocol: gatewayv1.HTTPProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+							}, {
+								Name:     "example-com-https",
+								Port:     443,
+								Protocol: gatewayv1.HTTPSProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+								TLS: &gatewayv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayv1.SecretObjectReference{{
+										Name: "example-cert",
+									}},
+								},
+							}},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
+		{
+			name: "TLS-only host rule merged with the paths of another ingress",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls-only", Namespace: "test"},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{{
+						Hosts:      []string{"example.com"},
+						SecretName: "example-cert",
+					}},
+					Rules:            []networkingv1.IngressRule{{Host: "example.com"}},
+					IngressClassName: PtrTo("with-tls"),
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{Name: "paths", Namespace: "test"},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: &iPrefix,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "example",
+											Port: networkingv1.ServiceBackendPort{
+												Number: 3000,
+											},
+										},
+									},
+								}},
+							},
+						},
+					}},
+					IngressClassName: PtrTo("with-tls"),
+				},
+			}},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "test", Name: "with-tls"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "with-tls", Namespace: "test"},
+						Spec: gatewayv1.GatewaySpec{
+							GatewayClassName: "with-tls",
+							Listeners: []gatewayv1.Listener{{
+								Name:     "example-com-http",
+								Port:     80,
+								Protocol: gatewayv1.HTTPProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+							}, {
+								Name:     "example-com-https",
+								Port:     443,
+								Protocol: gatewayv1.HTTPSProtocolType,
+								Hostname: PtrTo(gatewayv1.Hostname("example.com")),
+								TLS: &gatewayv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayv1.SecretObjectReference{{
+										Name: "example-cert",
+									}},
+								},
+							}},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: "test", Name: "tls-only-example-com"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "tls-only-example-com", Namespace: "test"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{
+									Name: "with-tls",
+								}},
+							},
+							Hostnames: []gatewayv1.Hostname{"example.com"},
+							Rules: []gatewayv1.HTTPRouteRule{{
+								Matches: []gatewayv1.HTTPRouteMatch{{
+									Path: &gatewayv1.HTTPPathMatch{
+										Type:  &gPathPrefix,
+										Value: PtrTo("/foo"),
+									},
+								}},
+								BackendRefs: []gatewayv1.HTTPBackendRef{{
+									BackendRef: gatewayv1.BackendRef{
+										BackendObjectReference: gatewayv1.BackendObjectReference{
+											Name: "example",
+											Port: PtrTo(gatewayv1.PortNumber(3000)),
+										},
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 		{
 			name: "ingress with custom and default backend",
 			ingresses: []networkingv1.Ingress{{
@@ -458,3 +758,107 @@ func Test_ingresses2GatewaysAndHttpRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSCoversHost(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tls      networkingv1.IngressTLS
+		host     string
+		expected bool
+	}{
+		{name: "listed host", tls: networkingv1.IngressTLS{Hosts: []string{"example.com"}}, host: "example.com", expected: true},
+		{name: "other host", tls: networkingv1.IngressTLS{Hosts: []string{"example.com"}}, host: "example.net"},
+		{name: "no TLS hosts", tls: networkingv1.IngressTLS{}, host: "example.com", expected: true},
+		{name: "no rule host", tls: networkingv1.IngressTLS{Hosts: []string{"example.com"}}, expected: true},
+		{name: "wildcard", tls: networkingv1.IngressTLS{Hosts: []string{"*.example.com"}}, host: "foo.example.com", expected: true},
+		{name: "wildcard of a single label", tls: networkingv1.IngressTLS{Hosts: []string{"*.example.com"}}, host: "foo.bar.example.com"},
+		{name: "wildcard of the domain", tls: networkingv1.IngressTLS{Hosts: []string{"*.example.com"}}, host: "example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := TLSCoversHost(tc.tls, tc.host); got != tc.expected {
+				t.Errorf("Expected TLSCoversHost to return %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestToGatewayOmitsIngressOwnership(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "owned",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "example.com/v1", Kind: "App", Name: "app", UID: "app-uid"}},
+			Finalizers:      []string{"example.com/cleanup"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: PtrTo("ingress-nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &iPrefix,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "app",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	gatewayResources, errs := ToGateway([]networkingv1.Ingress{ingress}, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	for _, gateway := range gatewayResources.Gateways {
+		if len(gateway.OwnerReferences) != 0 || len(gateway.Finalizers) != 0 {
+			t.Errorf("Expected Gateway %s to have no owner references and finalizers, got %v and %v", gateway.Name, gateway.OwnerReferences, gateway.Finalizers)
+		}
+	}
+	for _, route := range gatewayResources.HTTPRoutes {
+		if len(route.OwnerReferences) != 0 || len(route.Finalizers) != 0 {
+			t.Errorf("Expected HTTPRoute %s to have no owner references and finalizers, got %v and %v", route.Name, route.OwnerReferences, route.Finalizers)
+		}
+	}
+}
+
+func TestToGatewayProgress(t *testing.T) {
+	iPrefix := networkingv1.PathTypePrefix
+	var ingresses []networkingv1.Ingress
+	for _, name := range []string{"a", "b", "c"} {
+		ingresses = append(ingresses, networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: PtrTo("ingress-nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: name + ".example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &iPrefix,
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: name,
+								Port: networkingv1.ServiceBackendPort{Number: 80},
+							}},
+						}},
+					}},
+				}},
+			},
+		})
+	}
+
+	var messages []string
+	progress := func(message string, _ int) { messages = append(messages, message) }
+	if _, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{Progress: progress}); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	expected := []string{"Converted 1/3 Ingresses", "Converted 2/3 Ingresses", "Converted 3/3 Ingresses"}
+	if diff := cmp.Diff(expected, messages); diff != "" {
+		t.Errorf("Unexpected progress messages (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// httpRouteFilterOrder is the order in which the filters of an HTTPRoute rule are
+// emitted. Request modifiers come first, so that the mirrored requests and the
+// redirects are computed from the modified request, and ExtensionRef filters,
+// whose semantics are implementation-specific, come last.
+var httpRouteFilterOrder = map[gatewayv1.HTTPRouteFilterType]int{
+	gatewayv1.HTTPRouteFilterRequestHeaderModifier:  0,
+	gatewayv1.HTTPRouteFilterResponseHeaderModifier: 1,
+	gatewayv1.HTTPRouteFilterURLRewrite:             2,
+	gatewayv1.HTTPRouteFilterRequestRedirect:        3,
+	gatewayv1.HTTPRouteFilterRequestMirror:          4,
+	gatewayv1.HTTPRouteFilterExtensionRef:           5,
+}
+
+// AssembleHTTPRouteFilters makes the filters of every HTTPRoute rule comply with
+// the Gateway API filter rules, as the filters of a rule may be produced by
+// several annotations independently.
+//
+// For every rule:
+//   - Identical filters that cannot be repeated are deduplicated, and header
+//     modifiers are merged into a single filter. If the filters conflict, only the
+//     first one is kept and an Error notification is returned.
+//   - A URLRewrite filter cannot be used with a RequestRedirect filter, so it is
+//     removed and an Error notification is returned.
+//   - A RequestRedirect filter cannot be used with backendRefs, which are never
+//     reached by the redirected requests, so they are removed and an Error
+//     notification is returned.
+//   - The filters are sorted in a deterministic order, with the modifiers before
+//     the redirect and mirror filters.
+func AssembleHTTPRouteFilters(gatewayResources *i2gw.GatewayResources) []notifications.Notification {
+	var notifs []notifications.Notification
+	for key := range gatewayResources.HTTPRoutes {
+		httpRoute := gatewayResources.HTTPRoutes[key]
+		for i := range httpRoute.Spec.Rules {
+			rule := &httpRoute.Spec.Rules[i]
+			if len(rule.Filters) == 0 {
+				continue
+			}
+			var errs []string
+			rule.Filters, errs = assembleFilters(rule.Filters)
+			if hasFilter(rule.Filters, gatewayv1.HTTPRouteFilterRequestRedirect) && len(rule.BackendRefs) > 0 {
+				rule.BackendRefs = nil
+				errs = append(errs, "a RequestRedirect filter cannot be used with backendRefs, the backendRefs were removed")
+			}
+			for _, err := range errs {
+				notifs = append(notifs, notifications.Notification{
+					Type:           notifications.ErrorNotification,
+					Message:        fmt.Sprintf("HTTPRoute %s/%s rule %d: %s", httpRoute.Namespace, httpRoute.Name, i, err),
+					CallingObjects: []client.Object{&httpRoute},
+				})
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return notifs
+}
+
+// assembleFilters returns the deduplicated and sorted filters, along with a
+// description of every conflict that was resolved.
+func assembleFilters(filters []gatewayv1.HTTPRouteFilter) ([]gatewayv1.HTTPRouteFilter, []string) {
+	var assembled []gatewayv1.HTTPRouteFilter
+	var errs []string
+	for _, filter := range filters {
+		i := filterIndex(assembled, filter.Type)
+		switch {
+		case filter.Type == gatewayv1.HTTPRouteFilterRequestMirror || filter.Type == gatewayv1.HTTPRouteFilterExtensionRef:
+			if !containsFilter(assembled, filter) {
+				assembled = append(assembled, filter)
+			}
+		case i < 0:
+			assembled = append(assembled, filter)
+		case apiequality.Semantic.DeepEqual(assembled[i], filter):
+		case filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier:
+			merged, ok := mergeHeaderFilters(assembled[i].RequestHeaderModifier, filter.RequestHeaderModifier)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("conflicting %s filters, only the first one was kept", filter.Type))
+				continue
+			}
+			assembled[i].RequestHeaderModifier = merged
+		case filter.Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+			merged, ok := mergeHeaderFilters(assembled[i].ResponseHeaderModifier, filter.ResponseHeaderModifier)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("conflicting %s filters, only the first one was kept", filter.Type))
+				continue
+			}
+			assembled[i].ResponseHeaderModifier = merged
+		default:
+			errs = append(errs, fmt.Sprintf("conflicting %s filters, only the first one was kept", filter.Type))
+		}
+	}
+
+	if hasFilter(assembled, gatewayv1.HTTPRouteFilterRequestRedirect) && hasFilter(assembled, gatewayv1.HTTPRouteFilterURLRewrite) {
+		assembled = removeFilters(assembled, gatewayv1.HTTPRouteFilterURLRewrite)
+		errs = append(errs, "a URLRewrite filter cannot be used with a RequestRedirect filter, the URLRewrite filter was removed")
+	}
+
+	sort.SliceStable(assembled, func(i, j int) bool {
+		return httpRouteFilterOrder[assembled[i].Type] < httpRouteFilterOrder[assembled[j].Type]
+	})
+	return assembled, errs
+}
+
+// mergeHeaderFilters merges two header filters into one. It returns false if
+// both filters set or add the same header with different values, or if a
+// header set or added by one of the filters is removed by the other.
+func mergeHeaderFilters(first, second *gatewayv1.HTTPHeaderFilter) (*gatewayv1.HTTPHeaderFilter, bool) {
+	values := map[string]string{}
+	for _, header := range modifiedHeaders(first) {
+		values[strings.ToLower(string(header.Name))] = header.Value
+	}
+	for _, header := range modifiedHeaders(second) {
+		if value, ok := values[strings.ToLower(string(header.Name))]; ok && value != header.Value {
+			return nil, false
+		}
+		if containsHeaderName(first.Remove, string(header.Name)) {
+			return nil, false
+		}
+	}
+	for _, name := range second.Remove {
+		if _, ok := values[strings.ToLower(name)]; ok {
+			return nil, false
+		}
+	}
+
+	merged := first.DeepCopy()
+	merged.Set = appendMissingHeaders(merged.Set, second.Set)
+	merged.Add = appendMissingHeaders(merged.Add, second.Add)
+	for _, name := range second.Remove {
+		if !containsHeaderName(merged.Remove, name) {
+			merged.Remove = append(merged.Remove, name)
+		}
+	}
+	return merged, true
+}
+
+// modifiedHeaders returns the headers set or added by the filter.
+func modifiedHeaders(filter *gatewayv1.HTTPHeaderFilter) []gatewayv1.HTTPHeader {
+	return append(append([]gatewayv1.HTTPHeader{}, filter.Set...), filter.Add...)
+}
+
+func appendMissingHeaders(headers, others []gatewayv1.HTTPHeader) []gatewayv1.HTTPHeader {
+	for _, other := range others {
+		found := false
+		for _, header := range headers {
+			if strings.EqualFold(string(header.Name), string(other.Name)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			headers = append(headers, other)
+		}
+	}
+	return headers
+}
+
+func containsHeaderName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterIndex(filters []gatewayv1.HTTPRouteFilter, filterType gatewayv1.HTTPRouteFilterType) int {
+	for i, filter := range filters {
+		if filter.Type == filterType {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasFilter(filters []gatewayv1.HTTPRouteFilter, filterType gatewayv1.HTTPRouteFilterType) bool {
+	return filterIndex(filters, filterType) >= 0
+}
+
+func containsFilter(filters []gatewayv1.HTTPRouteFilter, filter gatewayv1.HTTPRouteFilter) bool {
+	for _, f := range filters {
+		if apiequality.Semantic.DeepEqual(f, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFilters(filters []gatewayv1.HTTPRouteFilter, filterType gatewayv1.HTTPRouteFilterType) []gatewayv1.HTTPRouteFilter {
+	var kept []gatewayv1.HTTPRouteFilter
+	for _, filter := range filters {
+		if filter.Type != filterType {
+			kept = append(kept, filter)
+		}
+	}
+	return kept
+}
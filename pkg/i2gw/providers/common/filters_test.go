@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestAssembleHTTPRouteFilters(t *testing.T) {
+	redirect := gatewayv1.HTTPRouteFilter{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: PtrTo("https"), StatusCode: PtrTo(301)},
+	}
+	otherRedirect := gatewayv1.HTTPRouteFilter{
+		Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Hostname: PtrTo(gatewayv1.PreciseHostname("example.com"))},
+	}
+	rewrite := gatewayv1.HTTPRouteFilter{
+		Type: gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: PtrTo("/")},
+		},
+	}
+	mirror := gatewayv1.HTTPRouteFilter{
+		Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: "mirror"}},
+	}
+	requestHeaders := func(headerFilter gatewayv1.HTTPHeaderFilter) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: &headerFilter}
+	}
+	backendRefs := []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "foo"}}}}
+
+	testCases := []struct {
+		name                  string
+		filters               []gatewayv1.HTTPRouteFilter
+		backendRefs           []gatewayv1.HTTPBackendRef
+		expectedFilters       []gatewayv1.HTTPRouteFilter
+		expectedBackendRefs   []gatewayv1.HTTPBackendRef
+		expectedNotifications []string
+	}{
+		{
+			name:                "modifiers are ordered before mirror",
+			filters:             []gatewayv1.HTTPRouteFilter{mirror, rewrite, requestHeaders(gatewayv1.HTTPHeaderFilter{Remove: []string{"foo"}})},
+			backendRefs:         backendRefs,
+			expectedFilters:     []gatewayv1.HTTPRouteFilter{requestHeaders(gatewayv1.HTTPHeaderFilter{Remove: []string{"foo"}}), rewrite, mirror},
+			expectedBackendRefs: backendRefs,
+		},
+		{
+			name:                "identical filters are deduplicated",
+			filters:             []gatewayv1.HTTPRouteFilter{rewrite, mirror, rewrite, mirror},
+			backendRefs:         backendRefs,
+			expectedFilters:     []gatewayv1.HTTPRouteFilter{rewrite, mirror},
+			expectedBackendRefs: backendRefs,
+		},
+		{
+			name: "header modifiers are merged",
+			filters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "a", Value: "1"}}}),
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "A", Value: "1"}, {Name: "b", Value: "2"}}, Remove: []string{"c"}}),
+			},
+			backendRefs: backendRefs,
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}, Remove: []string{"c"}}),
+			},
+			expectedBackendRefs: backendRefs,
+		},
+		{
+			name: "conflicting header modifiers",
+			filters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "a", Value: "1"}}}),
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Remove: []string{"a"}}),
+			},
+			backendRefs: backendRefs,
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				requestHeaders(gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "a", Value: "1"}}}),
+			},
+			expectedBackendRefs:   backendRefs,
+			expectedNotifications: []string{"HTTPRoute default/foo rule 0: conflicting RequestHeaderModifier filters, only the first one was kept"},
+		},
+		{
+			name:            "conflicting redirects",
+			filters:         []gatewayv1.HTTPRouteFilter{redirect, otherRedirect},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{redirect},
+			expectedNotifications: []string{
+				"HTTPRoute default/foo rule 0: conflicting RequestRedirect filters, only the first one was kept",
+			},
+		},
+		{
+			name:            "rewrite and redirect",
+			filters:         []gatewayv1.HTTPRouteFilter{rewrite, redirect},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{redirect},
+			expectedNotifications: []string{
+				"HTTPRoute default/foo rule 0: a URLRewrite filter cannot be used with a RequestRedirect filter, the URLRewrite filter was removed",
+			},
+		},
+		{
+			name:            "redirect and backendRefs",
+			filters:         []gatewayv1.HTTPRouteFilter{redirect},
+			backendRefs:     backendRefs,
+			expectedFilters: []gatewayv1.HTTPRouteFilter{redirect},
+			expectedNotifications: []string{
+				"HTTPRoute default/foo rule 0: a RequestRedirect filter cannot be used with backendRefs, the backendRefs were removed",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := types.NamespacedName{Namespace: "default", Name: "foo"}
+			gatewayResources := i2gw.GatewayResources{
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					key: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							Rules: []gatewayv1.HTTPRouteRule{{Filters: tc.filters, BackendRefs: tc.backendRefs}},
+						},
+					},
+				},
+			}
+
+			notifs := AssembleHTTPRouteFilters(&gatewayResources)
+
+			rule := gatewayResources.HTTPRoutes[key].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedBackendRefs, rule.BackendRefs); diff != "" {
+				t.Errorf("Unexpected backendRefs (-want +got):\n%s", diff)
+			}
+			var messages []string
+			for _, n := range notifs {
+				if n.Type != notifications.ErrorNotification {
+					t.Errorf("Expected an Error notification, got %s", n.Type)
+				}
+				messages = append(messages, n.Message)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, messages); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProviderLabel is set on the routes generated from the Ingresses that several
+// ingress controllers may serve, to the name of the provider that generated
+// them.
+const ProviderLabel = "ingress2gateway.k8s.io/provider"
+
+// inferredIngressClassAnnotation marks the Ingresses without ingress class that
+// were given the class of the provider whose annotations they carry.
+const inferredIngressClassAnnotation = "ingress2gateway.k8s.io/inferred-ingress-class"
+
+// ingressController is an ingress controller whose annotations are recognized.
+type ingressController struct {
+	// name is the name of the controller, which is the name of its provider when
+	// there is one.
+	name string
+	// ingressClasses are the ingress classes handled by the controller, the
+	// empty class meaning that it handles the Ingresses without class.
+	ingressClasses []string
+	// annotationPrefixes are the prefixes of the annotations of the controller.
+	annotationPrefixes []string
+}
+
+// ingressControllers are the known ingress controllers, sorted by name.
+var ingressControllers = []ingressController{
+	{name: "apisix", ingressClasses: []string{"apisix"}, annotationPrefixes: []string{"k8s.apisix.apache.org/"}},
+	{name: "aws-load-balancer-controller", ingressClasses: []string{"alb"}, annotationPrefixes: []string{"alb.ingress.kubernetes.io/"}},
+	{name: "azure-appgw", ingressClasses: []string{"azure-application-gateway", "azure/application-gateway"}, annotationPrefixes: []string{"appgw.ingress.kubernetes.io/"}},
+	{name: "contour", ingressClasses: []string{"contour"}, annotationPrefixes: []string{"projectcontour.io/"}},
+	{name: "gce", ingressClasses: []string{"gce", "gce-internal", ""}, annotationPrefixes: []string{"ingress.gcp.kubernetes.io/", "networking.gke.io/", "kubernetes.io/ingress.global-static-ip-name", "kubernetes.io/ingress.regional-static-ip-name"}},
+	{name: "haproxy", ingressClasses: []string{"haproxy"}, annotationPrefixes: []string{"haproxy.org/"}},
+	{name: "ingress-nginx", ingressClasses: []string{"nginx"}, annotationPrefixes: []string{"nginx.ingress.kubernetes.io/"}},
+	{name: "kong", ingressClasses: []string{"kong"}, annotationPrefixes: []string{"konghq.com/"}},
+	{name: "traefik", ingressClasses: []string{"traefik"}, annotationPrefixes: []string{"traefik.ingress.kubernetes.io/"}},
+}
+
+// IngressControllerName returns the name of the known ingress controller
+// handling the ingress class, which is the name of its provider when there is
+// one, or false if the class is empty or unknown.
+func IngressControllerName(ingressClass string) (string, bool) {
+	if ingressClass == "" {
+		return "", false
+	}
+	for _, controller := range ingressControllers {
+		if slices.Contains(controller.ingressClasses, ingressClass) {
+			return controller.name, true
+		}
+	}
+	return "", false
+}
+
+// annotatedControllers returns the known ingress controllers whose annotations
+// the Ingress carries.
+func annotatedControllers(ingress networkingv1.Ingress) []ingressController {
+	var controllers []ingressController
+	for _, controller := range ingressControllers {
+		if slices.ContainsFunc(controller.annotationPrefixes, func(prefix string) bool {
+			for annotation := range ingress.Annotations {
+				if strings.HasPrefix(annotation, prefix) {
+					return true
+				}
+			}
+			return false
+		}) {
+			controllers = append(controllers, controller)
+		}
+	}
+	return controllers
+}
+
+// selectIngress returns the Ingress converted by the provider of the ingress
+// classes, and whether the provider converts it. The ingress class of the
+// Ingress selects the provider. An Ingress without class is converted by a
+// single provider of the conversion, see classlessIngressClass. When that
+// provider is the one of a controller whose annotations the Ingress carries, a
+// copy of the Ingress is returned, given the first class of the controller, so
+// that the read Ingress is kept as is.
+func selectIngress(ingress *networkingv1.Ingress, ingressClasses sets.Set[string], providers []string) (*networkingv1.Ingress, bool) {
+	ingressClass := GetIngressClass(*ingress)
+	if ingressClass != "" {
+		return ingress, ingressClasses.Has(ingressClass)
+	}
+	ingressClass = classlessIngressClass(*ingress, ingressClasses, providers)
+	if ingressClass == "" || !ingressClasses.Has(ingressClass) {
+		return ingress, ingressClasses.Has(ingressClass)
+	}
+	inferred := ingress.DeepCopy()
+	inferred.Spec.IngressClassName = PtrTo(ingressClass)
+	if inferred.Annotations == nil {
+		inferred.Annotations = map[string]string{}
+	}
+	inferred.Annotations[inferredIngressClassAnnotation] = "true"
+	return inferred, true
+}
+
+// classlessIngressClass returns the ingress class an Ingress without class is
+// converted as, so that exactly one of the providers converts it: the first
+// class of the first controller by name whose annotations it carries and whose
+// provider is one of the providers, or else the empty class, converted by the
+// provider handling the Ingresses without class, like gce. Without providers,
+// the Ingress is converted by the provider of the ingress classes if it carries
+// the annotations of its controller.
+func classlessIngressClass(ingress networkingv1.Ingress, ingressClasses sets.Set[string], providers []string) string {
+	for _, controller := range annotatedControllers(ingress) {
+		if providers != nil && !slices.Contains(providers, controller.name) {
+			continue
+		}
+		if slices.Contains(controller.ingressClasses, "") {
+			return ""
+		}
+		if providers == nil && !ingressClasses.Has(controller.ingressClasses[0]) {
+			continue
+		}
+		return controller.ingressClasses[0]
+	}
+	return ""
+}
+
+// ResolveMixedIngressControllers returns the notifications for the ingresses
+// carrying annotations of other ingress controllers than the one of the
+// provider, or converted without ingress class. The annotations of the other
+// controllers of an Ingress with an ingress class are ignored, which a Warning
+// notification reports. An Ingress without class may be served by any of the
+// controllers of its annotations, or by the one serving the Ingresses without
+// class, but is only converted by the provider selected by selectIngress: the
+// routes generated from it are labeled with ProviderLabel, and a Warning
+// notification reports the ambiguity. An Ingress without class converted by the
+// only controller of its annotations gets an Info notification.
+func ResolveMixedIngressControllers(gatewayResources *i2gw.GatewayResources, ingresses []networkingv1.Ingress, providerName string) []notifications.Notification {
+	var notifs []notifications.Notification
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		controllers := annotatedControllers(*ingress)
+		ingressClass := GetIngressClass(*ingress)
+		inferred := ingress.Annotations[inferredIngressClassAnnotation] == "true"
+
+		if ingressClass != "" && !inferred {
+			var ignored []string
+			for _, controller := range controllers {
+				if controller.name != providerName && !slices.Contains(controller.ingressClasses, ingressClass) {
+					ignored = append(ignored, fmt.Sprintf("%s (%s)", controller.name, strings.Join(controller.annotationPrefixes, ", ")))
+				}
+			}
+			if len(ignored) > 0 {
+				notifs = append(notifs, notifications.Notification{
+					Type:           notifications.WarningNotification,
+					Message:        fmt.Sprintf("the ingress class %s of the Ingress selects provider %s, its annotations of %s are ignored", ingressClass, providerName, strings.Join(ignored, ", ")),
+					CallingObjects: []client.Object{ingress},
+				})
+			}
+			continue
+		}
+
+		var candidates []string
+		for _, controller := range controllers {
+			candidates = append(candidates, controller.name)
+		}
+		if !slices.Contains(candidates, providerName) {
+			candidates = append(candidates, providerName)
+			slices.Sort(candidates)
+		}
+		if len(candidates) < 2 {
+			if inferred {
+				notifs = append(notifs, notifications.Notification{
+					Type:           notifications.InfoNotification,
+					Message:        fmt.Sprintf("the Ingress has no ingress class, it is converted by provider %s as an Ingress of class %s as it carries its annotations", providerName, ingressClass),
+					CallingObjects: []client.Object{ingress},
+				})
+			}
+			continue
+		}
+		labelIngressRoutes(gatewayResources, ingress, providerName)
+		notifs = append(notifs, notifications.Notification{
+			Type: notifications.WarningNotification,
+			Message: fmt.Sprintf("the Ingress has no ingress class and may be served by the ingress controllers %s, it is only converted by provider %s and the generated routes are labeled %s=%s, only apply them if its controller serves the Ingress",
+				strings.Join(candidates, ", "), providerName, ProviderLabel, providerName),
+			CallingObjects: []client.Object{ingress},
+		})
+	}
+	return notifs
+}
+
+// labelIngressRoutes sets ProviderLabel on the HTTPRoutes and GRPCRoutes
+// generated from the Ingress.
+func labelIngressRoutes(gatewayResources *i2gw.GatewayResources, ingress *networkingv1.Ingress, providerName string) {
+	for key, route := range gatewayResources.HTTPRoutes {
+		if !provenance.ProvenanceAggr.HasIngressSource(provenance.ObjectRef{Kind: HTTPRouteGVK.Kind, NamespacedName: key}, ingress.Namespace, ingress.Name) {
+			continue
+		}
+		route.Labels = withProviderLabel(route.Labels, providerName)
+		gatewayResources.HTTPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		if !provenance.ProvenanceAggr.HasIngressSource(provenance.ObjectRef{Kind: GRPCRouteGVK.Kind, NamespacedName: key}, ingress.Namespace, ingress.Name) {
+			continue
+		}
+		route.Labels = withProviderLabel(route.Labels, providerName)
+		gatewayResources.GRPCRoutes[key] = route
+	}
+}
+
+func withProviderLabel(labels map[string]string, providerName string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ProviderLabel] = providerName
+	return labels
+}
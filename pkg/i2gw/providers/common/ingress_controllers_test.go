@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+)
+
+func TestResolveMixedIngressControllers(t *testing.T) {
+	newIngress := func(ingressClass *string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target":       "/",
+				"traefik.ingress.kubernetes.io/router.entrypoints": "web",
+			}},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ingressClass,
+				Rules: []networkingv1.IngressRule{{
+					Host: "foo.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     "/",
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		ingressClass     *string
+		ingressClasses   sets.Set[string]
+		providerName     string
+		expectedSelected bool
+		expectedClass    string
+		expectedNotif    *notifications.Notification
+		expectedLabel    string
+	}{
+		{
+			name:             "ingress class selects the provider",
+			ingressClass:     ptr.To("nginx"),
+			ingressClasses:   sets.New("nginx"),
+			providerName:     "ingress-nginx",
+			expectedSelected: true,
+			expectedClass:    "nginx",
+			expectedNotif: &notifications.Notification{
+				Type:    notifications.WarningNotification,
+				Message: "the ingress class nginx of the Ingress selects provider ingress-nginx, its annotations of traefik (traefik.ingress.kubernetes.io/) are ignored",
+			},
+		},
+		{
+			name:           "ingress class of another provider",
+			ingressClass:   ptr.To("nginx"),
+			ingressClasses: sets.New("kong"),
+			providerName:   "kong",
+		},
+		{
+			name:             "no ingress class, converted by the provider of the annotations",
+			ingressClasses:   sets.New("nginx"),
+			providerName:     "ingress-nginx",
+			expectedSelected: true,
+			expectedClass:    "nginx",
+			expectedNotif: &notifications.Notification{
+				Type:    notifications.WarningNotification,
+				Message: "the Ingress has no ingress class and may be served by the ingress controllers ingress-nginx, traefik, it is only converted by provider ingress-nginx and the generated routes are labeled ingress2gateway.k8s.io/provider=ingress-nginx, only apply them if its controller serves the Ingress",
+			},
+			expectedLabel: "ingress-nginx",
+		},
+		{
+			name:           "no ingress class, annotations of other controllers",
+			ingressClasses: sets.New("kong"),
+			providerName:   "kong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+
+			read := newIngress(tc.ingressClass)
+			ingress, selected := selectIngress(&read, tc.ingressClasses, nil)
+			if selected != tc.expectedSelected {
+				t.Fatalf("Expected the Ingress to be selected: %t, got %t", tc.expectedSelected, selected)
+			}
+			if !tc.expectedSelected {
+				return
+			}
+			if class := GetIngressClass(*ingress); class != tc.expectedClass {
+				t.Errorf("Expected ingress class %q, got %q", tc.expectedClass, class)
+			}
+			if diff := cmp.Diff(newIngress(tc.ingressClass), read); diff != "" {
+				t.Errorf("Expected the read Ingress to be kept as is (-want +got):\n%s", diff)
+			}
+
+			ingresses := []networkingv1.Ingress{*ingress}
+			gatewayResources, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+			notifs := ResolveMixedIngressControllers(&gatewayResources, ingresses, tc.providerName)
+			if tc.expectedNotif == nil {
+				if len(notifs) > 0 {
+					t.Errorf("Expected no notification, got %+v", notifs)
+				}
+			} else if len(notifs) != 1 || notifs[0].Type != tc.expectedNotif.Type || notifs[0].Message != tc.expectedNotif.Message {
+				t.Errorf("Expected notification %q of type %s, got %+v", tc.expectedNotif.Message, tc.expectedNotif.Type, notifs)
+			}
+
+			route := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+			if label := route.Labels[ProviderLabel]; label != tc.expectedLabel {
+				t.Errorf("Expected label %s=%q, got %q", ProviderLabel, tc.expectedLabel, label)
+			}
+		})
+	}
+}
+
+func TestSelectIngressWithoutClass(t *testing.T) {
+	ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	if _, selected := selectIngress(&ingress, sets.New("nginx"), nil); selected {
+		t.Errorf("Expected an Ingress without class nor annotations not to be selected by the nginx class")
+	}
+	selected, ok := selectIngress(&ingress, sets.New("gce", ""), nil)
+	if !ok {
+		t.Errorf("Expected an Ingress without class to be selected by the empty class")
+	}
+	if selected.Spec.IngressClassName != nil {
+		t.Errorf("Expected the ingress class to be kept empty, got %s", *selected.Spec.IngressClassName)
+	}
+}
+
+func TestSelectIngressWithoutClassOwner(t *testing.T) {
+	ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        "foo",
+		Namespace:   "default",
+		Annotations: map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+	}}
+	gceClasses := sets.New("gce", "gce-internal", "")
+
+	testCases := []struct {
+		name          string
+		providers     []string
+		expectedNginx bool
+		expectedGCE   bool
+	}{
+		{
+			name:          "provider of the annotations and provider of the Ingresses without class",
+			providers:     []string{"gce", "ingress-nginx"},
+			expectedNginx: true,
+		},
+		{
+			name:        "provider of the Ingresses without class only",
+			providers:   []string{"gce"},
+			expectedGCE: true,
+		},
+		{
+			name:          "provider of the annotations only",
+			providers:     []string{"ingress-nginx"},
+			expectedNginx: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, selected := selectIngress(&ingress, sets.New("nginx"), tc.providers); selected != tc.expectedNginx {
+				t.Errorf("Expected the Ingress to be selected by ingress-nginx: %t, got %t", tc.expectedNginx, selected)
+			}
+			if _, selected := selectIngress(&ingress, gceClasses, tc.providers); selected != tc.expectedGCE {
+				t.Errorf("Expected the Ingress to be selected by gce: %t, got %t", tc.expectedGCE, selected)
+			}
+		})
+	}
+}
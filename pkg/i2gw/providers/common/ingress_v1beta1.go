@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strconv"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// legacyIngressGVKs are the removed Ingress versions that are converted to
+// networking.k8s.io/v1 when read from a file. extensions/v1beta1 and
+// networking.k8s.io/v1beta1 Ingresses have the same schema.
+var legacyIngressGVKs = map[schema.GroupVersionKind]bool{
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:        true,
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}: true,
+}
+
+// isIngressGroupKind returns whether the GroupKind is an Ingress of any of the
+// supported groups.
+func isIngressGroupKind(gk schema.GroupKind) bool {
+	return gk == IngressGVK.GroupKind() || gk == schema.GroupKind{Group: "extensions", Kind: "Ingress"}
+}
+
+// ingressFromUnstructured converts an unstructured Ingress of a supported
+// version to a networking.k8s.io/v1 Ingress. It returns false if the version
+// is not supported.
+func ingressFromUnstructured(obj *unstructured.Unstructured) (*networkingv1.Ingress, bool, error) {
+	if obj.GroupVersionKind() == IngressGVK {
+		var ingress networkingv1.Ingress
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingress); err != nil {
+			return nil, true, err
+		}
+		return &ingress, true, nil
+	}
+	if !legacyIngressGVKs[obj.GroupVersionKind()] {
+		return nil, false, nil
+	}
+	var legacy networkingv1beta1.Ingress
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &legacy); err != nil {
+		return nil, true, err
+	}
+	return ingressFromV1beta1(legacy), true, nil
+}
+
+// ingressFromV1beta1 converts a v1beta1 Ingress to networking.k8s.io/v1.
+func ingressFromV1beta1(legacy networkingv1beta1.Ingress) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: IngressGVK.GroupVersion().String(), Kind: IngressGVK.Kind},
+		ObjectMeta: legacy.ObjectMeta,
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: legacy.Spec.IngressClassName,
+		},
+	}
+	if legacy.Spec.Backend != nil {
+		ingress.Spec.DefaultBackend = backendFromV1beta1(*legacy.Spec.Backend)
+	}
+	for _, tls := range legacy.Spec.TLS {
+		ingress.Spec.TLS = append(ingress.Spec.TLS, networkingv1.IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+	}
+	for _, rule := range legacy.Spec.Rules {
+		converted := networkingv1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			converted.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				converted.HTTP.Paths = append(converted.HTTP.Paths, networkingv1.HTTPIngressPath{
+					Path:     path.Path,
+					PathType: (*networkingv1.PathType)(path.PathType),
+					Backend:  *backendFromV1beta1(path.Backend),
+				})
+			}
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, converted)
+	}
+	return ingress
+}
+
+// backendFromV1beta1 converts a v1beta1 backend. The servicePort may be a
+// number, a numeric string like "8080", which is converted to the port number,
+// or the name of a Service port.
+func backendFromV1beta1(legacy networkingv1beta1.IngressBackend) *networkingv1.IngressBackend {
+	if legacy.Resource != nil {
+		return &networkingv1.IngressBackend{Resource: legacy.Resource}
+	}
+	backend := &networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{Name: legacy.ServiceName},
+	}
+	if legacy.ServicePort.Type == intstr.Int {
+		backend.Service.Port.Number = legacy.ServicePort.IntVal
+	} else {
+		backend.Service.Port = parseServiceBackendPort(legacy.ServicePort.StrVal)
+	}
+	return backend
+}
+
+// parseServiceBackendPort returns the port number of a numeric string, or the
+// port name otherwise.
+func parseServiceBackendPort(port string) networkingv1.ServiceBackendPort {
+	if number, err := strconv.ParseInt(port, 10, 32); err == nil {
+		return networkingv1.ServiceBackendPort{Number: int32(number)}
+	}
+	return networkingv1.ServiceBackendPort{Name: port}
+}
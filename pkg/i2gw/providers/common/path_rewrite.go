@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// PathPrefixRewrite is the common representation of the provider annotations
+// replacing the path prefix matched by a rule before the requests are forwarded
+// to the backends, like the strip-prefix annotations of ingress-nginx, Kong and
+// Application Gateway. The providers parse their annotations to a
+// PathPrefixRewrite, so that they all generate the same URLRewrite filters.
+type PathPrefixRewrite struct {
+	// Replacement replaces the matched prefix, `/` stripping it.
+	Replacement string
+}
+
+// StripPathPrefix is the rewrite removing the matched prefix from the paths.
+var StripPathPrefix = PathPrefixRewrite{Replacement: "/"}
+
+// PathModifier returns the path modifier of the rewrite for the matches of the
+// type. The matched prefix of the PathPrefix matches is replaced, and the full
+// path of the Exact matches, which are their own prefix. There is none for the
+// RegularExpression matches, whose matched prefix is not known.
+func (r PathPrefixRewrite) PathModifier(matchType gatewayv1.PathMatchType) *gatewayv1.HTTPPathModifier {
+	switch matchType {
+	case gatewayv1.PathMatchPathPrefix:
+		return &gatewayv1.HTTPPathModifier{
+			Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+			ReplacePrefixMatch: PtrTo(r.Replacement),
+		}
+	case gatewayv1.PathMatchExact:
+		return &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: PtrTo(r.Replacement),
+		}
+	default:
+		return nil
+	}
+}
+
+// Apply adds the URLRewrite filter of the rewrite to the rule, and returns
+// false if the path of the rule cannot be rewritten. The filter is chosen by
+// the first path match of the rule, as the rules generated from the Ingress
+// paths have a single path match.
+func (r PathPrefixRewrite) Apply(rule *gatewayv1.HTTPRouteRule) bool {
+	matchType := gatewayv1.PathMatchPathPrefix
+	if len(rule.Matches) > 0 && rule.Matches[0].Path != nil && rule.Matches[0].Path.Type != nil {
+		matchType = *rule.Matches[0].Path.Type
+	}
+	modifier := r.PathModifier(matchType)
+	if modifier == nil {
+		return false
+	}
+	rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+		Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: modifier},
+	})
+	return true
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPathPrefixRewriteApply(t *testing.T) {
+	testCases := []struct {
+		name             string
+		matchType        *gatewayv1.PathMatchType
+		expectedModifier *gatewayv1.HTTPPathModifier
+	}{
+		{
+			name:             "default match type",
+			expectedModifier: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: PtrTo("/v2")},
+		},
+		{
+			name:             "prefix",
+			matchType:        PtrTo(gatewayv1.PathMatchPathPrefix),
+			expectedModifier: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: PtrTo("/v2")},
+		},
+		{
+			name:             "exact",
+			matchType:        PtrTo(gatewayv1.PathMatchExact),
+			expectedModifier: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: PtrTo("/v2")},
+		},
+		{
+			name:      "regular expression",
+			matchType: PtrTo(gatewayv1.PathMatchRegularExpression),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: tc.matchType, Value: PtrTo("/v1")}}}}
+			applied := PathPrefixRewrite{Replacement: "/v2"}.Apply(&rule)
+			if applied != (tc.expectedModifier != nil) {
+				t.Fatalf("Expected Apply to return %t, got %t", tc.expectedModifier != nil, applied)
+			}
+			var expectedFilters []gatewayv1.HTTPRouteFilter
+			if tc.expectedModifier != nil {
+				expectedFilters = []gatewayv1.HTTPRouteFilter{{
+					Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: tc.expectedModifier},
+				}}
+			}
+			if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
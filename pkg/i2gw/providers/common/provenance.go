@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// RecordIngressProvenance records that the field of the generated object of the
+// given kind was produced by a field or annotation of the Ingress. An empty
+// fieldPath refers to the generated object itself.
+func RecordIngressProvenance(kind string, key types.NamespacedName, fieldPath string, ingress *networkingv1.Ingress, from string) {
+	provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: kind, NamespacedName: key}, fieldPath, provenance.IngressSource(ingress.Namespace, ingress.Name, from))
+}
+
+// RecordUnconvertedAnnotations records, for every generated HTTPRoute and
+// GRPCRoute, the annotations of its source Ingresses that start with the prefix
+// of the provider annotations, like `nginx.ingress.kubernetes.io/`, and are not
+// among the supported annotations.
+func RecordUnconvertedAnnotations(gatewayResources *i2gw.GatewayResources, ingresses []networkingv1.Ingress, prefix string, supported []string) {
+	var refs []provenance.ObjectRef
+	for key := range gatewayResources.HTTPRoutes {
+		refs = append(refs, provenance.ObjectRef{Kind: HTTPRouteGVK.Kind, NamespacedName: key})
+	}
+	for key := range gatewayResources.GRPCRoutes {
+		refs = append(refs, provenance.ObjectRef{Kind: GRPCRouteGVK.Kind, NamespacedName: key})
+	}
+
+	for _, ingress := range ingresses {
+		var unconverted []string
+		var annotations []string
+		for annotation := range ingress.Annotations {
+			if strings.HasPrefix(annotation, prefix) {
+				annotations = append(annotations, annotation)
+			}
+		}
+		slices.Sort(annotations)
+		for _, annotation := range annotations {
+			if slices.Contains(supported, annotation) {
+				klog.V(4).Infof("Ingress %s/%s: annotation %s=%q is converted", ingress.Namespace, ingress.Name, annotation, ingress.Annotations[annotation])
+				continue
+			}
+			klog.V(4).Infof("Ingress %s/%s: annotation %s=%q is not converted", ingress.Namespace, ingress.Name, annotation, ingress.Annotations[annotation])
+			unconverted = append(unconverted, annotation)
+		}
+		if len(unconverted) == 0 {
+			continue
+		}
+		for _, ref := range refs {
+			if !provenance.ProvenanceAggr.HasIngressSource(ref, ingress.Namespace, ingress.Name) {
+				continue
+			}
+			for _, annotation := range unconverted {
+				provenance.ProvenanceAggr.RecordUnconverted(ref, annotation)
+			}
+		}
+	}
+}
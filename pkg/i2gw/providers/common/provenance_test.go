@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestRecordUnconvertedAnnotations(t *testing.T) {
+	provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+	provenance.ProvenanceAggr.Unconverted = map[provenance.ObjectRef][]string{}
+
+	newIngress := func(name, host string, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     "/",
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{
+		newIngress("foo", "foo.com", map[string]string{
+			"example.com/rewrite-target": "/",
+			"example.com/supported":      "true",
+			"example.com/modsecurity":    "true",
+			"other.com/rewrite-target":   "/",
+		}),
+		newIngress("bar", "bar.com", map[string]string{"example.com/supported": "true"}),
+	}
+
+	gatewayResources, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	RecordUnconvertedAnnotations(&gatewayResources, ingresses, "example.com/", []string{"example.com/supported"})
+
+	expected := map[string][]string{
+		"foo-foo-com": {"example.com/modsecurity", "example.com/rewrite-target"},
+		"bar-bar-com": {},
+	}
+	for name, want := range expected {
+		ref := provenance.ObjectRef{Kind: HTTPRouteGVK.Kind, NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+		if diff := cmp.Diff(want, provenance.ProvenanceAggr.UnconvertedAnnotations(ref)); diff != "" {
+			t.Errorf("Unexpected unconverted annotations of HTTPRoute %s (-want +got):\n%s", name, diff)
+		}
+	}
+}
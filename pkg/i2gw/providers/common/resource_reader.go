@@ -22,18 +22,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-func ReadIngressesFromCluster(ctx context.Context, client client.Client, ingressClasses sets.Set[string]) (map[types.NamespacedName]*networkingv1.Ingress, error) {
+// ReadIngressesFromCluster reads the Ingresses of the cluster converted by the
+// provider of the ingress classes, among the providers of the conversion.
+func ReadIngressesFromCluster(ctx context.Context, client client.Client, ingressClasses sets.Set[string], providers []string) (map[types.NamespacedName]*networkingv1.Ingress, error) {
 	var ingressList networkingv1.IngressList
 	err := client.List(ctx, &ingressList)
 	if err != nil {
@@ -41,17 +47,35 @@ func ReadIngressesFromCluster(ctx context.Context, client client.Client, ingress
 	}
 
 	ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
-	for i, ingress := range ingressList.Items {
-		if !ingressClasses.Has(GetIngressClass(ingress)) {
+	for i := range ingressList.Items {
+		ingress, ok := selectIngress(&ingressList.Items[i], ingressClasses, providers)
+		if !ok {
 			continue
 		}
-		ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &ingressList.Items[i]
+		ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = ingress
 	}
 
 	return ingresses, nil
 }
 
-func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[string]) (map[types.NamespacedName]*networkingv1.Ingress, error) {
+func ReadServicesFromCluster(ctx context.Context, client client.Client) (map[types.NamespacedName]*corev1.Service, error) {
+	var serviceList corev1.ServiceList
+	err := client.List(ctx, &serviceList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services from the cluster: %w", err)
+	}
+
+	services := map[types.NamespacedName]*corev1.Service{}
+	for i, service := range serviceList.Items {
+		services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = &serviceList.Items[i]
+	}
+
+	return services, nil
+}
+
+// ReadIngressesFromFile reads the Ingresses of the file converted by the
+// provider of the ingress classes, among the providers of the conversion.
+func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[string], providers []string) (map[types.NamespacedName]*networkingv1.Ingress, error) {
 	stream, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
@@ -64,42 +88,137 @@ func ReadIngressesFromFile(filename, namespace string, ingressClasses sets.Set[s
 
 	ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
 	for _, f := range unstructuredObjects {
-		if !f.GroupVersionKind().Empty() && f.GroupVersionKind().Kind == "Ingress" {
-			var ingress networkingv1.Ingress
-			err = runtime.DefaultUnstructuredConverter.
-				FromUnstructured(f.UnstructuredContent(), &ingress)
-			if err != nil {
-				return nil, err
-			}
-			if !ingressClasses.Has(GetIngressClass(ingress)) {
-				continue
-			}
-			ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = &ingress
+		if !isIngressGroupKind(f.GroupVersionKind().GroupKind()) {
+			continue
 		}
-
+		ingress, ok, err := ingressFromUnstructured(f)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			log.Printf("skipped Ingress %s/%s with unsupported APIVersion: %v", f.GetNamespace(), f.GetName(), f.GetAPIVersion())
+			continue
+		}
+		if ingress, ok = selectIngress(ingress, ingressClasses, providers); !ok {
+			continue
+		}
+		ingresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = ingress
 	}
 	return ingresses, nil
 }
 
+// ReadServicesFromFile reads the Services present in the file, so that the ingresses
+// backends can be resolved without querying the cluster.
+func ReadServicesFromFile(filename, namespace string) (map[types.NamespacedName]*corev1.Service, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	unstructuredObjects, err := ExtractObjectsFromReader(bytes.NewReader(stream), namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	services := map[types.NamespacedName]*corev1.Service{}
+	for _, f := range unstructuredObjects {
+		if f.GroupVersionKind() != corev1.SchemeGroupVersion.WithKind("Service") {
+			continue
+		}
+		var service corev1.Service
+		err = runtime.DefaultUnstructuredConverter.
+			FromUnstructured(f.UnstructuredContent(), &service)
+		if err != nil {
+			return nil, err
+		}
+		services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = &service
+	}
+	return services, nil
+}
+
+// ReadGatewaysFromFile reads the Gateways present in the file.
+func ReadGatewaysFromFile(filename string) ([]gatewayv1.Gateway, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	unstructuredObjects, err := ExtractObjectsFromReader(bytes.NewReader(stream), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	var gateways []gatewayv1.Gateway
+	for _, f := range unstructuredObjects {
+		if f.GroupVersionKind() != GatewayGVK {
+			continue
+		}
+		var gateway gatewayv1.Gateway
+		err = runtime.DefaultUnstructuredConverter.
+			FromUnstructured(f.UnstructuredContent(), &gateway)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, gateway)
+	}
+	return gateways, nil
+}
+
+// ValidateObjectKindsFromFile ensures that every document of the input file is a
+// Kubernetes object whose kind is one of expectedKinds. It is used to fail early
+// on unexpected resources instead of silently skipping them.
+func ValidateObjectKindsFromFile(filename string, expectedKinds sets.Set[schema.GroupKind]) error {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %v: %w", filename, err)
+	}
+
+	objs, err := extractObjectsFromReader(bytes.NewReader(stream), "", true)
+	if err != nil {
+		return fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	for _, obj := range objs {
+		gk := obj.GroupVersionKind().GroupKind()
+		if isIngressGroupKind(gk) {
+			// The legacy extensions Ingresses are read as networking.k8s.io Ingresses.
+			gk = IngressGVK.GroupKind()
+		}
+		if !expectedKinds.Has(gk) {
+			return fmt.Errorf("unexpected %s %s/%s", obj.GroupVersionKind().GroupKind().String(), obj.GetNamespace(), obj.GetName())
+		}
+	}
+	return nil
+}
+
 // ExtractObjectsFromReader extracts all objects from a reader,
 // which is created from YAML or JSON input files.
 // It retrieves all objects, including nested ones if they are contained within a list.
+// Documents that are not Kubernetes objects, i.e. with no apiVersion or kind, are skipped.
 // The function takes a namespace parameter to optionally return only namespaced resources.
 func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructured.Unstructured, error) {
+	return extractObjectsFromReader(reader, namespace, false)
+}
+
+func extractObjectsFromReader(reader io.Reader, namespace string, strict bool) ([]*unstructured.Unstructured, error) {
 	d := kubeyaml.NewYAMLOrJSONDecoder(reader, 4096)
 	var objs []*unstructured.Unstructured
 	for {
-		u := &unstructured.Unstructured{}
-		if err := d.Decode(&u); err != nil {
+		var content map[string]interface{}
+		if err := d.Decode(&content); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
 			return objs, fmt.Errorf("failed to unmarshal manifest: %w", err)
 		}
-		if u == nil {
+		if content == nil {
 			continue
 		}
-		if namespace != "" && u.GetNamespace() != namespace {
+		u := &unstructured.Unstructured{Object: content}
+		if u.GetAPIVersion() == "" || u.GetKind() == "" {
+			if strict {
+				return nil, fmt.Errorf("failed to unmarshal manifest: document is not a Kubernetes object, apiVersion and kind are required")
+			}
 			continue
 		}
 		objs = append(objs, u)
@@ -123,8 +242,35 @@ func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructur
 		} else {
 			tmpObjs = append(tmpObjs, obj)
 		}
-		finalObjs = append(finalObjs, tmpObjs...)
+		for _, tmpObj := range tmpObjs {
+			// The namespace is checked after the lists are expanded, since a list is not namespaced.
+			if namespace != "" && tmpObj.GetNamespace() != namespace {
+				continue
+			}
+			stripServerManagedFields(tmpObj)
+			finalObjs = append(finalObjs, tmpObj)
+		}
 	}
 
 	return finalObjs, nil
 }
+
+// serverManagedFields are the fields set by the API server on live objects, as
+// found in the output of `kubectl get -o yaml`.
+var serverManagedFields = [][]string{
+	{"status"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+	{"metadata", "managedFields"},
+	{"metadata", "generation"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "selfLink"},
+}
+
+// stripServerManagedFields removes the server-managed fields of the object, so
+// that they do not affect the conversion nor end up in generated resources.
+func stripServerManagedFields(obj *unstructured.Unstructured) {
+	for _, fields := range serverManagedFields {
+		unstructured.RemoveNestedField(obj.Object, fields...)
+	}
+}
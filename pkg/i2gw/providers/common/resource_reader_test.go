@@ -18,16 +18,22 @@ package common
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	networkingv1 "k8s.io/api/networking/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func Test_ExtractObjectsFromReader(t *testing.T) {
@@ -56,6 +62,16 @@ func Test_ExtractObjectsFromReader(t *testing.T) {
 			filePath:        "testdata/input-file.yaml",
 			namespace:       "namespace1",
 			wantIngressList: []networkingv1.Ingress{ingress1},
+		}, {
+			name:            "Test helm template output with non-Kubernetes documents and a list",
+			filePath:        "testdata/helm-template.yaml",
+			namespace:       "",
+			wantIngressList: []networkingv1.Ingress{ingress1},
+		}, {
+			name:            "Test helm template output with a list and namespace1 flag",
+			filePath:        "testdata/helm-template.yaml",
+			namespace:       "namespace1",
+			wantIngressList: []networkingv1.Ingress{ingress1},
 		},
 	}
 
@@ -73,19 +89,151 @@ func Test_ExtractObjectsFromReader(t *testing.T) {
 			if err != nil {
 				t.Errorf("got unexpected error: %v", err)
 			}
+			if len(gotIngressList.Items) != len(tc.wantIngressList) {
+				t.Fatalf("Expected %d Ingresses, got %d", len(tc.wantIngressList), len(gotIngressList.Items))
+			}
 			compareIngressLists(t, gotIngressList, tc.wantIngressList)
 		})
 	}
 }
 
+func Test_ValidateObjectKindsFromFile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		filePath      string
+		expectedKinds sets.Set[schema.GroupKind]
+		expectedError bool
+	}{
+		{
+			name:          "all kinds are expected",
+			filePath:      "testdata/input-file.yaml",
+			expectedKinds: sets.New(IngressGVK.GroupKind(), schema.GroupKind{Group: "apps", Kind: "Deployment"}, schema.GroupKind{Kind: "Pod"}),
+		}, {
+			name:          "unexpected kind",
+			filePath:      "testdata/input-file.yaml",
+			expectedKinds: sets.New(IngressGVK.GroupKind()),
+			expectedError: true,
+		}, {
+			name:          "document that is not a Kubernetes object",
+			filePath:      "testdata/helm-template.yaml",
+			expectedKinds: sets.New(IngressGVK.GroupKind(), schema.GroupKind{Kind: "ConfigMap"}),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateObjectKindsFromFile(tc.filePath, tc.expectedKinds)
+			if tc.expectedError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tc.expectedError && err != nil {
+				t.Errorf("Expected no error but got %v", err)
+			}
+		})
+	}
+}
+
+func Test_ReadIngressesFromFileStripsServerManagedFields(t *testing.T) {
+	ingresses, err := ReadIngressesFromFile("testdata/live-ingress.yaml", "", sets.New("nginx"), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	ingress, ok := ingresses[types.NamespacedName{Namespace: "default", Name: "web"}]
+	if !ok {
+		t.Fatalf("Expected Ingress default/web to be read, got %v", ingresses)
+	}
+	if ingress.ResourceVersion != "" || ingress.UID != "" || ingress.ManagedFields != nil || ingress.Generation != 0 || !ingress.CreationTimestamp.IsZero() {
+		t.Errorf("Expected server-managed metadata to be stripped, got %+v", ingress.ObjectMeta)
+	}
+	if !apiequality.Semantic.DeepEqual(ingress.Status, networkingv1.IngressStatus{}) {
+		t.Errorf("Expected status to be stripped, got %+v", ingress.Status)
+	}
+
+	gatewayResources, errs := ToGateway([]networkingv1.Ingress{*ingress}, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	var generated []interface{}
+	for _, gateway := range gatewayResources.Gateways {
+		generated = append(generated, gateway)
+	}
+	for _, httpRoute := range gatewayResources.HTTPRoutes {
+		generated = append(generated, httpRoute)
+	}
+	output, err := json.Marshal(generated)
+	if err != nil {
+		t.Fatalf("Expected no error marshaling the Gateway resources but got %v", err)
+	}
+	for _, leaked := range []string{"123456", "5f0c2d4e-8a7b-4c1d-9e3f-2b6a1c0d9e8f", "managedFields", "2024-03-01", "203.0.113.10"} {
+		if strings.Contains(string(output), leaked) {
+			t.Errorf("Expected %q not to be copied to the Gateway resources, got %s", leaked, output)
+		}
+	}
+}
+
+func Test_ReadIngressesFromFileConvertsV1beta1(t *testing.T) {
+	ingresses, err := ReadIngressesFromFile("testdata/v1beta1-ingress.yaml", "", sets.New("nginx"), nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	legacy, ok := ingresses[types.NamespacedName{Namespace: "default", Name: "legacy"}]
+	if !ok {
+		t.Fatalf("Expected Ingress default/legacy to be read, got %v", ingresses)
+	}
+	wantSpec := networkingv1.IngressSpec{
+		DefaultBackend: &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{Name: "default-backend", Port: networkingv1.ServiceBackendPort{Number: 80}},
+		},
+		TLS: []networkingv1.IngressTLS{{Hosts: []string{"foo.example.com"}, SecretName: "foo-cert"}},
+		Rules: []networkingv1.IngressRule{{
+			Host: "foo.example.com",
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path: "/numeric-string",
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Number: 8080}},
+							},
+						},
+						{
+							Path:     "/named",
+							PathType: PtrTo(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+	if diff := cmp.Diff(wantSpec, legacy.Spec); diff != "" {
+		t.Errorf("Unexpected spec of Ingress default/legacy (-want +got):\n%s", diff)
+	}
+	if legacy.APIVersion != "networking.k8s.io/v1" {
+		t.Errorf("Expected Ingress default/legacy to be converted to networking.k8s.io/v1, got %s", legacy.APIVersion)
+	}
+
+	networkingLegacy, ok := ingresses[types.NamespacedName{Namespace: "default", Name: "networking-legacy"}]
+	if !ok {
+		t.Fatalf("Expected Ingress default/networking-legacy to be read, got %v", ingresses)
+	}
+	if port := networkingLegacy.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port; port.Number != 9090 {
+		t.Errorf("Expected port 9090, got %+v", port)
+	}
+}
+
 func ingress(port int32, name, namespace string) networkingv1.Ingress {
 	iPrefix := networkingv1.PathTypePrefix
 	ingressClassName := fmt.Sprintf("ingressClass-%s", name)
 	var objMeta metav1.ObjectMeta
 	if namespace != "" {
-		objMeta = metav1.ObjectMeta{Name: name, ResourceVersion: "999", Namespace: namespace}
+		objMeta = metav1.ObjectMeta{Name: name, Namespace: namespace}
 	} else {
-		objMeta = metav1.ObjectMeta{Name: name, ResourceVersion: "999"}
+		objMeta = metav1.ObjectMeta{Name: name}
 	}
 
 	ing := networkingv1.Ingress{
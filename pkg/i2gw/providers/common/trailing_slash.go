@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TrailingSlashFeature returns the feature parser normalizing the Prefix paths
+// ending with a trailing slash, for the providers whose controller does not
+// match the path without its trailing slash, like ingress-nginx and
+// ingress-gce.
+//
+// These controllers serve a Prefix path like `/foo/` as the location or URL
+// map path `/foo/`, which does not match requests to `/foo`. Gateway API
+// ignores the trailing slash of PathPrefix matches, so the path is converted to
+// its canonical form `/foo` and an Info notification is dispatched for the
+// provider, as `/foo` will now be matched as well. Prefix paths without a
+// trailing slash, like `/foo` or `/foo/bar`, already have the same semantics in
+// both APIs and are left untouched.
+func TrailingSlashFeature(providerName, controller string) i2gw.FeatureParser {
+	return func(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+		ruleGroups := GetRuleGroups(ingresses)
+		for _, rg := range ruleGroups {
+			key := types.NamespacedName{Namespace: rg.Namespace, Name: RouteName(rg.Name, rg.Host)}
+			httpRoute, ok := gatewayResources.HTTPRoutes[key]
+			if !ok {
+				continue
+			}
+			for _, rule := range rg.Rules {
+				if rule.IngressRule.HTTP == nil {
+					continue
+				}
+				for _, path := range rule.IngressRule.HTTP.Paths {
+					if path.PathType == nil || *path.PathType != networkingv1.PathTypePrefix {
+						continue
+					}
+					normalizedPath, changed := NormalizePathPrefix(path.Path)
+					if !changed {
+						continue
+					}
+					PatchHTTPRoutePathPrefix(&httpRoute, path.Path, normalizedPath)
+					ingress := rule.Ingress
+					notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+						Type:           notifications.InfoNotification,
+						Message:        fmt.Sprintf("Prefix path %q was converted to PathPrefix %q: %s does not match %q for this path, while the generated HTTPRoute %s/%s does", path.Path, normalizedPath, controller, normalizedPath, httpRoute.Namespace, httpRoute.Name),
+						CallingObjects: []client.Object{&ingress},
+					}, providerName)
+				}
+			}
+			gatewayResources.HTTPRoutes[key] = httpRoute
+		}
+		return nil
+	}
+}
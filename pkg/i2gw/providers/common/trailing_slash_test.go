@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestTrailingSlashFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		path                 string
+		expectedPath         string
+		expectedNotification bool
+	}{
+		{
+			name:                 "prefix without trailing slash",
+			path:                 "/foo",
+			expectedPath:         "/foo",
+			expectedNotification: false,
+		},
+		{
+			name:                 "prefix with trailing slash",
+			path:                 "/foo/",
+			expectedPath:         "/foo",
+			expectedNotification: true,
+		},
+		{
+			name:                 "prefix with trailing slashes",
+			path:                 "/foo//",
+			expectedPath:         "/foo/",
+			expectedNotification: true,
+		},
+		{
+			name:                 "nested prefix without trailing slash",
+			path:                 "/foo/bar",
+			expectedPath:         "/foo/bar",
+			expectedNotification: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To("nginx"),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     tc.path,
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = TrailingSlashFeature("test-provider", "ingress-nginx")(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+			match := httpRoute.Spec.Rules[0].Matches[0]
+			if *match.Path.Type != gatewayv1.PathMatchPathPrefix || *match.Path.Value != tc.expectedPath {
+				t.Errorf("Expected PathPrefix %s, got %s %s", tc.expectedPath, *match.Path.Type, *match.Path.Value)
+			}
+
+			gotNotification := len(notifications.NotificationAggr.Notifications["test-provider"]) > 0
+			if gotNotification != tc.expectedNotification {
+				t.Errorf("Expected notification: %v, got %+v", tc.expectedNotification, notifications.NotificationAggr.Notifications["test-provider"])
+			}
+		})
+	}
+}
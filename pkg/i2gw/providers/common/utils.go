@@ -19,10 +19,15 @@ package common
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -107,14 +112,22 @@ func RouteName(ingressName, host string) string {
 
 func ToBackendRef(ib networkingv1.IngressBackend, path *field.Path) (*gatewayv1.BackendRef, *field.Error) {
 	if ib.Service != nil {
-		if ib.Service.Port.Name != "" {
-			fieldPath := path.Child("service", "port")
-			return nil, field.Invalid(fieldPath, "name", fmt.Sprintf("named ports not supported: %s", ib.Service.Port.Name))
+		port := ib.Service.Port
+		if port.Name != "" {
+			// Some tooling sets the port number as a numeric string in the name.
+			port = parseServiceBackendPort(port.Name)
+		}
+		fieldPath := path.Child("service", "port")
+		if port.Name != "" {
+			return nil, field.Invalid(fieldPath, "name", fmt.Sprintf("named port %s could not be resolved to a port number", port.Name))
+		}
+		if port.Number == 0 {
+			return nil, field.Required(fieldPath, "a port number or name is required")
 		}
 		return &gatewayv1.BackendRef{
 			BackendObjectReference: gatewayv1.BackendObjectReference{
 				Name: gatewayv1.ObjectName(ib.Service.Name),
-				Port: (*gatewayv1.PortNumber)(&ib.Service.Port.Number),
+				Port: PtrTo(gatewayv1.PortNumber(port.Number)),
 			},
 		}, nil
 	}
@@ -140,6 +153,9 @@ func groupIngressPathsByMatchKey(rules []ingressRule) orderedIngressPathsByMatch
 	}
 
 	for i, ir := range rules {
+		if ir.rule.HTTP == nil {
+			continue
+		}
 		for j, path := range ir.rule.HTTP.Paths {
 			ip := ingressPath{ruleIdx: i, pathIdx: j, ruleType: "http", path: path}
 			pmKey := getPathMatchKey(ip)
@@ -200,3 +216,129 @@ func removeBackendRefsDuplicates(backendRefs []gatewayv1.HTTPBackendRef) []gatew
 	}
 	return uniqueBackendRefs
 }
+
+// InferListenerProtocol returns the protocol of a generated listener from its TLS
+// settings and the kind of routes it accepts. The listeners of HTTP routes, like
+// the ones on port 80 and 443 of the Ingresses, use HTTPS when they terminate
+// TLS, TLS when they pass it through and HTTP otherwise. The listeners of layer 4
+// routes, like the ones on the ports of the TCP services, use TLS when they have
+// TLS settings and TCP otherwise. The inferred protocols can be overridden per
+// port with the --listener-protocol flag.
+func InferListenerProtocol(tls *gatewayv1.GatewayTLSConfig, layer4 bool) gatewayv1.ProtocolType {
+	switch {
+	case tls == nil && layer4:
+		return gatewayv1.TCPProtocolType
+	case tls == nil:
+		return gatewayv1.HTTPProtocolType
+	case layer4 || (tls.Mode != nil && *tls.Mode == gatewayv1.TLSModePassthrough):
+		return gatewayv1.TLSProtocolType
+	default:
+		return gatewayv1.HTTPSProtocolType
+	}
+}
+
+// NormalizePathPrefix returns the Gateway API PathPrefix equivalent of an Ingress
+// Prefix path. Gateway API ignores the trailing slash of a PathPrefix value, so
+// "/foo/" is returned as "/foo". Only a single trailing slash is dropped, and "/"
+// is returned as is. The returned bool reports whether the trailing slash was
+// dropped, which means the converted match also covers the path without the
+// trailing slash.
+func NormalizePathPrefix(path string) (string, bool) {
+	if path == "/" || !strings.HasSuffix(path, "/") {
+		return path, false
+	}
+	return strings.TrimSuffix(path, "/"), true
+}
+
+// PatchHTTPRoutePathPrefix replaces the value of all the PathPrefix matches of the
+// HTTPRoute equal to oldPath with newPath.
+func PatchHTTPRoutePathPrefix(httpRoute *gatewayv1.HTTPRoute, oldPath, newPath string) {
+	for i := range httpRoute.Spec.Rules {
+		for j := range httpRoute.Spec.Rules[i].Matches {
+			match := &httpRoute.Spec.Rules[i].Matches[j]
+			if match.Path == nil || match.Path.Type == nil || match.Path.Value == nil {
+				continue
+			}
+			if *match.Path.Type == gatewayv1.PathMatchPathPrefix && *match.Path.Value == oldPath {
+				match.Path.Value = PtrTo(newPath)
+			}
+		}
+	}
+}
+
+// ResolveNamedServicePorts replaces the Service ports referenced by name in the
+// ingresses backends with the port numbers of the matching services, so that
+// they can be converted to BackendRefs. The ingresses are copied before being
+// modified. A Warning notification is returned for every backend whose port
+// cannot be resolved, which then fails the conversion.
+func ResolveNamedServicePorts(ingresses []networkingv1.Ingress, services map[types.NamespacedName]*corev1.Service) []notifications.Notification {
+	var notifs []notifications.Notification
+	for i := range ingresses {
+		if !hasNamedServicePort(ingresses[i]) {
+			continue
+		}
+		ingress := ingresses[i].DeepCopy()
+		resolve := func(backend *networkingv1.IngressBackend) {
+			if backend == nil || backend.Service == nil || backend.Service.Port.Name == "" {
+				return
+			}
+			if port := parseServiceBackendPort(backend.Service.Port.Name); port.Name == "" {
+				backend.Service.Port = port
+				return
+			}
+			serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: backend.Service.Name}
+			service, ok := services[serviceKey]
+			if !ok {
+				notifs = append(notifs, notifications.Notification{
+					Type:           notifications.WarningNotification,
+					Message:        fmt.Sprintf("Service %s was not found, the named port %q cannot be resolved to a port number", serviceKey, backend.Service.Port.Name),
+					CallingObjects: []client.Object{ingress},
+				})
+				return
+			}
+			for _, port := range service.Spec.Ports {
+				if port.Name == backend.Service.Port.Name {
+					backend.Service.Port = networkingv1.ServiceBackendPort{Number: port.Port}
+					return
+				}
+			}
+			notifs = append(notifs, notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        fmt.Sprintf("Service %s has no port named %q", serviceKey, backend.Service.Port.Name),
+				CallingObjects: []client.Object{ingress},
+			})
+		}
+
+		resolve(ingress.Spec.DefaultBackend)
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for j := range rule.HTTP.Paths {
+				resolve(&rule.HTTP.Paths[j].Backend)
+			}
+		}
+		ingresses[i] = *ingress
+	}
+	return notifs
+}
+
+func hasNamedServicePort(ingress networkingv1.Ingress) bool {
+	isNamed := func(backend *networkingv1.IngressBackend) bool {
+		return backend != nil && backend.Service != nil && backend.Service.Port.Name != ""
+	}
+	if isNamed(ingress.Spec.DefaultBackend) {
+		return true
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for j := range rule.HTTP.Paths {
+			if isNamed(&rule.HTTP.Paths[j].Backend) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -20,7 +20,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func TestGroupIngressPathsByMatchKey(t *testing.T) {
@@ -43,7 +48,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "1 rule with 1 match",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -96,7 +101,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "1 rule, multiple matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -181,7 +186,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with single matches, same path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -203,7 +208,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -273,7 +278,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with single matches, different path",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -295,7 +300,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -368,7 +373,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 			name: "multiple rules with multiple matches, mixed paths",
 			rules: []ingressRule{
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -402,7 +407,7 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 					},
 				},
 				{
-					networkingv1.IngressRule{
+					rule: networkingv1.IngressRule{
 						IngressRuleValue: networkingv1.IngressRuleValue{
 							HTTP: &networkingv1.HTTPIngressRuleValue{
 								Paths: []networkingv1.HTTPIngressPath{
@@ -530,3 +535,165 @@ func TestGroupIngressPathsByMatchKey(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizePathPrefix(t *testing.T) {
+	testCases := []struct {
+		name            string
+		path            string
+		expectedPath    string
+		expectedChanged bool
+	}{
+		{name: "root", path: "/", expectedPath: "/", expectedChanged: false},
+		{name: "no trailing slash", path: "/foo", expectedPath: "/foo", expectedChanged: false},
+		{name: "trailing slash", path: "/foo/", expectedPath: "/foo", expectedChanged: true},
+		{name: "trailing slashes", path: "/foo//", expectedPath: "/foo/", expectedChanged: true},
+		{name: "root with a trailing slash", path: "//", expectedPath: "/", expectedChanged: true},
+		{name: "nested path", path: "/foo/bar", expectedPath: "/foo/bar", expectedChanged: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			path, changed := NormalizePathPrefix(tc.path)
+			require.Equal(t, tc.expectedPath, path)
+			require.Equal(t, tc.expectedChanged, changed)
+		})
+	}
+}
+
+func TestInferListenerProtocol(t *testing.T) {
+	terminate := &gatewayv1.GatewayTLSConfig{}
+	passthrough := &gatewayv1.GatewayTLSConfig{Mode: PtrTo(gatewayv1.TLSModePassthrough)}
+	testCases := []struct {
+		name             string
+		tls              *gatewayv1.GatewayTLSConfig
+		layer4           bool
+		expectedProtocol gatewayv1.ProtocolType
+	}{
+		{name: "http", expectedProtocol: gatewayv1.HTTPProtocolType},
+		{name: "https", tls: terminate, expectedProtocol: gatewayv1.HTTPSProtocolType},
+		{name: "tls passthrough", tls: passthrough, expectedProtocol: gatewayv1.TLSProtocolType},
+		{name: "tcp", layer4: true, expectedProtocol: gatewayv1.TCPProtocolType},
+		{name: "layer 4 tls", tls: passthrough, layer4: true, expectedProtocol: gatewayv1.TLSProtocolType},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedProtocol, InferListenerProtocol(tc.tls, tc.layer4))
+		})
+	}
+}
+
+func TestResolveNamedServicePorts(t *testing.T) {
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "web"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		port                  networkingv1.ServiceBackendPort
+		expectedPort          networkingv1.ServiceBackendPort
+		expectedNotifications int
+	}{
+		{
+			name:         "port number",
+			port:         networkingv1.ServiceBackendPort{Number: 80},
+			expectedPort: networkingv1.ServiceBackendPort{Number: 80},
+		},
+		{
+			name:         "named port of a known service",
+			port:         networkingv1.ServiceBackendPort{Name: "http"},
+			expectedPort: networkingv1.ServiceBackendPort{Number: 8080},
+		},
+		{
+			name:         "numeric string port",
+			port:         networkingv1.ServiceBackendPort{Name: "8080"},
+			expectedPort: networkingv1.ServiceBackendPort{Number: 8080},
+		},
+		{
+			name:                  "unknown named port",
+			port:                  networkingv1.ServiceBackendPort{Name: "grpc"},
+			expectedPort:          networkingv1.ServiceBackendPort{Name: "grpc"},
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			backend := networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "web", Port: tc.port},
+			}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress"},
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: backend.DeepCopy(),
+					Rules: []networkingv1.IngressRule{{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{Path: "/", Backend: *backend.DeepCopy()}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			notifs := ResolveNamedServicePorts(ingresses, services)
+			require.Len(t, notifs, tc.expectedNotifications*2)
+			require.Equal(t, tc.expectedPort, ingresses[0].Spec.DefaultBackend.Service.Port)
+			require.Equal(t, tc.expectedPort, ingresses[0].Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port)
+			// The original ingress must not be modified.
+			require.Equal(t, tc.port, ingress.Spec.DefaultBackend.Service.Port)
+		})
+	}
+
+	t.Run("missing service", func(t *testing.T) {
+		ingresses := []networkingv1.Ingress{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "ingress"},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+				},
+			},
+		}}
+		notifs := ResolveNamedServicePorts(ingresses, services)
+		require.Len(t, notifs, 1)
+		require.Contains(t, notifs[0].Message, "Service other/web was not found")
+	})
+}
+
+func TestToBackendRef(t *testing.T) {
+	testCases := []struct {
+		name          string
+		port          networkingv1.ServiceBackendPort
+		expectedPort  *gatewayv1.PortNumber
+		expectedError bool
+	}{
+		{name: "port number", port: networkingv1.ServiceBackendPort{Number: 8080}, expectedPort: PtrTo(gatewayv1.PortNumber(8080))},
+		{name: "numeric string port", port: networkingv1.ServiceBackendPort{Name: "8080"}, expectedPort: PtrTo(gatewayv1.PortNumber(8080))},
+		{name: "unresolved named port", port: networkingv1.ServiceBackendPort{Name: "http"}, expectedError: true},
+		{name: "missing port", port: networkingv1.ServiceBackendPort{}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			backendRef, err := ToBackendRef(networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "web", Port: tc.port},
+			}, field.NewPath("backend"))
+			if tc.expectedError {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedPort, backendRef.Port)
+		})
+	}
+}
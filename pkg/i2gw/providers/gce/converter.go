@@ -18,6 +18,7 @@ package gce
 
 import (
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -34,10 +35,14 @@ type converter struct {
 // newConverter returns an ingress-gce converter instance.
 func newConverter(conf *i2gw.ProviderConf) converter {
 	return converter{
-		conf:           conf,
-		featureParsers: []i2gw.FeatureParser{},
+		conf: conf,
+		featureParsers: []i2gw.FeatureParser{
+			implementationSpecificPathFeature,
+			common.TrailingSlashFeature(string(ProviderName), "ingress-gce"),
+		},
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			Progress: conf.Progress,
 		},
 	}
 }
@@ -47,6 +52,9 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 	for _, ing := range storage.Ingresses {
 		ingressList = append(ingressList, *ing)
 	}
+	for _, notification := range common.ResolveNamedServicePorts(ingressList, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, string(ProviderName))
+	}
 
 	// Convert plain ingress resources to gateway resources, ignoring all
 	// provider-specific features.
@@ -67,5 +75,23 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		errs = append(errs, parseErrs...)
 	}
 
+	for _, notification := range common.ResolveCrossNamespaceBackends(&gatewayResources, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, string(ProviderName))
+	}
+
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, string(ProviderName))
+	}
+
+	for _, notification := range common.ResolveMixedIngressControllers(&gatewayResources, ingressList, string(ProviderName)) {
+		notifications.NotificationAggr.DispatchNotification(notification, string(ProviderName))
+	}
+
+	for _, notification := range common.CopyCertManagerAnnotations(&gatewayResources, ingressList) {
+		notifications.NotificationAggr.DispatchNotification(notification, string(ProviderName))
+	}
+
+	notifyHealthChecks(ingressList, storage.Services, storage.HealthChecks)
+
 	return gatewayResources, errs
 }
@@ -21,13 +21,14 @@ import (
 	"fmt"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 const ProviderName = "gce"
 
 func init() {
-	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProvider(ProviderName, NewProvider, common.IngressGVK.GroupKind(), common.ServiceGVK.GroupKind(), backendConfigGVK.GroupKind())
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	backendConfigKey     = "cloud.google.com/backend-config"
+	betaBackendConfigKey = "beta.cloud.google.com/backend-config"
+)
+
+var backendConfigGVK = schema.GroupVersionKind{Group: "cloud.google.com", Version: "v1", Kind: "BackendConfig"}
+
+// healthCheckConfig is the spec.healthCheck of a BackendConfig.
+type healthCheckConfig struct {
+	CheckIntervalSec   *int64  `json:"checkIntervalSec,omitempty"`
+	TimeoutSec         *int64  `json:"timeoutSec,omitempty"`
+	HealthyThreshold   *int64  `json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold *int64  `json:"unhealthyThreshold,omitempty"`
+	Type               *string `json:"type,omitempty"`
+	Port               *int64  `json:"port,omitempty"`
+	RequestPath        *string `json:"requestPath,omitempty"`
+}
+
+// backendConfigReference is the value of the backend-config annotation of a
+// Service, which references a BackendConfig for all the Service ports, or per
+// port number or name.
+type backendConfigReference struct {
+	Default string            `json:"default,omitempty"`
+	Ports   map[string]string `json:"ports,omitempty"`
+}
+
+// healthCheckFromUnstructured returns the health check of an unstructured
+// BackendConfig, or nil if it has none.
+func healthCheckFromUnstructured(obj *unstructured.Unstructured) (*healthCheckConfig, error) {
+	content, found, err := unstructured.NestedMap(obj.Object, "spec", "healthCheck")
+	if err != nil || !found {
+		return nil, err
+	}
+	var healthCheck healthCheckConfig
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &healthCheck); err != nil {
+		return nil, fmt.Errorf("failed to parse the health check of BackendConfig %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return &healthCheck, nil
+}
+
+// backendConfigName returns the name of the BackendConfig the Service
+// references for the port, if any.
+func backendConfigName(service *corev1.Service, port networkingv1.ServiceBackendPort) (string, error) {
+	annotation, ok := service.Annotations[backendConfigKey]
+	if !ok {
+		annotation, ok = service.Annotations[betaBackendConfigKey]
+	}
+	if !ok {
+		return "", nil
+	}
+	var reference backendConfigReference
+	if err := json.Unmarshal([]byte(annotation), &reference); err != nil {
+		return "", fmt.Errorf("failed to parse the backend-config annotation of Service %s/%s: %w", service.Namespace, service.Name, err)
+	}
+	if port.Number != 0 {
+		if name, ok := reference.Ports[strconv.Itoa(int(port.Number))]; ok {
+			return name, nil
+		}
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.Port == port.Number && servicePort.Name != "" {
+				port.Name = servicePort.Name
+			}
+		}
+	}
+	if name, ok := reference.Ports[port.Name]; ok && port.Name != "" {
+		return name, nil
+	}
+	return reference.Default, nil
+}
+
+// serviceHealthCheck is the health check of a Service port, along with the
+// Ingresses routing to it.
+type serviceHealthCheck struct {
+	service       types.NamespacedName
+	port          int32
+	backendConfig string
+	healthCheck   *healthCheckConfig
+	ingresses     []client.Object
+}
+
+// notifyHealthChecks emits a Warning notification for every Service port of
+// the Ingresses backends whose health check is configured by a BackendConfig.
+//
+// The health check of the load balancer created for the Gateway is not
+// derived from the BackendConfig, so the notification lists the settings of a
+// HealthCheckPolicy of group networking.gke.io targeting the Service, which
+// configures it for the GKE Gateway classes.
+func notifyHealthChecks(ingresses []networkingv1.Ingress, services map[types.NamespacedName]*corev1.Service, healthChecks map[types.NamespacedName]*healthCheckConfig) {
+	byServicePort := map[string]*serviceHealthCheck{}
+	invalidServices := map[types.NamespacedName]bool{}
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		check := func(backend *networkingv1.IngressBackend) {
+			if backend == nil || backend.Service == nil {
+				return
+			}
+			serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: backend.Service.Name}
+			service, ok := services[serviceKey]
+			if !ok {
+				return
+			}
+			name, err := backendConfigName(service, backend.Service.Port)
+			if err != nil {
+				if invalidServices[serviceKey] {
+					return
+				}
+				invalidServices[serviceKey] = true
+				notify(notifications.WarningNotification, fmt.Sprintf("%v, its health check cannot be reported", err), ingress)
+				return
+			}
+			if name == "" {
+				return
+			}
+			healthCheck, ok := healthChecks[types.NamespacedName{Namespace: ingress.Namespace, Name: name}]
+			if !ok {
+				return
+			}
+			key := fmt.Sprintf("%s:%d", serviceKey, backend.Service.Port.Number)
+			servicePort, ok := byServicePort[key]
+			if !ok {
+				servicePort = &serviceHealthCheck{service: serviceKey, port: backend.Service.Port.Number, backendConfig: name, healthCheck: healthCheck}
+				byServicePort[key] = servicePort
+			}
+			for _, obj := range servicePort.ingresses {
+				if obj == ingress {
+					return
+				}
+			}
+			servicePort.ingresses = append(servicePort.ingresses, ingress)
+		}
+
+		check(ingress.Spec.DefaultBackend)
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for j := range rule.HTTP.Paths {
+				check(&rule.HTTP.Paths[j].Backend)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(byServicePort))
+	for key := range byServicePort {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		servicePort := byServicePort[key]
+		notify(notifications.WarningNotification, fmt.Sprintf("the health check of Service %s port %d is configured by BackendConfig %s and is not converted, create a networking.gke.io/v1 HealthCheckPolicy targeting the Service with %s",
+			servicePort.service, servicePort.port, servicePort.backendConfig, describeHealthCheck(servicePort.healthCheck)), servicePort.ingresses...)
+	}
+}
+
+// describeHealthCheck returns the settings of the health check, named after
+// the fields of the HealthCheckPolicy spec.default, e.g.
+// `config.httpHealthCheck.requestPath` for the requestPath of an HTTP check.
+func describeHealthCheck(healthCheck *healthCheckConfig) string {
+	checkType := "HTTP"
+	if healthCheck.Type != nil {
+		checkType = strings.ToUpper(*healthCheck.Type)
+	}
+	config := fmt.Sprintf("config.%sHealthCheck", strings.ToLower(checkType))
+	settings := []string{fmt.Sprintf("config.type: %s", checkType)}
+	if healthCheck.RequestPath != nil {
+		settings = append(settings, fmt.Sprintf("%s.requestPath: %s", config, *healthCheck.RequestPath))
+	}
+	if healthCheck.Port != nil {
+		settings = append(settings, fmt.Sprintf("%s.port: %d", config, *healthCheck.Port))
+	}
+	for _, setting := range []struct {
+		name  string
+		value *int64
+	}{
+		{"checkIntervalSec", healthCheck.CheckIntervalSec},
+		{"timeoutSec", healthCheck.TimeoutSec},
+		{"healthyThreshold", healthCheck.HealthyThreshold},
+		{"unhealthyThreshold", healthCheck.UnhealthyThreshold},
+	} {
+		if setting.value != nil {
+			settings = append(settings, fmt.Sprintf("%s: %d", setting.name, *setting.value))
+		}
+	}
+	return strings.Join(settings, ", ")
+}
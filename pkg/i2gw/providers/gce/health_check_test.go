@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func Test_healthCheckFromUnstructured(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cloud.google.com/v1",
+		"kind":       "BackendConfig",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "hc"},
+		"spec": map[string]interface{}{
+			"healthCheck": map[string]interface{}{
+				"checkIntervalSec": int64(15),
+				"type":             "HTTP",
+				"requestPath":      "/healthz",
+				"port":             int64(8080),
+			},
+		},
+	}}
+	healthCheck, err := healthCheckFromUnstructured(obj)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if healthCheck == nil || *healthCheck.CheckIntervalSec != 15 || *healthCheck.RequestPath != "/healthz" || *healthCheck.Port != 8080 {
+		t.Errorf("Unexpected health check %+v", healthCheck)
+	}
+
+	unstructured.RemoveNestedField(obj.Object, "spec", "healthCheck")
+	if healthCheck, err := healthCheckFromUnstructured(obj); err != nil || healthCheck != nil {
+		t.Errorf("Expected no health check, got %+v, %v", healthCheck, err)
+	}
+}
+
+func Test_notifyHealthChecks(t *testing.T) {
+	healthChecks := map[types.NamespacedName]*healthCheckConfig{
+		{Namespace: "default", Name: "hc"}: {
+			CheckIntervalSec: ptr.To[int64](15),
+			TimeoutSec:       ptr.To[int64](5),
+			RequestPath:      ptr.To("/healthz"),
+			Port:             ptr.To[int64](8080),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedMessages []string
+	}{
+		{
+			name: "no backend config",
+		},
+		{
+			name:        "default backend config",
+			annotations: map[string]string{backendConfigKey: `{"default": "hc"}`},
+			expectedMessages: []string{
+				"the health check of Service default/web port 80 is configured by BackendConfig hc and is not converted, create a networking.gke.io/v1 HealthCheckPolicy targeting the Service with config.type: HTTP, config.httpHealthCheck.requestPath: /healthz, config.httpHealthCheck.port: 8080, checkIntervalSec: 15, timeoutSec: 5",
+			},
+		},
+		{
+			name:        "backend config of the port name",
+			annotations: map[string]string{betaBackendConfigKey: `{"ports": {"http": "hc"}}`},
+			expectedMessages: []string{
+				"the health check of Service default/web port 80 is configured by BackendConfig hc and is not converted, create a networking.gke.io/v1 HealthCheckPolicy targeting the Service with config.type: HTTP, config.httpHealthCheck.requestPath: /healthz, config.httpHealthCheck.port: 8080, checkIntervalSec: 15, timeoutSec: 5",
+			},
+		},
+		{
+			name:        "backend config of another port",
+			annotations: map[string]string{backendConfigKey: `{"ports": {"443": "hc"}}`},
+		},
+		{
+			name:        "backend config without health check",
+			annotations: map[string]string{backendConfigKey: `{"default": "cdn"}`},
+		},
+		{
+			name:             "invalid annotation",
+			annotations:      map[string]string{backendConfigKey: `hc`},
+			expectedMessages: []string{"failed to parse the backend-config annotation of Service default/web: invalid character 'h' looking for beginning of value, its health check cannot be reported"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			services := map[types.NamespacedName]*corev1.Service{
+				{Namespace: "default", Name: "web"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", Annotations: tc.annotations},
+					Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+				},
+			}
+			backend := networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: &backend,
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{Path: "/", PathType: ptr.To(networkingv1.PathTypePrefix), Backend: backend}},
+						}},
+					}},
+				},
+			}}
+
+			notifyHealthChecks(ingresses, services, healthChecks)
+
+			notifs := notifications.NotificationAggr.Notifications[string(ProviderName)]
+			if tc.expectedMessages == nil {
+				if len(notifs) != 0 {
+					t.Errorf("Expected no notifications, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != len(tc.expectedMessages) {
+				t.Fatalf("Expected %d notifications, got %+v", len(tc.expectedMessages), notifs)
+			}
+			for i, n := range notifs {
+				if n.Type != notifications.WarningNotification || n.Message != tc.expectedMessages[i] || len(n.CallingObjects) != 1 {
+					t.Errorf("Expected Warning %q for the Ingress, got %+v", tc.expectedMessages[i], n)
+				}
+			}
+		})
+	}
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package gce
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
-	"k8s.io/klog/v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -38,21 +42,50 @@ import (
 // | /v1/                                  | /v1/ Exact                             |
 // | /v1/*                                 | /v1 Prefix                             |
 func implementationSpecificHTTPPathTypeMatch(path *gatewayv1.HTTPPathMatch) {
-	pmExact := gatewayv1.PathMatchExact
-	pmPrefix := gatewayv1.PathMatchPathPrefix
+	pathType, value := implementationSpecificPath(*path.Value)
+	path.Type = &pathType
+	path.Value = common.PtrTo(value)
+}
 
-	if *path.Value == "/*" {
-		path.Type = &pmPrefix
-		path.Value = common.PtrTo("/")
-		return
+// implementationSpecificPath returns the Gateway path type and value an
+// ImplementationSpecific Ingress path is interpreted as by ingress-gce.
+func implementationSpecificPath(path string) (gatewayv1.PathMatchType, string) {
+	if path == "/*" {
+		return gatewayv1.PathMatchPathPrefix, "/"
+	}
+	if !strings.HasSuffix(path, "/*") {
+		return gatewayv1.PathMatchExact, path
 	}
-	if !strings.HasSuffix(*path.Value, "/*") {
-		path.Type = &pmExact
-		return
+	return gatewayv1.PathMatchPathPrefix, strings.TrimSuffix(path, "/*")
+}
+
+// implementationSpecificPathFeature emits an Info notification describing how
+// every ImplementationSpecific path was interpreted, as the conversion relies on
+// the ingress-gce semantics of the path. A path like `/foo/*` is notably
+// converted to the PathPrefix `/foo`, which additionally matches `/foo`.
+func implementationSpecificPathFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.PathType == nil || *path.PathType != networkingv1.PathTypeImplementationSpecific {
+					continue
+				}
+				notify(notifications.InfoNotification, implementationSpecificPathMessage(path.Path), ingress)
+			}
+		}
 	}
+	return nil
+}
 
-	currentValue := *path.Value
-	path.Type = &pmPrefix
-	path.Value = common.PtrTo(strings.TrimSuffix(*path.Value, "/*"))
-	klog.Warningf("After conversion, ImplementationSpecific Path %s/* will additionally map to %s. See README.md for details.", currentValue, *path.Value)
+func implementationSpecificPathMessage(path string) string {
+	pathType, value := implementationSpecificPath(path)
+	message := fmt.Sprintf("ImplementationSpecific path %q was interpreted with ingress-gce semantics and converted to %s %q", path, pathType, value)
+	if pathType == gatewayv1.PathMatchPathPrefix && value != "/" {
+		message += fmt.Sprintf(", which additionally matches %q", value)
+	}
+	return message
 }
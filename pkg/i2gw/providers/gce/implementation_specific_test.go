@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_implementationSpecificPathFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		path                 string
+		expectedType         gatewayv1.PathMatchType
+		expectedPath         string
+		expectedNotification string
+	}{
+		{
+			name:                 "root",
+			path:                 "/",
+			expectedType:         gatewayv1.PathMatchExact,
+			expectedPath:         "/",
+			expectedNotification: `ImplementationSpecific path "/" was interpreted with ingress-gce semantics and converted to Exact "/"`,
+		},
+		{
+			name:                 "root wildcard",
+			path:                 "/*",
+			expectedType:         gatewayv1.PathMatchPathPrefix,
+			expectedPath:         "/",
+			expectedNotification: `ImplementationSpecific path "/*" was interpreted with ingress-gce semantics and converted to PathPrefix "/"`,
+		},
+		{
+			name:                 "bare path",
+			path:                 "/foo",
+			expectedType:         gatewayv1.PathMatchExact,
+			expectedPath:         "/foo",
+			expectedNotification: `ImplementationSpecific path "/foo" was interpreted with ingress-gce semantics and converted to Exact "/foo"`,
+		},
+		{
+			name:                 "path wildcard",
+			path:                 "/foo/*",
+			expectedType:         gatewayv1.PathMatchPathPrefix,
+			expectedPath:         "/foo",
+			expectedNotification: `ImplementationSpecific path "/foo/*" was interpreted with ingress-gce semantics and converted to PathPrefix "/foo", which additionally matches "/foo"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(gceIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     tc.path,
+									PathType: ptr.To(networkingv1.PathTypeImplementationSpecific),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{
+				ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = implementationSpecificPathFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+			match := httpRoute.Spec.Rules[0].Matches[0]
+			if *match.Path.Type != tc.expectedType || *match.Path.Value != tc.expectedPath {
+				t.Errorf("Expected %s %s, got %s %s", tc.expectedType, tc.expectedPath, *match.Path.Type, *match.Path.Value)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[ProviderName]
+			if len(gotNotifications) != 1 {
+				t.Fatalf("Expected 1 notification, got %+v", gotNotifications)
+			}
+			if gotNotifications[0].Type != notifications.InfoNotification || gotNotifications[0].Message != tc.expectedNotification {
+				t.Errorf("Expected Info notification %q, got %s %q", tc.expectedNotification, gotNotifications[0].Type, gotNotifications[0].Message)
+			}
+		})
+	}
+}
@@ -17,11 +17,18 @@ limitations under the License.
 package gce
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // GCE supports the following Ingress Class values:
@@ -45,21 +52,101 @@ func newResourceReader(conf *i2gw.ProviderConf) reader {
 func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, supportedGCEIngressClass)
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, supportedGCEIngressClass, r.conf.Providers)
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = (ingresses)
+
+	services, err := common.ReadServicesFromCluster(ctx, r.conf.Client)
+	if err != nil {
+		return nil, err
+	}
+	storage.Services = services
+
+	healthChecks, err := r.readHealthChecksFromCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BackendConfigs: %w", err)
+	}
+	storage.HealthChecks = healthChecks
 	return storage, nil
 }
 
 func (r *reader) readResourcesFromFile(filename string) (*storage, error) {
 	storage := newResourcesStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, supportedGCEIngressClass)
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, supportedGCEIngressClass, r.conf.Providers)
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = ingresses
+
+	services, err := common.ReadServicesFromFile(filename, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	storage.Services = services
+
+	healthChecks, err := r.readHealthChecksFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BackendConfigs: %w", err)
+	}
+	storage.HealthChecks = healthChecks
 	return storage, nil
 }
+
+// readHealthChecksFromCluster returns the health checks of the BackendConfigs
+// of the cluster, in the namespace of the conversion if one is set. There is
+// none if the BackendConfig CRD is not installed.
+func (r *reader) readHealthChecksFromCluster(ctx context.Context) (map[types.NamespacedName]*healthCheckConfig, error) {
+	backendConfigList := &unstructured.UnstructuredList{}
+	backendConfigList.SetGroupVersionKind(backendConfigGVK)
+	if err := r.conf.Client.List(ctx, backendConfigList, client.InNamespace(r.conf.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return map[types.NamespacedName]*healthCheckConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", backendConfigGVK.GroupKind().String(), err)
+	}
+
+	healthChecks := map[types.NamespacedName]*healthCheckConfig{}
+	for i := range backendConfigList.Items {
+		if err := addHealthCheck(healthChecks, &backendConfigList.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return healthChecks, nil
+}
+
+func (r *reader) readHealthChecksFromFile(filename string) (map[types.NamespacedName]*healthCheckConfig, error) {
+	stream, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := common.ExtractObjectsFromReader(bytes.NewReader(stream), r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	healthChecks := map[types.NamespacedName]*healthCheckConfig{}
+	for _, obj := range objs {
+		if r.conf.Namespace != "" && obj.GetNamespace() != r.conf.Namespace {
+			continue
+		}
+		if obj.GroupVersionKind() != backendConfigGVK {
+			continue
+		}
+		if err := addHealthCheck(healthChecks, obj); err != nil {
+			return nil, err
+		}
+	}
+	return healthChecks, nil
+}
+
+func addHealthCheck(healthChecks map[types.NamespacedName]*healthCheckConfig, obj *unstructured.Unstructured) error {
+	healthCheck, err := healthCheckFromUnstructured(obj)
+	if err != nil || healthCheck == nil {
+		return err
+	}
+	healthChecks[types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}] = healthCheck
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_readHealthChecksFromCluster(t *testing.T) {
+	backendConfig := func(namespace string) client.Object {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"namespace": namespace, "name": "bc"},
+			"spec": map[string]interface{}{
+				"healthCheck": map[string]interface{}{"requestPath": "/healthz"},
+			},
+		}}
+		obj.SetGroupVersionKind(backendConfigGVK)
+		return obj
+	}
+	cl := fake.NewClientBuilder().WithObjects(backendConfig("default"), backendConfig("other")).Build()
+
+	testCases := []struct {
+		name      string
+		namespace string
+		expected  []types.NamespacedName
+	}{
+		{
+			name:      "namespace of the conversion",
+			namespace: "default",
+			expected:  []types.NamespacedName{{Namespace: "default", Name: "bc"}},
+		},
+		{
+			name:     "all namespaces",
+			expected: []types.NamespacedName{{Namespace: "default", Name: "bc"}, {Namespace: "other", Name: "bc"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResourceReader(&i2gw.ProviderConf{Client: cl, Namespace: tc.namespace})
+			healthChecks, err := r.readHealthChecksFromCluster(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			if len(healthChecks) != len(tc.expected) {
+				t.Fatalf("Expected health checks of %v, got %+v", tc.expected, healthChecks)
+			}
+			for _, key := range tc.expected {
+				if _, ok := healthChecks[key]; !ok {
+					t.Errorf("Expected the health check of BackendConfig %s, got %+v", key, healthChecks)
+				}
+			}
+		})
+	}
+}
+
+func Test_resourceKinds(t *testing.T) {
+	kinds := i2gw.ProviderResourceKindsByName[ProviderName]
+	for _, kind := range kinds {
+		if kind == backendConfigGVK.GroupKind() {
+			return
+		}
+	}
+	t.Errorf("Expected BackendConfig in the resource kinds, got %v", kinds)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// accessRequirement is a kind of access control a request must satisfy, along
+// with the annotations configuring it, in the order they are reported.
+type accessRequirement struct {
+	name           string
+	annotationKeys []string
+	// convertible is whether the requirement is converted on its own, when
+	// every requirement must be met.
+	convertible bool
+}
+
+var accessRequirements = []accessRequirement{
+	{name: "client source range", annotationKeys: []string{whitelistSourceRangeKey, allowlistSourceRangeKey, denylistSourceRangeKey}, convertible: true},
+	{name: "basic or digest authentication", annotationKeys: []string{authTypeKey, authSecretKey, authRealmKey}},
+	{name: "external authentication", annotationKeys: []string{authURLKey, authSigninKey}},
+}
+
+// accessControlFeature reports the access control annotations of every Ingress:
+// the client source ranges and the basic, digest and external authentication,
+// along with the `nginx.ingress.kubernetes.io/satisfy` annotation combining them.
+//
+// Gateway API has no equivalent to any of these requirements, and silently
+// dropping them would leave the migrated routes open. The client source ranges
+// are converted to the access control of the TrafficPolicy of the routes by
+// trafficPolicyFeature, like an Envoy Gateway SecurityPolicy whose rules deny
+// the denied ranges before allowing the allowed ones, as ingress-nginx does.
+// They are only converted when the request must meet every requirement, with
+// the default `satisfy: all`, or when they are the only requirement: with
+// `satisfy: any`, a request is allowed as soon as one of them is met, and
+// enforcing a single requirement would reject the clients meeting another.
+//
+// Hence a single notification is emitted per Ingress, describing the full
+// access policy: how the requirements combine, and every requirement with its
+// annotations and whether it was converted. It is an Error if a requirement
+// was not converted, to be reconstructed with a policy of the Gateway
+// implementation, and an Info otherwise.
+func accessControlFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		type requirementSettings struct {
+			requirement accessRequirement
+			settings    []string
+		}
+		var requirements []requirementSettings
+		for _, requirement := range accessRequirements {
+			var settings []string
+			for _, key := range requirement.annotationKeys {
+				if value, ok := ingress.Annotations[nginxAnnotation(key)]; ok {
+					settings = append(settings, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+				}
+			}
+			if len(settings) > 0 {
+				requirements = append(requirements, requirementSettings{requirement: requirement, settings: settings})
+			}
+		}
+		if len(requirements) == 0 {
+			continue
+		}
+
+		satisfyAny := len(requirements) > 1 && strings.TrimSpace(ingress.Annotations[nginxAnnotation(satisfyKey)]) == "any"
+		var lines []string
+		var converted, unconverted int
+		for _, r := range requirements {
+			status := "not converted, as Gateway API has no equivalent"
+			switch {
+			case !r.requirement.convertible:
+			case satisfyAny:
+				status = "not converted, as enforcing it alone would reject the requests meeting another requirement"
+			default:
+				if _, err := parseAccessControl(ingress); err != nil {
+					status = fmt.Sprintf("not converted, %v", err)
+				} else {
+					status = "converted to the client source ranges of the traffic policy of the routes, e.g. an Envoy Gateway SecurityPolicy denying the denied ranges first, which applies to the whole HTTPRoute"
+				}
+			}
+			if strings.HasPrefix(status, "converted") {
+				converted++
+			} else {
+				unconverted++
+			}
+			lines = append(lines, fmt.Sprintf("- %s (%s): %s", r.requirement.name, strings.Join(r.settings, ", "), status))
+		}
+
+		var logic string
+		switch {
+		case unconverted == 0:
+			logic = "the requirements are converted, check the generated policy before routing traffic to it"
+		case len(requirements) == 1:
+			logic = "configure it with a policy of your Gateway implementation before routing traffic to it"
+		case satisfyAny:
+			logic = fmt.Sprintf("with %s: any, a request is allowed as soon as ONE of them is met, which cannot be expressed by filters or policies that must each pass: rebuild this OR logic with a single authorization policy of your Gateway implementation before routing traffic to it", nginxAnnotation(satisfyKey))
+		case converted > 0:
+			logic = "as a request must meet ALL of them, the converted ones are enforced first, configure each of the other ones with a policy of your Gateway implementation before routing traffic to it"
+		default:
+			logic = "as a request must meet ALL of them, configure each of them with a policy of your Gateway implementation before routing traffic to it"
+		}
+
+		ingress := ingress
+		if unconverted == 0 {
+			notify(notifications.InfoNotification, fmt.Sprintf("the access control settings are converted: %s. Access requirements:\n%s", logic, strings.Join(lines, "\n")), &ingress)
+			continue
+		}
+		summary := "the access control settings are not converted, as Gateway API has no equivalent to client source ranges or authentication"
+		if converted > 0 {
+			summary = "the access control settings are partially converted, as Gateway API has no equivalent to authentication"
+		}
+		notify(notifications.ErrorNotification, fmt.Sprintf("%s: %s. Access requirements:\n%s", summary, logic, strings.Join(lines, "\n")), &ingress)
+	}
+	return nil
+}
+
+// parseAccessControl returns the client source ranges of the Ingress, or nil if
+// they are not converted: when it has none, or combines them with another
+// requirement with `satisfy: any`. The allowlist-source-range annotation takes
+// precedence over its legacy whitelist-source-range name, and the single IPs
+// are converted to single-address ranges.
+func parseAccessControl(ingress networkingv1.Ingress) (*i2gw.AccessControl, error) {
+	allowed, allowedSet := ingress.Annotations[nginxAnnotation(allowlistSourceRangeKey)]
+	allowedKey := allowlistSourceRangeKey
+	if !allowedSet {
+		allowed, allowedSet = ingress.Annotations[nginxAnnotation(whitelistSourceRangeKey)]
+		allowedKey = whitelistSourceRangeKey
+	}
+	denied, deniedSet := ingress.Annotations[nginxAnnotation(denylistSourceRangeKey)]
+	if !allowedSet && !deniedSet {
+		return nil, nil
+	}
+	if strings.TrimSpace(ingress.Annotations[nginxAnnotation(satisfyKey)]) == "any" {
+		for _, requirement := range accessRequirements[1:] {
+			for _, key := range requirement.annotationKeys {
+				if _, ok := ingress.Annotations[nginxAnnotation(key)]; ok {
+					return nil, nil
+				}
+			}
+		}
+	}
+
+	accessControl := &i2gw.AccessControl{}
+	var err error
+	if accessControl.AllowedCIDRs, err = parseSourceRanges(allowedKey, allowed); err != nil {
+		return nil, err
+	}
+	if accessControl.DeniedCIDRs, err = parseSourceRanges(denylistSourceRangeKey, denied); err != nil {
+		return nil, err
+	}
+	if len(accessControl.AllowedCIDRs) == 0 && len(accessControl.DeniedCIDRs) == 0 {
+		return nil, nil
+	}
+	return accessControl, nil
+}
+
+// parseSourceRanges parses the comma-separated CIDRs or IPs of the annotation.
+func parseSourceRanges(key, value string) ([]string, error) {
+	var cidrs []string
+	for _, sourceRange := range strings.Split(value, ",") {
+		sourceRange = strings.TrimSpace(sourceRange)
+		if sourceRange == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(sourceRange); err == nil {
+			cidrs = append(cidrs, sourceRange)
+			continue
+		}
+		ip := net.ParseIP(sourceRange)
+		switch {
+		case ip == nil:
+			return nil, fmt.Errorf("as %s %q is not a CIDR or an IP", nginxAnnotation(key), sourceRange)
+		case ip.To4() != nil:
+			cidrs = append(cidrs, sourceRange+"/32")
+		default:
+			cidrs = append(cidrs, sourceRange+"/128")
+		}
+	}
+	return cidrs, nil
+}
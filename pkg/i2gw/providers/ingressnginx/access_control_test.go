@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_accessControlFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedType         notifications.MessageType
+		expectedLogic        string
+		expectedRequirements []string
+	}{
+		{
+			name:        "no access control annotations",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/satisfy": "any"},
+		},
+		{
+			name:          "single requirement",
+			annotations:   map[string]string{"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8, 192.168.0.0/16"},
+			expectedType:  notifications.InfoNotification,
+			expectedLogic: "the requirements are converted",
+			expectedRequirements: []string{
+				"- client source range (nginx.ingress.kubernetes.io/whitelist-source-range: 10.0.0.0/8, 192.168.0.0/16): converted to the client source ranges",
+			},
+		},
+		{
+			name:          "invalid source range",
+			annotations:   map[string]string{"nginx.ingress.kubernetes.io/denylist-source-range": "10.0.0.0/8, internal"},
+			expectedType:  notifications.ErrorNotification,
+			expectedLogic: "configure it with a policy",
+			expectedRequirements: []string{
+				`- client source range (nginx.ingress.kubernetes.io/denylist-source-range: 10.0.0.0/8, internal): not converted, as nginx.ingress.kubernetes.io/denylist-source-range "internal" is not a CIDR or an IP`,
+			},
+		},
+		{
+			name: "satisfy any",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/satisfy":                "any",
+				"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8",
+				"nginx.ingress.kubernetes.io/auth-type":              "basic",
+				"nginx.ingress.kubernetes.io/auth-secret":            "basic-auth",
+				"nginx.ingress.kubernetes.io/auth-realm":             "Authentication Required",
+			},
+			expectedType:  notifications.ErrorNotification,
+			expectedLogic: "nginx.ingress.kubernetes.io/satisfy: any, a request is allowed as soon as ONE of them is met",
+			expectedRequirements: []string{
+				"- client source range (nginx.ingress.kubernetes.io/whitelist-source-range: 10.0.0.0/8): not converted, as enforcing it alone",
+				"- basic or digest authentication (nginx.ingress.kubernetes.io/auth-type: basic, nginx.ingress.kubernetes.io/auth-secret: basic-auth, nginx.ingress.kubernetes.io/auth-realm: Authentication Required): not converted",
+			},
+		},
+		{
+			name: "satisfy all by default",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/allowlist-source-range": "10.0.0.0/8",
+				"nginx.ingress.kubernetes.io/denylist-source-range":  "10.0.0.1/32",
+				"nginx.ingress.kubernetes.io/auth-url":               "http://auth.default.svc/verify",
+				"nginx.ingress.kubernetes.io/auth-signin":            "https://auth.example.com/start",
+			},
+			expectedType:  notifications.ErrorNotification,
+			expectedLogic: "as a request must meet ALL of them, the converted ones are enforced first",
+			expectedRequirements: []string{
+				"- client source range (nginx.ingress.kubernetes.io/allowlist-source-range: 10.0.0.0/8, nginx.ingress.kubernetes.io/denylist-source-range: 10.0.0.1/32): converted to the client source ranges",
+				"- external authentication (nginx.ingress.kubernetes.io/auth-url: http://auth.default.svc/verify, nginx.ingress.kubernetes.io/auth-signin: https://auth.example.com/start): not converted",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "access", Annotations: tc.annotations},
+			}}
+
+			if errs := accessControlFeature(ingresses, &i2gw.GatewayResources{}); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(tc.expectedRequirements) == 0 {
+				if len(gotNotifications) != 0 {
+					t.Errorf("Expected no notifications, got %+v", gotNotifications)
+				}
+				return
+			}
+			if len(gotNotifications) != 1 || gotNotifications[0].Type != tc.expectedType {
+				t.Fatalf("Expected a single %s notification, got %+v", tc.expectedType, gotNotifications)
+			}
+			message := gotNotifications[0].Message
+			if !strings.Contains(message, tc.expectedLogic) {
+				t.Errorf("Expected notification to explain the access logic with %q, got %q", tc.expectedLogic, message)
+			}
+			lines := strings.Split(message, "\n")[1:]
+			if len(lines) != len(tc.expectedRequirements) {
+				t.Fatalf("Expected notification to list %d access requirements, got %q", len(tc.expectedRequirements), message)
+			}
+			for i, requirement := range tc.expectedRequirements {
+				if !strings.HasPrefix(lines[i], requirement) {
+					t.Errorf("Expected access requirement %d to start with %q, got %q", i, requirement, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_accessControlPartialConversion(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingresses := []networkingv1.Ingress{
+		conflictTestIngress("admin", "/admin", map[string]string{
+			"nginx.ingress.kubernetes.io/satisfy":                "all",
+			"nginx.ingress.kubernetes.io/allowlist-source-range": "10.0.0.0/8, 2001:db8::1",
+			"nginx.ingress.kubernetes.io/denylist-source-range":  "10.0.0.1",
+			"nginx.ingress.kubernetes.io/auth-type":              "basic",
+			"nginx.ingress.kubernetes.io/auth-secret":            "basic-auth",
+		}),
+		conflictTestIngress("web", "/", nil),
+	}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = accessControlFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	if errs = trafficPolicyFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	expected := map[types.NamespacedName]i2gw.TrafficPolicy{
+		{Namespace: "default", Name: "admin-www-example-com"}: {
+			AccessControl: &i2gw.AccessControl{
+				AllowedCIDRs: []string{"10.0.0.0/8", "2001:db8::1/128"},
+				DeniedCIDRs:  []string{"10.0.0.1/32"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, gatewayResources.TrafficPolicies); diff != "" {
+		t.Errorf("Unexpected traffic policies (-want +got):\n%s", diff)
+	}
+
+	var report, unrestricted bool
+	for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+		switch {
+		case notification.Type == notifications.ErrorNotification && strings.Contains(notification.Message, "partially converted"):
+			report = strings.Contains(notification.Message, "- client source range (") &&
+				strings.Contains(notification.Message, "- basic or digest authentication (nginx.ingress.kubernetes.io/auth-type: basic, nginx.ingress.kubernetes.io/auth-secret: basic-auth): not converted")
+		case notification.Type == notifications.WarningNotification && strings.Contains(notification.Message, "including the paths of the Ingresses web"):
+			unrestricted = true
+		}
+	}
+	if !report {
+		t.Errorf("Expected an Error notification reporting the unconverted basic authentication, got %+v", notifications.NotificationAggr.Notifications[Name])
+	}
+	if !unrestricted {
+		t.Errorf("Expected a Warning notification for the Ingress without client source ranges, got %+v", notifications.NotificationAggr.Notifications[Name])
+	}
+}
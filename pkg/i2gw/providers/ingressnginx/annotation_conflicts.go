@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ingressPathKey identifies the Ingress paths merged on the same HTTPRoute rule.
+type ingressPathKey struct {
+	namespace, ingressClass, host string
+	pathType, path                string
+}
+
+// resolveAnnotationConflicts detects the Ingresses merged on the same host and
+// path, like a canary Ingress and its primary Ingress, that set different values
+// for the same converted annotation, as the result would otherwise depend on the
+// order the features are applied in.
+//
+// The value of the first non-canary Ingress setting the annotation is kept, as
+// ingress-nginx ignores the annotations of canary Ingresses, or the value of the
+// first Ingress if they are all canaries. The annotation of the other Ingresses
+// is only ignored for the conflicting paths: their conflicting paths are moved
+// to copies of the Ingresses without the annotation, returned in their place,
+// so that their other paths are still converted with it. The input Ingresses
+// are not modified, and an Error notification describing each conflict is
+// emitted.
+func resolveAnnotationConflicts(ingresses []networkingv1.Ingress) []networkingv1.Ingress {
+	var keys []ingressPathKey
+	ingressesByPath := map[ingressPathKey][]int{}
+	for i, ingress := range ingresses {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				key := pathKey(ingress, rule.Host, path)
+				if _, ok := ingressesByPath[key]; !ok {
+					keys = append(keys, key)
+				}
+				if !slices.Contains(ingressesByPath[key], i) {
+					ingressesByPath[key] = append(ingressesByPath[key], i)
+				}
+			}
+		}
+	}
+
+	// ignored are the annotations ignored for the paths of each Ingress.
+	ignored := map[int]map[ingressPathKey][]string{}
+	for _, key := range keys {
+		if len(ingressesByPath[key]) < 2 {
+			continue
+		}
+		for _, annotationKey := range convertedAnnotationKeys {
+			if strings.HasPrefix(annotationKey, "canary") {
+				continue
+			}
+			for _, i := range resolveAnnotationConflict(ingresses, ingressesByPath[key], nginxAnnotation(annotationKey), key) {
+				if ignored[i] == nil {
+					ignored[i] = map[ingressPathKey][]string{}
+				}
+				ignored[i][key] = append(ignored[i][key], nginxAnnotation(annotationKey))
+			}
+		}
+	}
+	if len(ignored) == 0 {
+		return ingresses
+	}
+
+	resolved := make([]networkingv1.Ingress, 0, len(ingresses))
+	for i, ingress := range ingresses {
+		if len(ignored[i]) == 0 {
+			resolved = append(resolved, ingress)
+			continue
+		}
+		resolved = append(resolved, splitIngressPaths(ingress, ignored[i])...)
+	}
+	return resolved
+}
+
+func pathKey(ingress networkingv1.Ingress, host string, path networkingv1.HTTPIngressPath) ingressPathKey {
+	key := ingressPathKey{namespace: ingress.Namespace, ingressClass: common.GetIngressClass(ingress), host: host, path: path.Path}
+	if path.PathType != nil {
+		key.pathType = string(*path.PathType)
+	}
+	return key
+}
+
+// resolveAnnotationConflict returns the indexes of the Ingresses whose value of
+// the annotation conflicts with the kept one for the path, and emits an Error
+// notification if there are any.
+func resolveAnnotationConflict(ingresses []networkingv1.Ingress, indexes []int, annotation string, key ingressPathKey) []int {
+	var setters []int
+	winner := -1
+	for _, i := range indexes {
+		if ingresses[i].Annotations[annotation] == "" {
+			continue
+		}
+		setters = append(setters, i)
+		if winner < 0 && !isCanary(ingresses[i]) {
+			winner = i
+		}
+	}
+	if len(setters) < 2 {
+		return nil
+	}
+	if winner < 0 {
+		winner = setters[0]
+	}
+
+	value := ingresses[winner].Annotations[annotation]
+	var losers []int
+	var ignored, sources []string
+	var objects []client.Object
+	for _, i := range setters {
+		ingress := &ingresses[i]
+		sources = append(sources, fmt.Sprintf("%s/%s (%q)", ingress.Namespace, ingress.Name, ingress.Annotations[annotation]))
+		objects = append(objects, ingress)
+		if ingress.Annotations[annotation] == value {
+			continue
+		}
+		losers = append(losers, i)
+		ignored = append(ignored, fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name))
+	}
+	if len(losers) == 0 {
+		return nil
+	}
+	notify(notifications.ErrorNotification, fmt.Sprintf("the Ingresses %s set conflicting %s annotations for the path %q of the host %q, the value of Ingress %s/%s is kept and the annotation of %s is ignored for this path", strings.Join(sources, ", "), annotation, key.path, key.host, ingresses[winner].Namespace, ingresses[winner].Name, strings.Join(ignored, ", ")), objects...)
+	return losers
+}
+
+// splitIngressPaths returns copies of the Ingress grouping its paths by the
+// annotations ignored for them, without these annotations. The first copy keeps
+// all the annotations, with the paths without conflict, the rules without paths
+// and the default backend, and is omitted if it has none of them.
+func splitIngressPaths(ingress networkingv1.Ingress, ignored map[ingressPathKey][]string) []networkingv1.Ingress {
+	var copies []networkingv1.Ingress
+	copyIndexes := map[string]int{}
+	copyIndex := func(annotations []string) int {
+		slices.Sort(annotations)
+		annotations = slices.Compact(annotations)
+		name := strings.Join(annotations, ",")
+		if i, ok := copyIndexes[name]; ok {
+			return i
+		}
+		ingressCopy := ingress.DeepCopy()
+		ingressCopy.Spec.Rules = nil
+		ingressCopy.Spec.DefaultBackend = nil
+		for _, annotation := range annotations {
+			delete(ingressCopy.Annotations, annotation)
+		}
+		copies = append(copies, *ingressCopy)
+		copyIndexes[name] = len(copies) - 1
+		return len(copies) - 1
+	}
+
+	copyIndex(nil)
+	copies[0].Spec.DefaultBackend = ingress.Spec.DefaultBackend.DeepCopy()
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			copies[0].Spec.Rules = append(copies[0].Spec.Rules, *rule.DeepCopy())
+			continue
+		}
+		// ruleIndexes are the indexes of the rule in each copy.
+		ruleIndexes := map[int]int{}
+		for _, path := range rule.HTTP.Paths {
+			i := copyIndex(slices.Clone(ignored[pathKey(ingress, rule.Host, path)]))
+			if _, ok := ruleIndexes[i]; !ok {
+				copies[i].Spec.Rules = append(copies[i].Spec.Rules, networkingv1.IngressRule{
+					Host:             rule.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{}},
+				})
+				ruleIndexes[i] = len(copies[i].Spec.Rules) - 1
+			}
+			httpRule := copies[i].Spec.Rules[ruleIndexes[i]].HTTP
+			httpRule.Paths = append(httpRule.Paths, *path.DeepCopy())
+		}
+	}
+
+	if len(copies[0].Spec.Rules) == 0 && copies[0].Spec.DefaultBackend == nil {
+		return copies[1:]
+	}
+	return copies
+}
+
+func isCanary(ingress networkingv1.Ingress) bool {
+	return ingress.Annotations[nginxAnnotation("canary")] == "true"
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_resolveAnnotationConflicts(t *testing.T) {
+	redirect := "nginx.ingress.kubernetes.io/permanent-redirect"
+	canary := map[string]string{"nginx.ingress.kubernetes.io/canary": "true", "nginx.ingress.kubernetes.io/canary-weight": "10"}
+
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		expectedRedirects     []string
+		expectedPaths         [][]string
+		expectedNotifications int
+	}{
+		{
+			name: "conflicting redirect targets keep the primary Ingress",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a-canary", "/", withAnnotations(canary, redirect, "https://canary.example.com")),
+				conflictTestIngress("b-primary", "/", map[string]string{redirect: "https://primary.example.com"}),
+			},
+			expectedRedirects:     []string{"", "https://primary.example.com"},
+			expectedNotifications: 1,
+		},
+		{
+			name: "conflicting redirect targets of primary Ingresses keep the first one",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a", "/", map[string]string{redirect: "https://a.example.com"}),
+				conflictTestIngress("b", "/", map[string]string{redirect: "https://b.example.com"}),
+			},
+			expectedRedirects:     []string{"https://a.example.com", ""},
+			expectedNotifications: 1,
+		},
+		{
+			name: "conflicting redirect targets of one of the paths of an Ingress",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a", "/", map[string]string{redirect: "https://a.example.com"}),
+				withPath(conflictTestIngress("b", "/", map[string]string{redirect: "https://b.example.com"}), "/b"),
+			},
+			expectedRedirects:     []string{"https://a.example.com", "https://b.example.com", ""},
+			expectedPaths:         [][]string{{"/"}, {"/b"}, {"/"}},
+			expectedNotifications: 1,
+		},
+		{
+			name: "identical redirect targets",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a-canary", "/", withAnnotations(canary, redirect, "https://www.example.com")),
+				conflictTestIngress("b-primary", "/", map[string]string{redirect: "https://www.example.com"}),
+			},
+			expectedRedirects: []string{"https://www.example.com", "https://www.example.com"},
+		},
+		{
+			name: "redirect targets of different paths",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a", "/a", map[string]string{redirect: "https://a.example.com"}),
+				conflictTestIngress("b", "/b", map[string]string{redirect: "https://b.example.com"}),
+			},
+			expectedRedirects: []string{"https://a.example.com", "https://b.example.com"},
+		},
+		{
+			name: "redirect target set by a single Ingress",
+			ingresses: []networkingv1.Ingress{
+				conflictTestIngress("a-canary", "/", canary),
+				conflictTestIngress("b-primary", "/", map[string]string{redirect: "https://primary.example.com"}),
+			},
+			expectedRedirects: []string{"", "https://primary.example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			var inputs, originals []map[string]string
+			for _, ingress := range tc.ingresses {
+				inputs = append(inputs, ingress.Annotations)
+				originals = append(originals, maps.Clone(ingress.Annotations))
+			}
+
+			resolved := resolveAnnotationConflicts(tc.ingresses)
+
+			var redirects []string
+			var paths [][]string
+			for _, ingress := range resolved {
+				redirects = append(redirects, ingress.Annotations[redirect])
+				var ingressPaths []string
+				for _, rule := range ingress.Spec.Rules {
+					for _, path := range rule.HTTP.Paths {
+						ingressPaths = append(ingressPaths, path.Path)
+					}
+				}
+				paths = append(paths, ingressPaths)
+			}
+			if diff := cmp.Diff(tc.expectedRedirects, redirects); diff != "" {
+				t.Errorf("Unexpected redirect annotations, \n want: %+v\n got: %+v\n diff (-want +got):\n%s", tc.expectedRedirects, redirects, diff)
+			}
+			if tc.expectedPaths != nil {
+				if diff := cmp.Diff(tc.expectedPaths, paths); diff != "" {
+					t.Errorf("Unexpected paths of the Ingresses, diff (-want +got):\n%s", diff)
+				}
+			}
+			if got := len(notifications.NotificationAggr.Notifications[Name]); got != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %d: %+v", tc.expectedNotifications, got, notifications.NotificationAggr.Notifications[Name])
+			}
+			for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+				if notification.Type != notifications.ErrorNotification {
+					t.Errorf("Expected an Error notification, got %s", notification.Type)
+				}
+			}
+			if diff := cmp.Diff(originals, inputs); diff != "" {
+				t.Errorf("The annotations of the input Ingresses were modified, diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ToGatewayConflictingRedirects(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	redirect := "nginx.ingress.kubernetes.io/permanent-redirect"
+	primary := conflictTestIngress("primary", "/", map[string]string{redirect: "https://primary.example.com"})
+	canary := conflictTestIngress("canary", "/", map[string]string{
+		"nginx.ingress.kubernetes.io/canary":        "true",
+		"nginx.ingress.kubernetes.io/canary-weight": "10",
+		redirect: "https://canary.example.com",
+	})
+
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames: []types.NamespacedName{{Namespace: "default", Name: "canary"}, {Namespace: "default", Name: "primary"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+			{Namespace: "default", Name: "canary"}:  &canary,
+			{Namespace: "default", Name: "primary"}: &primary,
+		},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if len(gatewayResources.HTTPRoutes) != 1 {
+		t.Fatalf("Expected 1 HTTPRoute, got %d: %+v", len(gatewayResources.HTTPRoutes), gatewayResources.HTTPRoutes)
+	}
+	var httpRoute gatewayv1.HTTPRoute
+	for _, route := range gatewayResources.HTTPRoutes {
+		httpRoute = route
+	}
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+		RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+			Scheme:     ptr.To("https"),
+			Hostname:   ptr.To(gatewayv1.PreciseHostname("primary.example.com")),
+			Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+			StatusCode: ptr.To(301),
+		},
+	}}
+	if len(httpRoute.Spec.Rules) != 1 {
+		t.Fatalf("Expected 1 rule, got %d: %+v", len(httpRoute.Spec.Rules), httpRoute.Spec.Rules)
+	}
+	if diff := cmp.Diff(expectedFilters, httpRoute.Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("Unexpected filters, diff (-want +got):\n%s", diff)
+	}
+	if canary.Annotations[redirect] != "https://canary.example.com" {
+		t.Errorf("The annotations of the stored Ingress were modified")
+	}
+}
+
+func Test_ToGatewayConflictingRedirectOfOnePath(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	redirect := "nginx.ingress.kubernetes.io/permanent-redirect"
+	a := conflictTestIngress("a", "/", map[string]string{redirect: "https://a.example.com"})
+	b := withPath(conflictTestIngress("b", "/", map[string]string{redirect: "https://b.example.com"}), "/b")
+
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames: []types.NamespacedName{{Namespace: "default", Name: "a"}, {Namespace: "default", Name: "b"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+			{Namespace: "default", Name: "a"}: &a,
+			{Namespace: "default", Name: "b"}: &b,
+		},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if len(gatewayResources.HTTPRoutes) != 1 {
+		t.Fatalf("Expected 1 HTTPRoute, got %d: %+v", len(gatewayResources.HTTPRoutes), gatewayResources.HTTPRoutes)
+	}
+	// The redirect of Ingress b is only ignored for the conflicting path.
+	redirectHosts := map[string]string{}
+	for _, route := range gatewayResources.HTTPRoutes {
+		for _, rule := range route.Spec.Rules {
+			for _, filter := range rule.Filters {
+				if filter.RequestRedirect != nil && filter.RequestRedirect.Hostname != nil {
+					redirectHosts[*rule.Matches[0].Path.Value] = string(*filter.RequestRedirect.Hostname)
+				}
+			}
+		}
+	}
+	expected := map[string]string{"/": "a.example.com", "/b": "b.example.com"}
+	if diff := cmp.Diff(expected, redirectHosts); diff != "" {
+		t.Errorf("Unexpected redirects by path, diff (-want +got):\n%s", diff)
+	}
+}
+
+func conflictTestIngress(name, path string, annotations map[string]string) networkingv1.Ingress {
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "www.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     path,
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: name,
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func withPath(ingress networkingv1.Ingress, path string) networkingv1.Ingress {
+	httpRule := ingress.Spec.Rules[0].HTTP
+	extraPath := *httpRule.Paths[0].DeepCopy()
+	extraPath.Path = path
+	httpRule.Paths = append(httpRule.Paths, extraPath)
+	return ingress
+}
+
+func withAnnotations(annotations map[string]string, key, value string) map[string]string {
+	merged := map[string]string{key: value}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
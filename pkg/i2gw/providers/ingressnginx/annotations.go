@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import "fmt"
+
+const (
+	annotationPrefix = "nginx.ingress.kubernetes.io"
+
+	backendProtocolKey       = "backend-protocol"
+	configurationSnippetKey  = "configuration-snippet"
+	connectionProxyHeaderKey = "connection-proxy-header"
+	customHTTPErrorsKey      = "custom-http-errors"
+	defaultBackendKey        = "default-backend"
+	enableProxyProtocolKey   = "enable-proxy-protocol"
+	forceSSLRedirectKey      = "force-ssl-redirect"
+	grpcBackendKey           = "grpc-backend"
+	hstsKey                  = "hsts"
+	hstsIncludeSubdomainsKey = "hsts-include-subdomains"
+	hstsMaxAgeKey            = "hsts-max-age"
+	hstsPreloadKey           = "hsts-preload"
+	mirrorHostKey            = "mirror-host"
+	mirrorRequestBodyKey     = "mirror-request-body"
+	mirrorTargetKey          = "mirror-target"
+	permanentRedirectKey     = "permanent-redirect"
+	permanentRedirectCodeKey = "permanent-redirect-code"
+	proxyReadTimeoutKey      = "proxy-read-timeout"
+	proxySendTimeoutKey      = "proxy-send-timeout"
+	proxySSLNameKey          = "proxy-ssl-name"
+	proxySSLSecretKey        = "proxy-ssl-secret"
+	proxySSLVerifyKey        = "proxy-ssl-verify"
+	rewriteTargetKey         = "rewrite-target"
+	serverSnippetKey         = "server-snippet"
+	serviceUpstreamKey       = "service-upstream"
+	sslRedirectKey           = "ssl-redirect"
+	temporalRedirectKey      = "temporal-redirect"
+	useProxyProtocolKey      = "use-proxy-protocol"
+	useRegexKey              = "use-regex"
+	xForwardedPrefixKey      = "x-forwarded-prefix"
+
+	enableModSecurityKey        = "enable-modsecurity"
+	enableOWASPCoreRulesKey     = "enable-owasp-core-rules"
+	modSecurityTransactionIDKey = "modsecurity-transaction-id"
+	modSecuritySnippetKey       = "modsecurity-snippet"
+
+	satisfyKey              = "satisfy"
+	whitelistSourceRangeKey = "whitelist-source-range"
+	allowlistSourceRangeKey = "allowlist-source-range"
+	denylistSourceRangeKey  = "denylist-source-range"
+	authTypeKey             = "auth-type"
+	authSecretKey           = "auth-secret"
+	authRealmKey            = "auth-realm"
+	authURLKey              = "auth-url"
+	authSigninKey           = "auth-signin"
+	authTLSSecretKey        = "auth-tls-secret"
+	authTLSVerifyClientKey  = "auth-tls-verify-client"
+	authTLSVerifyDepthKey   = "auth-tls-verify-depth"
+
+	upstreamKeepaliveConnectionsKey = "upstream-keepalive-connections"
+	upstreamKeepaliveTimeoutKey     = "upstream-keepalive-timeout"
+	upstreamKeepaliveRequestsKey    = "upstream-keepalive-requests"
+	proxyHTTPVersionKey             = "proxy-http-version"
+	proxyBufferingKey               = "proxy-buffering"
+	proxyBufferSizeKey              = "proxy-buffer-size"
+	proxyBuffersNumberKey           = "proxy-buffers-number"
+	proxyRequestBufferingKey        = "proxy-request-buffering"
+	proxyMaxTempFileSizeKey         = "proxy-max-temp-file-size"
+
+	enableAccessLogKey                = "enable-access-log"
+	enableRewriteLogKey               = "enable-rewrite-log"
+	enableOpentracingKey              = "enable-opentracing"
+	opentracingTrustIncomingSpanKey   = "opentracing-trust-incoming-span"
+	enableOpentelemetryKey            = "enable-opentelemetry"
+	opentelemetryTrustIncomingSpanKey = "opentelemetry-trust-incoming-span"
+	opentelemetryOperationNameKey     = "opentelemetry-operation-name"
+
+	limitRPSKey               = "limit-rps"
+	limitRPMKey               = "limit-rpm"
+	proxyNextUpstreamKey      = "proxy-next-upstream"
+	proxyNextUpstreamTriesKey = "proxy-next-upstream-tries"
+	loadBalanceKey            = "load-balance"
+	upstreamHashByKey         = "upstream-hash-by"
+	proxyBodySizeKey          = "proxy-body-size"
+
+	affinityKey                     = "affinity"
+	affinityModeKey                 = "affinity-mode"
+	affinityCanaryBehaviorKey       = "affinity-canary-behavior"
+	sessionCookieNameKey            = "session-cookie-name"
+	sessionCookiePathKey            = "session-cookie-path"
+	sessionCookieExpiresKey         = "session-cookie-expires"
+	sessionCookieMaxAgeKey          = "session-cookie-max-age"
+	sessionCookieSecureKey          = "session-cookie-secure"
+	sessionCookieSameSiteKey        = "session-cookie-samesite"
+	sessionCookieChangeOnFailureKey = "session-cookie-change-on-failure"
+)
+
+// convertedAnnotationKeys are the suffixes of the annotations converted to
+// Gateway API resources.
+var convertedAnnotationKeys = []string{
+	"canary",
+	"canary-by-header",
+	"canary-by-header-value",
+	"canary-by-header-pattern",
+	"canary-weight",
+	"canary-weight-total",
+	backendProtocolKey,
+	configurationSnippetKey,
+	connectionProxyHeaderKey,
+	defaultBackendKey,
+	enableProxyProtocolKey,
+	forceSSLRedirectKey,
+	grpcBackendKey,
+	hstsKey,
+	hstsIncludeSubdomainsKey,
+	hstsMaxAgeKey,
+	hstsPreloadKey,
+	mirrorRequestBodyKey,
+	mirrorTargetKey,
+	permanentRedirectKey,
+	permanentRedirectCodeKey,
+	proxySSLNameKey,
+	proxySSLSecretKey,
+	proxySSLVerifyKey,
+	rewriteTargetKey,
+	serverSnippetKey,
+	sslRedirectKey,
+	temporalRedirectKey,
+	useProxyProtocolKey,
+	xForwardedPrefixKey,
+	limitRPSKey,
+	limitRPMKey,
+	proxyNextUpstreamKey,
+	proxyNextUpstreamTriesKey,
+	loadBalanceKey,
+	upstreamHashByKey,
+	proxyBodySizeKey,
+	proxyRequestBufferingKey,
+}
+
+// reportedAnnotationKeys are the suffixes of the annotations that have no
+// Gateway API equivalent, but are reported with a notification listing the
+// settings to reconfigure.
+var reportedAnnotationKeys = []string{
+	customHTTPErrorsKey,
+	authTLSSecretKey,
+	authTLSVerifyClientKey,
+	authTLSVerifyDepthKey,
+	proxyBufferingKey,
+	enableAccessLogKey,
+	enableRewriteLogKey,
+	enableOpentracingKey,
+	opentracingTrustIncomingSpanKey,
+	enableOpentelemetryKey,
+	opentelemetryTrustIncomingSpanKey,
+	opentelemetryOperationNameKey,
+	affinityKey,
+	affinityModeKey,
+	affinityCanaryBehaviorKey,
+	sessionCookieNameKey,
+	sessionCookiePathKey,
+	sessionCookieExpiresKey,
+	sessionCookieMaxAgeKey,
+	sessionCookieSecureKey,
+	sessionCookieSameSiteKey,
+	sessionCookieChangeOnFailureKey,
+}
+
+// supportedAnnotations returns the keys of the annotations converted to Gateway
+// API resources or reported with a notification.
+func supportedAnnotations() []string {
+	annotations := make([]string, 0, len(convertedAnnotationKeys)+len(reportedAnnotationKeys))
+	for _, key := range convertedAnnotationKeys {
+		annotations = append(annotations, nginxAnnotation(key))
+	}
+	for _, key := range reportedAnnotationKeys {
+		annotations = append(annotations, nginxAnnotation(key))
+	}
+	return annotations
+}
+
+func nginxAnnotation(suffix string) string {
+	return fmt.Sprintf("%s/%s", annotationPrefix, suffix)
+}
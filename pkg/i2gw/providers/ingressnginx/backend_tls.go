@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// backendTLSFeature converts the `nginx.ingress.kubernetes.io/proxy-ssl-*` annotations
+// of Ingresses with the HTTPS or GRPCS backend protocol into a BackendTLSPolicy for
+// every backend Service.
+//
+// The policy validates the backends with the CA bundle (`ca.crt`) of the Secret set by
+// `proxy-ssl-secret` and the hostname set by `proxy-ssl-name`, and is only generated
+// when `proxy-ssl-verify` is on, as a BackendTLSPolicy always verifies the backend
+// certificate. In any other case a Warning notification is emitted, as TLS to the
+// backends must then be configured with the Gateway implementation. As the
+// Secret also holds the client certificate ingress-nginx presents to the backends,
+// which BackendTLSPolicy cannot express, a Warning notification is emitted for it too.
+// A single policy is generated per Service: if Ingresses set different TLS settings
+// for the same Service, the first one is kept and a Warning notification is emitted.
+func backendTLSFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	for i := range ingresses {
+		ingress := ingresses[i]
+		protocol := strings.ToUpper(ingress.Annotations[nginxAnnotation(backendProtocolKey)])
+		secret, hasSecret := ingress.Annotations[nginxAnnotation(proxySSLSecretKey)]
+		verify := ingress.Annotations[nginxAnnotation(proxySSLVerifyKey)] == "on"
+		hostname, hasHostname := ingress.Annotations[nginxAnnotation(proxySSLNameKey)]
+
+		if protocol != "HTTPS" && protocol != "GRPCS" {
+			if hasSecret || verify || hasHostname {
+				notify(notifications.WarningNotification, fmt.Sprintf("the proxy-ssl annotations are ignored, as the %s annotation is not HTTPS or GRPCS", nginxAnnotation(backendProtocolKey)), &ingress)
+			}
+			continue
+		}
+		if hasSecret {
+			notify(notifications.WarningNotification, fmt.Sprintf("the client certificate of Secret %s presented to the backends is not converted, as BackendTLSPolicy has no equivalent: configure it with your Gateway implementation", secret), &ingress)
+		}
+		if !verify {
+			notify(notifications.WarningNotification, fmt.Sprintf("the TLS connections to the %s backends are not verified, which BackendTLSPolicy cannot express: configure TLS to the backends with your Gateway implementation", protocol), &ingress)
+			continue
+		}
+		if !hasSecret {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s is on without a %s annotation holding the CA bundle, no BackendTLSPolicy is generated", nginxAnnotation(proxySSLVerifyKey), nginxAnnotation(proxySSLSecretKey)), &ingress)
+			continue
+		}
+		secretKey := toNamespacedName(secret)
+		if secretKey.Namespace == "" {
+			secretKey.Namespace = ingress.Namespace
+		}
+		if secretKey.Namespace != ingress.Namespace {
+			notify(notifications.ErrorNotification, fmt.Sprintf("Secret %s cannot be referenced by a BackendTLSPolicy in namespace %s, as CA certificate references must be local: copy the CA bundle to namespace %s", secretKey, ingress.Namespace, ingress.Namespace), &ingress)
+			continue
+		}
+
+		for _, service := range backendServiceNames(ingress) {
+			policyHostname := hostname
+			if !hasHostname {
+				policyHostname = fmt.Sprintf("%s.%s.svc", service, ingress.Namespace)
+				notify(notifications.WarningNotification, fmt.Sprintf("the %s annotation is not set, the backend certificate of Service %s/%s is validated against the hostname %s", nginxAnnotation(proxySSLNameKey), ingress.Namespace, service, policyHostname), &ingress)
+			}
+			if errs := validation.IsDNS1123Subdomain(policyHostname); len(errs) > 0 {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%s %q is not a valid hostname, no BackendTLSPolicy is generated for Service %s/%s", nginxAnnotation(proxySSLNameKey), policyHostname, ingress.Namespace, service), &ingress)
+				continue
+			}
+			addBackendTLSPolicy(gatewayResources, &ingress, service, secretKey.Name, policyHostname)
+		}
+	}
+	return nil
+}
+
+// backendServiceNames returns the names of the Services the Ingress routes to, in
+// order of appearance.
+func backendServiceNames(ingress networkingv1.Ingress) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(backend *networkingv1.IngressBackend) {
+		if backend == nil || backend.Service == nil || seen[backend.Service.Name] {
+			return
+		}
+		seen[backend.Service.Name] = true
+		names = append(names, backend.Service.Name)
+	}
+	add(ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			add(&rule.HTTP.Paths[i].Backend)
+		}
+	}
+	return names
+}
+
+func addBackendTLSPolicy(gatewayResources *i2gw.GatewayResources, ingress *networkingv1.Ingress, service, caSecret, hostname string) {
+	if gatewayResources.BackendTLSPolicies == nil {
+		gatewayResources.BackendTLSPolicies = map[types.NamespacedName]gatewayv1alpha2.BackendTLSPolicy{}
+	}
+	policy := gatewayv1alpha2.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-backend-tls", service),
+			Namespace: ingress.Namespace,
+		},
+		Spec: gatewayv1alpha2.BackendTLSPolicySpec{
+			TargetRef: gatewayv1alpha2.PolicyTargetReferenceWithSectionName{
+				PolicyTargetReference: gatewayv1alpha2.PolicyTargetReference{
+					Group: "",
+					Kind:  "Service",
+					Name:  gatewayv1.ObjectName(service),
+				},
+			},
+			TLS: gatewayv1alpha2.BackendTLSPolicyConfig{
+				CACertRefs: []gatewayv1.LocalObjectReference{{
+					Group: "",
+					Kind:  "Secret",
+					Name:  gatewayv1.ObjectName(caSecret),
+				}},
+				Hostname: gatewayv1.PreciseHostname(hostname),
+			},
+		},
+		Status: gatewayv1alpha2.PolicyStatus{
+			Ancestors: []gatewayv1alpha2.PolicyAncestorStatus{},
+		},
+	}
+	policy.SetGroupVersionKind(common.BackendTLSPolicyGVK)
+
+	key := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
+	if existing, ok := gatewayResources.BackendTLSPolicies[key]; ok {
+		if !apiequality.Semantic.DeepEqual(existing.Spec, policy.Spec) {
+			notify(notifications.WarningNotification, fmt.Sprintf("the proxy-ssl annotations conflict with the ones of another Ingress routing to Service %s/%s, BackendTLSPolicy %s is kept as generated from the first Ingress", ingress.Namespace, service, key), ingress)
+		}
+		return
+	}
+	gatewayResources.BackendTLSPolicies[key] = policy
+	common.RecordIngressProvenance(common.BackendTLSPolicyGVK.Kind, key, "", ingress, nginxAnnotation(proxySSLVerifyKey))
+	common.RecordIngressProvenance(common.BackendTLSPolicyGVK.Kind, key, "spec.tls.caCertRefs", ingress, nginxAnnotation(proxySSLSecretKey))
+	if _, ok := ingress.Annotations[nginxAnnotation(proxySSLNameKey)]; ok {
+		common.RecordIngressProvenance(common.BackendTLSPolicyGVK.Kind, key, "spec.tls.hostname", ingress, nginxAnnotation(proxySSLNameKey))
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("BackendTLSPolicy %s was generated from the proxy-ssl annotations: the CA bundle is read from the ca.crt key of Secret %s, while only ConfigMaps have core support in Gateway API", key, caSecret), ingress)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_backendTLSFeature(t *testing.T) {
+	backendTLSIngress := func(name string, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: "secure", Port: networkingv1.ServiceBackendPort{Number: 443}},
+				},
+			},
+		}
+	}
+	verifiedAnnotations := map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+		"nginx.ingress.kubernetes.io/proxy-ssl-secret": "default/backend-ca",
+		"nginx.ingress.kubernetes.io/proxy-ssl-verify": "on",
+		"nginx.ingress.kubernetes.io/proxy-ssl-name":   "secure.example.com",
+	}
+	expectedPolicySpec := gatewayv1alpha2.BackendTLSPolicySpec{
+		TargetRef: gatewayv1alpha2.PolicyTargetReferenceWithSectionName{
+			PolicyTargetReference: gatewayv1alpha2.PolicyTargetReference{Kind: "Service", Name: "secure"},
+		},
+		TLS: gatewayv1alpha2.BackendTLSPolicyConfig{
+			CACertRefs: []gatewayv1.LocalObjectReference{{Kind: "Secret", Name: "backend-ca"}},
+			Hostname:   "secure.example.com",
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		expectedPolicySpec    *gatewayv1alpha2.BackendTLSPolicySpec
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name:                  "verified TLS with a CA bundle and hostname",
+			ingresses:             []networkingv1.Ingress{backendTLSIngress("verified", verifiedAnnotations)},
+			expectedPolicySpec:    &expectedPolicySpec,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1, notifications.InfoNotification: 1},
+		},
+		{
+			name: "unverified TLS",
+			ingresses: []networkingv1.Ingress{backendTLSIngress("unverified", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+			})},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "proxy-ssl annotations without an HTTPS backend protocol",
+			ingresses: []networkingv1.Ingress{backendTLSIngress("http", map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-ssl-secret": "default/backend-ca",
+				"nginx.ingress.kubernetes.io/proxy-ssl-verify": "on",
+			})},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "CA bundle in another namespace",
+			ingresses: []networkingv1.Ingress{backendTLSIngress("cross-namespace", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+				"nginx.ingress.kubernetes.io/proxy-ssl-secret": "other/backend-ca",
+				"nginx.ingress.kubernetes.io/proxy-ssl-verify": "on",
+				"nginx.ingress.kubernetes.io/proxy-ssl-name":   "secure.example.com",
+			})},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1, notifications.ErrorNotification: 1},
+		},
+		{
+			name: "conflicting settings for the same Service",
+			ingresses: []networkingv1.Ingress{
+				backendTLSIngress("verified", verifiedAnnotations),
+				backendTLSIngress("conflicting", map[string]string{
+					"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+					"nginx.ingress.kubernetes.io/proxy-ssl-secret": "default/backend-ca",
+					"nginx.ingress.kubernetes.io/proxy-ssl-verify": "on",
+					"nginx.ingress.kubernetes.io/proxy-ssl-name":   "other.example.com",
+				}),
+			},
+			expectedPolicySpec:    &expectedPolicySpec,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 3, notifications.InfoNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			gatewayResources := i2gw.GatewayResources{}
+			if errs := backendTLSFeature(tc.ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			policy, ok := gatewayResources.BackendTLSPolicies[types.NamespacedName{Namespace: "default", Name: "secure-backend-tls"}]
+			if tc.expectedPolicySpec == nil {
+				if len(gatewayResources.BackendTLSPolicies) != 0 {
+					t.Errorf("Expected no BackendTLSPolicy, got %+v", gatewayResources.BackendTLSPolicies)
+				}
+			} else if !ok || len(gatewayResources.BackendTLSPolicies) != 1 {
+				t.Errorf("Expected a single BackendTLSPolicy default/secure-backend-tls, got %+v", gatewayResources.BackendTLSPolicies)
+			} else if diff := cmp.Diff(*tc.expectedPolicySpec, policy.Spec); diff != "" {
+				t.Errorf("Unexpected BackendTLSPolicy spec (-want +got):\n%s", diff)
+			}
+
+			gotNotifications := map[notifications.MessageType]int{}
+			for _, n := range notifications.NotificationAggr.Notifications[Name] {
+				gotNotifications[n.Type]++
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, gotNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,14 +18,18 @@ package ingressnginx
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -43,6 +47,9 @@ func canaryFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.Gate
 
 			backendRefs, calculationErrs := calculateBackendRefWeight(paths)
 			errs = append(errs, calculationErrs...)
+			if len(calculationErrs) == 0 {
+				notifyCanaryWeightTotal(paths, backendRefs)
+			}
 
 			key := types.NamespacedName{Namespace: path.ingress.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
 			httpRoute, ok := gatewayResources.HTTPRoutes[key]
@@ -52,7 +59,15 @@ func canaryFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.Gate
 				continue
 			}
 
-			patchHTTPRouteWithBackendRefs(&httpRoute, backendRefs)
+			for _, i := range patchHTTPRouteWithBackendRefs(&httpRoute, path.path, backendRefs) {
+				for _, p := range paths {
+					if p.extra != nil && p.extra.canary != nil && p.extra.canary.enable {
+						ingress := p.ingress
+						common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].backendRefs", i), &ingress, "nginx.ingress.kubernetes.io/canary-weight")
+					}
+				}
+			}
+			gatewayResources.HTTPRoutes[key] = httpRoute
 		}
 		if len(errs) > 0 {
 			return errs
@@ -68,6 +83,9 @@ func getPathsByMatchGroups(rg common.IngressRuleGroup) (map[pathMatchKey][]ingre
 	for _, ir := range rg.Rules {
 
 		ingress := ir.Ingress
+		if ir.IngressRule.HTTP == nil {
+			continue
+		}
 		annotations, errs := parseCanaryAnnotations(ingress)
 		if len(errs) > 0 {
 			return nil, errs
@@ -85,33 +103,56 @@ func getPathsByMatchGroups(rg common.IngressRuleGroup) (map[pathMatchKey][]ingre
 	return ingressPathsByMatchKey, nil
 }
 
-func patchHTTPRouteWithBackendRefs(httpRoute *gatewayv1.HTTPRoute, backendRefs []gatewayv1.HTTPBackendRef) {
-	for _, backendRef := range backendRefs {
-
-		ruleExists := false
-
-		for j, rule := range httpRoute.Spec.Rules {
+// patchHTTPRouteWithBackendRefs sets the backendRefs of the HTTPRoute rules generated
+// from the path, so that the backends of the other paths of a fanout Ingress are
+// left untouched. It returns the indexes of the patched rules.
+func patchHTTPRouteWithBackendRefs(httpRoute *gatewayv1.HTTPRoute, path networkingv1.HTTPIngressPath, backendRefs []gatewayv1.HTTPBackendRef) []int {
+	var patched []int
+	for j, rule := range httpRoute.Spec.Rules {
+		if !ruleMatchesIngressPath(rule, path) {
+			continue
+		}
+		for _, backendRef := range backendRefs {
 			foundBackendRef := false
 			for i := range rule.BackendRefs {
 				if backendRef.Name == rule.BackendRefs[i].Name {
 					rule.BackendRefs[i].Weight = backendRef.Weight
 					foundBackendRef = true
-					ruleExists = true
 					break
 				}
 			}
 
 			if !foundBackendRef {
-				httpRoute.Spec.Rules[j].BackendRefs = append(rule.BackendRefs, backendRef)
+				rule.BackendRefs = append(rule.BackendRefs, backendRef)
 			}
 		}
+		httpRoute.Spec.Rules[j].BackendRefs = rule.BackendRefs
+		patched = append(patched, j)
+	}
+	return patched
+}
 
-		if !ruleExists {
-			httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, gatewayv1.HTTPRouteRule{
-				BackendRefs: []gatewayv1.HTTPBackendRef{backendRef},
-			})
+// ruleMatchesIngressPath returns whether the rule matches the path with the same
+// path type.
+func ruleMatchesIngressPath(rule gatewayv1.HTTPRouteRule, path networkingv1.HTTPIngressPath) bool {
+	var matchType gatewayv1.PathMatchType
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypePrefix:
+			matchType = gatewayv1.PathMatchPathPrefix
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		}
+	}
+	for _, match := range rule.Matches {
+		if match.Path == nil || match.Path.Value == nil || *match.Path.Value != path.Path {
+			continue
+		}
+		if matchType == "" || match.Path.Type == nil || *match.Path.Type == matchType {
+			return true
 		}
 	}
+	return false
 }
 
 func calculateBackendRefWeight(paths []ingressPath) ([]gatewayv1.HTTPBackendRef, field.ErrorList) {
@@ -158,6 +199,48 @@ func calculateBackendRefWeight(paths []ingressPath) ([]gatewayv1.HTTPBackendRef,
 	return backendRefs, errors
 }
 
+// notifyCanaryWeightTotal emits a notification with the traffic percentages of
+// the backends when a canary Ingress of the paths sets a canary-weight-total
+// other than 100. The weights are kept relative to that total, as Gateway API
+// splits the traffic proportionally to the sum of the weights.
+func notifyCanaryWeightTotal(paths []ingressPath, backendRefs []gatewayv1.HTTPBackendRef) {
+	var weightTotal int
+	var canaries []client.Object
+	for _, path := range paths {
+		if path.extra == nil || path.extra.canary == nil || !path.extra.canary.enable {
+			continue
+		}
+		if path.extra.canary.weightTotal > 0 && path.extra.canary.weightTotal != 100 {
+			weightTotal = path.extra.canary.weightTotal
+			ingress := path.ingress
+			canaries = append(canaries, &ingress)
+		}
+	}
+	if weightTotal == 0 {
+		return
+	}
+
+	var sum int32
+	for _, backendRef := range backendRefs {
+		if backendRef.Weight != nil {
+			sum += *backendRef.Weight
+		}
+	}
+	if sum == 0 {
+		return
+	}
+	percentages := make([]string, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		var weight int32
+		if backendRef.Weight != nil {
+			weight = *backendRef.Weight
+		}
+		percentage := math.Round(float64(weight)*10000/float64(sum)) / 100
+		percentages = append(percentages, fmt.Sprintf("%s: %d (%s%%)", backendRef.Name, weight, strconv.FormatFloat(percentage, 'f', -1, 64)))
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("the canary weights are relative to %s %d, the traffic is split as %s", nginxAnnotation("canary-weight-total"), weightTotal, strings.Join(percentages, ", ")), canaries...)
+}
+
 type canaryAnnotations struct {
 	enable           bool
 	headerKey        string
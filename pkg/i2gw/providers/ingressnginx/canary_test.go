@@ -20,10 +20,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -324,3 +328,55 @@ func Test_parseCanaryAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func Test_ToGatewayCanaryWeightTotal(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	primary := conflictTestIngress("primary", "/", nil)
+	canary := conflictTestIngress("canary", "/", map[string]string{
+		"nginx.ingress.kubernetes.io/canary":              "true",
+		"nginx.ingress.kubernetes.io/canary-weight":       "50",
+		"nginx.ingress.kubernetes.io/canary-weight-total": "1000",
+	})
+
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames: []types.NamespacedName{{Namespace: "default", Name: "canary"}, {Namespace: "default", Name: "primary"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+			{Namespace: "default", Name: "canary"}:  &canary,
+			{Namespace: "default", Name: "primary"}: &primary,
+		},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if len(gatewayResources.HTTPRoutes) != 1 {
+		t.Fatalf("Expected 1 HTTPRoute, got %d: %+v", len(gatewayResources.HTTPRoutes), gatewayResources.HTTPRoutes)
+	}
+	var httpRoute gatewayv1.HTTPRoute
+	for _, route := range gatewayResources.HTTPRoutes {
+		httpRoute = route
+	}
+	if len(httpRoute.Spec.Rules) != 1 {
+		t.Fatalf("Expected 1 rule, got %d: %+v", len(httpRoute.Spec.Rules), httpRoute.Spec.Rules)
+	}
+	weights := map[gatewayv1.ObjectName]int32{}
+	for _, backendRef := range httpRoute.Spec.Rules[0].BackendRefs {
+		weights[backendRef.Name] = ptr.Deref(backendRef.Weight, 1)
+	}
+	if diff := cmp.Diff(map[gatewayv1.ObjectName]int32{"canary": 50, "primary": 950}, weights); diff != "" {
+		t.Errorf("Unexpected backend weights (-want +got):\n%s", diff)
+	}
+
+	var messages []string
+	for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+		if notification.Type == notifications.InfoNotification {
+			messages = append(messages, notification.Message)
+		}
+	}
+	expected := []string{"the canary weights are relative to nginx.ingress.kubernetes.io/canary-weight-total 1000, the traffic is split as canary: 50 (5%), primary: 950 (95%)"}
+	if diff := cmp.Diff(expected, messages); diff != "" {
+		t.Errorf("Unexpected info notifications (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// clientCertificateAuthFeature reports the client certificate authentication
+// of the Ingresses, configured by `nginx.ingress.kubernetes.io/auth-tls-secret`,
+// `auth-tls-verify-client` and `auth-tls-verify-depth`.
+//
+// The listener tls.frontendValidation validating the client certificates was
+// only added to the experimental channel of Gateway API v1.1, after the version
+// generated by the tool, so the client certificate authentication cannot be
+// converted to any channel. An Error notification is emitted per Ingress, with
+// the HTTPS listeners of its hosts, the CA Secret, the verify mode and depth to
+// set up with the implementation instead, as the hosts would otherwise accept
+// the clients without certificate.
+func clientCertificateAuthFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		secret := strings.TrimSpace(ingress.Annotations[nginxAnnotation(authTLSSecretKey)])
+		if secret == "" {
+			continue
+		}
+		ingress := ingress
+		verifyClient := strings.TrimSpace(ingress.Annotations[nginxAnnotation(authTLSVerifyClientKey)])
+		if verifyClient == "" {
+			verifyClient = "on"
+		}
+		if verifyClient == "off" {
+			notify(notifications.InfoNotification, fmt.Sprintf("%s is ignored, as %s: off disables the client certificate authentication", nginxAnnotation(authTLSSecretKey), nginxAnnotation(authTLSVerifyClientKey)), &ingress)
+			continue
+		}
+
+		secretNamespace, secretName, ok := strings.Cut(secret, "/")
+		if !ok {
+			secretNamespace, secretName = ingress.Namespace, secret
+		}
+		settings := []string{fmt.Sprintf("- CA certificate: the ca.crt key of Secret %s/%s", secretNamespace, secretName)}
+		if secretNamespace != ingress.Namespace {
+			settings = append(settings, fmt.Sprintf("- cross-namespace CA: referencing Secret %s/%s from another namespace requires a ReferenceGrant in namespace %s", secretNamespace, secretName, secretNamespace))
+		}
+		switch verifyClient {
+		case "on":
+			settings = append(settings, "- verify mode: on, the requests without a valid client certificate are rejected")
+		case "optional":
+			settings = append(settings, "- verify mode: optional, the requests without client certificate are accepted, and the ones with an invalid certificate rejected")
+		case "optional_no_ca":
+			settings = append(settings, "- verify mode: optional_no_ca, the client certificates are requested but not verified, and passed to the backends")
+		default:
+			settings = append(settings, fmt.Sprintf("- verify mode: %s, not a valid value, ingress-nginx expects on, off, optional or optional_no_ca", verifyClient))
+		}
+		depth := "1"
+		if value, ok := ingress.Annotations[nginxAnnotation(authTLSVerifyDepthKey)]; ok {
+			depth = strings.TrimSpace(value)
+		}
+		if parsed, err := strconv.Atoi(depth); err == nil && parsed > 0 {
+			settings = append(settings, fmt.Sprintf("- verify depth: %d, the maximum length of the client certificate chains", parsed))
+		} else {
+			settings = append(settings, fmt.Sprintf("- verify depth: %q, not a valid depth, ingress-nginx expects a positive number", depth))
+		}
+
+		listeners := "the HTTPS listeners of its hosts"
+		if names := ingressHTTPSListeners(ingress, gatewayResources); len(names) > 0 {
+			listeners = strings.Join(names, ", ")
+		}
+		notify(notifications.ErrorNotification, fmt.Sprintf("the client certificate authentication was not converted, as the generated Gateway API version has no listener tls.frontendValidation in any channel, and the hosts accept the clients without certificate until it is set up with your Gateway implementation, e.g. with Envoy Gateway in ClientTrafficPolicy spec.tls.clientValidation, on %s. Settings:\n%s", listeners, strings.Join(settings, "\n")), &ingress)
+	}
+	return nil
+}
+
+// ingressHTTPSListeners returns the HTTPS listeners of the generated Gateways
+// for the hosts of the Ingress, as `<namespace>/<gateway> listener <name>`.
+func ingressHTTPSListeners(ingress networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) []string {
+	hosts := sets.New[string]()
+	for _, rule := range ingress.Spec.Rules {
+		hosts.Insert(rule.Host)
+	}
+	var names []string
+	for _, gateway := range gatewayResources.Gateways {
+		if gateway.Namespace != ingress.Namespace {
+			continue
+		}
+		for _, listener := range gateway.Spec.Listeners {
+			hostname := ""
+			if listener.Hostname != nil {
+				hostname = string(*listener.Hostname)
+			}
+			if listener.Protocol == gatewayv1.HTTPSProtocolType && hosts.Has(hostname) {
+				names = append(names, fmt.Sprintf("%s/%s listener %s", gateway.Namespace, gateway.Name, listener.Name))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_clientCertificateAuthFeature(t *testing.T) {
+	gatewayResources := &i2gw.GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "nginx"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+				Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+					{Name: "foo-com-http", Hostname: ptr.To(gatewayv1.Hostname("foo.com")), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+					{Name: "foo-com-https", Hostname: ptr.To(gatewayv1.Hostname("foo.com")), Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedNotification notifications.MessageType
+		expectedMessages     []string
+	}{
+		{
+			name: "no client certificate authentication",
+		},
+		{
+			name:                 "CA of the Ingress namespace",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/auth-tls-secret": "ca"},
+			expectedNotification: notifications.ErrorNotification,
+			expectedMessages: []string{
+				"on default/nginx listener foo-com-https",
+				"- CA certificate: the ca.crt key of Secret default/ca",
+				"- verify mode: on, the requests without a valid client certificate are rejected",
+				"- verify depth: 1,",
+			},
+		},
+		{
+			name: "optional verification with a CA of another namespace",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/auth-tls-secret":        "certs/ca",
+				"nginx.ingress.kubernetes.io/auth-tls-verify-client": "optional",
+				"nginx.ingress.kubernetes.io/auth-tls-verify-depth":  "3",
+			},
+			expectedNotification: notifications.ErrorNotification,
+			expectedMessages: []string{
+				"- CA certificate: the ca.crt key of Secret certs/ca",
+				"requires a ReferenceGrant in namespace certs",
+				"- verify mode: optional,",
+				"- verify depth: 3,",
+			},
+		},
+		{
+			name: "verification disabled",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/auth-tls-secret":        "ca",
+				"nginx.ingress.kubernetes.io/auth-tls-verify-client": "off",
+			},
+			expectedNotification: notifications.InfoNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mtls", Annotations: tc.annotations},
+				Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "foo.com"}}},
+			}}
+
+			if errs := clientCertificateAuthFeature(ingresses, gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedNotification == "" {
+				if len(notifs) > 0 {
+					t.Errorf("Expected no notification, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Type != tc.expectedNotification {
+				t.Fatalf("Expected a single %s notification, got %+v", tc.expectedNotification, notifs)
+			}
+			for _, message := range tc.expectedMessages {
+				if !strings.Contains(notifs[0].Message, message) {
+					t.Errorf("Expected the notification to contain %q, got %q", message, notifs[0].Message)
+				}
+			}
+		})
+	}
+}
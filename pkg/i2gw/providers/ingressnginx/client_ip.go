@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// controllerServiceLabel is the label set by the ingress-nginx manifests and
+// helm chart on the Service fronting the controller.
+const controllerServiceLabel = "app.kubernetes.io/name"
+
+// controllerNamespace is the namespace the ingress-nginx manifests and helm
+// chart install the controller in, and the namespace of a controller-service
+// flag value without one.
+const controllerNamespace = "ingress-nginx"
+
+// notifyClientIPPreservation surfaces the settings of ingress-nginx affecting the
+// preservation of the client source IP, as they have to be reconfigured at the
+// Gateway implementation level:
+//   - The externalTrafficPolicy of the Service fronting the controller, which is
+//     reported on every generated Gateway.
+//   - The nginx.ingress.kubernetes.io/service-upstream annotation.
+func notifyClientIPPreservation(ingresses []networkingv1.Ingress, services map[types.NamespacedName]*corev1.Service, controllerService types.NamespacedName, gatewayResources i2gw.GatewayResources) {
+	for _, ingress := range ingresses {
+		if ingress.Annotations[nginxAnnotation(serviceUpstreamKey)] == "true" {
+			ingress := ingress
+			notify(notifications.WarningNotification, fmt.Sprintf("%s is not supported: ingress-nginx proxied the requests to the Service cluster IP instead of its endpoints, which Gateway API implementations usually route to directly", nginxAnnotation(serviceUpstreamKey)), &ingress)
+		}
+	}
+
+	if len(gatewayResources.Gateways) == 0 {
+		return
+	}
+
+	if _, ok := services[controllerService]; controllerService.Name != "" && !ok {
+		notify(notifications.WarningNotification, fmt.Sprintf("the ingress-nginx controller Service %s was not found, the client IP preservation settings cannot be reported", controllerService))
+		return
+	}
+
+	frontingServices := controllerServices(services, controllerService)
+	if len(frontingServices) == 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("no LoadBalancer or NodePort Service labeled %s=ingress-nginx was found, the client IP preservation settings cannot be reported: set the Service fronting the controller with --%s-%s", controllerServiceLabel, Name, ControllerServiceFlag))
+		return
+	}
+
+	for _, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		for _, service := range frontingServices {
+			notify(notifications.InfoNotification, clientIPPreservationMessage(service, gateway.Namespace, gateway.Name), &gateway)
+		}
+	}
+}
+
+// controllerServices returns the Service fronting the ingress-nginx controller
+// set with the controller-service provider-specific flag, if any, or the
+// Services labeled as ingress-nginx ones otherwise.
+func controllerServices(services map[types.NamespacedName]*corev1.Service, controllerService types.NamespacedName) []*corev1.Service {
+	if controllerService.Name == "" {
+		return findControllerServices(services)
+	}
+	if service, ok := services[controllerService]; ok {
+		return []*corev1.Service{service}
+	}
+	return nil
+}
+
+// findControllerServices returns the LoadBalancer and NodePort Services labeled
+// as ingress-nginx ones, sorted by namespace/name.
+func findControllerServices(services map[types.NamespacedName]*corev1.Service) []*corev1.Service {
+	var controllerServices []*corev1.Service
+	for _, service := range services {
+		if service.Labels[controllerServiceLabel] != "ingress-nginx" {
+			continue
+		}
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer && service.Spec.Type != corev1.ServiceTypeNodePort {
+			continue
+		}
+		controllerServices = append(controllerServices, service)
+	}
+	sort.Slice(controllerServices, func(i, j int) bool {
+		return types.NamespacedName{Namespace: controllerServices[i].Namespace, Name: controllerServices[i].Name}.String() <
+			types.NamespacedName{Namespace: controllerServices[j].Namespace, Name: controllerServices[j].Name}.String()
+	})
+	return controllerServices
+}
+
+func clientIPPreservationMessage(service *corev1.Service, gatewayNamespace, gatewayName string) string {
+	serviceName := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+	if service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+		return fmt.Sprintf("the ingress-nginx controller Service %s has externalTrafficPolicy %s, so the client source IP was preserved: configure the Service fronting Gateway %s/%s the same way in your Gateway implementation to keep preserving it", serviceName, service.Spec.ExternalTrafficPolicy, gatewayNamespace, gatewayName)
+	}
+	return fmt.Sprintf("the ingress-nginx controller Service %s has externalTrafficPolicy %s, so the client source IP was not preserved by the Service: check how your Gateway implementation exposes the client IP for Gateway %s/%s", serviceName, corev1.ServiceExternalTrafficPolicyCluster, gatewayNamespace, gatewayName)
+}
+
+// toNamespacedName parses a namespace/name string. The namespace is optional.
+func toNamespacedName(s string) types.NamespacedName {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) == 1 {
+		return types.NamespacedName{Name: parts[0]}
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+}
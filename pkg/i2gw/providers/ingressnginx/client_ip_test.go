@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_notifyClientIPPreservation(t *testing.T) {
+	controllerService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ingress-nginx",
+			Name:      "ingress-nginx-controller",
+			Labels:    map[string]string{"app.kubernetes.io/name": "ingress-nginx"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+		},
+	}
+	customService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "edge", Name: "nginx"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"}: controllerService,
+		{Namespace: "edge", Name: "nginx"}:                             customService,
+	}
+
+	testCases := []struct {
+		name              string
+		ingresses         []networkingv1.Ingress
+		services          map[types.NamespacedName]*corev1.Service
+		controllerService types.NamespacedName
+		expectedMessages  []string
+	}{
+		{
+			name:             "controller service found by label",
+			expectedMessages: []string{"ingress-nginx/ingress-nginx-controller has externalTrafficPolicy Local"},
+		},
+		{
+			name:              "controller service set by flag",
+			controllerService: types.NamespacedName{Namespace: "edge", Name: "nginx"},
+			expectedMessages:  []string{"edge/nginx has externalTrafficPolicy Cluster"},
+		},
+		{
+			name:              "controller service set by flag not found",
+			controllerService: types.NamespacedName{Namespace: "edge", Name: "missing"},
+			expectedMessages:  []string{"edge/missing was not found"},
+		},
+		{
+			name:             "no controller service found by label",
+			services:         map[types.NamespacedName]*corev1.Service{{Namespace: "edge", Name: "nginx"}: customService},
+			expectedMessages: []string{"no LoadBalancer or NodePort Service labeled app.kubernetes.io/name=ingress-nginx was found"},
+		},
+		{
+			name: "service-upstream annotation",
+			ingresses: []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "upstream",
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/service-upstream": "true"},
+				},
+			}},
+			expectedMessages: []string{
+				"nginx.ingress.kubernetes.io/service-upstream is not supported",
+				"ingress-nginx/ingress-nginx-controller has externalTrafficPolicy Local",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			gatewayResources := i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "default", Name: "nginx"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}},
+				},
+			}
+
+			testServices := services
+			if tc.services != nil {
+				testServices = tc.services
+			}
+			notifyClientIPPreservation(tc.ingresses, testServices, tc.controllerService, gatewayResources)
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(gotNotifications) != len(tc.expectedMessages) {
+				t.Fatalf("Expected %d notifications, got %+v", len(tc.expectedMessages), gotNotifications)
+			}
+			for i, message := range tc.expectedMessages {
+				if !strings.Contains(gotNotifications[i].Message, message) {
+					t.Errorf("Expected notification %d to contain %q, got %q", i, message, gotNotifications[i].Message)
+				}
+			}
+		})
+	}
+}
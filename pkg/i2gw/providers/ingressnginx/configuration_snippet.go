@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// proxyPassRegexp matches the proxy_pass directives of an nginx snippet.
+var proxyPassRegexp = regexp.MustCompile(`\bproxy_pass\s+([^;]*);`)
+
+// snippetBackend is a cluster-local Service a proxy_pass directive points to.
+type snippetBackend struct {
+	types.NamespacedName
+	port int32
+}
+
+// configurationSnippetFeature overrides the HTTPRoute backends with the Service
+// targeted by a `proxy_pass` directive of the `nginx.ingress.kubernetes.io/configuration-snippet`
+// annotation.
+//
+// Only a single proxy_pass to a cluster-local Service, like `http://my-service`,
+// `http://my-service.my-namespace.svc:8080` or `http://my-service.my-namespace.svc.cluster.local`,
+// is supported. As the override is inferred from a snippet, a Warning notification is
+// always emitted. Any other proxy_pass form produces a Warning notification and the
+// backend declared in the Ingress is kept.
+func configurationSnippetFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			snippet := ingress.Annotations[nginxAnnotation(configurationSnippetKey)]
+			if snippet == "" || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			backend, err := parseProxyPass(snippet, ingress.Namespace)
+			if err != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("%v, the backend declared in the Ingress is kept in HTTPRoute %s/%s", err, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+			if backend == nil {
+				continue
+			}
+			overridden := overrideBackendRefs(&httpRoute, rule.IngressRule.HTTP.Paths, *backend, ingress.Namespace)
+			if len(overridden) == 0 {
+				continue
+			}
+			for _, i := range overridden {
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].backendRefs", i), &ingress, nginxAnnotation(configurationSnippetKey))
+			}
+			notify(notifications.WarningNotification, fmt.Sprintf("the backends of HTTPRoute %s/%s were overridden with Service %s port %d, as inferred from a proxy_pass in the %s annotation", httpRoute.Namespace, httpRoute.Name, backend.NamespacedName, backend.port, nginxAnnotation(configurationSnippetKey)), &ingress)
+			if backend.Namespace != ingress.Namespace {
+				common.AddServiceReferenceGrant(gatewayResources, common.HTTPRouteGVK.Kind, ingress.Namespace, backend.NamespacedName)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// overrideBackendRefs replaces the backendRefs generated from the paths with the
+// snippet backend. It returns the indexes of the rules whose backendRefs were
+// replaced.
+func overrideBackendRefs(httpRoute *gatewayv1.HTTPRoute, paths []networkingv1.HTTPIngressPath, backend snippetBackend, namespace string) []int {
+	var overridden []int
+	for _, path := range paths {
+		declared, err := common.ToBackendRef(path.Backend, field.NewPath("paths", "backend"))
+		if err != nil {
+			continue
+		}
+		for i, rule := range httpRoute.Spec.Rules {
+			if !ruleMatchesPath(rule, path.Path) {
+				continue
+			}
+			for j, backendRef := range rule.BackendRefs {
+				if backendRef.Name != declared.Name || backendRef.Namespace != nil || !equalPorts(backendRef.Port, declared.Port) {
+					continue
+				}
+				ref := &httpRoute.Spec.Rules[i].BackendRefs[j]
+				ref.Name = gatewayv1.ObjectName(backend.Name)
+				ref.Port = common.PtrTo(gatewayv1.PortNumber(backend.port))
+				if backend.Namespace != namespace {
+					ref.Namespace = common.PtrTo(gatewayv1.Namespace(backend.Namespace))
+				}
+				if !slices.Contains(overridden, i) {
+					overridden = append(overridden, i)
+				}
+			}
+		}
+	}
+	return overridden
+}
+
+func ruleMatchesPath(rule gatewayv1.HTTPRouteRule, path string) bool {
+	for _, match := range rule.Matches {
+		if match.Path != nil && match.Path.Value != nil && *match.Path.Value == path {
+			return true
+		}
+	}
+	return false
+}
+
+func equalPorts(a, b *gatewayv1.PortNumber) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// parseProxyPass returns the cluster-local Service targeted by the proxy_pass
+// directive of the snippet, or nil if the snippet has no proxy_pass directive.
+func parseProxyPass(snippet, namespace string) (*snippetBackend, error) {
+	directives := proxyPassRegexp.FindAllStringSubmatch(snippet, -1)
+	if len(directives) == 0 {
+		return nil, nil
+	}
+	if len(directives) > 1 {
+		return nil, fmt.Errorf("multiple proxy_pass directives are not supported")
+	}
+
+	target := strings.TrimSpace(directives[0][1])
+	if strings.Contains(target, "$") {
+		return nil, fmt.Errorf("proxy_pass %q uses variables, which are not supported", target)
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "http" || u.Host == "" {
+		return nil, fmt.Errorf("proxy_pass %q is not supported, only http targets are", target)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.User != nil {
+		return nil, fmt.Errorf("proxy_pass %q is not supported, as it changes the request URI", target)
+	}
+
+	backend := &snippetBackend{port: 80}
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseInt(p, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("proxy_pass %q has an invalid port", target)
+		}
+		backend.port = int32(port)
+	}
+
+	service, ok := serviceFromHostname(u.Hostname(), namespace)
+	if !ok {
+		return nil, fmt.Errorf("proxy_pass %q does not target a cluster-local Service", target)
+	}
+	backend.NamespacedName = service
+	return backend, nil
+}
+
+// serviceFromHostname returns the Service of a cluster-local hostname, like
+// `my-service`, `my-service.my-namespace.svc` or `my-service.my-namespace.svc.cluster.local`.
+// A single-label hostname refers to a Service of the given namespace. Other
+// hostnames, like `example.com`, are external and name no Service.
+func serviceFromHostname(hostname, namespace string) (types.NamespacedName, bool) {
+	if strings.Contains(hostname, ".") {
+		return common.ClusterLocalService(hostname)
+	}
+	if len(validation.IsDNS1035Label(hostname)) > 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: hostname}, true
+}
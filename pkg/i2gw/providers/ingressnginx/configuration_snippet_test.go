@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_parseProxyPass(t *testing.T) {
+	testCases := []struct {
+		name            string
+		snippet         string
+		expectedBackend *snippetBackend
+		expectedError   bool
+	}{
+		{
+			name:    "no proxy_pass",
+			snippet: "more_set_headers \"X-Foo: bar\";",
+		},
+		{
+			name:    "proxy_pass_header is not a proxy_pass",
+			snippet: "proxy_pass_header Server;",
+		},
+		{
+			name:            "service in the same namespace",
+			snippet:         "proxy_pass http://other-service;",
+			expectedBackend: &snippetBackend{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other-service"}, port: 80},
+		},
+		{
+			name:            "service in another namespace with port",
+			snippet:         "proxy_set_header Host $host;\nproxy_pass http://other-service.other.svc:8080/;",
+			expectedBackend: &snippetBackend{NamespacedName: types.NamespacedName{Namespace: "other", Name: "other-service"}, port: 8080},
+		},
+		{
+			name:            "fully qualified service name",
+			snippet:         "proxy_pass http://other-service.other.svc.cluster.local;",
+			expectedBackend: &snippetBackend{NamespacedName: types.NamespacedName{Namespace: "other", Name: "other-service"}, port: 80},
+		},
+		{
+			name:          "external host",
+			snippet:       "proxy_pass http://example.com.foo.bar;",
+			expectedError: true,
+		},
+		{
+			name:          "two-label external host",
+			snippet:       "proxy_pass http://example.com;",
+			expectedError: true,
+		},
+		{
+			name:          "https target",
+			snippet:       "proxy_pass https://other-service;",
+			expectedError: true,
+		},
+		{
+			name:          "target with a URI",
+			snippet:       "proxy_pass http://other-service/api;",
+			expectedError: true,
+		},
+		{
+			name:          "target with variables",
+			snippet:       "proxy_pass http://$upstream;",
+			expectedError: true,
+		},
+		{
+			name:          "multiple proxy_pass",
+			snippet:       "proxy_pass http://a;proxy_pass http://b;",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, err := parseProxyPass(tc.snippet, "default")
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedBackend, backend, cmp.AllowUnexported(snippetBackend{})); diff != "" {
+				t.Errorf("Unexpected backend (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_configurationSnippetFeature(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		snippet                string
+		expectedBackendRef     gatewayv1.BackendObjectReference
+		expectedReferenceGrant bool
+	}{
+		{
+			name:    "override with a service in the same namespace",
+			snippet: "proxy_pass http://other-service:8080;",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "other-service",
+				Port: ptr.To(gatewayv1.PortNumber(8080)),
+			},
+		},
+		{
+			name:    "override with a service in another namespace",
+			snippet: "proxy_pass http://other-service.other.svc;",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name:      "other-service",
+				Namespace: ptr.To(gatewayv1.Namespace("other")),
+				Port:      ptr.To(gatewayv1.PortNumber(80)),
+			},
+			expectedReferenceGrant: true,
+		},
+		{
+			name:    "external host keeps the declared backend",
+			snippet: "proxy_pass http://example.com;",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "service",
+				Port: ptr.To(gatewayv1.PortNumber(80)),
+			},
+		},
+		{
+			name:    "unsupported proxy_pass keeps the declared backend",
+			snippet: "proxy_pass https://other-service;",
+			expectedBackendRef: gatewayv1.BackendObjectReference{
+				Name: "service",
+				Port: ptr.To(gatewayv1.PortNumber(80)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "snippet",
+					Namespace:   "default",
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/configuration-snippet": tc.snippet},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "service",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = configurationSnippetFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "snippet-example-com"}]
+			got := httpRoute.Spec.Rules[0].BackendRefs[0].BackendObjectReference
+			if diff := cmp.Diff(tc.expectedBackendRef, got); diff != "" {
+				t.Errorf("Unexpected backendRef (-want +got):\n%s", diff)
+			}
+
+			_, ok := gatewayResources.ReferenceGrants[types.NamespacedName{Namespace: "other", Name: "from-default-to-service-other-service"}]
+			if ok != tc.expectedReferenceGrant {
+				t.Errorf("Expected ReferenceGrant to exist: %v, got %v", tc.expectedReferenceGrant, ok)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification {
+				t.Errorf("Expected a single Warning notification, got %+v", gotNotifications)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// connectionProxyHeaderFeature converts the
+// `nginx.ingress.kubernetes.io/connection-proxy-header` annotation to a
+// RequestHeaderModifier filter setting the Connection header of the requests
+// sent to the backends, on the HTTPRoute rules generated from the Ingress paths.
+// The filter is merged with the other header modifiers of the rules by
+// common.AssembleHTTPRouteFilters, which reports the conflicting Connection
+// values.
+//
+// Connection is a hop-by-hop header, which proxies usually handle themselves,
+// e.g. to manage the connection pools to the backends, so the Gateway
+// implementation may ignore or override it. A Warning notification is emitted
+// for every converted Ingress. The annotation is not converted for gRPC
+// backends, whose GRPCRoutes have no filter.
+func connectionProxyHeaderFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			value := strings.TrimSpace(ingress.Annotations[nginxAnnotation(connectionProxyHeaderKey)])
+			if value == "" || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			if isGRPCBackend(ingress) {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %s annotation is not converted for gRPC backends", nginxAnnotation(connectionProxyHeaderKey)), &ingress)
+				continue
+			}
+			converted := false
+			for i := range httpRoute.Spec.Rules {
+				if !ruleMatchesAnyPath(httpRoute.Spec.Rules[i], rule.IngressRule.HTTP.Paths) {
+					continue
+				}
+				httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, gatewayv1.HTTPRouteFilter{
+					Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set: []gatewayv1.HTTPHeader{{Name: "Connection", Value: value}},
+					},
+				})
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, nginxAnnotation(connectionProxyHeaderKey))
+				converted = true
+			}
+			if converted {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s: %s is converted to a RequestHeaderModifier setting the Connection header in HTTPRoute %s/%s, but Connection is a hop-by-hop header, which the Gateway implementation may ignore or override", nginxAnnotation(connectionProxyHeaderKey), value, httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_connectionProxyHeaderFeature(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/connection-proxy-header": "keep-alive",
+			"nginx.ingress.kubernetes.io/x-forwarded-prefix":      "/foo",
+		}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/foo",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: "foo",
+									Port: networkingv1.ServiceBackendPort{Number: 80},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = rewriteFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	if errs = connectionProxyHeaderFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+	// The RequestHeaderModifier filters setting the Connection and
+	// X-Forwarded-Prefix headers are merged, as a rule can only have one.
+	if notifs := common.AssembleHTTPRouteFilters(&gatewayResources); len(notifs) != 0 {
+		t.Fatalf("Expected no filter conflicts, got %+v", notifs)
+	}
+
+	expectedFilters := []gatewayv1.HTTPRouteFilter{{
+		Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{
+			{Name: "X-Forwarded-Prefix", Value: "/foo"},
+			{Name: "Connection", Value: "keep-alive"},
+		}},
+	}}
+	rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+	if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+		t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications[Name]
+	expectedMessage := "nginx.ingress.kubernetes.io/connection-proxy-header: keep-alive is converted to a RequestHeaderModifier setting the Connection header in HTTPRoute default/foo-foo-com, but Connection is a hop-by-hop header, which the Gateway implementation may ignore or override"
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification || gotNotifications[0].Message != expectedMessage {
+		t.Errorf("Expected a single Warning notification %q, got %+v", expectedMessage, gotNotifications)
+	}
+}
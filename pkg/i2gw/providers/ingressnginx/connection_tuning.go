@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// connectionTuningAnnotationKeys are the upstream connection tuning annotations,
+// in the order they are reported. The buffering switches are reported by
+// streamingFeature.
+var connectionTuningAnnotationKeys = []string{
+	upstreamKeepaliveConnectionsKey,
+	upstreamKeepaliveTimeoutKey,
+	upstreamKeepaliveRequestsKey,
+	proxyHTTPVersionKey,
+	proxyBufferSizeKey,
+	proxyBuffersNumberKey,
+	proxyMaxTempFileSizeKey,
+}
+
+// connectionTuningFeature reports the backend connection tuning annotations of
+// every Ingress.
+//
+// Gateway API core has no equivalent to the keepalive, HTTP version and buffer
+// size settings of the connections to the backends. As they are usually set for
+// performance-sensitive workloads, a single Warning notification is emitted per
+// Ingress with their original values, instead of silently dropping them.
+func connectionTuningFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		var settings []string
+		for _, key := range connectionTuningAnnotationKeys {
+			value, ok := ingress.Annotations[nginxAnnotation(key)]
+			if !ok {
+				continue
+			}
+			settings = append(settings, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		ingress := ingress
+		notify(notifications.WarningNotification, fmt.Sprintf("the backend connection tuning settings are not converted, as Gateway API has no equivalent: configure them with your Gateway implementation, e.g. with a BackendTrafficPolicy for Envoy Gateway. Backend connection tuning settings:\n%s", strings.Join(settings, "\n")), &ingress)
+	}
+	return nil
+}
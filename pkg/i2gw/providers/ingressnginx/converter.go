@@ -18,21 +18,63 @@ package ingressnginx
 
 import (
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // converter implements the ToGatewayAPI function of i2gw.ResourceConverter interface.
 type converter struct {
 	featureParsers []i2gw.FeatureParser
+
+	// controllerService is the Service fronting the ingress-nginx controller, if
+	// set with the controller-service provider-specific flag.
+	controllerService types.NamespacedName
+
+	// progress is called with the number of Ingresses converted.
+	progress i2gw.ProgressFunc
 }
 
 // newConverter returns an ingress-nginx converter instance.
-func newConverter() *converter {
+func newConverter(conf *i2gw.ProviderConf) *converter {
+	var controllerService types.NamespacedName
+	if ps := conf.ProviderSpecificFlags[Name]; ps != nil && ps[ControllerServiceFlag] != "" {
+		controllerService = toNamespacedName(ps[ControllerServiceFlag])
+		if controllerService.Namespace == "" {
+			controllerService.Namespace = controllerNamespace
+		}
+	}
 	return &converter{
 		featureParsers: []i2gw.FeatureParser{
 			canaryFeature,
+			sessionAffinityFeature,
+			configurationSnippetFeature,
+			redirectFeature,
+			sslRedirectFeature,
+			mirrorFeature,
+			common.TrailingSlashFeature(Name, "ingress-nginx"),
+			useRegexFeature,
+			rewriteFeature,
+			connectionProxyHeaderFeature,
+			hstsFeature,
+			proxyProtocolFeature,
+			regexHostFeature,
+			serverSnippetLocationFeature,
+			grpcFeature,
+			backendTLSFeature,
+			wafFeature,
+			accessControlFeature,
+			clientCertificateAuthFeature,
+			observabilityFeature,
+			connectionTuningFeature,
+			streamingFeature,
+			websocketFeature,
+			trafficPolicyFeature,
+			caseInsensitivePathFeature,
 		},
+		controllerService: controllerService,
+		progress:          conf.Progress,
 	}
 }
 
@@ -40,10 +82,14 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 
 	// TODO(liorliberman) temporary until we decide to change ToGateway and featureParsers to get a map of [types.NamespacedName]*networkingv1.Ingress instead of a list
 	ingressList := storage.Ingresses.List()
+	for _, notification := range common.ResolveNamedServicePorts(ingressList, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+	ingressList = resolveAnnotationConflicts(ingressList)
 
 	// Convert plain ingress resources to gateway resources, ignoring all
 	// provider-specific features.
-	gatewayResources, errs := common.ToGateway(ingressList, i2gw.ProviderImplementationSpecificOptions{})
+	gatewayResources, errs := common.ToGateway(ingressList, i2gw.ProviderImplementationSpecificOptions{Progress: c.progress})
 	if len(errs) > 0 {
 		return i2gw.GatewayResources{}, errs
 	}
@@ -54,6 +100,27 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		// Append the parsing errors to the error list.
 		errs = append(errs, parseErrs...)
 	}
+	convertDefaultBackends(ingressList, storage.Services, &gatewayResources)
+
+	for _, notification := range common.ResolveCrossNamespaceBackends(&gatewayResources, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.ResolveMixedIngressControllers(&gatewayResources, ingressList, Name) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.CopyCertManagerAnnotations(&gatewayResources, ingressList) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	notifyClientIPPreservation(ingressList, storage.Services, c.controllerService, gatewayResources)
+	convertLoadBalancerSettings(storage.Services, c.controllerService, &gatewayResources)
+	common.RecordUnconvertedAnnotations(&gatewayResources, ingressList, annotationPrefix+"/", supportedAnnotations())
 
 	return gatewayResources, errs
 }
@@ -169,6 +169,113 @@ func Test_ToGateway(t *testing.T) {
 			},
 			expectedErrors: field.ErrorList{},
 		},
+		{
+			name: "fanout",
+			ingresses: OrderedIngressMap{
+				ingressNames: []types.NamespacedName{{Namespace: "default", Name: "fanout"}},
+				ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+					{Namespace: "default", Name: "fanout"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "fanout", Namespace: "default"},
+						Spec: networkingv1.IngressSpec{
+							IngressClassName: ptrTo("ingress-nginx"),
+							Rules: []networkingv1.IngressRule{{
+								Host: "fanout.mydomain.com",
+								IngressRuleValue: networkingv1.IngressRuleValue{
+									HTTP: &networkingv1.HTTPIngressRuleValue{
+										Paths: []networkingv1.HTTPIngressPath{
+											{
+												Path:     "/foo",
+												PathType: &iPrefix,
+												Backend: networkingv1.IngressBackend{
+													Service: &networkingv1.IngressServiceBackend{
+														Name: "service-foo",
+														Port: networkingv1.ServiceBackendPort{Number: 80},
+													},
+												},
+											},
+											{
+												Path:     "/bar",
+												PathType: &iPrefix,
+												Backend: networkingv1.IngressBackend{
+													Service: &networkingv1.IngressServiceBackend{
+														Name: "service-bar",
+														Port: networkingv1.ServiceBackendPort{Number: 80},
+													},
+												},
+											},
+										},
+									},
+								},
+							}},
+						},
+					},
+				},
+			},
+			expectedGatewayResources: i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					{Namespace: "default", Name: "ingress-nginx"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx", Namespace: "default"},
+						Spec: gatewayv1.GatewaySpec{
+							GatewayClassName: "ingress-nginx",
+							Listeners: []gatewayv1.Listener{{
+								Name:     "fanout-mydomain-com-http",
+								Port:     80,
+								Protocol: gatewayv1.HTTPProtocolType,
+								Hostname: ptrTo(gatewayv1.Hostname("fanout.mydomain.com")),
+							}},
+						},
+					},
+				},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					{Namespace: "default", Name: "fanout-fanout-mydomain-com"}: {
+						ObjectMeta: metav1.ObjectMeta{Name: "fanout-fanout-mydomain-com", Namespace: "default"},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{
+								ParentRefs: []gatewayv1.ParentReference{{
+									Name: "ingress-nginx",
+								}},
+							},
+							Hostnames: []gatewayv1.Hostname{"fanout.mydomain.com"},
+							Rules: []gatewayv1.HTTPRouteRule{
+								{
+									Matches: []gatewayv1.HTTPRouteMatch{{
+										Path: &gatewayv1.HTTPPathMatch{
+											Type:  &gPathPrefix,
+											Value: ptrTo("/foo"),
+										},
+									}},
+									BackendRefs: []gatewayv1.HTTPBackendRef{{
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{
+												Name: "service-foo",
+												Port: ptrTo(gatewayv1.PortNumber(80)),
+											},
+										},
+									}},
+								},
+								{
+									Matches: []gatewayv1.HTTPRouteMatch{{
+										Path: &gatewayv1.HTTPPathMatch{
+											Type:  &gPathPrefix,
+											Value: ptrTo("/bar"),
+										},
+									}},
+									BackendRefs: []gatewayv1.HTTPBackendRef{{
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{
+												Name: "service-bar",
+												Port: ptrTo(gatewayv1.PortNumber(80)),
+											},
+										},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: field.ErrorList{},
+		},
 		{
 			name: "ImplementationSpecific HTTPRouteMatching",
 			ingresses: OrderedIngressMap{
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// convertDefaultBackends converts the `spec.defaultBackend` of the Ingresses with
+// rules, and the `nginx.ingress.kubernetes.io/default-backend` annotation, naming
+// a Service of the namespace of the Ingress.
+//
+// The HTTPRoutes of the rules of an Ingress with a `spec.defaultBackend` get a
+// trailing rule matching the prefix `/` and sending the requests not matching
+// the paths of the Ingress to the default backend, which takes precedence over
+// the annotation. The requests of the other hosts are still routed by the
+// `<name>-default-backend` HTTPRoute.
+//
+// With `nginx.ingress.kubernetes.io/custom-http-errors`, the default backend
+// serves the error responses of the backends of the Ingress, which Gateway API
+// cannot intercept, so a Warning notification is emitted and nothing is
+// generated. Otherwise, the default backend is meant as a catch-all: a trailing
+// rule matching the prefix `/` and sending the requests to the first port of the
+// Service, as ingress-nginx does, is added to the HTTPRoutes of the Ingress, and
+// an Info notification is emitted, as ingress-nginx also uses the default backend
+// when the Services of the Ingress have no endpoints. The HTTPRoutes already
+// matching the prefix `/` are left unchanged.
+func convertDefaultBackends(ingresses []networkingv1.Ingress, services map[types.NamespacedName]*corev1.Service, gatewayResources *i2gw.GatewayResources) {
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		customHTTPErrors := ingress.Annotations[nginxAnnotation(customHTTPErrorsKey)]
+		if customHTTPErrors == "" {
+			continue
+		}
+		backend := "the default backend of the controller"
+		if name := ingress.Annotations[nginxAnnotation(defaultBackendKey)]; name != "" {
+			backend = fmt.Sprintf("Service %s/%s", ingress.Namespace, name)
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %q replaces the error responses of the backends of Ingress %s/%s with the ones of %s, which Gateway API cannot intercept, no route was generated for it. Reconfigure the error pages in the backends or with the implementation of the Gateway",
+			nginxAnnotation(customHTTPErrorsKey), customHTTPErrors, ingress.Namespace, ingress.Name, backend), ingress)
+	}
+
+	ruleGroups := common.GetRuleGroups(ingresses)
+	rgKeys := make([]string, 0, len(ruleGroups))
+	for rgKey := range ruleGroups {
+		rgKeys = append(rgKeys, rgKey)
+	}
+	sort.Strings(rgKeys)
+
+	for _, rgKey := range rgKeys {
+		rg := ruleGroups[rgKey]
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		var converted *networkingv1.Ingress
+		var convertedName string
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			from, name := specDefaultBackendField, ""
+			if ingress.Spec.DefaultBackend != nil {
+				name = defaultBackendName(*ingress.Spec.DefaultBackend)
+			} else {
+				from, name = nginxAnnotation(defaultBackendKey), ingress.Annotations[nginxAnnotation(defaultBackendKey)]
+				if name == "" || ingress.Annotations[nginxAnnotation(customHTTPErrorsKey)] != "" {
+					continue
+				}
+			}
+			if converted != nil {
+				if name != convertedName {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s %q of Ingress %s/%s is ignored, as HTTPRoute %s/%s already sends its other requests to the default backend %s of Ingress %s/%s",
+						from, name, ingress.Namespace, ingress.Name, httpRoute.Namespace, httpRoute.Name, convertedName, converted.Namespace, converted.Name), &ingress)
+				}
+				continue
+			}
+			if from != specDefaultBackendField && strings.Contains(name, "/") {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%s %q is not a Service name, the default backend must be a Service of namespace %s", from, name, ingress.Namespace), &ingress)
+				continue
+			}
+			if hasCatchAllRule(httpRoute) {
+				notify(notifications.InfoNotification, fmt.Sprintf("%s %q was not converted, as HTTPRoute %s/%s already matches all the paths", from, name, httpRoute.Namespace, httpRoute.Name), &ingress)
+				converted, convertedName = &ingress, name
+				continue
+			}
+
+			var backendRef gatewayv1.BackendRef
+			if from == specDefaultBackendField {
+				backendRef, ok = specDefaultBackendRef(ingress, services)
+				if !ok {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s port %q of Service %s/%s was not found, no catch-all rule was generated in HTTPRoute %s/%s", from, ingress.Spec.DefaultBackend.Service.Port.Name, ingress.Namespace, name, httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+			} else {
+				service, ok := services[types.NamespacedName{Namespace: ingress.Namespace, Name: name}]
+				if !ok || len(service.Spec.Ports) == 0 {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s Service %s/%s was not found or has no port, no catch-all rule was generated in HTTPRoute %s/%s", from, ingress.Namespace, name, httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+				backendRef = gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(name),
+					Port: common.PtrTo(gatewayv1.PortNumber(service.Spec.Ports[0].Port)),
+				}}
+			}
+
+			// The catch-all rule is appended after the rules of the paths, once
+			// all the features are converted, so that the paths keep matching
+			// first and the rule indices of the provenance stay valid.
+			httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{Type: common.PtrTo(gatewayv1.PathMatchPathPrefix), Value: common.PtrTo("/")},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+			})
+			common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d]", len(httpRoute.Spec.Rules)-1), &ingress, from)
+			if from == specDefaultBackendField {
+				notify(notifications.InfoNotification, fmt.Sprintf("%s %q was converted to a catch-all rule of HTTPRoute %s/%s, after the rules of the paths of the Ingress, for the requests not matching them",
+					from, name, httpRoute.Namespace, httpRoute.Name), &ingress)
+			} else {
+				notify(notifications.InfoNotification, fmt.Sprintf("%s %q was converted to a catch-all rule of HTTPRoute %s/%s, for the requests not matching the paths of the Ingress. ingress-nginx also used it when the Services of the Ingress had no endpoints, which Gateway API has no equivalent for",
+					from, name, httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+			converted, convertedName = &ingress, name
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+}
+
+// specDefaultBackendField is the field of the default backend of an Ingress.
+const specDefaultBackendField = "spec.defaultBackend"
+
+// defaultBackendName returns the name of the Service or resource of the
+// default backend.
+func defaultBackendName(backend networkingv1.IngressBackend) string {
+	if backend.Service != nil {
+		return backend.Service.Name
+	}
+	if backend.Resource != nil {
+		return backend.Resource.Name
+	}
+	return ""
+}
+
+// specDefaultBackendRef returns the backendRef of the default backend of the
+// Ingress, resolving a named Service port, or false if the port is not found.
+func specDefaultBackendRef(ingress networkingv1.Ingress, services map[types.NamespacedName]*corev1.Service) (gatewayv1.BackendRef, bool) {
+	backend := ingress.Spec.DefaultBackend
+	if backend.Resource != nil {
+		return gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+			Group: (*gatewayv1.Group)(backend.Resource.APIGroup),
+			Kind:  common.PtrTo(gatewayv1.Kind(backend.Resource.Kind)),
+			Name:  gatewayv1.ObjectName(backend.Resource.Name),
+		}}, true
+	}
+	port := backend.Service.Port.Number
+	if backend.Service.Port.Name != "" {
+		service, ok := services[types.NamespacedName{Namespace: ingress.Namespace, Name: backend.Service.Name}]
+		if !ok {
+			return gatewayv1.BackendRef{}, false
+		}
+		i := slices.IndexFunc(service.Spec.Ports, func(servicePort corev1.ServicePort) bool { return servicePort.Name == backend.Service.Port.Name })
+		if i < 0 {
+			return gatewayv1.BackendRef{}, false
+		}
+		port = service.Spec.Ports[i].Port
+	}
+	return gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+		Name: gatewayv1.ObjectName(backend.Service.Name),
+		Port: common.PtrTo(gatewayv1.PortNumber(port)),
+	}}, true
+}
+
+// hasCatchAllRule returns whether a rule of the HTTPRoute matches all the
+// requests, with no other condition than the prefix `/`.
+func hasCatchAllRule(httpRoute gatewayv1.HTTPRoute) bool {
+	for _, rule := range httpRoute.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			return true
+		}
+		for _, match := range rule.Matches {
+			if len(match.Headers) > 0 || len(match.QueryParams) > 0 || match.Method != nil {
+				continue
+			}
+			if match.Path == nil || (match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchPathPrefix && match.Path.Value != nil && *match.Path.Value == "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertDefaultBackends(t *testing.T) {
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "fallback"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fallback"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}, {Name: "metrics", Port: 9090}}},
+		},
+	}
+	catchAllRule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+		}},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "fallback", Port: ptr.To(gatewayv1.PortNumber(8080))}},
+		}},
+	}
+
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		path                 string
+		expectedCatchAll     bool
+		expectedNotification notifications.MessageType
+	}{
+		{
+			name: "no default backend",
+			path: "/foo",
+		},
+		{
+			name:                 "catch-all default backend",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/default-backend": "fallback"},
+			path:                 "/foo",
+			expectedCatchAll:     true,
+			expectedNotification: notifications.InfoNotification,
+		},
+		{
+			name:                 "catch-all default backend of a route matching all the paths",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/default-backend": "fallback"},
+			path:                 "/",
+			expectedNotification: notifications.InfoNotification,
+		},
+		{
+			name: "default backend of the error responses",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/default-backend":    "fallback",
+				"nginx.ingress.kubernetes.io/custom-http-errors": "404,503",
+			},
+			path:                 "/foo",
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:                 "error responses of the default backend of the controller",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/custom-http-errors": "503"},
+			path:                 "/foo",
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:                 "missing default backend Service",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/default-backend": "missing"},
+			path:                 "/foo",
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:                 "default backend of another namespace",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/default-backend": "other/fallback"},
+			path:                 "/foo",
+			expectedNotification: notifications.ErrorNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     tc.path,
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			convertDefaultBackends(ingresses, services, &gatewayResources)
+
+			rules := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules
+			expectedRules := 1
+			if tc.expectedCatchAll {
+				expectedRules = 2
+			}
+			if len(rules) != expectedRules {
+				t.Fatalf("Expected %d rules, got %+v", expectedRules, rules)
+			}
+			if tc.expectedCatchAll {
+				if diff := cmp.Diff(catchAllRule, rules[1]); diff != "" {
+					t.Errorf("Unexpected catch-all rule (-want +got):\n%s", diff)
+				}
+			}
+
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedNotification == "" {
+				if len(notifs) > 0 {
+					t.Errorf("Expected no notification, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a single %s notification, got %+v", tc.expectedNotification, notifs)
+			}
+		})
+	}
+}
+
+func Test_convertDefaultBackends_specDefaultBackend(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "fallback"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fallback"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+		},
+	}
+	path := func(value, service string) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     value,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: service, Port: networkingv1.ServiceBackendPort{Number: 80}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Annotations: map[string]string{"nginx.ingress.kubernetes.io/default-backend": "ignored"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "fallback", Port: networkingv1.ServiceBackendPort{Number: 8080}},
+			},
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{path("/api", "api"), path("/static", "static")},
+					},
+				},
+			}},
+		},
+	}}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	convertDefaultBackends(ingresses, services, &gatewayResources)
+
+	rule := func(pathType gatewayv1.PathMatchType, value, service string, port int32) gatewayv1.HTTPRouteRule {
+		return gatewayv1.HTTPRouteRule{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(value)},
+			}},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{
+				BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service), Port: ptr.To(gatewayv1.PortNumber(port))}},
+			}},
+		}
+	}
+	expectedRules := []gatewayv1.HTTPRouteRule{
+		rule(gatewayv1.PathMatchPathPrefix, "/api", "api", 80),
+		rule(gatewayv1.PathMatchPathPrefix, "/static", "static", 80),
+		rule(gatewayv1.PathMatchPathPrefix, "/", "fallback", 8080),
+	}
+	rules := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules
+	if diff := cmp.Diff(expectedRules, rules); diff != "" {
+		t.Errorf("Unexpected rules (-want +got):\n%s", diff)
+	}
+
+	notifs := notifications.NotificationAggr.Notifications[Name]
+	if len(notifs) != 1 || notifs[0].Type != notifications.InfoNotification {
+		t.Errorf("Expected a single Info notification, got %+v", notifs)
+	}
+}
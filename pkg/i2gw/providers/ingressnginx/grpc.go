@@ -0,0 +1,368 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+var (
+	// grpcServiceRegexp and grpcMethodRegexp follow the validation of the
+	// GRPCRoute Exact method matches.
+	grpcServiceRegexp = regexp.MustCompile(`^(?i)\.?[a-z_][a-z_0-9]*(\.[a-z_][a-z_0-9]*)*$`)
+	grpcMethodRegexp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*$`)
+)
+
+// grpcFeature converts the HTTPRoute rules generated from Ingresses annotated with
+// `nginx.ingress.kubernetes.io/backend-protocol: GRPC` (or GRPCS) into GRPCRoute rules.
+// The legacy `nginx.ingress.kubernetes.io/grpc-backend: "true"` annotation, which
+// preceded backend-protocol, is recognized as backend-protocol GRPC, and an Info
+// notification is emitted for it.
+//
+// gRPC requests are sent to the path `/<package>.<service>/<method>`, hence every
+// path is parsed into a GRPCRoute method match:
+//   - `/my.package.MyService/MyMethod` matches the method MyMethod of the service.
+//   - `/my.package.MyService` matches all the methods of the service.
+//   - `/` matches all the gRPC requests, so the rule gets no matches at all.
+//
+// The header matches of the HTTPRoute rules are kept alongside the method
+// matches. A gRPC canary Ingress with `nginx.ingress.kubernetes.io/canary-by-header`
+// gets an additional rule for each of its paths, combining the method match with
+// the header match of `canary-by-header-value` (Exact, `always` by default) or
+// `canary-by-header-pattern` (RegularExpression), routed to the canary backend
+// only.
+//
+// Rules whose path cannot be parsed as a gRPC method are kept in the HTTPRoute,
+// and a Warning notification is emitted for them.
+func grpcFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	if gatewayResources.GRPCRoutes == nil {
+		gatewayResources.GRPCRoutes = map[types.NamespacedName]gatewayv1alpha2.GRPCRoute{}
+	}
+	for i := range ingresses {
+		if isLegacyGRPCBackend(ingresses[i]) {
+			notify(notifications.InfoNotification, fmt.Sprintf("the deprecated %s annotation was recognized as %s: GRPC", nginxAnnotation(grpcBackendKey), nginxAnnotation(backendProtocolKey)), &ingresses[i])
+		}
+	}
+
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		grpcPaths := map[string]*networkingv1.Ingress{}
+		canaryPaths := map[string][]ingressPath{}
+		for _, rule := range rg.Rules {
+			if !isGRPCBackend(rule.Ingress) || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			ingress := rule.Ingress
+			canary, errs := parseCanaryAnnotations(ingress)
+			headerCanary := len(errs) == 0 && canary.enable && canary.headerKey != ""
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				paths := []string{path.Path}
+				if normalizedPath, changed := common.NormalizePathPrefix(path.Path); changed {
+					paths = append(paths, normalizedPath)
+				}
+				for _, p := range paths {
+					grpcPaths[p] = &ingress
+					if headerCanary {
+						canaryPaths[p] = append(canaryPaths[p], ingressPath{ingress: ingress, ruleType: "http", path: path, extra: &extra{canary: &canary}})
+					}
+				}
+			}
+		}
+		if len(grpcPaths) == 0 {
+			continue
+		}
+
+		httpRouteRef := provenance.ObjectRef{Kind: common.HTTPRouteGVK.Kind, NamespacedName: key}
+		grpcRouteRef := provenance.ObjectRef{Kind: common.GRPCRouteGVK.Kind, NamespacedName: key}
+		var grpcRules []gatewayv1alpha2.GRPCRouteRule
+		var httpRules []gatewayv1.HTTPRouteRule
+		for i, httpRule := range httpRoute.Spec.Rules {
+			rulePath := fmt.Sprintf("spec.rules[%d]", i)
+			ingress, isGRPC := grpcRuleIngress(httpRule, grpcPaths)
+			if !isGRPC {
+				provenance.ProvenanceAggr.Move(httpRouteRef, rulePath, httpRouteRef, fmt.Sprintf("spec.rules[%d]", len(httpRules)))
+				httpRules = append(httpRules, httpRule)
+				continue
+			}
+			grpcRule, unsupportedFilters, err := toGRPCRouteRule(httpRule)
+			if err != nil {
+				notify(notifications.WarningNotification, fmt.Sprintf("%v, the rule is kept in HTTPRoute %s/%s", err, httpRoute.Namespace, httpRoute.Name), ingress)
+				provenance.ProvenanceAggr.Move(httpRouteRef, rulePath, httpRouteRef, fmt.Sprintf("spec.rules[%d]", len(httpRules)))
+				httpRules = append(httpRules, httpRule)
+				continue
+			}
+			if len(unsupportedFilters) > 0 {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %s filters of the gRPC rule cannot be expressed in a GRPCRoute and are not converted in GRPCRoute %s/%s", strings.Join(unsupportedFilters, ", "), httpRoute.Namespace, httpRoute.Name), ingress)
+			}
+			provenance.ProvenanceAggr.Move(httpRouteRef, rulePath, grpcRouteRef, fmt.Sprintf("spec.rules[%d]", len(grpcRules)))
+			common.RecordIngressProvenance(common.GRPCRouteGVK.Kind, key, "", ingress, grpcBackendAnnotation(*ingress))
+			grpcRules = append(grpcRules, grpcRule)
+			for _, canaryPath := range ruleCanaryPaths(httpRule, canaryPaths) {
+				canaryRule, ok := grpcCanaryByHeaderRule(grpcRule, canaryPath)
+				if !ok {
+					continue
+				}
+				canaryIngress := canaryPath.ingress
+				common.RecordIngressProvenance(common.GRPCRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d]", len(grpcRules)), &canaryIngress, "nginx.ingress.kubernetes.io/canary-by-header")
+				grpcRules = append(grpcRules, canaryRule)
+			}
+		}
+		if len(grpcRules) == 0 {
+			continue
+		}
+
+		grpcRoute := gatewayv1alpha2.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      httpRoute.Name,
+				Namespace: httpRoute.Namespace,
+			},
+			Spec: gatewayv1alpha2.GRPCRouteSpec{
+				CommonRouteSpec: httpRoute.Spec.CommonRouteSpec,
+				Hostnames:       httpRoute.Spec.Hostnames,
+				Rules:           grpcRules,
+			},
+		}
+		grpcRoute.SetGroupVersionKind(common.GRPCRouteGVK)
+		gatewayResources.GRPCRoutes[key] = grpcRoute
+		for _, source := range provenance.ProvenanceAggr.ObjectSources(httpRouteRef)["spec.hostnames"] {
+			provenance.ProvenanceAggr.Record(grpcRouteRef, "spec.hostnames", source)
+		}
+
+		if len(httpRules) == 0 {
+			delete(gatewayResources.HTTPRoutes, key)
+			provenance.ProvenanceAggr.Delete(httpRouteRef)
+			continue
+		}
+		httpRoute.Spec.Rules = httpRules
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+
+	return nil
+}
+
+// isGRPCBackend returns whether the Ingress backends are served over gRPC.
+func isGRPCBackend(ingress networkingv1.Ingress) bool {
+	protocol := strings.ToUpper(ingress.Annotations[nginxAnnotation(backendProtocolKey)])
+	return protocol == "GRPC" || protocol == "GRPCS" || isLegacyGRPCBackend(ingress)
+}
+
+// isLegacyGRPCBackend returns whether the Ingress backends are declared as gRPC
+// by the legacy grpc-backend annotation, which backend-protocol overrides.
+func isLegacyGRPCBackend(ingress networkingv1.Ingress) bool {
+	if _, ok := ingress.Annotations[nginxAnnotation(backendProtocolKey)]; ok {
+		return false
+	}
+	grpcBackend, err := strconv.ParseBool(ingress.Annotations[nginxAnnotation(grpcBackendKey)])
+	return err == nil && grpcBackend
+}
+
+// grpcBackendAnnotation returns the annotation the gRPC Ingress was converted from.
+func grpcBackendAnnotation(ingress networkingv1.Ingress) string {
+	if isLegacyGRPCBackend(ingress) {
+		return nginxAnnotation(grpcBackendKey)
+	}
+	return nginxAnnotation(backendProtocolKey)
+}
+
+// grpcRuleIngress returns the gRPC Ingress the HTTPRoute rule was generated from, if any.
+func grpcRuleIngress(httpRule gatewayv1.HTTPRouteRule, grpcPaths map[string]*networkingv1.Ingress) (*networkingv1.Ingress, bool) {
+	for _, match := range httpRule.Matches {
+		if match.Path == nil || match.Path.Value == nil {
+			continue
+		}
+		if ingress, ok := grpcPaths[*match.Path.Value]; ok {
+			return ingress, true
+		}
+	}
+	return nil, false
+}
+
+// ruleCanaryPaths returns the paths of the canary-by-header Ingresses the
+// HTTPRoute rule was generated from.
+func ruleCanaryPaths(httpRule gatewayv1.HTTPRouteRule, canaryPaths map[string][]ingressPath) []ingressPath {
+	var paths []ingressPath
+	seen := map[string]bool{}
+	for _, match := range httpRule.Matches {
+		if match.Path == nil || match.Path.Value == nil {
+			continue
+		}
+		for _, path := range canaryPaths[*match.Path.Value] {
+			key := fmt.Sprintf("%s/%s/%s", path.ingress.Namespace, path.ingress.Name, path.path.Path)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// grpcCanaryByHeaderRule returns a copy of the GRPCRoute rule matching the
+// canary header of the path in addition to its method matches, routed to the
+// backend of the canary path only. It returns false if the canary backend
+// cannot be converted, which canaryFeature already reports.
+func grpcCanaryByHeaderRule(grpcRule gatewayv1alpha2.GRPCRouteRule, path ingressPath) (gatewayv1alpha2.GRPCRouteRule, bool) {
+	backendRef, err := common.ToBackendRef(path.path.Backend, field.NewPath("paths", "backends"))
+	if err != nil {
+		return gatewayv1alpha2.GRPCRouteRule{}, false
+	}
+
+	canary := path.extra.canary
+	headerMatch := gatewayv1alpha2.GRPCHeaderMatch{
+		Type:  common.PtrTo(gatewayv1.HeaderMatchExact),
+		Name:  gatewayv1alpha2.GRPCHeaderName(canary.headerKey),
+		Value: canary.headerValue,
+	}
+	if canary.headerRegexMatch {
+		headerMatch.Type = common.PtrTo(gatewayv1.HeaderMatchRegularExpression)
+	}
+
+	canaryRule := gatewayv1alpha2.GRPCRouteRule{
+		BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{BackendRef: *backendRef}},
+	}
+	if len(grpcRule.Matches) == 0 {
+		canaryRule.Matches = []gatewayv1alpha2.GRPCRouteMatch{{Headers: []gatewayv1alpha2.GRPCHeaderMatch{headerMatch}}}
+		return canaryRule, true
+	}
+	for _, match := range grpcRule.Matches {
+		headers := append(append([]gatewayv1alpha2.GRPCHeaderMatch{}, match.Headers...), headerMatch)
+		canaryRule.Matches = append(canaryRule.Matches, gatewayv1alpha2.GRPCRouteMatch{Method: match.Method, Headers: headers})
+	}
+	return canaryRule, true
+}
+
+// toGRPCRouteRule converts an HTTPRoute rule into the equivalent GRPCRoute rule,
+// translating each path match into a method match combined with the header
+// matches of the HTTPRoute match. A match without path matches all the gRPC
+// requests. The filters supported by GRPCRoutes are converted, and the types of
+// the other filters, of the rule or of its backendRefs, are returned.
+func toGRPCRouteRule(httpRule gatewayv1.HTTPRouteRule) (gatewayv1alpha2.GRPCRouteRule, []string, error) {
+	var grpcRule gatewayv1alpha2.GRPCRouteRule
+	for _, match := range httpRule.Matches {
+		path := "/"
+		if match.Path != nil && match.Path.Value != nil {
+			path = *match.Path.Value
+		}
+		methodMatch, err := toGRPCMethodMatch(path)
+		if err != nil {
+			return gatewayv1alpha2.GRPCRouteRule{}, nil, err
+		}
+		if len(match.QueryParams) > 0 || match.Method != nil {
+			return gatewayv1alpha2.GRPCRouteRule{}, nil, fmt.Errorf("the query parameter and HTTP method matches of path %q cannot be expressed in a GRPCRoute", path)
+		}
+		var headers []gatewayv1alpha2.GRPCHeaderMatch
+		for _, header := range match.Headers {
+			headers = append(headers, gatewayv1alpha2.GRPCHeaderMatch{
+				Type:  header.Type,
+				Name:  gatewayv1alpha2.GRPCHeaderName(header.Name),
+				Value: header.Value,
+			})
+		}
+		// A nil method match without headers stands for all the gRPC
+		// requests, which is expressed by a rule without matches.
+		if methodMatch == nil && len(headers) == 0 {
+			grpcRule.Matches = nil
+			break
+		}
+		grpcRule.Matches = append(grpcRule.Matches, gatewayv1alpha2.GRPCRouteMatch{Method: methodMatch, Headers: headers})
+	}
+	var unsupported []string
+	grpcRule.Filters, unsupported = toGRPCRouteFilters(httpRule.Filters, unsupported)
+	for _, backendRef := range httpRule.BackendRefs {
+		grpcBackendRef := gatewayv1alpha2.GRPCBackendRef{BackendRef: backendRef.BackendRef}
+		grpcBackendRef.Filters, unsupported = toGRPCRouteFilters(backendRef.Filters, unsupported)
+		grpcRule.BackendRefs = append(grpcRule.BackendRefs, grpcBackendRef)
+	}
+	return grpcRule, unsupported, nil
+}
+
+// toGRPCRouteFilters converts the HTTPRoute filters supported by GRPCRoutes, the
+// header modifiers, the mirrors and the extension references, and appends the
+// types of the other filters, like the redirects and the rewrites, to
+// unsupported.
+func toGRPCRouteFilters(filters []gatewayv1.HTTPRouteFilter, unsupported []string) ([]gatewayv1alpha2.GRPCRouteFilter, []string) {
+	var grpcFilters []gatewayv1alpha2.GRPCRouteFilter
+	for _, filter := range filters {
+		switch filter.Type {
+		case gatewayv1.HTTPRouteFilterRequestHeaderModifier:
+			grpcFilters = append(grpcFilters, gatewayv1alpha2.GRPCRouteFilter{Type: gatewayv1alpha2.GRPCRouteFilterRequestHeaderModifier, RequestHeaderModifier: filter.RequestHeaderModifier})
+		case gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+			grpcFilters = append(grpcFilters, gatewayv1alpha2.GRPCRouteFilter{Type: gatewayv1alpha2.GRPCRouteFilterResponseHeaderModifier, ResponseHeaderModifier: filter.ResponseHeaderModifier})
+		case gatewayv1.HTTPRouteFilterRequestMirror:
+			grpcFilters = append(grpcFilters, gatewayv1alpha2.GRPCRouteFilter{Type: gatewayv1alpha2.GRPCRouteFilterRequestMirror, RequestMirror: filter.RequestMirror})
+		case gatewayv1.HTTPRouteFilterExtensionRef:
+			grpcFilters = append(grpcFilters, gatewayv1alpha2.GRPCRouteFilter{Type: gatewayv1alpha2.GRPCRouteFilterExtensionRef, ExtensionRef: filter.ExtensionRef})
+		default:
+			if !slices.Contains(unsupported, string(filter.Type)) {
+				unsupported = append(unsupported, string(filter.Type))
+			}
+		}
+	}
+	return grpcFilters, unsupported
+}
+
+// toGRPCMethodMatch parses a path in the form `/<service>/<method>` or `/<service>`
+// into a GRPCRoute Exact method match. The root path `/` matches all the gRPC
+// requests, so a nil match is returned.
+func toGRPCMethodMatch(path string) (*gatewayv1alpha2.GRPCMethodMatch, error) {
+	trimmedPath := strings.Trim(path, "/")
+	if trimmedPath == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(trimmedPath, "/")
+	if len(parts) > 2 || !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path %q cannot be parsed as a gRPC method", path)
+	}
+	if !grpcServiceRegexp.MatchString(parts[0]) {
+		return nil, fmt.Errorf("path %q does not contain a valid gRPC service name", path)
+	}
+
+	methodMatch := &gatewayv1alpha2.GRPCMethodMatch{
+		Type:    common.PtrTo(gatewayv1alpha2.GRPCMethodMatchExact),
+		Service: common.PtrTo(parts[0]),
+	}
+	if len(parts) == 2 {
+		if !grpcMethodRegexp.MatchString(parts[1]) {
+			return nil, fmt.Errorf("path %q does not contain a valid gRPC method name", path)
+		}
+		methodMatch.Method = common.PtrTo(parts[1])
+	}
+	return methodMatch, nil
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func Test_toGRPCMethodMatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		path          string
+		expectedMatch *gatewayv1alpha2.GRPCMethodMatch
+		expectedError bool
+	}{
+		{
+			name: "full method path",
+			path: "/my.package.MyService/MyMethod",
+			expectedMatch: &gatewayv1alpha2.GRPCMethodMatch{
+				Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+				Service: ptr.To("my.package.MyService"),
+				Method:  ptr.To("MyMethod"),
+			},
+		},
+		{
+			name: "service only path",
+			path: "/my.package.MyService",
+			expectedMatch: &gatewayv1alpha2.GRPCMethodMatch{
+				Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+				Service: ptr.To("my.package.MyService"),
+			},
+		},
+		{
+			name:          "root path",
+			path:          "/",
+			expectedMatch: nil,
+		},
+		{
+			name:          "too many path segments",
+			path:          "/my.package.MyService/MyMethod/extra",
+			expectedError: true,
+		},
+		{
+			name:          "invalid service name",
+			path:          "/my-service/MyMethod",
+			expectedError: true,
+		},
+		{
+			name:          "invalid method name",
+			path:          "/my.package.MyService/my-method",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := toGRPCMethodMatch(tc.path)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedMatch, match); diff != "" {
+				t.Errorf("Unexpected method match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_toGRPCRouteRule(t *testing.T) {
+	backendRef := gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "grpc", Port: ptr.To(gatewayv1.PortNumber(50051))}}
+	headerModifier := &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "X-Source", Value: "nginx"}}}
+
+	testCases := []struct {
+		name                string
+		httpRule            gatewayv1.HTTPRouteRule
+		expectedRule        gatewayv1alpha2.GRPCRouteRule
+		expectedUnsupported []string
+	}{
+		{
+			name: "match without path",
+			httpRule: gatewayv1.HTTPRouteRule{
+				Matches:     []gatewayv1.HTTPRouteMatch{{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "X-Canary", Value: "always"}}}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+			},
+			expectedRule: gatewayv1alpha2.GRPCRouteRule{
+				Matches:     []gatewayv1alpha2.GRPCRouteMatch{{Headers: []gatewayv1alpha2.GRPCHeaderMatch{{Name: "X-Canary", Value: "always"}}}},
+				BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{BackendRef: backendRef}},
+			},
+		},
+		{
+			name: "match of a path without value",
+			httpRule: gatewayv1.HTTPRouteRule{
+				Matches:     []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{}}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+			},
+			expectedRule: gatewayv1alpha2.GRPCRouteRule{
+				BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{BackendRef: backendRef}},
+			},
+		},
+		{
+			name: "filters",
+			httpRule: gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Value: ptr.To("/helloworld.Greeter")}}},
+				Filters: []gatewayv1.HTTPRouteFilter{
+					{Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerModifier},
+					{Type: gatewayv1.HTTPRouteFilterRequestRedirect, RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https")}},
+				},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: backendRef,
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerModifier},
+						{Type: gatewayv1.HTTPRouteFilterURLRewrite, URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Hostname: ptr.To(gatewayv1.PreciseHostname("grpc.internal"))}},
+					},
+				}},
+			},
+			expectedRule: gatewayv1alpha2.GRPCRouteRule{
+				Matches: []gatewayv1alpha2.GRPCRouteMatch{{Method: &gatewayv1alpha2.GRPCMethodMatch{
+					Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+					Service: ptr.To("helloworld.Greeter"),
+				}}},
+				Filters: []gatewayv1alpha2.GRPCRouteFilter{{Type: gatewayv1alpha2.GRPCRouteFilterRequestHeaderModifier, RequestHeaderModifier: headerModifier}},
+				BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{
+					BackendRef: backendRef,
+					Filters:    []gatewayv1alpha2.GRPCRouteFilter{{Type: gatewayv1alpha2.GRPCRouteFilterResponseHeaderModifier, ResponseHeaderModifier: headerModifier}},
+				}},
+			},
+			expectedUnsupported: []string{"RequestRedirect", "URLRewrite"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			grpcRule, unsupported, err := toGRPCRouteRule(tc.httpRule)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedRule, grpcRule); diff != "" {
+				t.Errorf("Unexpected GRPCRoute rule (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedUnsupported, unsupported); diff != "" {
+				t.Errorf("Unexpected unsupported filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_grpcFeature(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+	backend := networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: "grpc-server",
+			Port: networkingv1.ServiceBackendPort{Number: 50051},
+		},
+	}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc",
+			Namespace:   "default",
+			Annotations: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "GRPC"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: "grpc.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/my.package.MyService/MyMethod", PathType: ptr.To(networkingv1.PathTypePrefix), Backend: backend},
+							{Path: "/other.Service", PathType: ptr.To(networkingv1.PathTypePrefix), Backend: backend},
+							{Path: "/not/a/grpc/method", PathType: ptr.To(networkingv1.PathTypePrefix), Backend: backend},
+						},
+					},
+				},
+			}},
+		},
+	}}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = grpcFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "grpc-grpc-example-com"}
+	backendRefs := []gatewayv1alpha2.GRPCBackendRef{{
+		BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: "grpc-server",
+				Port: ptr.To(gatewayv1.PortNumber(50051)),
+			},
+		},
+	}}
+	expectedGRPCRoute := gatewayv1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "grpc-grpc-example-com", Namespace: "default"},
+		Spec: gatewayv1alpha2.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: NginxIngressClass}},
+			},
+			Hostnames: []gatewayv1.Hostname{"grpc.example.com"},
+			Rules: []gatewayv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayv1alpha2.GRPCMethodMatch{
+							Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+							Service: ptr.To("my.package.MyService"),
+							Method:  ptr.To("MyMethod"),
+						},
+					}},
+					BackendRefs: backendRefs,
+				},
+				{
+					Matches: []gatewayv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayv1alpha2.GRPCMethodMatch{
+							Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+							Service: ptr.To("other.Service"),
+						},
+					}},
+					BackendRefs: backendRefs,
+				},
+			},
+		},
+	}
+	expectedGRPCRoute.SetGroupVersionKind(common.GRPCRouteGVK)
+
+	if diff := cmp.Diff(expectedGRPCRoute, gatewayResources.GRPCRoutes[key]); diff != "" {
+		t.Errorf("Unexpected GRPCRoute (-want +got):\n%s", diff)
+	}
+
+	httpRoute, ok := gatewayResources.HTTPRoutes[key]
+	if !ok {
+		t.Fatalf("Expected HTTPRoute %s to be kept for the malformed path", key)
+	}
+	if len(httpRoute.Spec.Rules) != 1 || *httpRoute.Spec.Rules[0].Matches[0].Path.Value != "/not/a/grpc/method" {
+		t.Errorf("Expected HTTPRoute to only keep the malformed path rule, got %+v", httpRoute.Spec.Rules)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications[Name]
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification {
+		t.Errorf("Expected a single Warning notification, got %+v", gotNotifications)
+	}
+}
+
+func Test_grpcFeature_legacyGRPCBackend(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc",
+			Namespace:   "default",
+			Annotations: map[string]string{"nginx.ingress.kubernetes.io/grpc-backend": "true"},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: "grpc.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/my.package.MyService",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: "grpc-server",
+									Port: networkingv1.ServiceBackendPort{Number: 50051},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = grpcFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "grpc-grpc-example-com"}
+	expectedRules := []gatewayv1alpha2.GRPCRouteRule{{
+		Matches: []gatewayv1alpha2.GRPCRouteMatch{{
+			Method: &gatewayv1alpha2.GRPCMethodMatch{
+				Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+				Service: ptr.To("my.package.MyService"),
+			},
+		}},
+		BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: "grpc-server",
+					Port: ptr.To(gatewayv1.PortNumber(50051)),
+				},
+			},
+		}},
+	}}
+	if diff := cmp.Diff(expectedRules, gatewayResources.GRPCRoutes[key].Spec.Rules); diff != "" {
+		t.Errorf("Unexpected GRPCRoute rules (-want +got):\n%s", diff)
+	}
+	if _, ok := gatewayResources.HTTPRoutes[key]; ok {
+		t.Errorf("Expected HTTPRoute %s to be replaced by the GRPCRoute", key)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications[Name]
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.InfoNotification {
+		t.Errorf("Expected a single Info notification, got %+v", gotNotifications)
+	}
+}
+
+func Test_ToGatewayGRPCCanaryByHeader(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	primary := conflictTestIngress("primary", "/my.package.MyService/MyMethod", map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
+	})
+	canary := conflictTestIngress("canary", "/my.package.MyService/MyMethod", map[string]string{
+		"nginx.ingress.kubernetes.io/backend-protocol":       "GRPC",
+		"nginx.ingress.kubernetes.io/canary":                 "true",
+		"nginx.ingress.kubernetes.io/canary-by-header":       "x-canary",
+		"nginx.ingress.kubernetes.io/canary-by-header-value": "yes",
+	})
+
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames: []types.NamespacedName{{Namespace: "default", Name: "canary"}, {Namespace: "default", Name: "primary"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+			{Namespace: "default", Name: "canary"}:  &canary,
+			{Namespace: "default", Name: "primary"}: &primary,
+		},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if len(gatewayResources.HTTPRoutes) != 0 {
+		t.Errorf("Expected the HTTPRoute to be replaced by the GRPCRoute, got %+v", gatewayResources.HTTPRoutes)
+	}
+
+	methodMatch := &gatewayv1alpha2.GRPCMethodMatch{
+		Type:    ptr.To(gatewayv1alpha2.GRPCMethodMatchExact),
+		Service: ptr.To("my.package.MyService"),
+		Method:  ptr.To("MyMethod"),
+	}
+	backendRef := func(name string) gatewayv1.BackendRef {
+		return gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(name),
+				Port: ptr.To(gatewayv1.PortNumber(80)),
+			},
+		}
+	}
+	canaryRef, primaryRef := backendRef("canary"), backendRef("primary")
+	canaryRef.Weight = ptr.To(int32(0))
+	expectedRules := []gatewayv1alpha2.GRPCRouteRule{
+		{
+			Matches:     []gatewayv1alpha2.GRPCRouteMatch{{Method: methodMatch}},
+			BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{BackendRef: canaryRef}, {BackendRef: primaryRef}},
+		},
+		{
+			Matches: []gatewayv1alpha2.GRPCRouteMatch{{
+				Method: methodMatch,
+				Headers: []gatewayv1alpha2.GRPCHeaderMatch{{
+					Type:  ptr.To(gatewayv1.HeaderMatchExact),
+					Name:  "x-canary",
+					Value: "yes",
+				}},
+			}},
+			BackendRefs: []gatewayv1alpha2.GRPCBackendRef{{BackendRef: backendRef("canary")}},
+		},
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "canary-www-example-com"}
+	grpcRoute, ok := gatewayResources.GRPCRoutes[key]
+	if !ok {
+		t.Fatalf("Expected GRPCRoute %s, got %+v", key, gatewayResources.GRPCRoutes)
+	}
+	if diff := cmp.Diff(expectedRules, grpcRoute.Spec.Rules); diff != "" {
+		t.Errorf("Unexpected GRPCRoute rules (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// defaultHSTSMaxAge is the max-age of ingress-nginx, one year, used when the
+// `nginx.ingress.kubernetes.io/hsts-max-age` annotation is not set.
+const defaultHSTSMaxAge = "31536000"
+
+// hstsFeature converts the `nginx.ingress.kubernetes.io/hsts`,
+// `hsts-max-age`, `hsts-include-subdomains` and `hsts-preload` annotations to a
+// ResponseHeaderModifier filter setting the Strict-Transport-Security header of
+// the responses, on the HTTPRoute rules generated from the Ingress paths.
+//
+// Nothing is generated unless hsts is true. The header is only set for the
+// hosts served over HTTPS, i.e. covered by the TLS section of the Ingress, the
+// other hosts getting a Warning notification. Like ingress-nginx, the
+// directives default to a max-age of one year and includeSubDomains, without
+// preload. As the HTTPRoute is also attached to the HTTP listeners, the header
+// is set on the HTTP responses too, where clients ignore it. The filter is
+// merged with the other response header modifiers of the rules by
+// common.AssembleHTTPRouteFilters, which reports the conflicting values.
+func hstsFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			value, err := hstsHeaderValue(ingress.Annotations)
+			if err != nil {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%v, no Strict-Transport-Security header was set in HTTPRoute %s/%s", err, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+			if value == "" {
+				continue
+			}
+			if !slices.ContainsFunc(ingress.Spec.TLS, func(tls networkingv1.IngressTLS) bool { return common.TLSCoversHost(tls, rg.Host) }) {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s is not converted for host %q, which is not served over HTTPS", nginxAnnotation(hstsKey), rg.Host), &ingress)
+				continue
+			}
+			for i := range httpRoute.Spec.Rules {
+				if !ruleMatchesAnyPath(httpRoute.Spec.Rules[i], rule.IngressRule.HTTP.Paths) {
+					continue
+				}
+				httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, gatewayv1.HTTPRouteFilter{
+					Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+						Set: []gatewayv1.HTTPHeader{{Name: "Strict-Transport-Security", Value: value}},
+					},
+				})
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, nginxAnnotation(hstsKey))
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// hstsHeaderValue returns the value of the Strict-Transport-Security header
+// configured by the annotations, e.g. `max-age=31536000; includeSubDomains`,
+// or an empty string if HSTS is not enabled.
+func hstsHeaderValue(annotations map[string]string) (string, error) {
+	parseBool := func(key string, defaultValue bool) (bool, error) {
+		value, ok := annotations[nginxAnnotation(key)]
+		if !ok {
+			return defaultValue, nil
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("%s %q is not a boolean", nginxAnnotation(key), value)
+		}
+		return parsed, nil
+	}
+
+	enabled, err := parseBool(hstsKey, false)
+	if err != nil || !enabled {
+		return "", err
+	}
+	maxAge := defaultHSTSMaxAge
+	if value, ok := annotations[nginxAnnotation(hstsMaxAgeKey)]; ok {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s %q is not a number of seconds", nginxAnnotation(hstsMaxAgeKey), value)
+		}
+		maxAge = strconv.FormatUint(parsed, 10)
+	}
+	includeSubdomains, err := parseBool(hstsIncludeSubdomainsKey, true)
+	if err != nil {
+		return "", err
+	}
+	preload, err := parseBool(hstsPreloadKey, false)
+	if err != nil {
+		return "", err
+	}
+
+	directives := []string{"max-age=" + maxAge}
+	if includeSubdomains {
+		directives = append(directives, "includeSubDomains")
+	}
+	if preload {
+		directives = append(directives, "preload")
+	}
+	return strings.Join(directives, "; "), nil
+}
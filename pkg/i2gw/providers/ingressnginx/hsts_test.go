@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_hstsFeature(t *testing.T) {
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		tls              bool
+		existingValue    string
+		expectedValue    string
+		expectedConflict bool
+	}{
+		{
+			name:          "defaults",
+			annotations:   map[string]string{"nginx.ingress.kubernetes.io/hsts": "true"},
+			tls:           true,
+			expectedValue: "max-age=31536000; includeSubDomains",
+		},
+		{
+			name: "all directives",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/hsts":                    "true",
+				"nginx.ingress.kubernetes.io/hsts-max-age":            "600",
+				"nginx.ingress.kubernetes.io/hsts-include-subdomains": "true",
+				"nginx.ingress.kubernetes.io/hsts-preload":            "true",
+			},
+			tls:           true,
+			expectedValue: "max-age=600; includeSubDomains; preload",
+		},
+		{
+			name: "without subdomains",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/hsts":                    "true",
+				"nginx.ingress.kubernetes.io/hsts-max-age":            "0",
+				"nginx.ingress.kubernetes.io/hsts-include-subdomains": "false",
+			},
+			tls:           true,
+			expectedValue: "max-age=0",
+		},
+		{
+			name: "disabled",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/hsts":         "false",
+				"nginx.ingress.kubernetes.io/hsts-max-age": "600",
+			},
+			tls: true,
+		},
+		{
+			name:          "same header already set",
+			annotations:   map[string]string{"nginx.ingress.kubernetes.io/hsts": "true"},
+			tls:           true,
+			existingValue: "max-age=31536000; includeSubDomains",
+			expectedValue: "max-age=31536000; includeSubDomains",
+		},
+		{
+			name:             "conflicting header already set",
+			annotations:      map[string]string{"nginx.ingress.kubernetes.io/hsts": "true"},
+			tls:              true,
+			existingValue:    "max-age=600",
+			expectedValue:    "max-age=600",
+			expectedConflict: true,
+		},
+		{
+			name:        "HTTP only",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/hsts": "true"},
+		},
+		{
+			name: "invalid max-age",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/hsts":         "true",
+				"nginx.ingress.kubernetes.io/hsts-max-age": "1y",
+			},
+			tls: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			if tc.tls {
+				ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-tls"}}
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			routeKey := types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}
+			if tc.existingValue != "" {
+				httpRoute := gatewayResources.HTTPRoutes[routeKey]
+				httpRoute.Spec.Rules[0].Filters = []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{
+						{Name: "Strict-Transport-Security", Value: tc.existingValue},
+					}},
+				}}
+				gatewayResources.HTTPRoutes[routeKey] = httpRoute
+			}
+			if errs = hstsFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+			if notifs := common.AssembleHTTPRouteFilters(&gatewayResources); (len(notifs) > 0) != tc.expectedConflict {
+				t.Errorf("Expected a filter conflict: %v, got %+v", tc.expectedConflict, notifs)
+			}
+
+			var expectedFilters []gatewayv1.HTTPRouteFilter
+			if tc.expectedValue != "" {
+				expectedFilters = []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{
+						{Name: "Strict-Transport-Security", Value: tc.expectedValue},
+					}},
+				}}
+			}
+			httpRoute := gatewayResources.HTTPRoutes[routeKey]
+			if diff := cmp.Diff(expectedFilters, httpRoute.Spec.Rules[0].Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
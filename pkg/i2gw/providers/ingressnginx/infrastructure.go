@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxInfrastructureAnnotations is the maximum number of annotations of the
+// infrastructure of a Gateway.
+const maxInfrastructureAnnotations = 8
+
+// loadBalancerAnnotationPrefixes are the prefixes of the Service annotations
+// configuring the load balancer provisioned by the cloud providers and the
+// common bare-metal load balancers.
+var loadBalancerAnnotationPrefixes = []string{
+	"service.beta.kubernetes.io/aws-load-balancer-",
+	"service.beta.kubernetes.io/azure-",
+	"service.beta.kubernetes.io/oci-load-balancer-",
+	"service.beta.kubernetes.io/do-loadbalancer-",
+	"service.beta.kubernetes.io/alibaba-cloud-loadbalancer-",
+	"service.kubernetes.io/qcloud-loadbalancer-",
+	"cloud.google.com/load-balancer-type",
+	"networking.gke.io/load-balancer-type",
+	"networking.gke.io/internal-load-balancer-",
+	"metallb.universe.tf/",
+}
+
+// convertLoadBalancerSettings sets the load balancer annotations of the Service
+// fronting the ingress-nginx controller, like `service.beta.kubernetes.io/aws-load-balancer-type`,
+// on the spec.infrastructure.annotations of every generated Gateway, so that the
+// implementation provisions a comparable load balancer for it. The other
+// annotations of the Service, like the ones set by helm, are not copied.
+//
+// As spec.infrastructure is an experimental field whose propagation to the
+// provisioned resources depends on the implementation, an Info notification is
+// emitted for every Gateway. The load balancer settings with no infrastructure
+// equivalent, like spec.loadBalancerSourceRanges, and the annotations beyond the
+// maximum of the infrastructure emit a Warning notification. Nothing is set if
+// several controller Services are found, as the one provisioning the load
+// balancer of a Gateway cannot be told.
+func convertLoadBalancerSettings(services map[types.NamespacedName]*corev1.Service, controllerService types.NamespacedName, gatewayResources *i2gw.GatewayResources) {
+	if len(gatewayResources.Gateways) == 0 {
+		return
+	}
+	fronting := controllerServices(services, controllerService)
+	if len(fronting) == 0 {
+		return
+	}
+	if len(fronting) > 1 {
+		var names []string
+		for _, service := range fronting {
+			names = append(names, fmt.Sprintf("%s/%s", service.Namespace, service.Name))
+		}
+		notify(notifications.WarningNotification, fmt.Sprintf("several ingress-nginx controller Services were found (%s), so their load balancer settings are not converted: set the one to convert with --%s-%s", strings.Join(names, ", "), Name, ControllerServiceFlag))
+		return
+	}
+	service := fronting[0]
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return
+	}
+	serviceName := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+
+	var keys []string
+	for key := range service.Annotations {
+		if isLoadBalancerAnnotation(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var dropped []string
+	if len(keys) > maxInfrastructureAnnotations {
+		keys, dropped = keys[:maxInfrastructureAnnotations], keys[maxInfrastructureAnnotations:]
+	}
+	unconverted := unconvertedLoadBalancerSettings(service)
+
+	for key, gateway := range gatewayResources.Gateways {
+		gateway := gateway
+		if len(keys) > 0 {
+			if gateway.Spec.Infrastructure == nil {
+				gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+			}
+			if gateway.Spec.Infrastructure.Annotations == nil {
+				gateway.Spec.Infrastructure.Annotations = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{}
+			}
+			for _, k := range keys {
+				gateway.Spec.Infrastructure.Annotations[gatewayv1.AnnotationKey(k)] = gatewayv1.AnnotationValue(service.Annotations[k])
+			}
+			gatewayResources.Gateways[key] = gateway
+			notify(notifications.InfoNotification, fmt.Sprintf("the load balancer annotations of the ingress-nginx controller Service %s (%s) were set on the spec.infrastructure.annotations of Gateway %s/%s: this field requires the experimental channel of the Gateway API CRDs, and whether the annotations are applied to the provisioned load balancer depends on your Gateway implementation", serviceName, strings.Join(keys, ", "), gateway.Namespace, gateway.Name), &gateway)
+		}
+		if len(dropped) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s/%s: spec.infrastructure supports at most %d annotations, the load balancer annotations %s of the ingress-nginx controller Service %s were not converted", gateway.Namespace, gateway.Name, maxInfrastructureAnnotations, strings.Join(dropped, ", "), serviceName), &gateway)
+		}
+		for _, setting := range unconverted {
+			notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s/%s: %s of the ingress-nginx controller Service %s has no infrastructure equivalent and is not converted", gateway.Namespace, gateway.Name, setting, serviceName), &gateway)
+		}
+	}
+}
+
+func isLoadBalancerAnnotation(key string) bool {
+	for _, prefix := range loadBalancerAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unconvertedLoadBalancerSettings describes the load balancer settings of the
+// Service spec, which cannot be passed as infrastructure annotations.
+func unconvertedLoadBalancerSettings(service *corev1.Service) []string {
+	var settings []string
+	if service.Spec.LoadBalancerClass != nil {
+		settings = append(settings, fmt.Sprintf("spec.loadBalancerClass %s", *service.Spec.LoadBalancerClass))
+	}
+	if service.Spec.LoadBalancerIP != "" {
+		settings = append(settings, fmt.Sprintf("spec.loadBalancerIP %s, which may be requested with the spec.addresses of the Gateway,", service.Spec.LoadBalancerIP))
+	}
+	if len(service.Spec.LoadBalancerSourceRanges) > 0 {
+		settings = append(settings, fmt.Sprintf("spec.loadBalancerSourceRanges %s", strings.Join(service.Spec.LoadBalancerSourceRanges, ", ")))
+	}
+	return settings
+}
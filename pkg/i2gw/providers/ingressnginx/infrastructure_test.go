@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_convertLoadBalancerSettings(t *testing.T) {
+	controllerService := func(annotations map[string]string, spec corev1.ServiceSpec) *corev1.Service {
+		if spec.Type == "" {
+			spec.Type = corev1.ServiceTypeLoadBalancer
+		}
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ingress-nginx",
+				Name:        "ingress-nginx-controller",
+				Labels:      map[string]string{"app.kubernetes.io/name": "ingress-nginx"},
+				Annotations: annotations,
+			},
+			Spec: spec,
+		}
+	}
+	manyAnnotations := map[string]string{}
+	for i := 0; i < 10; i++ {
+		manyAnnotations[fmt.Sprintf("service.beta.kubernetes.io/aws-load-balancer-setting-%d", i)] = "true"
+	}
+
+	testCases := []struct {
+		name                      string
+		services                  []*corev1.Service
+		expectedInfrastructure    *gatewayv1.GatewayInfrastructure
+		expectedNotificationTypes []notifications.MessageType
+	}{
+		{
+			name: "AWS network load balancer",
+			services: []*corev1.Service{controllerService(map[string]string{
+				"service.beta.kubernetes.io/aws-load-balancer-type":   "nlb",
+				"service.beta.kubernetes.io/aws-load-balancer-scheme": "internet-facing",
+				"meta.helm.sh/release-name":                           "ingress-nginx",
+			}, corev1.ServiceSpec{})},
+			expectedInfrastructure: &gatewayv1.GatewayInfrastructure{
+				Annotations: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+					"service.beta.kubernetes.io/aws-load-balancer-type":   "nlb",
+					"service.beta.kubernetes.io/aws-load-balancer-scheme": "internet-facing",
+				},
+			},
+			expectedNotificationTypes: []notifications.MessageType{notifications.InfoNotification},
+		},
+		{
+			name: "GKE internal load balancer with source ranges",
+			services: []*corev1.Service{controllerService(map[string]string{
+				"networking.gke.io/load-balancer-type": "Internal",
+			}, corev1.ServiceSpec{LoadBalancerSourceRanges: []string{"10.0.0.0/8"}})},
+			expectedInfrastructure: &gatewayv1.GatewayInfrastructure{
+				Annotations: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+					"networking.gke.io/load-balancer-type": "Internal",
+				},
+			},
+			expectedNotificationTypes: []notifications.MessageType{notifications.InfoNotification, notifications.WarningNotification},
+		},
+		{
+			name:     "too many annotations",
+			services: []*corev1.Service{controllerService(manyAnnotations, corev1.ServiceSpec{})},
+			expectedInfrastructure: &gatewayv1.GatewayInfrastructure{
+				Annotations: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+					"service.beta.kubernetes.io/aws-load-balancer-setting-0": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-1": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-2": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-3": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-4": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-5": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-6": "true",
+					"service.beta.kubernetes.io/aws-load-balancer-setting-7": "true",
+				},
+			},
+			expectedNotificationTypes: []notifications.MessageType{notifications.InfoNotification, notifications.WarningNotification},
+		},
+		{
+			name: "NodePort controller Service",
+			services: []*corev1.Service{controllerService(map[string]string{
+				"service.beta.kubernetes.io/aws-load-balancer-type": "nlb",
+			}, corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort})},
+		},
+		{
+			name: "several controller Services",
+			services: []*corev1.Service{
+				controllerService(map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"}, corev1.ServiceSpec{}),
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "ingress-nginx-controller", Labels: map[string]string{"app.kubernetes.io/name": "ingress-nginx"}},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				},
+			},
+			expectedNotificationTypes: []notifications.MessageType{notifications.WarningNotification},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			services := map[types.NamespacedName]*corev1.Service{}
+			for _, service := range tc.services {
+				services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = service
+			}
+			gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+			gatewayResources := i2gw.GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					gatewayKey: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}},
+				},
+			}
+
+			convertLoadBalancerSettings(services, types.NamespacedName{}, &gatewayResources)
+
+			if diff := cmp.Diff(tc.expectedInfrastructure, gatewayResources.Gateways[gatewayKey].Spec.Infrastructure); diff != "" {
+				t.Errorf("Unexpected infrastructure (-want +got):\n%s", diff)
+			}
+			var notificationTypes []notifications.MessageType
+			for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+				notificationTypes = append(notificationTypes, notification.Type)
+			}
+			if diff := cmp.Diff(tc.expectedNotificationTypes, notificationTypes); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s\n%+v", diff, notifications.NotificationAggr.Notifications[Name])
+			}
+		})
+	}
+}
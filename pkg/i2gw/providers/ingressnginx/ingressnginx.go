@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -28,8 +29,17 @@ import (
 const Name = "ingress-nginx"
 const NginxIngressClass = "nginx"
 
+// ControllerServiceFlag is the provider-specific flag naming the Service fronting
+// the ingress-nginx controller.
+const ControllerServiceFlag = "controller-service"
+
 func init() {
-	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProvider(Name, NewProvider, common.IngressGVK.GroupKind(), common.ServiceGVK.GroupKind())
+
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:        ControllerServiceFlag,
+		Description: "The namespace/name of the LoadBalancer Service fronting the ingress-nginx controller. The namespace defaults to ingress-nginx. Defaults to the LoadBalancer Services labeled app.kubernetes.io/name=ingress-nginx.",
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -44,7 +54,7 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		storage:        newResourcesStorage(),
 		resourceReader: newResourceReader(conf),
-		converter:      newConverter(),
+		converter:      newConverter(conf),
 	}
 }
 
@@ -54,6 +64,12 @@ func (p *Provider) ToGatewayAPI() (i2gw.GatewayResources, field.ErrorList) {
 	return p.converter.convert(p.storage)
 }
 
+// SupportedAnnotations returns the ingress-nginx annotations converted to Gateway API
+// resources.
+func (p *Provider) SupportedAnnotations() []string {
+	return supportedAnnotations()
+}
+
 func (p *Provider) ReadResourcesFromCluster(ctx context.Context) error {
 	storage, err := p.resourceReader.readResourcesFromCluster(ctx)
 	if err != nil {
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// requestURIVariable is the nginx variable mirror targets usually end with, so
+// that the mirrored requests keep the original request URI.
+const requestURIVariable = "$request_uri"
+
+// mirrorFeature converts the `nginx.ingress.kubernetes.io/mirror-target`
+// annotation to a RequestMirror filter on the HTTPRoute rules generated from the
+// Ingress paths.
+//
+// Only http targets pointing to a cluster-local Service and keeping the request
+// URI, like `http://my-service.my-namespace.svc:8080$request_uri`, are
+// supported, as RequestMirror cannot change the mirrored requests. ingress-nginx
+// mirrors every request, and so does a RequestMirror filter. The Gateway API
+// version generated by the tool has no mirror fraction, so a target using other
+// variables, which is how a sampling of the mirrored requests is implemented
+// with nginx, emits an Error notification. As the request body is always
+// mirrored and the Host header cannot be set, `mirror-request-body: off` and
+// `mirror-host` emit a Warning notification.
+func mirrorFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			target := ingress.Annotations[nginxAnnotation(mirrorTargetKey)]
+			if target == "" || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			backend, err := parseMirrorTarget(target, ingress.Namespace)
+			if err != nil {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%v, no mirror was generated in HTTPRoute %s/%s", err, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+
+			backendRef := gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(backend.Name),
+				Port: common.PtrTo(gatewayv1.PortNumber(backend.port)),
+			}
+			if backend.Namespace != ingress.Namespace {
+				backendRef.Namespace = common.PtrTo(gatewayv1.Namespace(backend.Namespace))
+			}
+			mirrored := false
+			for i := range httpRoute.Spec.Rules {
+				if !ruleMatchesAnyPath(httpRoute.Spec.Rules[i], rule.IngressRule.HTTP.Paths) {
+					continue
+				}
+				httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, gatewayv1.HTTPRouteFilter{
+					Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: backendRef},
+				})
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, nginxAnnotation(mirrorTargetKey))
+				mirrored = true
+			}
+			if !mirrored {
+				continue
+			}
+			if backend.Namespace != ingress.Namespace {
+				common.AddServiceReferenceGrant(gatewayResources, common.HTTPRouteGVK.Kind, ingress.Namespace, backend.NamespacedName)
+			}
+			if strings.EqualFold(ingress.Annotations[nginxAnnotation(mirrorRequestBodyKey)], "off") {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s: off is not supported, the request bodies are mirrored by HTTPRoute %s/%s", nginxAnnotation(mirrorRequestBodyKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+			if ingress.Annotations[nginxAnnotation(mirrorHostKey)] != "" {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s is not supported, as RequestMirror cannot set the Host header of the requests mirrored by HTTPRoute %s/%s", nginxAnnotation(mirrorHostKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// parseMirrorTarget returns the cluster-local Service the mirror target points to.
+func parseMirrorTarget(target, namespace string) (*snippetBackend, error) {
+	annotation := nginxAnnotation(mirrorTargetKey)
+	base, ok := strings.CutSuffix(target, requestURIVariable)
+	if !ok {
+		return nil, fmt.Errorf("%s %q is not supported, as it does not keep the %s of the mirrored requests", annotation, target, requestURIVariable)
+	}
+	if strings.Contains(base, "$") {
+		return nil, fmt.Errorf("%s %q uses variables, which are not supported. If they sample the mirrored requests, note that the generated Gateway API version has no mirror fraction", annotation, target)
+	}
+	u, err := url.Parse(strings.TrimSuffix(base, "/"))
+	if err != nil || u.Scheme != "http" || u.Host == "" {
+		return nil, fmt.Errorf("%s %q is not supported, only http targets are", annotation, target)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return nil, fmt.Errorf("%s %q is not supported, as it changes the request URI", annotation, target)
+	}
+
+	backend := &snippetBackend{port: 80}
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseInt(p, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("%s %q has an invalid port", annotation, target)
+		}
+		backend.port = int32(port)
+	}
+	service, ok := serviceFromHostname(u.Hostname(), namespace)
+	if !ok {
+		return nil, fmt.Errorf("%s %q does not target a cluster-local Service", annotation, target)
+	}
+	backend.NamespacedName = service
+	return backend, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_mirrorFeature(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		annotations            map[string]string
+		expectedFilters        []gatewayv1.HTTPRouteFilter
+		expectedReferenceGrant bool
+		expectedNotification   notifications.MessageType
+	}{
+		{
+			name: "no mirror",
+		},
+		{
+			name:        "mirror to a Service of the same namespace",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://mirror$request_uri"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: "mirror", Port: ptr.To(gatewayv1.PortNumber(80))}},
+			}},
+		},
+		{
+			name:        "mirror to a Service of another namespace",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://mirror.test.svc.cluster.local:8080/$request_uri"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{
+					Name:      "mirror",
+					Namespace: ptr.To(gatewayv1.Namespace("test")),
+					Port:      ptr.To(gatewayv1.PortNumber(8080)),
+				}},
+			}},
+			expectedReferenceGrant: true,
+		},
+		{
+			name:        "mirror to a Service of another namespace with the svc suffix",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://my-service.my-namespace.svc:8080$request_uri"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{
+					Name:      "my-service",
+					Namespace: ptr.To(gatewayv1.Namespace("my-namespace")),
+					Port:      ptr.To(gatewayv1.PortNumber(8080)),
+				}},
+			}},
+			expectedReferenceGrant: true,
+		},
+		{
+			name: "mirror without request body",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/mirror-target":       "http://mirror$request_uri",
+				"nginx.ingress.kubernetes.io/mirror-request-body": "off",
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: "mirror", Port: ptr.To(gatewayv1.PortNumber(80))}},
+			}},
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:                 "sampled mirror",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://mirror$mirror_sample$request_uri"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+		{
+			name:                 "mirror to a fixed path",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://mirror/audit"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+		{
+			name:                 "mirror to an external host",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "https://mirror.example.com$request_uri"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+		{
+			name:                 "mirror to an external http host",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/mirror-target": "http://example.com$request_uri"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = mirrorFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+			if len(rule.BackendRefs) != 1 {
+				t.Errorf("Expected the backendRefs to be kept, got %+v", rule.BackendRefs)
+			}
+			if gotReferenceGrant := len(gatewayResources.ReferenceGrants) > 0; gotReferenceGrant != tc.expectedReferenceGrant {
+				t.Errorf("Expected ReferenceGrant: %v, got %+v", tc.expectedReferenceGrant, gatewayResources.ReferenceGrants)
+			}
+
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedNotification == "" {
+				if len(notifs) > 0 {
+					t.Errorf("Expected no notification, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a single %s notification, got %+v", tc.expectedNotification, notifs)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// observabilitySetting is a kind of observability configured by annotations,
+// along with where Envoy Gateway, whose telemetry is configured per Gateway,
+// expects it.
+type observabilitySetting struct {
+	name           string
+	annotationKeys []string
+	envoyGateway   string
+}
+
+var observabilitySettings = []observabilitySetting{
+	{name: "access logging", annotationKeys: []string{enableAccessLogKey}, envoyGateway: "EnvoyProxy spec.telemetry.accessLog"},
+	{name: "rewrite logging", annotationKeys: []string{enableRewriteLogKey}, envoyGateway: "EnvoyProxy spec.logging"},
+	{name: "tracing", annotationKeys: []string{enableOpentracingKey, opentracingTrustIncomingSpanKey, enableOpentelemetryKey, opentelemetryTrustIncomingSpanKey, opentelemetryOperationNameKey}, envoyGateway: "EnvoyProxy spec.telemetry.tracing"},
+}
+
+// observabilityFeature reports the logging and tracing annotations of every
+// Ingress, like `nginx.ingress.kubernetes.io/enable-access-log: "false"`.
+//
+// Gateway API has no equivalent to these settings, which are configured per
+// Gateway, or per implementation, rather than per route, so none of them is
+// converted. As they matter to operate the migrated routes, a Warning
+// notification is emitted per Ingress, listing every setting with its
+// annotations and the Envoy Gateway resource field to configure it with.
+func observabilityFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		var settings []string
+		for _, setting := range observabilitySettings {
+			var values []string
+			for _, key := range setting.annotationKeys {
+				if value, ok := ingress.Annotations[nginxAnnotation(key)]; ok {
+					values = append(values, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+				}
+			}
+			if len(values) > 0 {
+				settings = append(settings, fmt.Sprintf("- %s (%s), e.g. with Envoy Gateway in %s", setting.name, strings.Join(values, ", "), setting.envoyGateway))
+			}
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		ingress := ingress
+		notify(notifications.WarningNotification, fmt.Sprintf("the observability settings are not converted, as Gateway API has no equivalent to them: reconfigure them with your Gateway implementation. Settings:\n%s", strings.Join(settings, "\n")), &ingress)
+	}
+	return nil
+}
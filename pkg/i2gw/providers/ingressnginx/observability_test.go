@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_observabilityFeature(t *testing.T) {
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedSettings []string
+	}{
+		{
+			name:        "no observability annotations",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-buffering": "on"},
+		},
+		{
+			name:             "access log disabled",
+			annotations:      map[string]string{"nginx.ingress.kubernetes.io/enable-access-log": "false"},
+			expectedSettings: []string{"- access logging (nginx.ingress.kubernetes.io/enable-access-log: false), e.g. with Envoy Gateway in EnvoyProxy spec.telemetry.accessLog"},
+		},
+		{
+			name: "logging and tracing",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/enable-rewrite-log":                "true",
+				"nginx.ingress.kubernetes.io/enable-opentelemetry":              "true",
+				"nginx.ingress.kubernetes.io/opentelemetry-operation-name":      "HTTP $request_method $service_name",
+				"nginx.ingress.kubernetes.io/opentelemetry-trust-incoming-span": "false",
+			},
+			expectedSettings: []string{
+				"- rewrite logging (nginx.ingress.kubernetes.io/enable-rewrite-log: true), e.g. with Envoy Gateway in EnvoyProxy spec.logging",
+				"- tracing (nginx.ingress.kubernetes.io/enable-opentelemetry: true, nginx.ingress.kubernetes.io/opentelemetry-trust-incoming-span: false, nginx.ingress.kubernetes.io/opentelemetry-operation-name: HTTP $request_method $service_name), e.g. with Envoy Gateway in EnvoyProxy spec.telemetry.tracing",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "observability", Annotations: tc.annotations},
+			}}
+
+			if errs := observabilityFeature(ingresses, &i2gw.GatewayResources{}); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(tc.expectedSettings) == 0 {
+				if len(gotNotifications) != 0 {
+					t.Errorf("Expected no notifications, got %+v", gotNotifications)
+				}
+				return
+			}
+			if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification {
+				t.Fatalf("Expected a single Warning notification, got %+v", gotNotifications)
+			}
+			if message := gotNotifications[0].Message; !strings.HasSuffix(message, strings.Join(tc.expectedSettings, "\n")) {
+				t.Errorf("Expected notification to list the observability settings %q, got %q", tc.expectedSettings, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// proxyProtocolKeys are the annotations enabling the PROXY protocol, with
+// which the load balancer in front of ingress-nginx sends the addresses of
+// the clients.
+var proxyProtocolKeys = []string{useProxyProtocolKey, enableProxyProtocolKey}
+
+// proxyProtocolFeature converts the `nginx.ingress.kubernetes.io/use-proxy-protocol`
+// and `enable-proxy-protocol` annotations to a GatewayPolicy accepting the
+// PROXY protocol on the Gateways of the Ingress, converted to a
+// ClientTrafficPolicy for Envoy Gateway.
+//
+// The PROXY protocol is a setting of the connections accepted by the Gateway,
+// not of its routes, so it applies to all the routes of the Gateway, and the
+// load balancer in front of the Gateway must be configured to send it. A
+// Warning notification is emitted for every Ingress enabling it, as the
+// clients are rejected, or their addresses lost, if the Gateway and its load
+// balancer do not agree.
+func proxyProtocolFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	gatewaysByIngress := map[types.NamespacedName]map[types.NamespacedName]bool{}
+	ingressByKey := map[types.NamespacedName]networkingv1.Ingress{}
+	for _, rg := range common.GetRuleGroups(ingresses) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			enabled, err := proxyProtocolEnabled(ingress.Annotations)
+			if err != nil {
+				notify(notifications.ErrorNotification, err.Error(), &ingress)
+				continue
+			}
+			if !enabled {
+				continue
+			}
+			ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+			ingressByKey[ingressKey] = ingress
+			if gatewaysByIngress[ingressKey] == nil {
+				gatewaysByIngress[ingressKey] = map[types.NamespacedName]bool{}
+			}
+			for _, parentRef := range httpRoute.Spec.ParentRefs {
+				if gatewayKey, ok := parentGateway(parentRef, httpRoute.Namespace); ok {
+					gatewaysByIngress[ingressKey][gatewayKey] = true
+				}
+			}
+		}
+	}
+
+	ingressKeys := make([]types.NamespacedName, 0, len(gatewaysByIngress))
+	for key := range gatewaysByIngress {
+		ingressKeys = append(ingressKeys, key)
+	}
+	sort.Slice(ingressKeys, func(i, j int) bool { return ingressKeys[i].String() < ingressKeys[j].String() })
+	for _, ingressKey := range ingressKeys {
+		ingress := ingressByKey[ingressKey]
+		var gateways []string
+		for gatewayKey := range gatewaysByIngress[ingressKey] {
+			if gatewayResources.GatewayPolicies == nil {
+				gatewayResources.GatewayPolicies = map[types.NamespacedName]i2gw.GatewayPolicy{}
+			}
+			policy := gatewayResources.GatewayPolicies[gatewayKey]
+			policy.ProxyProtocol = true
+			gatewayResources.GatewayPolicies[gatewayKey] = policy
+			gateways = append(gateways, gatewayKey.String())
+		}
+		sort.Strings(gateways)
+		notify(notifications.WarningNotification, fmt.Sprintf("the Ingress enables the PROXY protocol, which is a setting of the connections accepted by the Gateways %s, for all their routes: make sure their load balancer sends the PROXY protocol header and that the Gateways accept it, e.g. with a ClientTrafficPolicy for Envoy Gateway, or the clients are rejected or their addresses lost", strings.Join(gateways, ", ")), &ingress)
+	}
+	return nil
+}
+
+// proxyProtocolEnabled returns whether one of the annotations enables the
+// PROXY protocol.
+func proxyProtocolEnabled(annotations map[string]string) (bool, error) {
+	for _, key := range proxyProtocolKeys {
+		value, ok := annotations[nginxAnnotation(key)]
+		if !ok {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("%s %q is not a boolean, the PROXY protocol was not converted", nginxAnnotation(key), value)
+		}
+		if enabled {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parentGateway returns the Gateway of the parentRef of a route of the
+// namespace, or false if the parent is not a Gateway.
+func parentGateway(parentRef gatewayv1.ParentReference, routeNamespace string) (types.NamespacedName, bool) {
+	if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+		return types.NamespacedName{}, false
+	}
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}, true
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func Test_proxyProtocolFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedPolicies     map[types.NamespacedName]i2gw.GatewayPolicy
+		expectedNotification notifications.MessageType
+	}{
+		{
+			name:        "use-proxy-protocol",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/use-proxy-protocol": "true"},
+			expectedPolicies: map[types.NamespacedName]i2gw.GatewayPolicy{
+				{Namespace: "default", Name: NginxIngressClass}: {ProxyProtocol: true},
+			},
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:        "enable-proxy-protocol",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/enable-proxy-protocol": "true"},
+			expectedPolicies: map[types.NamespacedName]i2gw.GatewayPolicy{
+				{Namespace: "default", Name: NginxIngressClass}: {ProxyProtocol: true},
+			},
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:        "disabled",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/use-proxy-protocol": "false"},
+		},
+		{
+			name:                 "not a boolean",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/use-proxy-protocol": "yes please"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = proxyProtocolFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			if diff := cmp.Diff(tc.expectedPolicies, gatewayResources.GatewayPolicies); diff != "" {
+				t.Errorf("Unexpected Gateway policies (-want +got):\n%s", diff)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedNotification == "" {
+				if len(gotNotifications) != 0 {
+					t.Fatalf("Expected no notifications, got %+v", gotNotifications)
+				}
+				return
+			}
+			if len(gotNotifications) != 1 {
+				t.Fatalf("Expected 1 notification, got %+v", gotNotifications)
+			}
+			if gotNotifications[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a %s notification, got %+v", tc.expectedNotification, gotNotifications[0])
+			}
+			if tc.expectedNotification == notifications.WarningNotification && !strings.Contains(gotNotifications[0].Message, "default/"+NginxIngressClass) {
+				t.Errorf("Expected the notification to name the Gateway, got %q", gotNotifications[0].Message)
+			}
+		})
+	}
+}
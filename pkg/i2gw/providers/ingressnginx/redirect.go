@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// redirectFeature converts the `nginx.ingress.kubernetes.io/temporal-redirect`
+// and `nginx.ingress.kubernetes.io/permanent-redirect` annotations to a
+// RequestRedirect filter on the HTTPRoute rules generated from the Ingress paths.
+//
+// The scheme, hostname, port and path of the redirect URL are set on the filter.
+// The port is only set when the URL has an explicit one, so that the
+// implementation uses the default port of the scheme otherwise. As in
+// ingress-nginx, the temporal redirect takes precedence and uses the 302 status
+// code, while the permanent redirect uses the `permanent-redirect-code`, 301 by
+// default. The 307 and 308 codes are not supported by RequestRedirect, and are
+// converted to 302 and 301 with a Warning. As the redirected requests never reach the backends, the backendRefs
+// of the rules are removed.
+func redirectFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			annotation, filter, warning, err := redirectFilter(ingress.Annotations)
+			if err != nil {
+				notify(notifications.ErrorNotification, fmt.Sprintf("%v, no redirect was generated in HTTPRoute %s/%s", err, httpRoute.Namespace, httpRoute.Name), &ingress)
+				continue
+			}
+			if filter == nil {
+				continue
+			}
+			if warning != "" {
+				notify(notifications.WarningNotification, fmt.Sprintf("%s in HTTPRoute %s/%s", warning, httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+			for i := range httpRoute.Spec.Rules {
+				if !ruleMatchesAnyPath(httpRoute.Spec.Rules[i], rule.IngressRule.HTTP.Paths) {
+					continue
+				}
+				httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, gatewayv1.HTTPRouteFilter{
+					Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: filter.DeepCopy(),
+				})
+				httpRoute.Spec.Rules[i].BackendRefs = nil
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, annotation)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// methodPreservingRedirectCodes are the redirect status codes preserving the
+// request method, which RequestRedirect does not support, by the code of the
+// same permanence they are converted to.
+var methodPreservingRedirectCodes = map[int]int{
+	307: 302,
+	308: 301,
+}
+
+// redirectFilter returns the redirect filter of the annotations, along with the
+// annotation it was built from, or nil if the Ingress has no redirect. A
+// warning is returned when the redirect status code is not the requested one.
+func redirectFilter(annotations map[string]string) (string, *gatewayv1.HTTPRequestRedirectFilter, string, error) {
+	var warning string
+	annotation, statusCode := nginxAnnotation(temporalRedirectKey), 302
+	target := annotations[annotation]
+	if target == "" {
+		annotation, statusCode = nginxAnnotation(permanentRedirectKey), 301
+		target = annotations[annotation]
+		if target == "" {
+			return "", nil, "", nil
+		}
+		if code := annotations[nginxAnnotation(permanentRedirectCodeKey)]; code != "" {
+			parsed, err := strconv.Atoi(code)
+			if converted, ok := methodPreservingRedirectCodes[parsed]; err == nil && ok {
+				warning = fmt.Sprintf("%s %d is not supported, as RequestRedirect only allows 301 and 302, the redirect uses %d instead, which lets clients change the request method to GET", nginxAnnotation(permanentRedirectCodeKey), parsed, converted)
+				parsed = converted
+			}
+			if err != nil || (parsed != 301 && parsed != 302) {
+				return "", nil, "", fmt.Errorf("%s %q is not supported, only 301 and 302 are", nginxAnnotation(permanentRedirectCodeKey), code)
+			}
+			statusCode = parsed
+		}
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", nil, "", fmt.Errorf("%s %q is not an absolute http or https URL", annotation, target)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", nil, "", fmt.Errorf("%s %q is not supported, as RequestRedirect cannot set a query or a fragment", annotation, target)
+	}
+
+	filter := &gatewayv1.HTTPRequestRedirectFilter{
+		Scheme:     common.PtrTo(u.Scheme),
+		Hostname:   common.PtrTo(gatewayv1.PreciseHostname(u.Hostname())),
+		StatusCode: common.PtrTo(statusCode),
+	}
+	if p := u.Port(); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return "", nil, "", fmt.Errorf("%s %q has an invalid port", annotation, target)
+		}
+		filter.Port = common.PtrTo(gatewayv1.PortNumber(port))
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	filter.Path = &gatewayv1.HTTPPathModifier{
+		Type:            gatewayv1.FullPathHTTPPathModifier,
+		ReplaceFullPath: common.PtrTo(path),
+	}
+	return annotation, filter, warning, nil
+}
+
+func ruleMatchesAnyPath(rule gatewayv1.HTTPRouteRule, paths []networkingv1.HTTPIngressPath) bool {
+	for _, path := range paths {
+		if ruleMatchesPath(rule, path.Path) {
+			return true
+		}
+	}
+	return false
+}
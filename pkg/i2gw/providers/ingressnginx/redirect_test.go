@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_redirectFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedFilters      []gatewayv1.HTTPRouteFilter
+		expectedNotification notifications.MessageType
+	}{
+		{
+			name: "no redirect",
+		},
+		{
+			name:        "permanent redirect with default port",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("www.example.com")),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+					StatusCode: ptr.To(301),
+				},
+			}},
+		},
+		{
+			name: "permanent redirect with custom port and code",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/permanent-redirect":      "https://www.example.com:8443/new",
+				"nginx.ingress.kubernetes.io/permanent-redirect-code": "302",
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("www.example.com")),
+					Port:       ptr.To(gatewayv1.PortNumber(8443)),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/new")},
+					StatusCode: ptr.To(302),
+				},
+			}},
+		},
+		{
+			name: "temporal redirect takes precedence",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/permanent-redirect": "https://permanent.example.com",
+				"nginx.ingress.kubernetes.io/temporal-redirect":  "http://temporal.example.com:8080/",
+			},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("http"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("temporal.example.com")),
+					Port:       ptr.To(gatewayv1.PortNumber(8080)),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+					StatusCode: ptr.To(302),
+				},
+			}},
+		},
+		{
+			name:        "permanent redirect with explicit 301 code",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com", "nginx.ingress.kubernetes.io/permanent-redirect-code": "301"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("www.example.com")),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+					StatusCode: ptr.To(301),
+				},
+			}},
+		},
+		{
+			name:        "method preserving permanent redirect code",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com", "nginx.ingress.kubernetes.io/permanent-redirect-code": "308"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("www.example.com")),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+					StatusCode: ptr.To(301),
+				},
+			}},
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:        "method preserving temporary redirect code",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com", "nginx.ingress.kubernetes.io/permanent-redirect-code": "307"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("www.example.com")),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")},
+					StatusCode: ptr.To(302),
+				},
+			}},
+			expectedNotification: notifications.WarningNotification,
+		},
+		{
+			name:                 "unsupported redirect code",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com", "nginx.ingress.kubernetes.io/permanent-redirect-code": "303"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+		{
+			name:                 "relative redirect",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "/new"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+		{
+			name:                 "invalid port",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/permanent-redirect": "https://www.example.com:70000"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = redirectFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+			if hasRedirect := len(tc.expectedFilters) > 0; hasRedirect != (len(rule.BackendRefs) == 0) {
+				t.Errorf("Expected backendRefs to be removed: %v, got %+v", hasRedirect, rule.BackendRefs)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedNotification == "" {
+				if len(gotNotifications) != 0 {
+					t.Errorf("Expected no notifications, got %+v", gotNotifications)
+				}
+			} else if len(gotNotifications) != 1 || gotNotifications[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a single %s notification, got %+v", tc.expectedNotification, gotNotifications)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var (
+	// strippedPrefixPathRegexp matches the paths capturing the rest of the
+	// request path after a literal prefix, like `/app(/|$)(.*)`.
+	strippedPrefixPathRegexp = regexp.MustCompile(`^(/[A-Za-z0-9\-._~%!&',;=:@/]*?)\(/\|\$\)\(\.\*\)$`)
+	// literalPathRegexp matches the paths without regular expression characters.
+	literalPathRegexp = regexp.MustCompile(`^/[A-Za-z0-9\-._~%!&',;=:@/]*$`)
+	// nginxVariableRegexp matches the nginx variables, like `$host`, but not the
+	// `$N` groups captured by the path.
+	nginxVariableRegexp = regexp.MustCompile(`\$\{?[A-Za-z_][A-Za-z0-9_]*\}?`)
+)
+
+// passthroughRewriteTargets are the rewrite targets made of nginx variables
+// holding the request URI, which leave the request path unchanged.
+var passthroughRewriteTargets = []string{"$request_uri", "$uri", "$uri$is_args$args"}
+
+// rewriteFeature converts the `nginx.ingress.kubernetes.io/rewrite-target`
+// annotation to a URLRewrite filter on the HTTPRoute rules generated from the
+// Ingress paths, and the `nginx.ingress.kubernetes.io/x-forwarded-prefix`
+// annotation to a RequestHeaderModifier filter setting the X-Forwarded-Prefix
+// header.
+//
+// ingress-nginx replaces the whole request path with the rewrite target, whose
+// `$N` placeholders are the groups captured by the path. Two forms have a
+// Gateway API equivalent:
+//   - A literal target without placeholders, like `/`, becomes a ReplaceFullPath
+//     modifier.
+//   - A path capturing the rest of the request path after a prefix, like
+//     `/app(/|$)(.*)`, with a target ending with `/$2`, like `/$2` or `/v1/$2`,
+//     strips the prefix: the path becomes a PathPrefix match on `/app` and the
+//     target a ReplacePrefixMatch modifier on `/` or `/v1`. The target `/` of
+//     such a path is converted the same way, as it is meant to strip the
+//     prefix, and a Warning notification is emitted, as ingress-nginx rewrites
+//     all the requests to `/` instead.
+//
+// The targets passing the request URI through unchanged, like `$request_uri`
+// or `$uri`, are no-ops, for which no URLRewrite filter is generated. The
+// other nginx variables, like `$host`, cannot be represented, and a Warning
+// notification is emitted for the targets using them.
+//
+// The other rewrites cannot be converted, and an Error notification is emitted
+// for them. The X-Forwarded-Prefix header is set to the value of the annotation,
+// typically the stripped prefix, so that the backends can still generate
+// absolute URLs. It is set whether or not the path is rewritten, as Ingresses
+// also use it alone to tell the backends their mount point.
+func rewriteFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			target, forwardedPrefix := ingress.Annotations[nginxAnnotation(rewriteTargetKey)], ingress.Annotations[nginxAnnotation(xForwardedPrefixKey)]
+			if rule.IngressRule.HTTP == nil || (target == "" && forwardedPrefix == "") {
+				continue
+			}
+			if isGRPCBackend(ingress) {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %s and %s annotations are not converted for gRPC backends", nginxAnnotation(rewriteTargetKey), nginxAnnotation(xForwardedPrefixKey)), &ingress)
+				continue
+			}
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				matchPath := path.Path
+				var filters []gatewayv1.HTTPRouteFilter
+				var annotations []string
+				variables := nginxVariableRegexp.FindAllString(target, -1)
+				switch {
+				case target == "" || slices.Contains(passthroughRewriteTargets, target):
+				case len(variables) > 0:
+					notify(notifications.WarningNotification, fmt.Sprintf("%s %q uses the nginx variables %s, which cannot be represented, the path %q is not rewritten in HTTPRoute %s/%s", nginxAnnotation(rewriteTargetKey), target, strings.Join(variables, ", "), path.Path, httpRoute.Namespace, httpRoute.Name), &ingress)
+				default:
+					rewritePath, modifier, err := toURLRewritePath(path, target)
+					if err != nil {
+						notify(notifications.ErrorNotification, fmt.Sprintf("%v, the path %q is not rewritten in HTTPRoute %s/%s", err, path.Path, httpRoute.Namespace, httpRoute.Name), &ingress)
+					} else {
+						if isRootStripRewrite(path, target) {
+							notify(notifications.WarningNotification, fmt.Sprintf("%s %q of the path %q strips the prefix %q in HTTPRoute %s/%s, e.g. %s/foo is rewritten to /foo, while ingress-nginx rewrites all of them to /, set the target to /$2 for ingress-nginx to strip the prefix too", nginxAnnotation(rewriteTargetKey), target, path.Path, rewritePath, httpRoute.Namespace, httpRoute.Name, strings.TrimSuffix(rewritePath, "/")), &ingress)
+						}
+						if rewritePath != path.Path {
+							common.PatchHTTPRoutePathPrefix(&httpRoute, path.Path, rewritePath)
+							matchPath = rewritePath
+						}
+						filters = append(filters, gatewayv1.HTTPRouteFilter{
+							Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+							URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: modifier},
+						})
+						annotations = append(annotations, nginxAnnotation(rewriteTargetKey))
+					}
+				}
+				if forwardedPrefix != "" {
+					filters = append(filters, gatewayv1.HTTPRouteFilter{
+						Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+							Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: forwardedPrefix}},
+						},
+					})
+					annotations = append(annotations, nginxAnnotation(xForwardedPrefixKey))
+				}
+				if len(filters) == 0 {
+					continue
+				}
+				for i := range httpRoute.Spec.Rules {
+					if !ruleMatchesPath(httpRoute.Spec.Rules[i], matchPath) {
+						continue
+					}
+					for _, filter := range filters {
+						httpRoute.Spec.Rules[i].Filters = append(httpRoute.Spec.Rules[i].Filters, *filter.DeepCopy())
+					}
+					for _, annotation := range annotations {
+						common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, annotation)
+					}
+					if matchPath != path.Path {
+						common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].matches", i), &ingress, nginxAnnotation(rewriteTargetKey))
+					}
+				}
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// toURLRewritePath returns the path to match and the path modifier equivalent to
+// rewriting the Ingress path to the target.
+func toURLRewritePath(path networkingv1.HTTPIngressPath, target string) (string, *gatewayv1.HTTPPathModifier, error) {
+	if !strings.HasPrefix(target, "/") {
+		return "", nil, fmt.Errorf("%s %q is not an absolute path", nginxAnnotation(rewriteTargetKey), target)
+	}
+	if isRootStripRewrite(path, target) {
+		return strippedPrefixPathRegexp.FindStringSubmatch(path.Path)[1], common.StripPathPrefix.PathModifier(gatewayv1.PathMatchPathPrefix), nil
+	}
+	if !strings.Contains(target, "$") {
+		if !literalPathRegexp.MatchString(path.Path) {
+			return "", nil, fmt.Errorf("%s %q of the regular expression path %q is not supported", nginxAnnotation(rewriteTargetKey), target, path.Path)
+		}
+		return path.Path, &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: common.PtrTo(target),
+		}, nil
+	}
+
+	prefix := strippedPrefixPathRegexp.FindStringSubmatch(path.Path)
+	replacement, found := strings.CutSuffix(target, "/$2")
+	if prefix == nil || !found || strings.Contains(replacement, "$") || (path.PathType != nil && *path.PathType != networkingv1.PathTypePrefix) {
+		return "", nil, fmt.Errorf("%s %q is not supported for the path %q, only the targets ending with `/$2` of Prefix paths like `/app(/|$)(.*)` are", nginxAnnotation(rewriteTargetKey), target, path.Path)
+	}
+	rewrite := common.StripPathPrefix
+	if replacement != "" {
+		rewrite = common.PathPrefixRewrite{Replacement: replacement}
+	}
+	return prefix[1], rewrite.PathModifier(gatewayv1.PathMatchPathPrefix), nil
+}
+
+// isRootStripRewrite returns whether the rewrite is the target `/` of a Prefix
+// path capturing the rest of the request path, like `/api(/|$)(.*)`, meant to
+// strip the prefix.
+func isRootStripRewrite(path networkingv1.HTTPIngressPath, target string) bool {
+	return target == "/" && strippedPrefixPathRegexp.MatchString(path.Path) && (path.PathType == nil || *path.PathType == networkingv1.PathTypePrefix)
+}
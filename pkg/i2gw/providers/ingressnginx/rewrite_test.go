@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_rewriteFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		path                 string
+		annotations          map[string]string
+		expectedPath         string
+		expectedFilters      []gatewayv1.HTTPRouteFilter
+		expectedNotification bool
+	}{
+		{
+			name:         "no rewrite",
+			path:         "/foo",
+			expectedPath: "/foo",
+		},
+		{
+			name:         "literal target",
+			path:         "/foo",
+			annotations:  map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+			expectedPath: "/foo",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/")}},
+			}},
+		},
+		{
+			name:         "stripped prefix",
+			path:         "/foo(/|$)(.*)",
+			annotations:  map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/v1/$2"},
+			expectedPath: "/foo",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To("/v1")}},
+			}},
+		},
+		{
+			name: "stripped prefix with forwarded prefix",
+			path: "/foo(/|$)(.*)",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target":     "/$2",
+				"nginx.ingress.kubernetes.io/x-forwarded-prefix": "/foo",
+			},
+			expectedPath: "/foo",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{
+				{
+					Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To("/")}},
+				},
+				{
+					Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: "/foo"}}},
+				},
+			},
+		},
+		{
+			name:         "standalone forwarded prefix",
+			path:         "/foo",
+			annotations:  map[string]string{"nginx.ingress.kubernetes.io/x-forwarded-prefix": "/mount"},
+			expectedPath: "/foo",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: "/mount"}}},
+			}},
+		},
+		{
+			name:         "prefix stripped to the root",
+			path:         "/api(/|$)(.*)",
+			annotations:  map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+			expectedPath: "/api",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{Type: gatewayv1.PrefixMatchHTTPPathModifier, ReplacePrefixMatch: ptr.To("/")}},
+			}},
+			expectedNotification: true,
+		},
+		{
+			name:                 "unsupported capture group",
+			path:                 "/foo/(.*)/bar",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/$1"},
+			expectedPath:         "/foo/(.*)/bar",
+			expectedNotification: true,
+		},
+		{
+			name:                 "literal target of a regular expression path",
+			path:                 "/foo/.+",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+			expectedPath:         "/foo/.+",
+			expectedNotification: true,
+		},
+		{
+			name:         "request URI passthrough",
+			path:         "/foo",
+			annotations:  map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "$request_uri"},
+			expectedPath: "/foo",
+		},
+		{
+			name: "URI passthrough with forwarded prefix",
+			path: "/foo",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/rewrite-target":     "$uri",
+				"nginx.ingress.kubernetes.io/x-forwarded-prefix": "/foo",
+			},
+			expectedPath: "/foo",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: "/foo"}}},
+			}},
+		},
+		{
+			name:                 "host variable",
+			path:                 "/foo(/|$)(.*)",
+			annotations:          map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/$host/$2"},
+			expectedPath:         "/foo(/|$)(.*)",
+			expectedNotification: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: tc.annotations},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     tc.path,
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			ingresses := []networkingv1.Ingress{ingress}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = rewriteFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0]
+			if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+			if got := *rule.Matches[0].Path.Value; got != tc.expectedPath {
+				t.Errorf("Expected path %q, got %q", tc.expectedPath, got)
+			}
+
+			gotNotification := len(notifications.NotificationAggr.Notifications[Name]) > 0
+			if gotNotification != tc.expectedNotification {
+				t.Errorf("Expected notification: %v, got %+v", tc.expectedNotification, notifications.NotificationAggr.Notifications[Name])
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var (
+	// serverNameRegexp matches the server_name directives of an nginx snippet.
+	serverNameRegexp = regexp.MustCompile(`\bserver_name\s+([^;]*);`)
+
+	// wildcardHostRegexp matches the regex server names that are equivalent to a
+	// Gateway API wildcard hostname, like `~^.*\.example\.com$` or
+	// `~^(?<subdomain>.+)\.example\.com$`, capturing the escaped domain suffix.
+	wildcardHostRegexp = regexp.MustCompile(`^~\^(?:\.[*+]|\((?:\?P?<\w+>)?\.[*+]\))\\\.((?:[A-Za-z0-9-]+\\\.)*[A-Za-z0-9-]+)\$$`)
+
+	// locationRegexp matches the start of the location blocks of an nginx
+	// snippet, capturing the modifier and the URI.
+	locationRegexp = regexp.MustCompile(`\blocation\s+(?:(=|~\*|~|\^~)\s*)?([^\s{]+)\s*\{`)
+)
+
+// regexHostFeature converts the regex server names declared with a `server_name`
+// directive in the `nginx.ingress.kubernetes.io/server-snippet` annotation.
+//
+// Gateway API hostnames only support a wildcard as the first label, so a regex
+// server name is only converted when it matches any subdomain of a fixed domain,
+// like `~^.*\.example\.com$`, which becomes `*.example.com`. The wildcard hostname
+// is added to the HTTPRoutes generated for the hosts of the Ingress, along with a
+// matching Gateway listener. Any other regex server name produces an Error
+// notification, as it cannot be represented.
+func regexHostFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || rg.Host == "" {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			for _, serverName := range regexServerNames(ingress.Annotations[nginxAnnotation(serverSnippetKey)]) {
+				hostname, ok := toWildcardHostname(serverName)
+				if !ok {
+					notify(notifications.ErrorNotification, fmt.Sprintf("regex server_name %q in the %s annotation cannot be represented as a Gateway API hostname, which only supports a wildcard as the first label: it is not converted to HTTPRoute %s/%s", serverName, nginxAnnotation(serverSnippetKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+				if !addRouteHostname(&httpRoute, hostname) {
+					continue
+				}
+				addWildcardListeners(gatewayResources, types.NamespacedName{Namespace: rg.Namespace, Name: rg.IngressClass}, rg.Host, hostname)
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, "spec.hostnames", &ingress, nginxAnnotation(serverSnippetKey))
+				notify(notifications.InfoNotification, fmt.Sprintf("regex server_name %q in the %s annotation was converted to hostname %q of HTTPRoute %s/%s", serverName, nginxAnnotation(serverSnippetKey), hostname, httpRoute.Namespace, httpRoute.Name), &ingress)
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// serverSnippetLocationFeature converts the `location` blocks declared in the
+// `nginx.ingress.kubernetes.io/server-snippet` annotation, which route the
+// requests of their path next to the paths of the Ingress.
+//
+// Only the prefix and exact locations with a single proxy_pass directive to a
+// cluster-local Service, like `location /api { proxy_pass http://api:8080; }`,
+// are converted, to an additional rule of the HTTPRoutes generated for the hosts
+// of the Ingress. As the rule is inferred from a snippet, a Warning notification
+// is always emitted. Any other location, like a regex location, a location with
+// other directives or nested blocks, produces a Warning notification and is left
+// to manual migration.
+func serverSnippetLocationFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			for _, location := range parseLocations(ingress.Annotations[nginxAnnotation(serverSnippetKey)]) {
+				hrRule, backend, err := location.toHTTPRouteRule(ingress.Namespace)
+				if err != nil {
+					notify(notifications.WarningNotification, fmt.Sprintf("%v, location %q of the %s annotation was not converted to HTTPRoute %s/%s and must be migrated manually", err, location.uri, nginxAnnotation(serverSnippetKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+				if i := slices.IndexFunc(httpRoute.Spec.Rules, func(existing gatewayv1.HTTPRouteRule) bool {
+					return apiequality.Semantic.DeepEqual(existing.Matches, hrRule.Matches)
+				}); i >= 0 {
+					if !apiequality.Semantic.DeepEqual(httpRoute.Spec.Rules[i].BackendRefs, hrRule.BackendRefs) {
+						notify(notifications.WarningNotification, fmt.Sprintf("location %q of the %s annotation was not converted, as HTTPRoute %s/%s already has a rule matching its path", location.uri, nginxAnnotation(serverSnippetKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+					}
+					continue
+				}
+
+				httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, hrRule)
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d]", len(httpRoute.Spec.Rules)-1), &ingress, nginxAnnotation(serverSnippetKey))
+				notify(notifications.WarningNotification, fmt.Sprintf("location %q of the %s annotation was converted to a rule of HTTPRoute %s/%s sending its requests to Service %s port %d, as inferred from the snippet. Unlike nginx, a prefix match only matches whole path segments",
+					location.uri, nginxAnnotation(serverSnippetKey), httpRoute.Namespace, httpRoute.Name, backend.NamespacedName, backend.port), &ingress)
+				if backend.Namespace != ingress.Namespace {
+					common.AddServiceReferenceGrant(gatewayResources, common.HTTPRouteGVK.Kind, ingress.Namespace, backend.NamespacedName)
+				}
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return nil
+}
+
+// snippetLocation is a location block of an nginx snippet.
+type snippetLocation struct {
+	modifier string
+	uri      string
+	// body is the content of the block, between the braces, or nil if the
+	// block is not closed.
+	body *string
+}
+
+// parseLocations returns the outermost location blocks of the snippet, the
+// nested ones being part of their body.
+func parseLocations(snippet string) []snippetLocation {
+	var locations []snippetLocation
+	end := 0
+	for _, match := range locationRegexp.FindAllStringSubmatchIndex(snippet, -1) {
+		if match[0] < end {
+			continue
+		}
+		location := snippetLocation{uri: snippet[match[4]:match[5]]}
+		if match[2] >= 0 {
+			location.modifier = snippet[match[2]:match[3]]
+		}
+		end = len(snippet)
+		depth := 1
+		for i := match[1]; i < len(snippet) && location.body == nil; i++ {
+			switch snippet[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if depth == 0 {
+				body := snippet[match[1]:i]
+				location.body, end = &body, i+1
+			}
+		}
+		locations = append(locations, location)
+	}
+	return locations
+}
+
+// toHTTPRouteRule returns the HTTPRoute rule equivalent to the location, and
+// the Service it sends the requests to, or an error if the location is not
+// supported.
+func (l snippetLocation) toHTTPRouteRule(namespace string) (gatewayv1.HTTPRouteRule, *snippetBackend, error) {
+	var pathType gatewayv1.PathMatchType
+	switch l.modifier {
+	case "", "^~":
+		pathType = gatewayv1.PathMatchPathPrefix
+	case "=":
+		pathType = gatewayv1.PathMatchExact
+	default:
+		return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("regex locations are not supported")
+	}
+	if !strings.HasPrefix(l.uri, "/") {
+		return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("only the locations of a path are supported")
+	}
+	if l.body == nil {
+		return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("the location block is not closed")
+	}
+	if strings.ContainsAny(*l.body, "{}") {
+		return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("nested blocks are not supported")
+	}
+	for _, directive := range strings.Split(*l.body, ";") {
+		if fields := strings.Fields(directive); len(fields) > 0 && fields[0] != "proxy_pass" {
+			return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("directive %s is not supported, only a single proxy_pass is", fields[0])
+		}
+	}
+	backend, err := parseProxyPass(*l.body, namespace)
+	if err != nil {
+		return gatewayv1.HTTPRouteRule{}, nil, err
+	}
+	if backend == nil {
+		return gatewayv1.HTTPRouteRule{}, nil, fmt.Errorf("the location has no proxy_pass directive")
+	}
+
+	backendRef := gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+		Name: gatewayv1.ObjectName(backend.Name),
+		Port: common.PtrTo(gatewayv1.PortNumber(backend.port)),
+	}}
+	if backend.Namespace != namespace {
+		backendRef.Namespace = common.PtrTo(gatewayv1.Namespace(backend.Namespace))
+	}
+	return gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: common.PtrTo(pathType), Value: common.PtrTo(l.uri)},
+		}},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}},
+	}, backend, nil
+}
+
+// regexServerNames returns the regex names, prefixed by `~`, of the server_name
+// directives of the snippet.
+func regexServerNames(snippet string) []string {
+	var serverNames []string
+	for _, directive := range serverNameRegexp.FindAllStringSubmatch(snippet, -1) {
+		for _, name := range strings.Fields(directive[1]) {
+			name = strings.Trim(name, `"'`)
+			if strings.HasPrefix(name, "~") {
+				serverNames = append(serverNames, name)
+			}
+		}
+	}
+	return serverNames
+}
+
+// toWildcardHostname returns the wildcard hostname equivalent to the regex server
+// name, and whether there is one.
+func toWildcardHostname(serverName string) (gatewayv1.Hostname, bool) {
+	match := wildcardHostRegexp.FindStringSubmatch(serverName)
+	if match == nil {
+		return "", false
+	}
+	hostname := "*." + strings.ToLower(strings.ReplaceAll(match[1], `\.`, "."))
+	if len(validation.IsWildcardDNS1123Subdomain(hostname)) > 0 {
+		return "", false
+	}
+	return gatewayv1.Hostname(hostname), true
+}
+
+// addRouteHostname adds the hostname to the HTTPRoute. It returns whether the
+// hostname was added.
+func addRouteHostname(httpRoute *gatewayv1.HTTPRoute, hostname gatewayv1.Hostname) bool {
+	for _, h := range httpRoute.Spec.Hostnames {
+		if h == hostname {
+			return false
+		}
+	}
+	httpRoute.Spec.Hostnames = append(httpRoute.Spec.Hostnames, hostname)
+	return true
+}
+
+// addWildcardListeners adds to the Gateway a copy of the listeners of the host,
+// with the wildcard hostname.
+func addWildcardListeners(gatewayResources *i2gw.GatewayResources, gatewayKey types.NamespacedName, host string, hostname gatewayv1.Hostname) {
+	gateway, ok := gatewayResources.Gateways[gatewayKey]
+	if !ok {
+		return
+	}
+	var listeners []gatewayv1.Listener
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname == nil || string(*listener.Hostname) != host || hasListener(gateway, hostname, listener.Port) {
+			continue
+		}
+		wildcardListener := *listener.DeepCopy()
+		wildcardListener.Name = gatewayv1.SectionName(fmt.Sprintf("wildcard-%s-%s", common.NameFromHost(string(hostname)), strings.ToLower(string(listener.Protocol))))
+		wildcardListener.Hostname = common.PtrTo(hostname)
+		listeners = append(listeners, wildcardListener)
+	}
+	gateway.Spec.Listeners = append(gateway.Spec.Listeners, listeners...)
+	gatewayResources.Gateways[gatewayKey] = gateway
+}
+
+func hasListener(gateway gatewayv1.Gateway, hostname gatewayv1.Hostname, port gatewayv1.PortNumber) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname != nil && *listener.Hostname == hostname && listener.Port == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_toWildcardHostname(t *testing.T) {
+	testCases := []struct {
+		name             string
+		serverName       string
+		expectedHostname gatewayv1.Hostname
+		expectedOK       bool
+	}{
+		{
+			name:             "any subdomain",
+			serverName:       `~^.*\.example\.com$`,
+			expectedHostname: "*.example.com",
+			expectedOK:       true,
+		},
+		{
+			name:             "non-empty subdomain",
+			serverName:       `~^.+\.example\.com$`,
+			expectedHostname: "*.example.com",
+			expectedOK:       true,
+		},
+		{
+			name:             "named capture of the subdomain",
+			serverName:       `~^(?<subdomain>.+)\.Example\.com$`,
+			expectedHostname: "*.example.com",
+			expectedOK:       true,
+		},
+		{
+			name:       "alternation",
+			serverName: `~^(www|api)\.example\.com$`,
+		},
+		{
+			name:       "partial label",
+			serverName: `~^app-\d+\.example\.com$`,
+		},
+		{
+			name:       "unanchored",
+			serverName: `~.*\.example\.com`,
+		},
+		{
+			name:       "unescaped dot",
+			serverName: `~^.*\.example.com$`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hostname, ok := toWildcardHostname(tc.serverName)
+			if ok != tc.expectedOK || hostname != tc.expectedHostname {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tc.expectedHostname, tc.expectedOK, hostname, ok)
+			}
+		})
+	}
+}
+
+func Test_regexHostFeature(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		snippet              string
+		expectedHostnames    []gatewayv1.Hostname
+		expectedListeners    []gatewayv1.SectionName
+		expectedNotification notifications.MessageType
+	}{
+		{
+			name:                 "regex reducible to a wildcard",
+			snippet:              `server_name ~^.*\.example\.com$;`,
+			expectedHostnames:    []gatewayv1.Hostname{"foo.example.com", "*.example.com"},
+			expectedListeners:    []gatewayv1.SectionName{"foo-example-com-http", "foo-example-com-https", "wildcard-example-com-http", "wildcard-example-com-https"},
+			expectedNotification: notifications.InfoNotification,
+		},
+		{
+			name:                 "regex that cannot be represented",
+			snippet:              `server_name "~^(www|api)\d*\.example\.com$";`,
+			expectedHostnames:    []gatewayv1.Hostname{"foo.example.com"},
+			expectedListeners:    []gatewayv1.SectionName{"foo-example-com-http", "foo-example-com-https"},
+			expectedNotification: notifications.ErrorNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "regex",
+					Namespace:   "default",
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/server-snippet": tc.snippet},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					TLS:              []networkingv1.IngressTLS{{Hosts: []string{"foo.example.com"}, SecretName: "cert"}},
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "service",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = regexHostFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "regex-foo-example-com"}]
+			if diff := cmp.Diff(tc.expectedHostnames, httpRoute.Spec.Hostnames); diff != "" {
+				t.Errorf("Unexpected HTTPRoute hostnames (-want +got):\n%s", diff)
+			}
+
+			var gotListeners []gatewayv1.SectionName
+			for _, listener := range gatewayResources.Gateways[types.NamespacedName{Namespace: "default", Name: NginxIngressClass}].Spec.Listeners {
+				gotListeners = append(gotListeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, gotListeners); diff != "" {
+				t.Errorf("Unexpected Gateway listeners (-want +got):\n%s", diff)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(gotNotifications) != 1 || gotNotifications[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a single %s notification, got %+v", tc.expectedNotification, gotNotifications)
+			}
+		})
+	}
+}
+
+func Test_serverSnippetLocationFeature(t *testing.T) {
+	ingressRule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{{
+			Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/")},
+		}},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "service", Port: ptr.To(gatewayv1.PortNumber(80))}},
+		}},
+	}
+	locationRule := func(pathType gatewayv1.PathMatchType, path, service string, namespace *gatewayv1.Namespace, port int32) gatewayv1.HTTPRouteRule {
+		return gatewayv1.HTTPRouteRule{
+			Matches: []gatewayv1.HTTPRouteMatch{{
+				Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(path)},
+			}},
+			BackendRefs: []gatewayv1.HTTPBackendRef{{
+				BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service), Namespace: namespace, Port: ptr.To(gatewayv1.PortNumber(port))}},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name                  string
+		snippet               string
+		expectedRules         []gatewayv1.HTTPRouteRule
+		expectedNotifications int
+		expectedGrants        int
+	}{
+		{
+			name:                  "simple location",
+			snippet:               "location /api {\n  proxy_pass http://api:8080;\n}",
+			expectedRules:         []gatewayv1.HTTPRouteRule{ingressRule, locationRule(gatewayv1.PathMatchPathPrefix, "/api", "api", nil, 8080)},
+			expectedNotifications: 1,
+		},
+		{
+			name:    "exact location to another namespace, next to other directives",
+			snippet: "add_header X-Frame-Options DENY;\nlocation = /healthz { proxy_pass http://health.monitoring.svc; }",
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				ingressRule,
+				locationRule(gatewayv1.PathMatchExact, "/healthz", "health", ptr.To(gatewayv1.Namespace("monitoring")), 80),
+			},
+			expectedNotifications: 1,
+			expectedGrants:        1,
+		},
+		{
+			name:                  "location with other directives",
+			snippet:               "location /api { proxy_set_header X-Api true; proxy_pass http://api; }",
+			expectedRules:         []gatewayv1.HTTPRouteRule{ingressRule},
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "regex and nested locations",
+			snippet:               "location ~* \\.php$ { proxy_pass http://php; }\nlocation /a { location /a/b { proxy_pass http://b; } }",
+			expectedRules:         []gatewayv1.HTTPRouteRule{ingressRule},
+			expectedNotifications: 2,
+		},
+		{
+			name:                  "location to an external host",
+			snippet:               "location /ext { proxy_pass http://example.com; }",
+			expectedRules:         []gatewayv1.HTTPRouteRule{ingressRule},
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "location of a path of the Ingress",
+			snippet:               "location / { proxy_pass http://other; }",
+			expectedRules:         []gatewayv1.HTTPRouteRule{ingressRule},
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "snippet",
+					Namespace:   "default",
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/server-snippet": tc.snippet},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptr.To(NginxIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "foo.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "service",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if errs = serverSnippetLocationFeature(ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "snippet-foo-example-com"}]
+			if diff := cmp.Diff(tc.expectedRules, httpRoute.Spec.Rules); diff != "" {
+				t.Errorf("Unexpected HTTPRoute rules (-want +got):\n%s", diff)
+			}
+			if len(gatewayResources.ReferenceGrants) != tc.expectedGrants {
+				t.Errorf("Expected %d ReferenceGrants, got %+v", tc.expectedGrants, gatewayResources.ReferenceGrants)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if len(gotNotifications) != tc.expectedNotifications {
+				t.Fatalf("Expected %d notifications, got %+v", tc.expectedNotifications, gotNotifications)
+			}
+			for _, notification := range gotNotifications {
+				if notification.Type != notifications.WarningNotification {
+					t.Errorf("Expected a Warning notification, got %+v", notification)
+				}
+			}
+		})
+	}
+}
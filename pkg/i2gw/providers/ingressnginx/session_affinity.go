@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// sessionAffinityAnnotationKeys are the cookie session affinity annotations,
+// in the order they are reported.
+var sessionAffinityAnnotationKeys = []string{
+	affinityKey,
+	affinityModeKey,
+	affinityCanaryBehaviorKey,
+	sessionCookieNameKey,
+	sessionCookiePathKey,
+	sessionCookieExpiresKey,
+	sessionCookieMaxAgeKey,
+	sessionCookieSecureKey,
+	sessionCookieSameSiteKey,
+	sessionCookieChangeOnFailureKey,
+}
+
+// sessionAffinityFeature reports the cookie session affinity of the Ingresses
+// annotated with `nginx.ingress.kubernetes.io/affinity: cookie`.
+//
+// The Gateway API version the resources are generated for has no session
+// persistence, which HTTPRoute rules support from v1.1 with sessionPersistence,
+// so a Warning notification is emitted per Ingress with the affinity settings,
+// to configure them with the Gateway implementation instead.
+//
+// ingress-nginx keeps a client affined to the canary backend it was first sent
+// to, unless `affinity-canary-behavior: legacy` is set. When the HTTPRoute of
+// the Ingress splits its traffic between weighted canary backends, the weights
+// are kept, and an Info notification is emitted, as whether session persistence
+// sticks a client to the weighted backend it first hit depends on the
+// implementation.
+func sessionAffinityFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	affinityIngresses := map[types.NamespacedName]bool{}
+	for _, ingress := range ingresses {
+		if !strings.EqualFold(strings.TrimSpace(ingress.Annotations[nginxAnnotation(affinityKey)]), "cookie") {
+			continue
+		}
+		affinityIngresses[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = true
+
+		var settings []string
+		for _, key := range sessionAffinityAnnotationKeys {
+			value, ok := ingress.Annotations[nginxAnnotation(key)]
+			if !ok {
+				continue
+			}
+			settings = append(settings, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+		}
+		ingress := ingress
+		notify(notifications.WarningNotification, fmt.Sprintf("the cookie session affinity is not converted, as the Gateway API version the resources are generated for has no session persistence, the requests of a client may be sent to different backends: configure it with the sessionPersistence of the HTTPRoute rules from Gateway API v1.1, or with your Gateway implementation. Session affinity settings:\n%s", strings.Join(settings, "\n")), &ingress)
+	}
+	if len(affinityIngresses) == 0 {
+		return nil
+	}
+
+	for _, rg := range common.GetRuleGroups(ingresses) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || !hasWeightedBackendRefs(httpRoute) {
+			continue
+		}
+
+		var objs []client.Object
+		seen := map[types.NamespacedName]bool{}
+		for _, rule := range rg.Rules {
+			ingressKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}
+			if !affinityIngresses[ingressKey] || seen[ingressKey] {
+				continue
+			}
+			seen[ingressKey] = true
+			ingress := rule.Ingress
+			objs = append(objs, &ingress)
+		}
+		if len(objs) == 0 {
+			continue
+		}
+		sort.Slice(objs, func(i, j int) bool { return objs[i].GetName() < objs[j].GetName() })
+		notify(notifications.InfoNotification, fmt.Sprintf("HTTPRoute %s splits the traffic of the Ingresses with cookie session affinity between weighted canary backends, which are kept: ingress-nginx keeps a client on the backend it was first sent to, unless %s is legacy, whereas whether session persistence sticks a client to a weighted backend depends on the Gateway implementation", key, nginxAnnotation(affinityCanaryBehaviorKey)), objs...)
+	}
+	return nil
+}
+
+// hasWeightedBackendRefs returns whether a rule of the HTTPRoute splits its
+// traffic between weighted backends.
+func hasWeightedBackendRefs(httpRoute gatewayv1.HTTPRoute) bool {
+	for _, rule := range httpRoute.Spec.Rules {
+		if len(rule.BackendRefs) < 2 {
+			continue
+		}
+		for _, backendRef := range rule.BackendRefs {
+			if backendRef.Weight != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_sessionAffinityFeature_canary(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	primary := conflictTestIngress("primary", "/", map[string]string{
+		"nginx.ingress.kubernetes.io/affinity":               "cookie",
+		"nginx.ingress.kubernetes.io/session-cookie-name":    "route",
+		"nginx.ingress.kubernetes.io/session-cookie-max-age": "172800",
+	})
+	canary := conflictTestIngress("canary", "/", map[string]string{
+		"nginx.ingress.kubernetes.io/canary":        "true",
+		"nginx.ingress.kubernetes.io/canary-weight": "20",
+	})
+
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames: []types.NamespacedName{{Namespace: "default", Name: "canary"}, {Namespace: "default", Name: "primary"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{
+			{Namespace: "default", Name: "canary"}:  &canary,
+			{Namespace: "default", Name: "primary"}: &primary,
+		},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+	if len(gatewayResources.HTTPRoutes) != 1 {
+		t.Fatalf("Expected 1 HTTPRoute, got %d: %+v", len(gatewayResources.HTTPRoutes), gatewayResources.HTTPRoutes)
+	}
+	var httpRoute gatewayv1.HTTPRoute
+	for _, route := range gatewayResources.HTTPRoutes {
+		httpRoute = route
+	}
+	weights := map[gatewayv1.ObjectName]int32{}
+	for _, backendRef := range httpRoute.Spec.Rules[0].BackendRefs {
+		weights[backendRef.Name] = ptr.Deref(backendRef.Weight, 1)
+	}
+	if diff := cmp.Diff(map[gatewayv1.ObjectName]int32{"canary": 20, "primary": 80}, weights); diff != "" {
+		t.Errorf("Unexpected backend weights (-want +got):\n%s", diff)
+	}
+
+	var messages []string
+	for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+		if notification.Type == notifications.InfoNotification || notification.Type == notifications.WarningNotification {
+			messages = append(messages, string(notification.Type)+": "+notification.Message)
+		}
+	}
+	expected := []string{
+		"WARNING: the cookie session affinity is not converted, as the Gateway API version the resources are generated for has no session persistence, the requests of a client may be sent to different backends: configure it with the sessionPersistence of the HTTPRoute rules from Gateway API v1.1, or with your Gateway implementation. Session affinity settings:\n" +
+			"nginx.ingress.kubernetes.io/affinity: cookie\n" +
+			"nginx.ingress.kubernetes.io/session-cookie-name: route\n" +
+			"nginx.ingress.kubernetes.io/session-cookie-max-age: 172800",
+		"INFO: HTTPRoute default/" + httpRoute.Name + " splits the traffic of the Ingresses with cookie session affinity between weighted canary backends, which are kept: ingress-nginx keeps a client on the backend it was first sent to, unless nginx.ingress.kubernetes.io/affinity-canary-behavior is legacy, whereas whether session persistence sticks a client to a weighted backend depends on the Gateway implementation",
+		"WARNING: no LoadBalancer or NodePort Service labeled app.kubernetes.io/name=ingress-nginx was found, the client IP preservation settings cannot be reported: set the Service fronting the controller with --ingress-nginx-controller-service",
+	}
+	if diff := cmp.Diff(expected, messages); diff != "" {
+		t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// sslRedirectFeature converts the `nginx.ingress.kubernetes.io/ssl-redirect`
+// and `nginx.ingress.kubernetes.io/force-ssl-redirect` annotations, which
+// redirect the HTTP requests of the Ingress hosts to HTTPS.
+//
+// The HTTPRoute of the host is attached to the HTTPS listener only, and an
+// HTTPRoute named `<route>-ssl-redirect` is attached to the HTTP listener to
+// redirect all the requests of the host to HTTPS with a 301, as RequestRedirect
+// does not support the 308 of ingress-nginx. The port of the redirect is the
+// one of the HTTPS listener, and left unset for 443 so that the implementation
+// uses the default port of the scheme. As the redirect applies to the whole
+// host, a Warning notification is emitted when other Ingresses sharing it do
+// not set the annotations. A host without TLS configured has no HTTPS listener,
+// so only a Warning notification is emitted.
+func sslRedirectFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || len(httpRoute.Spec.ParentRefs) != 1 {
+			continue
+		}
+
+		var redirected []*networkingv1.Ingress
+		var annotations []string
+		var notRedirected []string
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if annotation := sslRedirectAnnotation(ingress.Annotations); annotation != "" {
+				redirected = append(redirected, &ingress)
+				annotations = append(annotations, annotation)
+			} else {
+				notRedirected = append(notRedirected, fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name))
+			}
+		}
+		if len(redirected) == 0 {
+			continue
+		}
+
+		gatewayKey := types.NamespacedName{Namespace: httpRoute.Namespace, Name: string(httpRoute.Spec.ParentRefs[0].Name)}
+		httpListener, httpsListener := hostListeners(gatewayResources.Gateways[gatewayKey], rg.Host)
+		if httpListener == nil || httpsListener == nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s is not converted, as the host %q has no TLS configured: the HTTP requests of HTTPRoute %s/%s are not redirected to HTTPS, configure the redirect where TLS is terminated", annotations[0], rg.Host, httpRoute.Namespace, httpRoute.Name), redirected[0])
+			continue
+		}
+
+		httpRoute.Spec.ParentRefs[0].SectionName = common.PtrTo(httpsListener.Name)
+		gatewayResources.HTTPRoutes[key] = httpRoute
+
+		redirect := &gatewayv1.HTTPRequestRedirectFilter{
+			Scheme:     common.PtrTo("https"),
+			StatusCode: common.PtrTo(301),
+		}
+		if httpsListener.Port != 443 {
+			redirect.Port = common.PtrTo(httpsListener.Port)
+		}
+		redirectKey := types.NamespacedName{Namespace: key.Namespace, Name: fmt.Sprintf("%s-ssl-redirect", key.Name)}
+		redirectRoute := gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: redirectKey.Namespace, Name: redirectKey.Name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayKey.Name), SectionName: common.PtrTo(httpListener.Name)}},
+				},
+				Hostnames: httpRoute.Spec.Hostnames,
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Filters: []gatewayv1.HTTPRouteFilter{{
+						Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+						RequestRedirect: redirect,
+					}},
+				}},
+			},
+			Status: gatewayv1.HTTPRouteStatus{
+				RouteStatus: gatewayv1.RouteStatus{
+					Parents: []gatewayv1.RouteParentStatus{},
+				},
+			},
+		}
+		redirectRoute.SetGroupVersionKind(common.HTTPRouteGVK)
+		gatewayResources.HTTPRoutes[redirectKey] = redirectRoute
+		for i, ingress := range redirected {
+			common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, redirectKey, "", ingress, annotations[i])
+			common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, "spec.parentRefs", ingress, annotations[i])
+		}
+
+		notify(notifications.InfoNotification, fmt.Sprintf("HTTPRoute %s redirects the HTTP requests of the host %q to HTTPS with a 301, as RequestRedirect does not support the 308 used by ingress-nginx", redirectKey, rg.Host), redirected[0])
+		if len(notRedirected) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("%s redirects all the HTTP requests of the host %q to HTTPS, including the ones of the Ingresses %s, which do not set it", annotations[0], rg.Host, strings.Join(notRedirected, ", ")), redirected[0])
+		}
+	}
+	return nil
+}
+
+// sslRedirectAnnotation returns the annotation redirecting the HTTP requests of
+// the Ingress to HTTPS, or an empty string if there is none.
+func sslRedirectAnnotation(annotations map[string]string) string {
+	for _, key := range []string{forceSSLRedirectKey, sslRedirectKey} {
+		if annotations[nginxAnnotation(key)] == "true" {
+			return nginxAnnotation(key)
+		}
+	}
+	return ""
+}
+
+// hostListeners returns the HTTP and HTTPS listeners of the Gateway for the
+// hostname, or nil if there are none.
+func hostListeners(gateway gatewayv1.Gateway, hostname string) (*gatewayv1.Listener, *gatewayv1.Listener) {
+	var httpListener, httpsListener *gatewayv1.Listener
+	for i, listener := range gateway.Spec.Listeners {
+		listenerHostname := ""
+		if listener.Hostname != nil {
+			listenerHostname = string(*listener.Hostname)
+		}
+		if listenerHostname != hostname {
+			continue
+		}
+		switch listener.Protocol {
+		case gatewayv1.HTTPProtocolType:
+			httpListener = &gateway.Spec.Listeners[i]
+		case gatewayv1.HTTPSProtocolType:
+			httpsListener = &gateway.Spec.Listeners[i]
+		}
+	}
+	return httpListener, httpsListener
+}
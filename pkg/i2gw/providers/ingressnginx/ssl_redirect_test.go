@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_sslRedirectFeature(t *testing.T) {
+	sslRedirectIngress := func(name, path string, annotations map[string]string, tls bool) networkingv1.Ingress {
+		ingress := networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To(NginxIngressClass),
+				Rules: []networkingv1.IngressRule{{
+					Host: "foo.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+		if tls {
+			ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-cert"}}
+		}
+		return ingress
+	}
+	redirect := map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"}
+
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		httpsPort             gatewayv1.PortNumber
+		expectedSectionName   *gatewayv1.SectionName
+		expectedRedirect      *gatewayv1.HTTPRequestRedirectFilter
+		expectedNotifications []notifications.MessageType
+	}{
+		{
+			name:      "no annotation",
+			ingresses: []networkingv1.Ingress{sslRedirectIngress("foo", "/", nil, true)},
+		},
+		{
+			name:                  "ssl redirect with default port",
+			ingresses:             []networkingv1.Ingress{sslRedirectIngress("foo", "/", redirect, true)},
+			expectedSectionName:   ptr.To(gatewayv1.SectionName("foo-com-https")),
+			expectedRedirect:      &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+			expectedNotifications: []notifications.MessageType{notifications.InfoNotification},
+		},
+		{
+			name:                  "force ssl redirect with custom port",
+			ingresses:             []networkingv1.Ingress{sslRedirectIngress("foo", "/", map[string]string{"nginx.ingress.kubernetes.io/force-ssl-redirect": "true"}, true)},
+			httpsPort:             8443,
+			expectedSectionName:   ptr.To(gatewayv1.SectionName("foo-com-https")),
+			expectedRedirect:      &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), Port: ptr.To(gatewayv1.PortNumber(8443)), StatusCode: ptr.To(301)},
+			expectedNotifications: []notifications.MessageType{notifications.InfoNotification},
+		},
+		{
+			name: "host shared with an Ingress without ssl redirect",
+			ingresses: []networkingv1.Ingress{
+				sslRedirectIngress("foo", "/", redirect, true),
+				sslRedirectIngress("bar", "/bar", nil, true),
+			},
+			expectedSectionName:   ptr.To(gatewayv1.SectionName("foo-com-https")),
+			expectedRedirect:      &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+			expectedNotifications: []notifications.MessageType{notifications.InfoNotification, notifications.WarningNotification},
+		},
+		{
+			name:                  "no TLS",
+			ingresses:             []networkingv1.Ingress{sslRedirectIngress("foo", "/", redirect, false)},
+			expectedNotifications: []notifications.MessageType{notifications.WarningNotification},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+
+			gatewayResources, errs := common.ToGateway(tc.ingresses, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			if tc.httpsPort != 0 {
+				gatewayKey := types.NamespacedName{Namespace: "default", Name: NginxIngressClass}
+				gateway := gatewayResources.Gateways[gatewayKey]
+				for i, listener := range gateway.Spec.Listeners {
+					if listener.Protocol == gatewayv1.HTTPSProtocolType {
+						gateway.Spec.Listeners[i].Port = tc.httpsPort
+					}
+				}
+				gatewayResources.Gateways[gatewayKey] = gateway
+			}
+			if errs = sslRedirectFeature(tc.ingresses, &gatewayResources); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}]
+			if diff := cmp.Diff(tc.expectedSectionName, httpRoute.Spec.ParentRefs[0].SectionName); diff != "" {
+				t.Errorf("Unexpected sectionName of the HTTPRoute (-want +got):\n%s", diff)
+			}
+
+			redirectRoute, ok := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com-ssl-redirect"}]
+			if ok != (tc.expectedRedirect != nil) {
+				t.Fatalf("Expected redirect HTTPRoute: %v, got %+v", tc.expectedRedirect != nil, gatewayResources.HTTPRoutes)
+			}
+			if ok {
+				expectedSpec := gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{Name: NginxIngressClass, SectionName: ptr.To(gatewayv1.SectionName("foo-com-http"))}},
+					},
+					Hostnames: []gatewayv1.Hostname{"foo.com"},
+					Rules: []gatewayv1.HTTPRouteRule{{
+						Filters: []gatewayv1.HTTPRouteFilter{{
+							Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+							RequestRedirect: tc.expectedRedirect,
+						}},
+					}},
+				}
+				if diff := cmp.Diff(expectedSpec, redirectRoute.Spec); diff != "" {
+					t.Errorf("Unexpected redirect HTTPRoute (-want +got):\n%s", diff)
+				}
+			}
+
+			var gotTypes []notifications.MessageType
+			for _, notification := range notifications.NotificationAggr.Notifications[Name] {
+				gotTypes = append(gotTypes, notification.Type)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, gotTypes); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// streamingSetting is an annotation switching the buffering of the requests or
+// responses on or off, with how each value is served by Envoy Gateway, whose
+// proxy streams both by default. An empty behavior is converted to the traffic
+// policy of the route by trafficPolicyFeature, and not reported.
+type streamingSetting struct {
+	annotationKey string
+	on            string
+	off           string
+}
+
+var streamingSettings = []streamingSetting{
+	{
+		annotationKey: proxyBufferingKey,
+		on:            "the responses are buffered before being sent to the client, Envoy-based implementations like Envoy Gateway stream them instead and have no equivalent",
+		off:           "the responses, like server-sent events, are streamed to the client, as Envoy-based implementations like Envoy Gateway do by default",
+	},
+	{
+		annotationKey: proxyRequestBufferingKey,
+		on:            "",
+		off:           "the request bodies, like large uploads, are streamed to the backend, as Envoy-based implementations like Envoy Gateway do by default, within the buffer limit of ClientTrafficPolicy spec.connection.bufferLimit",
+	},
+}
+
+// streamingFeature reports the request and response buffering annotations of
+// every Ingress, like `nginx.ingress.kubernetes.io/proxy-buffering: "off"`.
+//
+// Gateway API has no equivalent to the buffering of the proxy, which streaming
+// applications, like server-sent events, WebSockets or large uploads, depend on.
+// So that they don't silently break once migrated, a Warning notification is
+// emitted per Ingress, listing every setting with its original value and how
+// to get the same behavior with Envoy Gateway.
+func streamingFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		var settings []string
+		for _, setting := range streamingSettings {
+			value, ok := ingress.Annotations[nginxAnnotation(setting.annotationKey)]
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			var behavior string
+			switch value {
+			case "on":
+				if setting.on == "" {
+					continue
+				}
+				behavior = setting.on
+			case "off":
+				behavior = setting.off
+			default:
+				behavior = "not a valid value, ingress-nginx expects on or off"
+			}
+			settings = append(settings, fmt.Sprintf("- %s: %s, %s", nginxAnnotation(setting.annotationKey), value, behavior))
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		ingress := ingress
+		notify(notifications.WarningNotification, fmt.Sprintf("the streaming and buffering settings are not converted, as Gateway API has no equivalent: check that the streaming clients, like server-sent events, WebSockets or large uploads, keep working with your Gateway implementation. Settings:\n%s", strings.Join(settings, "\n")), &ingress)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	// bodySizeRegexp matches the nginx sizes, like `8m`.
+	bodySizeRegexp = regexp.MustCompile(`^(\d+)([kKmMgG]?)$`)
+
+	// bodySizeSuffixes are the quantity suffixes of the nginx size units.
+	bodySizeSuffixes = map[string]string{"": "", "k": "Ki", "m": "Mi", "g": "Gi"}
+
+	// nginxVariableNameRegexp matches the name of a header or cookie in an
+	// nginx variable, like `x_user` in `$http_x_user`.
+	nginxVariableNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+(_[a-zA-Z0-9]+)*$`)
+)
+
+// trafficPolicyFeature collects the rate limit, retry, load balancing, request
+// body size, request buffering and client source range annotations of the Ingresses into a single
+// TrafficPolicy per HTTPRoute, converted to the policies of the target
+// implementation, like an Envoy Gateway BackendTrafficPolicy and SecurityPolicy,
+// once all the routes are generated.
+//
+// A setting is taken from the first Ingress of the route setting it. The other
+// Ingresses setting it differently produce a Warning notification, as a single
+// value applies to the whole route. So do the Ingresses of a route with client
+// source ranges that have none, as they become restricted too.
+func trafficPolicyFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	rgKeys := make([]string, 0, len(ruleGroups))
+	for rgKey := range ruleGroups {
+		rgKeys = append(rgKeys, rgKey)
+	}
+	sort.Strings(rgKeys)
+
+	for _, rgKey := range rgKeys {
+		rg := ruleGroups[rgKey]
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		var policy i2gw.TrafficPolicy
+		sources := map[string]*networkingv1.Ingress{}
+		// merge sets the setting of the route from the Ingress, unless another
+		// Ingress already set it.
+		merge := func(setting string, ingress *networkingv1.Ingress, isSet, equal bool, set func()) {
+			if !isSet {
+				return
+			}
+			source, ok := sources[setting]
+			if !ok {
+				sources[setting] = ingress
+				set()
+				return
+			}
+			if !equal {
+				notify(notifications.WarningNotification, fmt.Sprintf("the %s of Ingress %s/%s was not converted, as HTTPRoute %s/%s already has the one of Ingress %s/%s",
+					setting, ingress.Namespace, ingress.Name, httpRoute.Namespace, httpRoute.Name, source.Namespace, source.Name), ingress)
+			}
+		}
+
+		var seen, unrestricted []string
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			if rule.IngressRule.HTTP == nil || slices.Contains(seen, ingress.Name) {
+				continue
+			}
+			seen = append(seen, ingress.Name)
+
+			ingressPolicy := parseTrafficPolicy(&ingress)
+			merge("rate limit", &ingress, ingressPolicy.RateLimit != nil, apiequality.Semantic.DeepEqual(policy.RateLimit, ingressPolicy.RateLimit), func() { policy.RateLimit = ingressPolicy.RateLimit })
+			merge("retry", &ingress, ingressPolicy.Retry != nil, apiequality.Semantic.DeepEqual(policy.Retry, ingressPolicy.Retry), func() { policy.Retry = ingressPolicy.Retry })
+			merge("load balancer", &ingress, ingressPolicy.LoadBalancer != "", policy.LoadBalancer == ingressPolicy.LoadBalancer && policy.LoadBalancerHashKey == ingressPolicy.LoadBalancerHashKey, func() {
+				policy.LoadBalancer, policy.LoadBalancerHashKey = ingressPolicy.LoadBalancer, ingressPolicy.LoadBalancerHashKey
+			})
+			merge("request body limit", &ingress, ingressPolicy.RequestBodyLimit != nil, apiequality.Semantic.DeepEqual(policy.RequestBodyLimit, ingressPolicy.RequestBodyLimit), func() { policy.RequestBodyLimit = ingressPolicy.RequestBodyLimit })
+			merge("request buffering", &ingress, ingressPolicy.RequestBuffering, policy.RequestBuffering, func() { policy.RequestBuffering = true })
+
+			// The errors are reported by accessControlFeature.
+			accessControl, _ := parseAccessControl(ingress)
+			merge("client source ranges", &ingress, accessControl != nil, apiequality.Semantic.DeepEqual(policy.AccessControl, accessControl), func() { policy.AccessControl = accessControl })
+			if accessControl == nil {
+				unrestricted = append(unrestricted, ingress.Name)
+			}
+		}
+		if policy.AccessControl != nil && len(unrestricted) > 0 {
+			source := sources["client source ranges"]
+			notify(notifications.WarningNotification, fmt.Sprintf("the client source ranges of Ingress %s/%s apply to the whole HTTPRoute %s/%s, including the paths of the Ingresses %s which have none",
+				source.Namespace, source.Name, httpRoute.Namespace, httpRoute.Name, strings.Join(unrestricted, ", ")), source)
+		}
+		if policy.IsEmpty() {
+			continue
+		}
+		if gatewayResources.TrafficPolicies == nil {
+			gatewayResources.TrafficPolicies = map[types.NamespacedName]i2gw.TrafficPolicy{}
+		}
+		gatewayResources.TrafficPolicies[key] = policy
+	}
+	return nil
+}
+
+// parseTrafficPolicy returns the traffic settings of the annotations of the
+// Ingress. The values that cannot be converted produce a Warning notification.
+func parseTrafficPolicy(ingress *networkingv1.Ingress) i2gw.TrafficPolicy {
+	var policy i2gw.TrafficPolicy
+	annotation := func(key string) (string, bool) {
+		value, ok := ingress.Annotations[nginxAnnotation(key)]
+		return strings.TrimSpace(value), ok
+	}
+	invalid := func(key, value, reason string) {
+		notify(notifications.WarningNotification, fmt.Sprintf("%s %q was not converted, %s", nginxAnnotation(key), value, reason), ingress)
+	}
+
+	for _, limit := range []struct {
+		key  string
+		unit i2gw.RateLimitUnit
+	}{{limitRPSKey, i2gw.RateLimitPerSecond}, {limitRPMKey, i2gw.RateLimitPerMinute}} {
+		value, ok := annotation(limit.key)
+		if !ok {
+			continue
+		}
+		requests, err := strconv.ParseInt(value, 10, 64)
+		switch {
+		case err != nil || requests < 1:
+			invalid(limit.key, value, "as it is not a positive number of requests")
+		case policy.RateLimit != nil:
+			invalid(limit.key, value, fmt.Sprintf("as a route has a single rate limit, the one of %s is kept", nginxAnnotation(limitRPSKey)))
+		default:
+			policy.RateLimit = &i2gw.RateLimit{Requests: requests, Unit: limit.unit}
+			notify(notifications.WarningNotification, fmt.Sprintf("%s %q limits the requests of every client IP, while the converted rate limit applies to all the clients of the route together", nginxAnnotation(limit.key), value), ingress)
+		}
+	}
+
+	policy.Retry = parseRetry(annotation, invalid)
+
+	if value, ok := annotation(upstreamHashByKey); ok {
+		policy.LoadBalancer, policy.LoadBalancerHashKey = parseUpstreamHashBy(value)
+		if policy.LoadBalancer == "" {
+			invalid(upstreamHashByKey, value, "as only the hashes of the client IP, $remote_addr or $binary_remote_addr, of a header, $http_<name>, or of a cookie, $cookie_<name>, are supported")
+		}
+	} else if value, ok := annotation(loadBalanceKey); ok {
+		switch value {
+		case "round_robin":
+			policy.LoadBalancer = i2gw.RoundRobinLoadBalancer
+		case "ewma":
+			// The least request algorithm is the closest to the peak EWMA of
+			// the response times.
+			policy.LoadBalancer = i2gw.LeastRequestLoadBalancer
+		default:
+			invalid(loadBalanceKey, value, "as only round_robin and ewma are supported")
+		}
+	}
+
+	if value, ok := annotation(proxyBodySizeKey); ok {
+		match := bodySizeRegexp.FindStringSubmatch(value)
+		if match == nil {
+			invalid(proxyBodySizeKey, value, "as it is not a size like 8m")
+		} else if limit := resource.MustParse(match[1] + bodySizeSuffixes[strings.ToLower(match[2])]); !limit.IsZero() {
+			// 0 disables the limit.
+			policy.RequestBodyLimit = &limit
+		}
+	}
+
+	if value, ok := annotation(proxyRequestBufferingKey); ok && value == "on" {
+		policy.RequestBuffering = true
+	}
+	return policy
+}
+
+// parseUpstreamHashBy returns the load balancer hashing the nginx variable of
+// the upstream-hash-by annotation, and the header or cookie it hashes, or an
+// empty algorithm if it is not supported. The header variables name the
+// headers in lowercase with underscores, so $http_x_user hashes X-User.
+func parseUpstreamHashBy(variable string) (i2gw.LoadBalancerAlgorithm, string) {
+	if variable == "$remote_addr" || variable == "$binary_remote_addr" {
+		return i2gw.SourceIPHashLoadBalancer, ""
+	}
+	if header, ok := strings.CutPrefix(variable, "$http_"); ok && nginxVariableNameRegexp.MatchString(header) {
+		parts := strings.Split(header, "_")
+		for i, part := range parts {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+		return i2gw.HeaderHashLoadBalancer, strings.Join(parts, "-")
+	}
+	if cookie, ok := strings.CutPrefix(variable, "$cookie_"); ok && nginxVariableNameRegexp.MatchString(cookie) {
+		return i2gw.CookieHashLoadBalancer, cookie
+	}
+	return "", ""
+}
+
+// parseRetry returns the retries of the proxy-next-upstream annotations, taking
+// the defaults of ingress-nginx, `error timeout` and 3 tries, for the one not
+// set, or nil if none is set.
+func parseRetry(annotation func(string) (string, bool), invalid func(key, value, reason string)) *i2gw.Retry {
+	conditions, conditionsSet := annotation(proxyNextUpstreamKey)
+	triesValue, triesSet := annotation(proxyNextUpstreamTriesKey)
+	if !conditionsSet && !triesSet {
+		return nil
+	}
+	if !conditionsSet {
+		conditions = "error timeout"
+	}
+
+	retry := &i2gw.Retry{Retries: 2}
+	if triesSet {
+		tries, err := strconv.ParseInt(triesValue, 10, 32)
+		switch {
+		case err != nil || tries < 0:
+			invalid(proxyNextUpstreamTriesKey, triesValue, "as it is not a number of tries")
+		case tries == 0:
+			invalid(proxyNextUpstreamTriesKey, triesValue, "as the number of retries cannot be unlimited, the default of the implementation applies")
+		default:
+			retry.Retries = int32(tries) - 1
+		}
+	}
+
+	var unsupported []string
+	for _, condition := range strings.Fields(conditions) {
+		switch condition {
+		case "off":
+			return &i2gw.Retry{}
+		case "error", "timeout":
+			retry.OnConnectionFailure = true
+		case "http_500", "http_502", "http_503", "http_504", "http_403", "http_404", "http_429":
+			code, _ := strconv.ParseInt(strings.TrimPrefix(condition, "http_"), 10, 32)
+			if !slices.Contains(retry.OnStatusCodes, int32(code)) {
+				retry.OnStatusCodes = append(retry.OnStatusCodes, int32(code))
+			}
+		default:
+			unsupported = append(unsupported, condition)
+		}
+	}
+	if len(unsupported) > 0 {
+		invalid(proxyNextUpstreamKey, conditions, fmt.Sprintf("for the conditions %s, which have no retry equivalent", strings.Join(unsupported, ", ")))
+	}
+	if !retry.OnConnectionFailure && len(retry.OnStatusCodes) == 0 {
+		return nil
+	}
+	return retry
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func Test_trafficPolicyFeature(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingress := func(name, path string, annotations map[string]string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To(NginxIngressClass),
+				Rules: []networkingv1.IngressRule{{
+					Host: "foo.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: name, Port: networkingv1.ServiceBackendPort{Number: 80}},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{
+		ingress("api", "/api", map[string]string{
+			"nginx.ingress.kubernetes.io/limit-rps":                 "10",
+			"nginx.ingress.kubernetes.io/proxy-next-upstream":       "error timeout http_502 http_503 non_idempotent",
+			"nginx.ingress.kubernetes.io/proxy-next-upstream-tries": "4",
+			"nginx.ingress.kubernetes.io/load-balance":              "ewma",
+		}),
+		ingress("web", "/", map[string]string{
+			"nginx.ingress.kubernetes.io/load-balance":            "round_robin",
+			"nginx.ingress.kubernetes.io/proxy-body-size":         "8m",
+			"nginx.ingress.kubernetes.io/proxy-request-buffering": "on",
+		}),
+	}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = trafficPolicyFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	expected := map[types.NamespacedName]i2gw.TrafficPolicy{
+		{Namespace: "default", Name: "api-foo-com"}: {
+			RateLimit:        &i2gw.RateLimit{Requests: 10, Unit: i2gw.RateLimitPerSecond},
+			Retry:            &i2gw.Retry{Retries: 3, OnConnectionFailure: true, OnStatusCodes: []int32{502, 503}},
+			LoadBalancer:     i2gw.LeastRequestLoadBalancer,
+			RequestBodyLimit: ptr.To(resource.MustParse("8Mi")),
+			RequestBuffering: true,
+		},
+	}
+	if diff := cmp.Diff(expected, gatewayResources.TrafficPolicies); diff != "" {
+		t.Errorf("Unexpected traffic policies (-want +got):\n%s", diff)
+	}
+
+	// The per client rate limit, the unsupported retry condition and the load
+	// balancer of the second Ingress are reported.
+	if notifs := notifications.NotificationAggr.Notifications[Name]; len(notifs) != 3 {
+		t.Errorf("Expected 3 notifications, got %+v", notifs)
+	}
+}
+
+func Test_parseTrafficPolicyUpstreamHashBy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		upstreamHashBy  string
+		expectedLB      i2gw.LoadBalancerAlgorithm
+		expectedHashKey string
+		expectedWarning bool
+	}{
+		{name: "client IP", upstreamHashBy: "$binary_remote_addr", expectedLB: i2gw.SourceIPHashLoadBalancer},
+		{name: "header", upstreamHashBy: "$http_x_user", expectedLB: i2gw.HeaderHashLoadBalancer, expectedHashKey: "X-User"},
+		{name: "single word header", upstreamHashBy: "$http_authorization", expectedLB: i2gw.HeaderHashLoadBalancer, expectedHashKey: "Authorization"},
+		{name: "cookie", upstreamHashBy: "$cookie_session", expectedLB: i2gw.CookieHashLoadBalancer, expectedHashKey: "session"},
+		{name: "request URI", upstreamHashBy: "$request_uri", expectedWarning: true},
+		{name: "combined variables", upstreamHashBy: "$http_x_user$request_uri", expectedWarning: true},
+		{name: "empty header name", upstreamHashBy: "$http_", expectedWarning: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Name:        "api",
+				Namespace:   "default",
+				Annotations: map[string]string{"nginx.ingress.kubernetes.io/upstream-hash-by": tc.upstreamHashBy},
+			}}
+
+			policy := parseTrafficPolicy(ingress)
+			if policy.LoadBalancer != tc.expectedLB || policy.LoadBalancerHashKey != tc.expectedHashKey {
+				t.Errorf("Expected load balancer %q on %q, got %q on %q", tc.expectedLB, tc.expectedHashKey, policy.LoadBalancer, policy.LoadBalancerHashKey)
+			}
+			notifs := notifications.NotificationAggr.Notifications[Name]
+			if !tc.expectedWarning && len(notifs) != 0 {
+				t.Errorf("Expected no notifications, got %+v", notifs)
+			}
+			if tc.expectedWarning && (len(notifs) != 1 || notifs[0].Type != notifications.WarningNotification) {
+				t.Errorf("Expected a single Warning notification, got %+v", notifs)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// useRegexFeature defines how the Exact paths of the hosts with the
+// `nginx.ingress.kubernetes.io/use-regex: "true"` annotation are converted.
+//
+// ingress-nginx applies use-regex to all the paths of the host, of any Ingress,
+// and then serves even the Exact paths as case-insensitive regular expressions
+// anchored at the start only, e.g. `/api` matches `/API/v1`, which contradicts
+// their path type. The path type wins: the Exact paths are converted to Exact
+// matches, ignoring use-regex, and a Warning notification lists them for every
+// Ingress, as ingress-nginx matches more requests.
+func useRegexFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || !hostUsesRegex(rg) {
+			continue
+		}
+		exactPaths := map[types.NamespacedName][]string{}
+		var ingressKeys []types.NamespacedName
+		ingressesByKey := map[types.NamespacedName]networkingv1.Ingress{}
+		for _, rule := range rg.Rules {
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			ingressKey := types.NamespacedName{Namespace: rule.Ingress.Namespace, Name: rule.Ingress.Name}
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				if path.PathType == nil || *path.PathType != networkingv1.PathTypeExact {
+					continue
+				}
+				if _, ok := exactPaths[ingressKey]; !ok {
+					ingressKeys = append(ingressKeys, ingressKey)
+					ingressesByKey[ingressKey] = rule.Ingress
+				}
+				exactPaths[ingressKey] = append(exactPaths[ingressKey], fmt.Sprintf("%q", path.Path))
+			}
+		}
+		for _, ingressKey := range ingressKeys {
+			ingress := ingressesByKey[ingressKey]
+			notify(notifications.WarningNotification, fmt.Sprintf("the Exact paths %s of host %q are converted to Exact matches in HTTPRoute %s/%s, ignoring %s: \"true\", while ingress-nginx matches them as case-insensitive regular expressions anchored at the start only, like prefixes", strings.Join(exactPaths[ingressKey], ", "), rg.Host, httpRoute.Namespace, httpRoute.Name, nginxAnnotation(useRegexKey)), &ingress)
+		}
+	}
+	return nil
+}
+
+// caseInsensitivePathFeature converts the regex paths of the hosts with the
+// `nginx.ingress.kubernetes.io/use-regex: "true"` annotation carrying the
+// case-insensitive flag `(?i)`.
+//
+// Gateway API path matches are case-sensitive, and cannot express the flag,
+// which is rejected in Prefix and Exact values. It is removed from the
+// converted matches, which only match the casing of the path, and a Warning
+// notification is emitted for every such path. It runs after all the other
+// features, as they find the rules of a path by its value in the Ingress.
+func caseInsensitivePathFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	for _, rg := range common.GetRuleGroups(ingresses) {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok || !hostUsesRegex(rg) {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			if rule.IngressRule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.IngressRule.HTTP.Paths {
+				if strings.Contains(path.Path, caseInsensitiveFlag) {
+					ingress := rule.Ingress
+					convertCaseInsensitivePath(&httpRoute, path.Path, rg.Host, &ingress)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// caseInsensitiveFlag makes the rest of an nginx regular expression match
+// case-insensitively.
+const caseInsensitiveFlag = "(?i)"
+
+// convertCaseInsensitivePath removes the case-insensitive flag from the
+// matches of the regex path in the HTTPRoute, and emits a Warning notification
+// as they only match its casing.
+func convertCaseInsensitivePath(httpRoute *gatewayv1.HTTPRoute, path, host string, ingress *networkingv1.Ingress) {
+	converted := strings.ReplaceAll(path, caseInsensitiveFlag, "")
+	if !strings.HasPrefix(converted, "/") {
+		converted = "/" + converted
+	}
+	for i := range httpRoute.Spec.Rules {
+		for j := range httpRoute.Spec.Rules[i].Matches {
+			match := httpRoute.Spec.Rules[i].Matches[j].Path
+			// The trailing slash of the Prefix paths may have been trimmed.
+			if match == nil || match.Value == nil || (*match.Value != path && *match.Value != strings.TrimSuffix(path, "/")) {
+				continue
+			}
+			value := converted
+			if *match.Value != path && converted != "/" {
+				value = strings.TrimSuffix(converted, "/")
+			}
+			match.Value = &value
+		}
+	}
+	notify(notifications.WarningNotification, fmt.Sprintf("the path %q of host %q matches case-insensitively with %s, which Gateway API path matches cannot express: it is converted to the case-sensitive match %q in HTTPRoute %s/%s, add a path for every other casing the clients use",
+		path, host, caseInsensitiveFlag, converted, httpRoute.Namespace, httpRoute.Name), ingress)
+}
+
+// hostUsesRegex returns whether an Ingress of the rule group enables use-regex,
+// which ingress-nginx then applies to all the paths of the host.
+func hostUsesRegex(rg common.IngressRuleGroup) bool {
+	for _, rule := range rg.Rules {
+		if useRegex, err := strconv.ParseBool(rule.Ingress.Annotations[nginxAnnotation(useRegexKey)]); err == nil && useRegex {
+			return true
+		}
+	}
+	return false
+}
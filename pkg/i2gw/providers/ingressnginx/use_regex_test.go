@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_useRegexFeature(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	path := func(value string, pathType networkingv1.PathType) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     value,
+			PathType: ptr.To(pathType),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Number: 80}},
+			},
+		}
+	}
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/use-regex": "true",
+		}},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To(NginxIngressClass),
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							path("/api", networkingv1.PathTypeExact),
+							path("/static", networkingv1.PathTypePrefix),
+						},
+					},
+				},
+			}},
+		},
+	}}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = useRegexFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	// The path type wins over use-regex.
+	match := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-foo-com"}].Spec.Rules[0].Matches[0]
+	if *match.Path.Type != gatewayv1.PathMatchExact || *match.Path.Value != "/api" {
+		t.Errorf("Expected an Exact match on /api, got %s %s", *match.Path.Type, *match.Path.Value)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications[Name]
+	expectedMessage := `the Exact paths "/api" of host "foo.com" are converted to Exact matches in HTTPRoute default/foo-foo-com, ignoring nginx.ingress.kubernetes.io/use-regex: "true", while ingress-nginx matches them as case-insensitive regular expressions anchored at the start only, like prefixes`
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification || gotNotifications[0].Message != expectedMessage {
+		t.Errorf("Expected a single Warning notification %q, got %+v", expectedMessage, gotNotifications)
+	}
+}
+
+func Test_caseInsensitivePathFeature(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingresses := []networkingv1.Ingress{conflictTestIngress("foo", "/(?i)api", map[string]string{
+		"nginx.ingress.kubernetes.io/use-regex": "true",
+	})}
+
+	gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+	}
+	if errs = caseInsensitivePathFeature(ingresses, &gatewayResources); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", errs)
+	}
+
+	// The flag is removed, as Gateway API rejects it in PathPrefix values.
+	match := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-www-example-com"}].Spec.Rules[0].Matches[0]
+	if *match.Path.Type != gatewayv1.PathMatchPathPrefix || *match.Path.Value != "/api" {
+		t.Errorf("Expected a PathPrefix match on /api, got %s %s", *match.Path.Type, *match.Path.Value)
+	}
+
+	gotNotifications := notifications.NotificationAggr.Notifications[Name]
+	expectedMessage := `the path "/(?i)api" of host "www.example.com" matches case-insensitively with (?i), which Gateway API path matches cannot express: it is converted to the case-sensitive match "/api" in HTTPRoute default/foo-www-example-com, add a path for every other casing the clients use`
+	if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.WarningNotification || gotNotifications[0].Message != expectedMessage {
+		t.Errorf("Expected a single Warning notification %q, got %+v", expectedMessage, gotNotifications)
+	}
+}
+
+func Test_ToGatewayCaseInsensitivePathHeaders(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	ingress := conflictTestIngress("foo", "/(?i)api", map[string]string{
+		"nginx.ingress.kubernetes.io/use-regex":               "true",
+		"nginx.ingress.kubernetes.io/x-forwarded-prefix":      "/foo",
+		"nginx.ingress.kubernetes.io/connection-proxy-header": "keep-alive",
+	})
+	provider := NewProvider(&i2gw.ProviderConf{}).(*Provider)
+	provider.storage.Ingresses = OrderedIngressMap{
+		ingressNames:   []types.NamespacedName{{Namespace: "default", Name: "foo"}},
+		ingressObjects: map[types.NamespacedName]*networkingv1.Ingress{{Namespace: "default", Name: "foo"}: &ingress},
+	}
+
+	gatewayResources, errs := provider.ToGatewayAPI()
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %+v", errs)
+	}
+
+	// The headers are set on the rule of the path before its flag is removed.
+	rule := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo-www-example-com"}].Spec.Rules[0]
+	if value := *rule.Matches[0].Path.Value; value != "/api" {
+		t.Errorf("Expected a match on /api, got %s", value)
+	}
+	headers := map[gatewayv1.HTTPHeaderName]string{}
+	for _, filter := range rule.Filters {
+		if filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier {
+			for _, header := range filter.RequestHeaderModifier.Set {
+				headers[header.Name] = header.Value
+			}
+		}
+	}
+	if headers["X-Forwarded-Prefix"] != "/foo" || headers["Connection"] != "keep-alive" {
+		t.Errorf("Expected the X-Forwarded-Prefix and Connection request headers, got filters %+v", rule.Filters)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// wafAnnotationKeys are the ModSecurity annotations, in the order they are reported.
+var wafAnnotationKeys = []string{
+	enableModSecurityKey,
+	enableOWASPCoreRulesKey,
+	modSecurityTransactionIDKey,
+	modSecuritySnippetKey,
+}
+
+// wafFeature reports the ModSecurity (WAF) annotations of every Ingress.
+//
+// Gateway API has no WAF equivalent, and silently dropping the WAF configuration
+// would leave the migrated routes unprotected. Hence a single Error notification
+// is emitted per Ingress, listing all of its WAF settings, including the snippet
+// content, so that they are explicitly addressed with the Gateway implementation.
+func wafFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		var settings []string
+		for _, key := range wafAnnotationKeys {
+			value, ok := ingress.Annotations[nginxAnnotation(key)]
+			if !ok {
+				continue
+			}
+			settings = append(settings, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+		}
+		if len(settings) == 0 {
+			continue
+		}
+		ingress := ingress
+		notify(notifications.ErrorNotification, fmt.Sprintf("the WAF settings are not converted, as Gateway API has no equivalent: configure them with your Gateway implementation before routing traffic to it. WAF settings:\n%s", strings.Join(settings, "\n")), &ingress)
+	}
+	return nil
+}
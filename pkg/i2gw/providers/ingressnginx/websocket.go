@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// websocketTimeoutSeconds is the proxy timeout from which an Ingress is assumed
+// to serve long-lived connections, like WebSockets, as the ingress-nginx
+// documentation recommends raising the timeouts to an hour for them.
+const websocketTimeoutSeconds = 3600
+
+// upgradeHeaderDirective matches the directives of a configuration snippet
+// forwarding the Upgrade header of WebSocket handshakes to the backend.
+var upgradeHeaderDirective = regexp.MustCompile(`(?i)proxy_set_header\s+(Upgrade|Connection\s+"?upgrade)`)
+
+// websocketFeature reports the Ingresses likely serving WebSockets, from their
+// proxy timeouts of an hour or more, like
+// `nginx.ingress.kubernetes.io/proxy-read-timeout: "3600"`, or from a
+// configuration snippet forwarding the Upgrade header.
+//
+// HTTPRoutes serve WebSockets without configuration, but the migrated
+// connections may be cut by the route timeouts or the idle timeouts of the
+// Gateway implementation, so an Info notification is emitted per Ingress,
+// recommending long timeouts or none.
+func websocketFeature(ingresses []networkingv1.Ingress, _ *i2gw.GatewayResources) field.ErrorList {
+	for _, ingress := range ingresses {
+		var reasons []string
+		for _, key := range []string{proxyReadTimeoutKey, proxySendTimeoutKey} {
+			value, ok := ingress.Annotations[nginxAnnotation(key)]
+			if !ok {
+				continue
+			}
+			seconds, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "s"))
+			if err == nil && seconds >= websocketTimeoutSeconds {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", nginxAnnotation(key), strings.TrimSpace(value)))
+			}
+		}
+		if upgradeHeaderDirective.MatchString(ingress.Annotations[nginxAnnotation(configurationSnippetKey)]) {
+			reasons = append(reasons, fmt.Sprintf("%s forwarding the Upgrade header", nginxAnnotation(configurationSnippetKey)))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		ingress := ingress
+		notify(notifications.InfoNotification, fmt.Sprintf("the Ingress likely serves WebSockets (%s), which HTTPRoutes support without configuration. As a WebSocket lasts as long as its request, leave the timeouts.request and timeouts.backendRequest of its HTTPRoute rules unset or longer than the connections, and check the idle timeouts of your Gateway implementation, e.g. with Envoy Gateway in ClientTrafficPolicy spec.timeout.http.idleTimeout", strings.Join(reasons, ", ")), &ingress)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_websocketFeature(t *testing.T) {
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		expectedReasons string
+	}{
+		{
+			name:        "default timeouts",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "60"},
+		},
+		{
+			name: "high read timeout",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600",
+				"nginx.ingress.kubernetes.io/proxy-send-timeout": "120",
+			},
+			expectedReasons: "(nginx.ingress.kubernetes.io/proxy-read-timeout: 3600)",
+		},
+		{
+			name: "upgrade header snippet",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/configuration-snippet": "proxy_set_header Upgrade $http_upgrade;\nproxy_set_header Connection \"upgrade\";",
+			},
+			expectedReasons: "(nginx.ingress.kubernetes.io/configuration-snippet forwarding the Upgrade header)",
+		},
+		{
+			name:        "invalid timeout",
+			annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "1h"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ws", Annotations: tc.annotations},
+			}}
+
+			if errs := websocketFeature(ingresses, &i2gw.GatewayResources{}); len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %+v", errs)
+			}
+
+			gotNotifications := notifications.NotificationAggr.Notifications[Name]
+			if tc.expectedReasons == "" {
+				if len(gotNotifications) != 0 {
+					t.Errorf("Expected no notifications, got %+v", gotNotifications)
+				}
+				return
+			}
+			if len(gotNotifications) != 1 || gotNotifications[0].Type != notifications.InfoNotification {
+				t.Fatalf("Expected a single Info notification, got %+v", gotNotifications)
+			}
+			message := gotNotifications[0].Message
+			if !strings.Contains(message, tc.expectedReasons) || !strings.Contains(message, "timeouts.backendRequest") {
+				t.Errorf("Expected notification to list %q and recommend long route timeouts, got %q", tc.expectedReasons, message)
+			}
+		})
+	}
+}
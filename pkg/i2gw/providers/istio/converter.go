@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
@@ -132,6 +133,10 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		}
 	}
 
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, ProviderName)
+	}
+
 	return gatewayResources, nil
 }
 
@@ -452,7 +457,16 @@ func (c *converter) convertVsHTTPRoutes(virtualService metav1.ObjectMeta, istioH
 				}
 			}
 
-			for query, queryMatch := range match.GetQueryParams() {
+			// The query params are sorted by name, so that the generated matches are deterministic.
+			queryParams := match.GetQueryParams()
+			queryNames := make([]string, 0, len(queryParams))
+			for query := range queryParams {
+				queryNames = append(queryNames, query)
+			}
+			sort.Strings(queryNames)
+
+			for _, query := range queryNames {
+				queryMatch := queryParams[query]
 				var (
 					matchType gatewayv1.QueryParamMatchType
 					value     string
@@ -652,6 +652,11 @@ func Test_converter_convertVsHTTPRoutes(t *testing.T) {
 											Exact: "v1",
 										},
 									},
+									"q0": {
+										MatchType: &istiov1beta1.StringMatch_Exact{
+											Exact: "v0",
+										},
+									},
 								},
 							},
 						},
@@ -687,6 +692,11 @@ func Test_converter_convertVsHTTPRoutes(t *testing.T) {
 								Matches: []gatewayv1.HTTPRouteMatch{
 									{
 										QueryParams: []gatewayv1.HTTPQueryParamMatch{
+											{
+												Type:  common.PtrTo[gatewayv1.QueryParamMatchType](gatewayv1.QueryParamMatchExact),
+												Name:  "q0",
+												Value: "v0",
+											},
 											{
 												Type:  common.PtrTo[gatewayv1.QueryParamMatchType](gatewayv1.QueryParamMatchExact),
 												Name:  "q1",
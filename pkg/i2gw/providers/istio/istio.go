@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -28,7 +29,10 @@ import (
 const ProviderName = "istio"
 
 func init() {
-	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProvider(ProviderName, NewProvider,
+		schema.GroupKind{Group: APIGroup, Kind: GatewayKind},
+		schema.GroupKind{Group: APIGroup, Kind: VirtualServiceKind},
+	)
 }
 
 type Provider struct {
@@ -17,7 +17,8 @@ limitations under the License.
 package istio
 
 const (
-	APIVersion         = "networking.istio.io/v1beta1"
+	APIGroup           = "networking.istio.io"
+	APIVersion         = APIGroup + "/v1beta1"
 	GatewayKind        = "Gateway"
 	VirtualServiceKind = "VirtualService"
 
@@ -25,9 +25,10 @@ import (
 const (
 	annotationPrefix = "konghq.com"
 
-	headersKey = "headers"
-	methodsKey = "methods"
-	pluginsKey = "plugins"
+	headersKey   = "headers"
+	methodsKey   = "methods"
+	pluginsKey   = "plugins"
+	stripPathKey = "strip-path"
 )
 
 const (
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/kong/crds"
 )
@@ -32,15 +33,17 @@ type converter struct {
 }
 
 // newConverter returns an kong converter instance.
-func newConverter() *converter {
+func newConverter(conf *i2gw.ProviderConf) *converter {
 	return &converter{
 		featureParsers: []i2gw.FeatureParser{
 			headerMatchingFeature,
 			methodMatchingFeature,
 			pluginsFeature,
+			stripPathFeature,
 		},
 		implementationSpecificOptions: i2gw.ProviderImplementationSpecificOptions{
 			ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			Progress: conf.Progress,
 		},
 	}
 }
@@ -51,6 +54,10 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		ingressList = append(ingressList, *ingress)
 	}
 
+	for _, notification := range common.ResolveNamedServicePorts(ingressList, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
 	errorList := field.ErrorList{}
 
 	// Convert plain ingress resources to gateway resources, ignoring all
@@ -81,5 +88,21 @@ func (c *converter) convert(storage *storage) (i2gw.GatewayResources, field.Erro
 		errorList = append(errorList, errs...)
 	}
 
+	for _, notification := range common.ResolveCrossNamespaceBackends(&gatewayResources, storage.Services) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.AssembleHTTPRouteFilters(&gatewayResources) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.ResolveMixedIngressControllers(&gatewayResources, ingressList, Name) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
+	for _, notification := range common.CopyCertManagerAnnotations(&gatewayResources, ingressList) {
+		notifications.NotificationAggr.DispatchNotification(notification, Name)
+	}
+
 	return gatewayResources, errorList
 }
@@ -174,7 +174,7 @@ func (a *tcpIngressAggregator) toRoutesAndGateways() ([]gatewayv1alpha2.TCPRoute
 			if listener.TLS != nil {
 				gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
 					Hostname: listener.Hostname,
-					Protocol: gatewayv1.TLSProtocolType,
+					Protocol: common.InferListenerProtocol(listener.TLS, true),
 					Port:     listener.Port,
 					Name:     *buildSectionName("tls", common.NameFromHost(hostname), strconv.Itoa(int(listener.Port))),
 					TLS:      listener.TLS,
@@ -182,7 +182,7 @@ func (a *tcpIngressAggregator) toRoutesAndGateways() ([]gatewayv1alpha2.TCPRoute
 			} else {
 				gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
 					Hostname: listener.Hostname,
-					Protocol: gatewayv1.TCPProtocolType,
+					Protocol: common.InferListenerProtocol(nil, true),
 					Port:     listener.Port,
 					Name:     *buildSectionName("tcp", common.NameFromHost(hostname), strconv.Itoa(int(listener.Port))),
 				})
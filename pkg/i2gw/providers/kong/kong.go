@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 )
 
 // The Name of the provider.
@@ -29,7 +30,7 @@ const Name = "kong"
 const KongIngressClass = "kong"
 
 func init() {
-	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.RegisterProvider(Name, NewProvider, common.IngressGVK.GroupKind(), tcpIngressGVK.GroupKind(), common.ServiceGVK.GroupKind())
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -43,7 +44,7 @@ type Provider struct {
 func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	return &Provider{
 		resourceReader: newResourceReader(conf),
-		converter:      newConverter(),
+		converter:      newConverter(conf),
 	}
 }
 
@@ -18,6 +18,7 @@ package kong
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
@@ -45,6 +46,12 @@ func pluginsFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.Gat
 			}
 			filters := parsePluginsAnnotation(rule.Ingress.Annotations)
 			patchHTTPRoutePlugins(&httpRoute, filters)
+			if len(filters) > 0 {
+				ingress := rule.Ingress
+				for i := range httpRoute.Spec.Rules {
+					common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, kongAnnotation(pluginsKey))
+				}
+			}
 		}
 	}
 	return nil
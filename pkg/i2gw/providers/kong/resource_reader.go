@@ -50,12 +50,18 @@ func newResourceReader(conf *i2gw.ProviderConf) *resourceReader {
 func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
 	storage := newResourceStorage()
 
-	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(KongIngressClass))
+	ingresses, err := common.ReadIngressesFromCluster(ctx, r.conf.Client, sets.New(KongIngressClass), r.conf.Providers)
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = ingresses
 
+	services, err := common.ReadServicesFromCluster(ctx, r.conf.Client)
+	if err != nil {
+		return nil, err
+	}
+	storage.Services = services
+
 	tcpIngresses, err := r.readTCPIngressesFromCluster(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read TCPIngresses: %w", err)
@@ -68,12 +74,18 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 func (r *resourceReader) readResourcesFromFile(filename string) (*storage, error) {
 	storage := newResourceStorage()
 
-	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New(KongIngressClass))
+	ingresses, err := common.ReadIngressesFromFile(filename, r.conf.Namespace, sets.New(KongIngressClass), r.conf.Providers)
 	if err != nil {
 		return nil, err
 	}
 	storage.Ingresses = ingresses
 
+	services, err := common.ReadServicesFromFile(filename, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	storage.Services = services
+
 	tcpIngresses, err := r.readTCPIngressesFromFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read TCPIngresses: %w", err)
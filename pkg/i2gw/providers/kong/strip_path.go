@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// stripPathFeature parses the Kong Ingress Controller strip-path annotation and
+// converts it into HTTPRoutes rule's URLRewrite filters stripping the matched
+// prefix, before the requests are forwarded to the backends.
+//
+// Example: konghq.com/strip-path: "true"
+//
+// The regular expression paths, prefixed with `/~`, are not rewritten, as the
+// prefix they matched is not known.
+func stripPathFeature(ingresses []networkingv1.Ingress, gatewayResources *i2gw.GatewayResources) field.ErrorList {
+	var errs field.ErrorList
+	ruleGroups := common.GetRuleGroups(ingresses)
+	for _, rg := range ruleGroups {
+		key := types.NamespacedName{Namespace: rg.Namespace, Name: common.RouteName(rg.Name, rg.Host)}
+		httpRoute, ok := gatewayResources.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+		for _, rule := range rg.Rules {
+			ingress := rule.Ingress
+			value, ok := ingress.Annotations[kongAnnotation(stripPathKey)]
+			if !ok || rule.IngressRule.HTTP == nil {
+				continue
+			}
+			stripPath, err := strconv.ParseBool(value)
+			if err != nil {
+				fieldPath := field.NewPath(fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)).Child("metadata").Child("annotations").Child(kongAnnotation(stripPathKey))
+				errs = append(errs, field.Invalid(fieldPath, value, "must be a boolean"))
+				continue
+			}
+			if !stripPath {
+				continue
+			}
+			for i := range httpRoute.Spec.Rules {
+				if !ruleMatchesAnyPath(httpRoute.Spec.Rules[i], rule.IngressRule.HTTP.Paths) {
+					continue
+				}
+				if !common.StripPathPrefix.Apply(&httpRoute.Spec.Rules[i]) {
+					notify(notifications.WarningNotification, fmt.Sprintf("%s is not converted for the regular expression paths, whose matched prefix is not known, in HTTPRoute %s/%s", kongAnnotation(stripPathKey), httpRoute.Namespace, httpRoute.Name), &ingress)
+					continue
+				}
+				common.RecordIngressProvenance(common.HTTPRouteGVK.Kind, key, fmt.Sprintf("spec.rules[%d].filters", i), &ingress, kongAnnotation(stripPathKey))
+			}
+		}
+		gatewayResources.HTTPRoutes[key] = httpRoute
+	}
+	return errs
+}
+
+// ruleMatchesAnyPath returns whether the rule matches one of the Ingress paths,
+// the `/~` prefix of the regular expression paths being trimmed.
+func ruleMatchesAnyPath(rule gatewayv1.HTTPRouteRule, paths []networkingv1.HTTPIngressPath) bool {
+	for _, path := range paths {
+		value := path.Path
+		if path.PathType != nil && *path.PathType == networkingv1.PathTypeImplementationSpecific {
+			value = strings.TrimPrefix(value, "/~")
+		}
+		for _, match := range rule.Matches {
+			if match.Path != nil && match.Path.Value != nil && *match.Path.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kong
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestStripPathFeature(t *testing.T) {
+	testCases := []struct {
+		name            string
+		stripPath       string
+		pathType        networkingv1.PathType
+		path            string
+		expectedFilters []gatewayv1.HTTPRouteFilter
+		expectedErrors  int
+	}{
+		{
+			name:      "prefix path",
+			stripPath: "true",
+			pathType:  networkingv1.PathTypePrefix,
+			path:      "/api",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: ptrTo("/"),
+				}},
+			}},
+		},
+		{
+			name:      "exact path",
+			stripPath: "true",
+			pathType:  networkingv1.PathTypeExact,
+			path:      "/api",
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: &gatewayv1.HTTPPathModifier{
+					Type:            gatewayv1.FullPathHTTPPathModifier,
+					ReplaceFullPath: ptrTo("/"),
+				}},
+			}},
+		},
+		{
+			name:      "regular expression path",
+			stripPath: "true",
+			pathType:  networkingv1.PathTypeImplementationSpecific,
+			path:      "/~/api/v[0-9]+",
+		},
+		{
+			name:      "disabled",
+			stripPath: "false",
+			pathType:  networkingv1.PathTypePrefix,
+			path:      "/api",
+		},
+		{
+			name:           "invalid",
+			stripPath:      "yes please",
+			pathType:       networkingv1.PathTypePrefix,
+			path:           "/api",
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingresses := []networkingv1.Ingress{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "api",
+					Namespace:   "default",
+					Annotations: map[string]string{"konghq.com/strip-path": tc.stripPath},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: ptrTo(KongIngressClass),
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     tc.path,
+									PathType: ptrTo(tc.pathType),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "api",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}}
+
+			gatewayResources, errs := common.ToGateway(ingresses, i2gw.ProviderImplementationSpecificOptions{
+				ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificHTTPPathTypeMatch,
+			})
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors converting ingresses, got %+v", errs)
+			}
+			errs = stripPathFeature(ingresses, &gatewayResources)
+			if len(errs) != tc.expectedErrors {
+				t.Fatalf("Expected %d errors, got %+v", tc.expectedErrors, errs)
+			}
+
+			httpRoute := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "api-example-com"}]
+			if diff := cmp.Diff(tc.expectedFilters, httpRoute.Spec.Rules[0].Filters); diff != "" {
+				t.Errorf("Unexpected filters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
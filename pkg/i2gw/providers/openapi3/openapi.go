@@ -36,7 +36,7 @@ const (
 )
 
 func init() {
-	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.RegisterProvider(ProviderName, NewProvider)
 
 	i2gw.RegisterProviderSpecificFlag(ProviderName, i2gw.ProviderSpecificFlag{
 		Name:        BackendFlag,
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// AddReferenceGrant allows the objects of the from group and kind in the from
+// namespace to reference the object of the to kind, with a ReferenceGrant of
+// the given name in the namespace of the object, like
+// from-<namespace>-to-<kind>-<name>. A name longer than the limit of object
+// names is truncated and suffixed with a hash of the name. ReferenceGrants are
+// keyed by namespace and name, so adding the same grant twice has no effect.
+func AddReferenceGrant(gatewayResources *GatewayResources, from gatewayv1beta1.ReferenceGrantFrom, toKind gatewayv1.Kind, to types.NamespacedName, name string) {
+	if gatewayResources.ReferenceGrants == nil {
+		gatewayResources.ReferenceGrants = map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{}
+	}
+	toName := gatewayv1.ObjectName(to.Name)
+	referenceGrant := gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prefixedName("", name, validation.DNS1123SubdomainMaxLength),
+			Namespace: to.Namespace,
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{from},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Kind: toKind,
+				Name: &toName,
+			}},
+		},
+	}
+	referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	gatewayResources.ReferenceGrants[types.NamespacedName{Namespace: referenceGrant.Namespace, Name: referenceGrant.Name}] = referenceGrant
+}
+
+// omitReferenceGrants removes the ReferenceGrants generated by the provider, for
+// the users managing them separately. The cross-namespace references of the
+// routes are kept, so a Warning notification is emitted for every removed
+// ReferenceGrant, listing the references it allows.
+func omitReferenceGrants(gatewayResources *GatewayResources, providerName ProviderName) {
+	keys := make([]types.NamespacedName, 0, len(gatewayResources.ReferenceGrants))
+	for key := range gatewayResources.ReferenceGrants {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, key := range keys {
+		referenceGrant := gatewayResources.ReferenceGrants[key]
+		var from, to []string
+		for _, f := range referenceGrant.Spec.From {
+			from = append(from, fmt.Sprintf("%s of namespace %s", f.Kind, f.Namespace))
+		}
+		for _, t := range referenceGrant.Spec.To {
+			if t.Name != nil {
+				to = append(to, fmt.Sprintf("%s %s/%s", t.Kind, key.Namespace, *t.Name))
+			} else {
+				to = append(to, fmt.Sprintf("every %s of namespace %s", t.Kind, key.Namespace))
+			}
+		}
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("ReferenceGrant %s is not generated, the references from the %s to %s are rejected until a ReferenceGrant in namespace %s allows them", key, strings.Join(from, ", "), strings.Join(to, ", "), key.Namespace),
+			CallingObjects: []client.Object{&referenceGrant},
+		}, string(providerName))
+	}
+	gatewayResources.ReferenceGrants = nil
+}
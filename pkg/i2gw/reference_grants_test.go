@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_omitReferenceGrants(t *testing.T) {
+	referenceGrantResources := func() GatewayResources {
+		return GatewayResources{
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				{Namespace: "default", Name: "foo"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+					Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{
+						BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name:      "bar",
+							Namespace: ptr.To(gatewayv1.Namespace("backends")),
+						}}}},
+					}}},
+				},
+			},
+			ReferenceGrants: map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+				{Namespace: "backends", Name: "from-default-to-service-bar"}: {
+					ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "from-default-to-service-bar"},
+					Spec: gatewayv1beta1.ReferenceGrantSpec{
+						From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "default"}},
+						To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service", Name: ptr.To(gatewayv1.ObjectName("bar"))}},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                    string
+		omitReferenceGrants     bool
+		expectedReferenceGrants int
+		expectedWarning         string
+	}{
+		{
+			name:                    "ReferenceGrants emitted",
+			expectedReferenceGrants: 1,
+		},
+		{
+			name:                "ReferenceGrants omitted",
+			omitReferenceGrants: true,
+			expectedWarning:     "the references from the HTTPRoute of namespace default to Service backends/bar are rejected",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			gatewayResources := referenceGrantResources()
+
+			applyGatewayOptions(&gatewayResources, GatewayOptions{OmitReferenceGrants: tc.omitReferenceGrants}, "test-provider")
+
+			if len(gatewayResources.ReferenceGrants) != tc.expectedReferenceGrants {
+				t.Errorf("Expected %d ReferenceGrants, got %+v", tc.expectedReferenceGrants, gatewayResources.ReferenceGrants)
+			}
+			if backendRef := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "foo"}].Spec.Rules[0].BackendRefs[0]; backendRef.Namespace == nil {
+				t.Errorf("Expected the cross-namespace backendRef to be kept, got %+v", backendRef)
+			}
+
+			notifs := notifications.NotificationAggr.Notifications["test-provider"]
+			if tc.expectedWarning == "" {
+				if len(notifs) > 0 {
+					t.Errorf("Expected no notification, got %+v", notifs)
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Type != notifications.WarningNotification || !strings.Contains(notifs[0].Message, tc.expectedWarning) {
+				t.Errorf("Expected a Warning containing %q, got %+v", tc.expectedWarning, notifs)
+			}
+		})
+	}
+}
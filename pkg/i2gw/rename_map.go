@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceNames are the names of the resources generated from an Ingress,
+// overriding the names derived by the providers. An empty name keeps the
+// derived one.
+type ResourceNames struct {
+	// Gateway is the name of the Gateway the Ingress is converted to.
+	Gateway string `json:"gateway,omitempty"`
+	// HTTPRoute is the name of the HTTPRoute generated from the Ingress. The
+	// HTTPRoutes of an Ingress with several hosts keep the suffix of their
+	// host, e.g. <name>-foo-example-com, and the HTTP to HTTPS redirect
+	// HTTPRoutes are named <name>-http-redirect.
+	HTTPRoute string `json:"httpRoute,omitempty"`
+}
+
+// validateRenameMap returns an error if a name of the rename map is not a
+// valid name of its kind.
+func validateRenameMap(renameMap map[types.NamespacedName]ResourceNames) error {
+	for ingress, names := range renameMap {
+		if names.Gateway != "" {
+			if errs := validation.IsDNS1123Label(names.Gateway); len(errs) > 0 {
+				return fmt.Errorf("invalid Gateway name %s for Ingress %s: %s", names.Gateway, ingress, strings.Join(errs, ", "))
+			}
+		}
+		if names.HTTPRoute != "" {
+			if errs := validation.IsDNS1123Subdomain(names.HTTPRoute); len(errs) > 0 {
+				return fmt.Errorf("invalid HTTPRoute name %s for Ingress %s: %s", names.HTTPRoute, ingress, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// renameResources renames the Gateways and HTTPRoutes generated from the
+// Ingresses of the rename map, and updates the route parentRefs to the renamed
+// Gateways.
+//
+// A Gateway is renamed if it was generated from the Ingress. An HTTPRoute is
+// renamed if it was generated from the Ingress and its derived name starts with
+// the name of the Ingress, like <ingress>-<host>, as the HTTPRoute of a host
+// shared by several Ingresses is named after one of them. An error is returned
+// when the Ingresses of a Gateway map it to distinct names, or when a new name
+// is already the one of another resource of the same kind and namespace.
+func renameResources(gatewayResources *GatewayResources, renameMap map[types.NamespacedName]ResourceNames) field.ErrorList {
+	ingresses := make([]types.NamespacedName, 0, len(renameMap))
+	for ingress := range renameMap {
+		ingresses = append(ingresses, ingress)
+	}
+	sort.Slice(ingresses, func(i, j int) bool { return ingresses[i].String() < ingresses[j].String() })
+
+	var errs field.ErrorList
+	gatewayNames := map[types.NamespacedName]string{}
+	gatewayIngresses := map[types.NamespacedName]types.NamespacedName{}
+	routeNames := map[types.NamespacedName]string{}
+	for _, ingress := range ingresses {
+		names := renameMap[ingress]
+		fieldPath := field.NewPath("renameMap").Key(ingress.String())
+		if names.Gateway != "" {
+			for _, key := range sortedKeys(gatewayResources.Gateways) {
+				if !provenance.ProvenanceAggr.HasIngressSource(provenance.ObjectRef{Kind: "Gateway", NamespacedName: key}, ingress.Namespace, ingress.Name) {
+					continue
+				}
+				if name, ok := gatewayNames[key]; ok && name != names.Gateway {
+					errs = append(errs, field.Invalid(fieldPath.Child("gateway"), names.Gateway, fmt.Sprintf("Gateway %s is already renamed to %s for Ingress %s", key, name, gatewayIngresses[key])))
+					continue
+				}
+				gatewayNames[key] = names.Gateway
+				gatewayIngresses[key] = ingress
+			}
+		}
+		if names.HTTPRoute != "" {
+			errs = append(errs, renameIngressRoutes(gatewayResources, ingress, names.HTTPRoute, fieldPath.Child("httpRoute"), routeNames)...)
+		}
+	}
+	errs = append(errs, renameCollisions(gatewayResources.Gateways, "Gateway", gatewayNames)...)
+	errs = append(errs, renameCollisions(gatewayResources.HTTPRoutes, "HTTPRoute", routeNames)...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	renames := map[provenance.ObjectRef]provenance.ObjectRef{}
+	gatewayResources.Gateways = renameObjects(gatewayResources.Gateways, "Gateway", gatewayNames, renames)
+	gatewayResources.HTTPRoutes = renameObjects(gatewayResources.HTTPRoutes, "HTTPRoute", routeNames, renames)
+	provenance.ProvenanceAggr.Rename(renames)
+	gatewayResources.TrafficPolicies = renameObjectKeys(gatewayResources.TrafficPolicies, routeNames)
+	gatewayResources.GatewayPolicies = renameObjectKeys(gatewayResources.GatewayPolicies, gatewayNames)
+
+	for key, route := range gatewayResources.HTTPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.HTTPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.GRPCRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TLSRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.TLSRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TCPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.TCPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.UDPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.UDPRoutes[key] = route
+	}
+	return nil
+}
+
+// renameIngressRoutes adds the new names of the HTTPRoutes generated from the
+// Ingress to routeNames. The HTTPRoutes of the hosts keep the suffix of their
+// host if there are several of them, and the redirect HTTPRoutes of the
+// --http-listener-policy redirect, <route>-http-redirect, are named after their
+// renamed HTTPRoute without counting as another host. An error is returned for
+// the new names that are not valid HTTPRoute names, e.g. too long once suffixed.
+func renameIngressRoutes(gatewayResources *GatewayResources, ingress types.NamespacedName, name string, fieldPath *field.Path, routeNames map[types.NamespacedName]string) field.ErrorList {
+	keys := sets.New[types.NamespacedName]()
+	for key := range gatewayResources.HTTPRoutes {
+		if key.Namespace == ingress.Namespace && strings.HasPrefix(key.Name, ingress.Name+"-") &&
+			provenance.ProvenanceAggr.HasIngressSource(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: key}, ingress.Namespace, ingress.Name) {
+			keys.Insert(key)
+		}
+	}
+	var hostKeys []types.NamespacedName
+	redirectKeys := map[types.NamespacedName]types.NamespacedName{}
+	for _, key := range sortedKeys(gatewayResources.HTTPRoutes) {
+		if !keys.Has(key) {
+			continue
+		}
+		if routeName, ok := strings.CutSuffix(key.Name, httpRedirectRouteSuffix); ok && keys.Has(types.NamespacedName{Namespace: key.Namespace, Name: routeName}) {
+			redirectKeys[types.NamespacedName{Namespace: key.Namespace, Name: routeName}] = key
+			continue
+		}
+		hostKeys = append(hostKeys, key)
+	}
+
+	var errs field.ErrorList
+	for _, key := range hostKeys {
+		newName := name
+		if len(hostKeys) > 1 {
+			newName += strings.TrimPrefix(key.Name, ingress.Name)
+		}
+		newNames := map[types.NamespacedName]string{key: newName}
+		if redirectKey, ok := redirectKeys[key]; ok {
+			newNames[redirectKey] = newName + httpRedirectRouteSuffix
+		}
+		for _, routeKey := range sortedKeys(newNames) {
+			if validationErrs := validation.IsDNS1123Subdomain(newNames[routeKey]); len(validationErrs) > 0 {
+				errs = append(errs, field.Invalid(fieldPath, name, fmt.Sprintf("HTTPRoute %s cannot be renamed to %s: %s", routeKey, newNames[routeKey], strings.Join(validationErrs, ", "))))
+				continue
+			}
+			routeNames[routeKey] = newNames[routeKey]
+		}
+	}
+	return errs
+}
+
+// renameCollisions returns an error for every object renamed to the name of
+// another object of the namespace, renamed or not.
+func renameCollisions[T any](objects map[types.NamespacedName]T, kind string, names map[types.NamespacedName]string) field.ErrorList {
+	var errs field.ErrorList
+	renamedFrom := map[types.NamespacedName]types.NamespacedName{}
+	for _, key := range sortedKeys(objects) {
+		newKey := key
+		if name, ok := names[key]; ok {
+			newKey.Name = name
+		}
+		if from, ok := renamedFrom[newKey]; ok {
+			errs = append(errs, field.Duplicate(field.NewPath("renameMap"), fmt.Sprintf("%s %s and %s would both be named %s", kind, from, key, newKey)))
+			continue
+		}
+		renamedFrom[newKey] = key
+	}
+	return errs
+}
+
+// renameObjects returns the objects with their new names, keyed by their new
+// names, and records the renamed objects in renames.
+func renameObjects[T any, PT interface {
+	*T
+	client.Object
+}](objects map[types.NamespacedName]T, kind string, names map[types.NamespacedName]string, renames map[provenance.ObjectRef]provenance.ObjectRef) map[types.NamespacedName]T {
+	if len(names) == 0 {
+		return objects
+	}
+	renamed := make(map[types.NamespacedName]T, len(objects))
+	for key, object := range objects {
+		name, ok := names[key]
+		if !ok {
+			renamed[key] = object
+			continue
+		}
+		newKey := types.NamespacedName{Namespace: key.Namespace, Name: name}
+		PT(&object).SetName(name)
+		renamed[newKey] = object
+		renames[provenance.ObjectRef{Kind: kind, NamespacedName: key}] = provenance.ObjectRef{Kind: kind, NamespacedName: newKey}
+	}
+	return renamed
+}
+
+// renameObjectKeys returns the objects keyed by the new names of the renamed
+// keys.
+func renameObjectKeys[T any](objects map[types.NamespacedName]T, names map[types.NamespacedName]string) map[types.NamespacedName]T {
+	if len(names) == 0 || objects == nil {
+		return objects
+	}
+	renamed := make(map[types.NamespacedName]T, len(objects))
+	for key, object := range objects {
+		if name, ok := names[key]; ok {
+			key.Name = name
+		}
+		renamed[key] = object
+	}
+	return renamed
+}
+
+// sortedKeys returns the keys of the objects sorted by namespace and name.
+func sortedKeys[T any](objects map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_renameResources(t *testing.T) {
+	newGatewayResources := func() GatewayResources {
+		provenance.ProvenanceAggr.Sources = map[provenance.ObjectRef]map[string][]string{}
+		provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "Gateway", NamespacedName: types.NamespacedName{Namespace: "default", Name: "nginx"}}, "", "Ingress default/shop")
+		provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "shop-foo-example-com"}}, "", "Ingress default/shop")
+		provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "shop-bar-example-com"}}, "", "Ingress default/shop")
+		provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "blog-blog-example-com"}}, "", "Ingress default/blog")
+		return GatewayResources{
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "nginx"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}},
+			},
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				{Namespace: "default", Name: "shop-foo-example-com"}:  testRenamedRoute("shop-foo-example-com", "nginx"),
+				{Namespace: "default", Name: "shop-bar-example-com"}:  testRenamedRoute("shop-bar-example-com", "nginx"),
+				{Namespace: "default", Name: "blog-blog-example-com"}: testRenamedRoute("blog-blog-example-com", "nginx"),
+			},
+		}
+	}
+
+	t.Run("overrides", func(t *testing.T) {
+		gatewayResources := newGatewayResources()
+		errs := renameResources(&gatewayResources, map[types.NamespacedName]ResourceNames{
+			{Namespace: "default", Name: "shop"}: {Gateway: "web", HTTPRoute: "shop-routes"},
+			{Namespace: "default", Name: "blog"}: {HTTPRoute: "blog"},
+			{Namespace: "other", Name: "shop"}:   {Gateway: "other"},
+		})
+		if len(errs) > 0 {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+
+		expected := GatewayResources{
+			Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+				{Namespace: "default", Name: "web"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}},
+			},
+			HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+				{Namespace: "default", Name: "shop-routes-foo-example-com"}: testRenamedRoute("shop-routes-foo-example-com", "web"),
+				{Namespace: "default", Name: "shop-routes-bar-example-com"}: testRenamedRoute("shop-routes-bar-example-com", "web"),
+				{Namespace: "default", Name: "blog"}:                        testRenamedRoute("blog", "web"),
+			},
+		}
+		if diff := cmp.Diff(expected, gatewayResources); diff != "" {
+			t.Errorf("Unexpected resources (-want +got):\n%s", diff)
+		}
+		renamedRoute := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "blog"}}
+		if !provenance.ProvenanceAggr.HasIngressSource(renamedRoute, "default", "blog") {
+			t.Errorf("Expected the renamed HTTPRoute to keep its source Ingress")
+		}
+	})
+
+	t.Run("redirect route", func(t *testing.T) {
+		gatewayResources := newGatewayResources()
+		redirectKey := types.NamespacedName{Namespace: "default", Name: "blog-blog-example-com-http-redirect"}
+		provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: redirectKey}, "", "Ingress default/blog")
+		gatewayResources.HTTPRoutes[redirectKey] = testRenamedRoute(redirectKey.Name, "nginx")
+		errs := renameResources(&gatewayResources, map[types.NamespacedName]ResourceNames{
+			{Namespace: "default", Name: "blog"}: {HTTPRoute: "blog"},
+		})
+		if len(errs) > 0 {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+
+		// The redirect route is not another host of the Ingress.
+		var names []string
+		for _, key := range sortedKeys(gatewayResources.HTTPRoutes) {
+			names = append(names, key.Name)
+		}
+		expected := []string{"blog", "blog-http-redirect", "shop-bar-example-com", "shop-foo-example-com"}
+		if diff := cmp.Diff(expected, names); diff != "" {
+			t.Errorf("Unexpected HTTPRoutes (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("suffixed name too long", func(t *testing.T) {
+		gatewayResources := newGatewayResources()
+		errs := renameResources(&gatewayResources, map[types.NamespacedName]ResourceNames{
+			{Namespace: "default", Name: "shop"}: {HTTPRoute: strings.Repeat("a", 245)},
+		})
+		if len(errs) != 2 {
+			t.Fatalf("Expected an error for each suffixed HTTPRoute name, got %v", errs)
+		}
+		if _, ok := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "shop-foo-example-com"}]; !ok {
+			t.Errorf("Expected the HTTPRoutes not to be renamed")
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		gatewayResources := newGatewayResources()
+		errs := renameResources(&gatewayResources, map[types.NamespacedName]ResourceNames{
+			{Namespace: "default", Name: "blog"}: {HTTPRoute: "shop-foo-example-com"},
+		})
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %v", errs)
+		}
+		if _, ok := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "default", Name: "blog-blog-example-com"}]; !ok {
+			t.Errorf("Expected the HTTPRoutes not to be renamed on errors")
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		err := GatewayOptions{RenameMap: map[types.NamespacedName]ResourceNames{
+			{Namespace: "default", Name: "shop"}: {Gateway: "Web.Example"},
+		}}.Validate()
+		if err == nil {
+			t.Errorf("Expected an error for the Gateway name which is not a DNS label")
+		}
+	})
+}
+
+func testRenamedRoute(name, gatewayName string) gatewayv1.HTTPRoute {
+	return gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+			{Name: gatewayv1.ObjectName(gatewayName)},
+		}}},
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxResourcePrefixLength leaves room in the Gateway names for at least the
+// hash suffix of the truncated names.
+const maxResourcePrefixLength = validation.DNS1123LabelMaxLength - len("-01234567")
+
+// validateResourcePrefix returns an error if the names starting with the
+// prefix are not valid object names.
+func validateResourcePrefix(prefix string) error {
+	if len(prefix) > maxResourcePrefixLength {
+		return fmt.Errorf("invalid resource prefix %s, it must be at most %d characters long", prefix, maxResourcePrefixLength)
+	}
+	if errs := validation.IsDNS1123Label(strings.ReplaceAll(prefix, ".", "-") + "x"); len(errs) > 0 {
+		return fmt.Errorf("invalid resource prefix %s, it must consist of lower case alphanumeric characters, '-' or '.', and start with an alphanumeric character", prefix)
+	}
+	return nil
+}
+
+// prefixResourceNames prefixes the names of the generated Gateways, routes,
+// ReferenceGrants and BackendTLSPolicies, and updates the route parentRefs to
+// the renamed Gateways. The GatewayClasses are not renamed, as they name the
+// Gateway API implementations.
+//
+// Names longer than the limit are truncated, and suffixed with a hash of the
+// prefixed name to stay unique. The limit is the one of object names, except
+// for the Gateways, whose names must be valid label values, as implementations
+// label the resources they provision for a Gateway with its name.
+func prefixResourceNames(gatewayResources *GatewayResources, prefix string) {
+	renames := map[provenance.ObjectRef]provenance.ObjectRef{}
+	gatewayNames := map[types.NamespacedName]string{}
+
+	gatewayResources.Gateways = prefixObjectNames(gatewayResources.Gateways, "Gateway", prefix, validation.DNS1123LabelMaxLength, renames)
+	for from, to := range renames {
+		gatewayNames[from.NamespacedName] = to.Name
+	}
+	gatewayResources.HTTPRoutes = prefixObjectNames(gatewayResources.HTTPRoutes, "HTTPRoute", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.GRPCRoutes = prefixObjectNames(gatewayResources.GRPCRoutes, "GRPCRoute", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.TLSRoutes = prefixObjectNames(gatewayResources.TLSRoutes, "TLSRoute", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.TCPRoutes = prefixObjectNames(gatewayResources.TCPRoutes, "TCPRoute", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.UDPRoutes = prefixObjectNames(gatewayResources.UDPRoutes, "UDPRoute", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.ReferenceGrants = prefixObjectNames(gatewayResources.ReferenceGrants, "ReferenceGrant", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	gatewayResources.BackendTLSPolicies = prefixObjectNames(gatewayResources.BackendTLSPolicies, "BackendTLSPolicy", prefix, validation.DNS1123SubdomainMaxLength, renames)
+	provenance.ProvenanceAggr.Rename(renames)
+	gatewayResources.TrafficPolicies = prefixTrafficPolicies(gatewayResources.TrafficPolicies, renames)
+	gatewayResources.GatewayPolicies = renameObjectKeys(gatewayResources.GatewayPolicies, gatewayNames)
+
+	for key, route := range gatewayResources.HTTPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.HTTPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.GRPCRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.GRPCRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TLSRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.TLSRoutes[key] = route
+	}
+	for key, route := range gatewayResources.TCPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.TCPRoutes[key] = route
+	}
+	for key, route := range gatewayResources.UDPRoutes {
+		renameParentRefs(route.Spec.ParentRefs, route.Namespace, gatewayNames)
+		gatewayResources.UDPRoutes[key] = route
+	}
+}
+
+// prefixTrafficPolicies returns the traffic policies keyed by the new names of
+// their routes.
+func prefixTrafficPolicies(policies map[types.NamespacedName]TrafficPolicy, renames map[provenance.ObjectRef]provenance.ObjectRef) map[types.NamespacedName]TrafficPolicy {
+	if policies == nil {
+		return nil
+	}
+	prefixed := make(map[types.NamespacedName]TrafficPolicy, len(policies))
+	for key, policy := range policies {
+		for _, kind := range []string{"HTTPRoute", "GRPCRoute"} {
+			if to, ok := renames[provenance.ObjectRef{Kind: kind, NamespacedName: key}]; ok {
+				prefixed[to.NamespacedName] = policy
+				break
+			}
+		}
+	}
+	return prefixed
+}
+
+// prefixObjectNames returns the objects with prefixed names, keyed by their new
+// names, and records the renamed objects in renames.
+func prefixObjectNames[T any, PT interface {
+	*T
+	client.Object
+}](objects map[types.NamespacedName]T, kind, prefix string, maxLength int, renames map[provenance.ObjectRef]provenance.ObjectRef) map[types.NamespacedName]T {
+	if objects == nil {
+		return nil
+	}
+	prefixed := make(map[types.NamespacedName]T, len(objects))
+	for key, object := range objects {
+		newKey := types.NamespacedName{Namespace: key.Namespace, Name: prefixedName(prefix, key.Name, maxLength)}
+		PT(&object).SetName(newKey.Name)
+		prefixed[newKey] = object
+		renames[provenance.ObjectRef{Kind: kind, NamespacedName: key}] = provenance.ObjectRef{Kind: kind, NamespacedName: newKey}
+	}
+	return prefixed
+}
+
+// prefixedName returns the name with the prefix, truncated to maxLength and
+// suffixed with a hash of the prefixed name if it is too long.
+func prefixedName(prefix, name string, maxLength int) string {
+	prefixed := prefix + name
+	if len(prefixed) <= maxLength {
+		return prefixed
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(prefixed))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return strings.TrimRight(prefixed[:maxLength-len(suffix)], "-.") + suffix
+}
+
+// renameParentRefs replaces the names of the renamed Gateways in the
+// parentRefs of a route of the namespace.
+func renameParentRefs(parentRefs []gatewayv1.ParentReference, routeNamespace string, gatewayNames map[types.NamespacedName]string) {
+	for i, parentRef := range parentRefs {
+		gateway, ok := parentRefGateway(parentRef, routeNamespace)
+		if !ok {
+			continue
+		}
+		if name, ok := gatewayNames[gateway]; ok {
+			parentRefs[i].Name = gatewayv1.ObjectName(name)
+		}
+	}
+}
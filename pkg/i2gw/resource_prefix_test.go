@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provenance"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_prefixResourceNames(t *testing.T) {
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	routeKey := types.NamespacedName{Namespace: "default", Name: "foo-example-com"}
+	provenance.ProvenanceAggr.Record(provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: routeKey}, "", "Ingress default/foo")
+
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			gatewayKey: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}, Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx"}},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			routeKey: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-example-com"},
+				Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+					{Name: "nginx"},
+					{Name: "existing", Namespace: (*gatewayv1.Namespace)(&gatewayKey.Namespace)},
+				}}},
+			},
+		},
+		ReferenceGrants: map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+			{Namespace: "backends", Name: "from-default"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "from-default"}},
+		},
+	}
+
+	applyGatewayOptions(&gatewayResources, GatewayOptions{ResourcePrefix: "migrated-"}, "test-provider")
+
+	expected := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "migrated-nginx"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "migrated-nginx"}, Spec: gatewayv1.GatewaySpec{GatewayClassName: "nginx"}},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "migrated-foo-example-com"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "migrated-foo-example-com"},
+				Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+					{Name: "migrated-nginx"},
+					{Name: "existing", Namespace: (*gatewayv1.Namespace)(&gatewayKey.Namespace)},
+				}}},
+			},
+		},
+		ReferenceGrants: map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+			{Namespace: "backends", Name: "migrated-from-default"}: {ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "migrated-from-default"}},
+		},
+	}
+	if diff := cmp.Diff(expected, gatewayResources); diff != "" {
+		t.Errorf("Unexpected resources (-want +got):\n%s", diff)
+	}
+
+	prefixedRoute := provenance.ObjectRef{Kind: "HTTPRoute", NamespacedName: types.NamespacedName{Namespace: "default", Name: "migrated-foo-example-com"}}
+	if diff := cmp.Diff(map[string][]string{"": {"Ingress default/foo"}}, provenance.ProvenanceAggr.ObjectSources(prefixedRoute)); diff != "" {
+		t.Errorf("Unexpected sources of the prefixed HTTPRoute (-want +got):\n%s", diff)
+	}
+}
+
+func Test_prefixedName(t *testing.T) {
+	long := strings.Repeat("a", 60)
+	testCases := []struct {
+		name      string
+		objName   string
+		maxLength int
+	}{
+		{name: "short name", objName: "foo", maxLength: validation.DNS1123LabelMaxLength},
+		{name: "name at the limit", objName: long[:validation.DNS1123LabelMaxLength-len("migrated-")], maxLength: validation.DNS1123LabelMaxLength},
+		{name: "truncated Gateway name", objName: long, maxLength: validation.DNS1123LabelMaxLength},
+		{name: "truncated route name", objName: strings.Repeat("b", 250), maxLength: validation.DNS1123SubdomainMaxLength},
+		{name: "truncated before a dash", objName: strings.Repeat("a", 45) + "-foo", maxLength: validation.DNS1123LabelMaxLength},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := prefixedName("migrated-", tc.objName, tc.maxLength)
+			if !strings.HasPrefix(got, "migrated-") {
+				t.Errorf("Expected %q to start with the prefix", got)
+			}
+			if len(got) > tc.maxLength {
+				t.Errorf("Expected %q to be at most %d characters long, got %d", got, tc.maxLength, len(got))
+			}
+			if len("migrated-"+tc.objName) <= tc.maxLength && got != "migrated-"+tc.objName {
+				t.Errorf("Expected %q not to be truncated, got %q", "migrated-"+tc.objName, got)
+			}
+			if errs := validation.IsDNS1123Subdomain(got); len(errs) > 0 {
+				t.Errorf("Expected %q to be a valid name, got %v", got, errs)
+			}
+		})
+	}
+
+	if prefixedName("migrated-", long+"1", validation.DNS1123LabelMaxLength) == prefixedName("migrated-", long+"2", validation.DNS1123LabelMaxLength) {
+		t.Errorf("Expected the truncated names of different names to differ")
+	}
+}
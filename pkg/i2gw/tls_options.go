@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Gateway API implementations supported by the --target-implementation flag.
+const (
+	EnvoyGatewayImplementation = "envoy-gateway"
+	IstioImplementation        = "istio"
+)
+
+// The supported TLS versions of the --tls-min-version flag.
+var supportedTLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+// genericTLSMinVersionOption is the tls.options key set for the targets without
+// a verified way to set the minimum TLS version. Gateway API defines no
+// standard key, so an implementation may ignore it.
+const genericTLSMinVersionOption gatewayv1.AnnotationKey = "tls-min-version"
+
+// setTLSMinVersion sets the minimum TLS version of the terminating TLS
+// listeners. For Envoy Gateway, it is set in the Gateway policy, converted to
+// the spec.tls.minVersion of the ClientTrafficPolicy of the Gateway, see
+// https://gateway.envoyproxy.io/docs/api/extension_types/#clienttlssettings.
+// Istio has no tls.options key for it, so the listeners are left unchanged and a
+// Warning notification is emitted. Otherwise, the generic tls-min-version key is
+// set in the tls.options of the listeners and a Warning notification is
+// emitted, as the key may need to be adjusted for the Gateway API
+// implementation.
+func setTLSMinVersion(gatewayResources *GatewayResources, minVersion, targetImplementation string, providerName ProviderName) {
+	for gatewayKey, gateway := range gatewayResources.Gateways {
+		var changed bool
+		for i, listener := range gateway.Spec.Listeners {
+			if listener.TLS == nil || (listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1.TLSModeTerminate) {
+				continue
+			}
+			changed = true
+			if targetImplementation == EnvoyGatewayImplementation || targetImplementation == IstioImplementation {
+				continue
+			}
+			if listener.TLS.Options == nil {
+				gateway.Spec.Listeners[i].TLS.Options = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{}
+			}
+			gateway.Spec.Listeners[i].TLS.Options[genericTLSMinVersionOption] = gatewayv1.AnnotationValue(minVersion)
+		}
+		if !changed {
+			continue
+		}
+		if targetImplementation == EnvoyGatewayImplementation {
+			if gatewayResources.GatewayPolicies == nil {
+				gatewayResources.GatewayPolicies = map[types.NamespacedName]GatewayPolicy{}
+			}
+			gatewayResources.GatewayPolicies[gatewayKey] = gatewayResources.GatewayPolicies[gatewayKey].merge(GatewayPolicy{TLSMinVersion: minVersion})
+			continue
+		}
+		if targetImplementation == IstioImplementation {
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        fmt.Sprintf("the minimum TLS version %s is not set on the TLS listeners of Gateway %s/%s, Istio has no tls.options key for it", minVersion, gateway.Namespace, gateway.Name),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+			continue
+		}
+		gatewayResources.Gateways[gatewayKey] = gateway
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.WarningNotification,
+			Message:        fmt.Sprintf("the TLS listeners of Gateway %s/%s have the generic tls.options %s: %s, which Gateway API does not standardize, the key may need to be adjusted for your Gateway API implementation", gateway.Namespace, gateway.Name, genericTLSMinVersionOption, minVersion),
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_applyGatewayOptions(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		options               GatewayOptions
+		expectedOptions       map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+		expectedPolicies      map[types.NamespacedName]GatewayPolicy
+		expectedNotifications int
+		expectedMessage       string
+	}{
+		{
+			name:    "no min version",
+			options: GatewayOptions{TargetImplementation: IstioImplementation},
+		},
+		{
+			name:             "envoy gateway",
+			options:          GatewayOptions{TLSMinVersion: "1.2", TargetImplementation: EnvoyGatewayImplementation},
+			expectedPolicies: map[types.NamespacedName]GatewayPolicy{{Namespace: "default", Name: "gateway"}: {TLSMinVersion: "1.2"}},
+		},
+		{
+			name:                  "istio",
+			options:               GatewayOptions{TLSMinVersion: "1.3", TargetImplementation: IstioImplementation},
+			expectedNotifications: 1,
+			expectedMessage:       "Istio has no tls.options key",
+		},
+		{
+			name:                  "no target implementation",
+			options:               GatewayOptions{TLSMinVersion: "1.2"},
+			expectedOptions:       map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{"tls-min-version": "1.2"},
+			expectedNotifications: 1,
+			expectedMessage:       "generic tls.options tls-min-version: 1.2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			key := types.NamespacedName{Namespace: "default", Name: "gateway"}
+			gatewayResources := GatewayResources{
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+					key: {
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway"},
+						Spec: gatewayv1.GatewaySpec{
+							Listeners: []gatewayv1.Listener{
+								{Name: "example-com-http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+								{
+									Name:     "example-com-https",
+									Protocol: gatewayv1.HTTPSProtocolType,
+									Port:     443,
+									TLS: &gatewayv1.GatewayTLSConfig{
+										CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "example-com"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			applyGatewayOptions(&gatewayResources, tc.options, "test-provider")
+
+			listeners := gatewayResources.Gateways[key].Spec.Listeners
+			if listeners[0].TLS != nil {
+				t.Errorf("Expected the HTTP listener to have no TLS config, got %+v", listeners[0].TLS)
+			}
+			if diff := cmp.Diff(tc.expectedOptions, listeners[1].TLS.Options); diff != "" {
+				t.Errorf("Unexpected tls.options (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedPolicies, gatewayResources.GatewayPolicies); diff != "" {
+				t.Errorf("Unexpected Gateway policies (-want +got):\n%s", diff)
+			}
+			notifs := notifications.NotificationAggr.Notifications["test-provider"]
+			if len(notifs) != tc.expectedNotifications {
+				t.Fatalf("Expected %d notifications, got %d", tc.expectedNotifications, len(notifs))
+			}
+			if tc.expectedMessage != "" && !strings.Contains(notifs[0].Message, tc.expectedMessage) {
+				t.Errorf("Expected a notification containing %q, got %q", tc.expectedMessage, notifs[0].Message)
+			}
+		})
+	}
+}
+
+func TestGatewayOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       GatewayOptions
+		expectedError bool
+	}{
+		{name: "empty options", options: GatewayOptions{}},
+		{name: "supported options", options: GatewayOptions{TLSMinVersion: "1.2", TargetImplementation: EnvoyGatewayImplementation}},
+		{name: "unsupported TLS version", options: GatewayOptions{TLSMinVersion: "1.4"}, expectedError: true},
+		{name: "unsupported target implementation", options: GatewayOptions{TargetImplementation: "foo"}, expectedError: true},
+		{name: "GatewayClass mapping", options: GatewayOptions{GatewayClassNames: map[string]string{"nginx": "nginx-gateway"}}},
+		{name: "empty GatewayClass in mapping", options: GatewayOptions{GatewayClassNames: map[string]string{"nginx": ""}}, expectedError: true},
+		{name: "listener protocol", options: GatewayOptions{ListenerProtocols: map[string]string{"8443": "https"}}},
+		{name: "invalid listener port", options: GatewayOptions{ListenerProtocols: map[string]string{"70000": "TCP"}}, expectedError: true},
+		{name: "unsupported listener protocol", options: GatewayOptions{ListenerProtocols: map[string]string{"8080": "GRPC"}}, expectedError: true},
+		{name: "HTTP listener policy", options: GatewayOptions{HTTPListenerPolicy: HTTPListenerPolicyRedirect}},
+		{name: "unsupported HTTP listener policy", options: GatewayOptions{HTTPListenerPolicy: "http-only"}, expectedError: true},
+		{name: "resource prefix", options: GatewayOptions{ResourcePrefix: "migrated-"}},
+		{name: "resource prefix with uppercase characters", options: GatewayOptions{ResourcePrefix: "Migrated-"}, expectedError: true},
+		{name: "resource prefix starting with a dash", options: GatewayOptions{ResourcePrefix: "-migrated"}, expectedError: true},
+		{name: "too long resource prefix", options: GatewayOptions{ResourcePrefix: strings.Repeat("a", 55)}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.options.Validate()
+			if tc.expectedError != (err != nil) {
+				t.Errorf("Expected error: %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// validateTLSSecretNamespace returns an error if the TLS Secret namespace is
+// not a valid namespace name.
+func validateTLSSecretNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid TLS Secret namespace %s: %s", namespace, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// setTLSSecretNamespace points the certificateRefs of the generated listeners
+// to the Secrets of the same name in the namespace, for the clusters storing
+// all the certificates in a dedicated namespace. A ReferenceGrant allowing the
+// Gateways of each namespace to reference the Secret is generated next to it,
+// and keyed by the Gateway namespace and the Secret name, so that the
+// Gateways of a namespace sharing a Secret share the grant. The
+// certificateRefs already referencing another namespace are left untouched.
+func setTLSSecretNamespace(gatewayResources *GatewayResources, namespace string, providerName ProviderName) {
+	for _, key := range sortedKeys(gatewayResources.Gateways) {
+		gateway := gatewayResources.Gateways[key]
+		if gateway.Namespace == namespace {
+			continue
+		}
+		var secrets []string
+		for i, listener := range gateway.Spec.Listeners {
+			if listener.TLS == nil {
+				continue
+			}
+			for j, ref := range listener.TLS.CertificateRefs {
+				if ref.Namespace != nil || (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Secret") {
+					continue
+				}
+				ns := gatewayv1.Namespace(namespace)
+				gateway.Spec.Listeners[i].TLS.CertificateRefs[j].Namespace = &ns
+				addSecretReferenceGrant(gatewayResources, gateway.Namespace, types.NamespacedName{Namespace: namespace, Name: string(ref.Name)})
+				secrets = append(secrets, string(ref.Name))
+			}
+		}
+		if len(secrets) == 0 {
+			continue
+		}
+		gatewayResources.Gateways[key] = gateway
+
+		sort.Strings(secrets)
+		secrets = slices.Compact(secrets)
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("the certificateRefs of Gateway %s reference the Secrets %s of namespace %s, allowed by the generated ReferenceGrants in that namespace", key, strings.Join(secrets, ", "), namespace),
+			CallingObjects: []client.Object{&gateway},
+		}, string(providerName))
+	}
+}
+
+// addSecretReferenceGrant allows the Gateways of the namespace to reference the
+// Secret. Adding the same grant twice has no effect.
+func addSecretReferenceGrant(gatewayResources *GatewayResources, fromNamespace string, secret types.NamespacedName) {
+	from := gatewayv1beta1.ReferenceGrantFrom{
+		Group:     gatewayv1.GroupName,
+		Kind:      "Gateway",
+		Namespace: gatewayv1.Namespace(fromNamespace),
+	}
+	AddReferenceGrant(gatewayResources, from, "Secret", secret, fmt.Sprintf("from-%s-to-secret-%s", fromNamespace, secret.Name))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func Test_setTLSSecretNamespace(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	gateway := func(namespace string, refs ...gatewayv1.SecretObjectReference) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "gateway"},
+			Spec: gatewayv1.GatewaySpec{
+				Listeners: []gatewayv1.Listener{
+					{Name: "example-com-http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+					{
+						Name:     "example-com-https",
+						Protocol: gatewayv1.HTTPSProtocolType,
+						Port:     443,
+						TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: refs},
+					},
+				},
+			},
+		}
+	}
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "gateway"}: gateway("default",
+				gatewayv1.SecretObjectReference{Name: "example-com"},
+				gatewayv1.SecretObjectReference{Name: "other", Namespace: ptr.To(gatewayv1.Namespace("other"))},
+			),
+			{Namespace: "team", Name: "gateway"}:  gateway("team", gatewayv1.SecretObjectReference{Name: "example-com"}),
+			{Namespace: "certs", Name: "gateway"}: gateway("certs", gatewayv1.SecretObjectReference{Name: "example-com"}),
+		},
+	}
+
+	if err := (GatewayOptions{TLSSecretNamespace: "certs"}).Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if errs := applyGatewayOptions(&gatewayResources, GatewayOptions{TLSSecretNamespace: "certs"}, "test-provider"); len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expectedRefs := map[string][]gatewayv1.SecretObjectReference{
+		"default": {
+			{Name: "example-com", Namespace: ptr.To(gatewayv1.Namespace("certs"))},
+			{Name: "other", Namespace: ptr.To(gatewayv1.Namespace("other"))},
+		},
+		"team":  {{Name: "example-com", Namespace: ptr.To(gatewayv1.Namespace("certs"))}},
+		"certs": {{Name: "example-com"}},
+	}
+	for namespace, refs := range expectedRefs {
+		gateway := gatewayResources.Gateways[types.NamespacedName{Namespace: namespace, Name: "gateway"}]
+		if diff := cmp.Diff(refs, gateway.Spec.Listeners[1].TLS.CertificateRefs); diff != "" {
+			t.Errorf("Unexpected certificateRefs of namespace %s (-want +got):\n%s", namespace, diff)
+		}
+	}
+
+	grant := func(from string) gatewayv1beta1.ReferenceGrant {
+		referenceGrant := gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "certs", Name: "from-" + from + "-to-secret-example-com"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: gatewayv1.Namespace(from)}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret", Name: ptr.To(gatewayv1.ObjectName("example-com"))}},
+			},
+		}
+		referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+		return referenceGrant
+	}
+	expectedGrants := map[types.NamespacedName]gatewayv1beta1.ReferenceGrant{
+		{Namespace: "certs", Name: "from-default-to-secret-example-com"}: grant("default"),
+		{Namespace: "certs", Name: "from-team-to-secret-example-com"}:    grant("team"),
+	}
+	if diff := cmp.Diff(expectedGrants, gatewayResources.ReferenceGrants); diff != "" {
+		t.Errorf("Unexpected ReferenceGrants (-want +got):\n%s", diff)
+	}
+
+	if got := len(notifications.NotificationAggr.Notifications["test-provider"]); got != 2 {
+		t.Errorf("Expected 2 notifications, got %d: %+v", got, notifications.NotificationAggr.Notifications["test-provider"])
+	}
+
+	if err := (GatewayOptions{TLSSecretNamespace: "Certs"}).Validate(); err == nil {
+		t.Errorf("Expected an error for an invalid namespace")
+	}
+}
+
+func Test_setTLSSecretNamespace_longSecretName(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	namespace := strings.Repeat("n", validation.DNS1123LabelMaxLength)
+	secretName := strings.Repeat("s", validation.DNS1123SubdomainMaxLength)
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: namespace, Name: "gateway"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					Listeners: []gatewayv1.Listener{{
+						Name:     "example-com-https",
+						Protocol: gatewayv1.HTTPSProtocolType,
+						Port:     443,
+						TLS:      &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: gatewayv1.ObjectName(secretName)}}},
+					}},
+				},
+			},
+		},
+	}
+
+	setTLSSecretNamespace(&gatewayResources, "certs", "test-provider")
+
+	if len(gatewayResources.ReferenceGrants) != 1 {
+		t.Fatalf("Expected a single ReferenceGrant, got %+v", gatewayResources.ReferenceGrants)
+	}
+	for key, referenceGrant := range gatewayResources.ReferenceGrants {
+		if errs := validation.IsDNS1123Subdomain(key.Name); len(errs) > 0 {
+			t.Errorf("Expected a valid ReferenceGrant name, got %s: %v", key.Name, errs)
+		}
+		if referenceGrant.Name != key.Name || referenceGrant.Namespace != "certs" {
+			t.Errorf("Expected ReferenceGrant %s to be keyed by its namespace and name, got %s/%s", key, referenceGrant.Namespace, referenceGrant.Name)
+		}
+		if to := referenceGrant.Spec.To; len(to) != 1 || to[0].Name == nil || string(*to[0].Name) != secretName {
+			t.Errorf("Expected the ReferenceGrant to allow the references to Secret %s, got %+v", secretName, to)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TrafficPolicy contains the settings of the traffic of a route that Gateway
+// API has no equivalent for. The providers collect them per route, and they
+// are converted together to a single policy of the target implementation,
+// like a BackendTrafficPolicy for Envoy Gateway.
+type TrafficPolicy struct {
+	// RateLimit limits the requests of the route.
+	RateLimit *RateLimit
+	// Retry retries the failed requests to the backends of the route.
+	Retry *Retry
+	// LoadBalancer is the algorithm distributing the requests among the
+	// endpoints of the backends, the implementation default if empty.
+	LoadBalancer LoadBalancerAlgorithm
+	// LoadBalancerHashKey is the name of the header or cookie hashed by the
+	// HeaderHash and CookieHash load balancers.
+	LoadBalancerHashKey string
+	// RequestBodyLimit is the maximum size of the request bodies, the larger
+	// requests being rejected.
+	RequestBodyLimit *resource.Quantity
+	// RequestBuffering buffers the request bodies before sending them to the
+	// backends, up to the RequestBodyLimit, or defaultRequestBufferLimit if
+	// there is none.
+	RequestBuffering bool
+	// AccessControl restricts the clients allowed to send requests to the
+	// route. It is converted to a SecurityPolicy for Envoy Gateway.
+	AccessControl *AccessControl
+}
+
+// AccessControl restricts the client source ranges of the requests of a route.
+// The denied ranges take precedence over the allowed ones.
+type AccessControl struct {
+	// AllowedCIDRs, if set, are the only source ranges the requests are
+	// accepted from.
+	AllowedCIDRs []string
+	// DeniedCIDRs are the source ranges the requests are rejected from.
+	DeniedCIDRs []string
+}
+
+// GatewayPolicy contains the settings of the traffic of a Gateway that Gateway
+// API has no equivalent for, like the settings of the connections of the
+// clients. They are converted to a policy of the target implementation
+// attached to the Gateway, like a ClientTrafficPolicy for Envoy Gateway.
+type GatewayPolicy struct {
+	// ProxyProtocol accepts the PROXY protocol header sent by the load
+	// balancers in front of the Gateway, with the addresses of the clients.
+	ProxyProtocol bool
+	// TLSMinVersion is the minimum TLS version of the terminating TLS
+	// listeners of the Gateway, e.g. 1.2.
+	TLSMinVersion string
+}
+
+// IsEmpty returns whether the policy has no setting.
+func (p GatewayPolicy) IsEmpty() bool {
+	return !p.ProxyProtocol && p.TLSMinVersion == ""
+}
+
+// settings returns the descriptions of the settings of the policy, for the
+// notifications.
+func (p GatewayPolicy) settings() []string {
+	var settings []string
+	if p.ProxyProtocol {
+		settings = append(settings, "PROXY protocol")
+	}
+	if p.TLSMinVersion != "" {
+		settings = append(settings, fmt.Sprintf("minimum TLS version %s", p.TLSMinVersion))
+	}
+	return settings
+}
+
+// merge returns the policy with the settings of both policies.
+func (p GatewayPolicy) merge(other GatewayPolicy) GatewayPolicy {
+	p.ProxyProtocol = p.ProxyProtocol || other.ProxyProtocol
+	if p.TLSMinVersion == "" {
+		p.TLSMinVersion = other.TLSMinVersion
+	}
+	return p
+}
+
+// RateLimitUnit is the unit of time of a rate limit.
+type RateLimitUnit string
+
+const (
+	RateLimitPerSecond RateLimitUnit = "Second"
+	RateLimitPerMinute RateLimitUnit = "Minute"
+)
+
+// RateLimit is a number of requests allowed per unit of time.
+type RateLimit struct {
+	Requests int64
+	Unit     RateLimitUnit
+}
+
+// Retry describes the requests retried and how many times.
+type Retry struct {
+	// Retries is the maximum number of retries of a request, 0 disabling them.
+	Retries int32
+	// OnConnectionFailure retries the requests whose connection to the backend
+	// failed, was reset or timed out.
+	OnConnectionFailure bool
+	// OnStatusCodes retries the requests answered with these status codes.
+	OnStatusCodes []int32
+}
+
+// LoadBalancerAlgorithm is an algorithm distributing the requests among the
+// endpoints of the backends.
+type LoadBalancerAlgorithm string
+
+const (
+	RoundRobinLoadBalancer   LoadBalancerAlgorithm = "RoundRobin"
+	LeastRequestLoadBalancer LoadBalancerAlgorithm = "LeastRequest"
+	// SourceIPHashLoadBalancer sends the requests of a client IP to the same
+	// endpoint, with a consistent hash.
+	SourceIPHashLoadBalancer LoadBalancerAlgorithm = "SourceIPHash"
+	// HeaderHashLoadBalancer sends the requests with the same value of the
+	// LoadBalancerHashKey header to the same endpoint, with a consistent hash.
+	HeaderHashLoadBalancer LoadBalancerAlgorithm = "HeaderHash"
+	// CookieHashLoadBalancer sends the requests with the same value of the
+	// LoadBalancerHashKey cookie to the same endpoint, with a consistent hash.
+	CookieHashLoadBalancer LoadBalancerAlgorithm = "CookieHash"
+)
+
+// IsEmpty returns whether the policy has no setting.
+func (p TrafficPolicy) IsEmpty() bool {
+	return p.RateLimit == nil && p.Retry == nil && p.LoadBalancer == "" && p.RequestBodyLimit == nil && !p.RequestBuffering && p.AccessControl == nil
+}
+
+// backendSettings returns the policy without its access control, which is
+// converted to a separate policy.
+func (p TrafficPolicy) backendSettings() TrafficPolicy {
+	p.AccessControl = nil
+	return p
+}
+
+// settings returns the descriptions of the settings of the policy, for the
+// notifications.
+func (p TrafficPolicy) settings() []string {
+	var settings []string
+	if p.RateLimit != nil {
+		settings = append(settings, fmt.Sprintf("rate limit of %d requests per %s", p.RateLimit.Requests, strings.ToLower(string(p.RateLimit.Unit))))
+	}
+	if p.Retry != nil {
+		settings = append(settings, fmt.Sprintf("%d retries", p.Retry.Retries))
+	}
+	if p.LoadBalancer != "" && p.LoadBalancerHashKey != "" {
+		settings = append(settings, fmt.Sprintf("%s load balancer on %s", p.LoadBalancer, p.LoadBalancerHashKey))
+	} else if p.LoadBalancer != "" {
+		settings = append(settings, fmt.Sprintf("%s load balancer", p.LoadBalancer))
+	}
+	if p.RequestBodyLimit != nil {
+		settings = append(settings, fmt.Sprintf("request body limit of %s", p.RequestBodyLimit.String()))
+	}
+	if p.RequestBuffering {
+		settings = append(settings, "request buffering")
+	}
+	if p.AccessControl != nil {
+		settings = append(settings, "client source ranges")
+	}
+	return settings
+}
+
+const (
+	envoyGatewayAPIVersion        = "gateway.envoyproxy.io/v1alpha1"
+	envoyBackendTrafficPolicyKind = "BackendTrafficPolicy"
+	envoyClientTrafficPolicyKind  = "ClientTrafficPolicy"
+	envoySecurityPolicyKind       = "SecurityPolicy"
+
+	// defaultRequestBufferLimit is the size up to which the request bodies are
+	// buffered without a RequestBodyLimit, the default body size limit of
+	// nginx.
+	defaultRequestBufferLimit = "1Mi"
+)
+
+// generateImplementationPolicies converts the traffic policies of the routes to
+// the policies of the target implementation, once the routes have their final
+// names. For Envoy Gateway, a single BackendTrafficPolicy targeting the route is
+// generated per route, with all its settings but the access control, converted
+// to a SecurityPolicy, and a ClientTrafficPolicy per Gateway with a
+// GatewayPolicy, and an Info notification is emitted. For the
+// other implementations, the settings are reported with a Warning
+// notification, to be configured manually.
+func generateImplementationPolicies(byProvider map[ProviderName]GatewayResources, targetImplementation string) {
+	for _, name := range sortedProviderNames(byProvider) {
+		gatewayResources := byProvider[name]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.TrafficPolicies))
+		for key := range gatewayResources.TrafficPolicies {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			policy := gatewayResources.TrafficPolicies[key]
+			route, kind, ok := trafficPolicyRoute(gatewayResources, key)
+			if !ok || policy.IsEmpty() {
+				continue
+			}
+			if targetImplementation != EnvoyGatewayImplementation {
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.WarningNotification,
+					Message:        fmt.Sprintf("the %s of %s %s were not converted, as Gateway API has no equivalent and the target implementation has no policy generated for them, configure them with your Gateway implementation, e.g. with a BackendTrafficPolicy for Envoy Gateway", strings.Join(policy.settings(), ", "), kind, key),
+					CallingObjects: []client.Object{route},
+				}, string(name))
+				continue
+			}
+
+			if gatewayResources.ImplementationPolicies == nil {
+				gatewayResources.ImplementationPolicies = map[PolicyKey]unstructured.Unstructured{}
+			}
+			if backendSettings := policy.backendSettings(); !backendSettings.IsEmpty() {
+				gatewayResources.ImplementationPolicies[PolicyKey{Kind: envoyBackendTrafficPolicyKind, NamespacedName: key}] = envoyBackendTrafficPolicy(backendSettings, kind, key)
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.InfoNotification,
+					Message:        fmt.Sprintf("the %s of %s %s were converted to %s %s", strings.Join(backendSettings.settings(), ", "), kind, key, envoyBackendTrafficPolicyKind, key),
+					CallingObjects: []client.Object{route},
+				}, string(name))
+			}
+			if policy.AccessControl != nil {
+				gatewayResources.ImplementationPolicies[PolicyKey{Kind: envoySecurityPolicyKind, NamespacedName: key}] = envoySecurityPolicy(*policy.AccessControl, kind, key)
+				notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+					Type:           notifications.InfoNotification,
+					Message:        fmt.Sprintf("the client source ranges of %s %s were converted to %s %s, whose authorization rules deny the denied ranges first", kind, key, envoySecurityPolicyKind, key),
+					CallingObjects: []client.Object{route},
+				}, string(name))
+			}
+		}
+		generateGatewayImplementationPolicies(&gatewayResources, name, targetImplementation)
+		byProvider[name] = gatewayResources
+	}
+}
+
+// generateGatewayImplementationPolicies converts the Gateway policies of the
+// provider to the policies of the target implementation.
+func generateGatewayImplementationPolicies(gatewayResources *GatewayResources, name ProviderName, targetImplementation string) {
+	keys := make([]types.NamespacedName, 0, len(gatewayResources.GatewayPolicies))
+	for key := range gatewayResources.GatewayPolicies {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, key := range keys {
+		policy := gatewayResources.GatewayPolicies[key]
+		gateway, ok := gatewayResources.Gateways[key]
+		if !ok || policy.IsEmpty() {
+			continue
+		}
+		if targetImplementation != EnvoyGatewayImplementation {
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.WarningNotification,
+				Message:        fmt.Sprintf("the %s of Gateway %s were not converted, as Gateway API has no equivalent and the target implementation has no policy generated for them, configure them with your Gateway implementation, e.g. with a ClientTrafficPolicy for Envoy Gateway", strings.Join(policy.settings(), ", "), key),
+				CallingObjects: []client.Object{&gateway},
+			}, string(name))
+			continue
+		}
+
+		if gatewayResources.ImplementationPolicies == nil {
+			gatewayResources.ImplementationPolicies = map[PolicyKey]unstructured.Unstructured{}
+		}
+		gatewayResources.ImplementationPolicies[PolicyKey{Kind: envoyClientTrafficPolicyKind, NamespacedName: key}] = envoyClientTrafficPolicy(policy, key)
+		notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+			Type:           notifications.InfoNotification,
+			Message:        fmt.Sprintf("the %s of Gateway %s were converted to %s %s", strings.Join(policy.settings(), ", "), key, envoyClientTrafficPolicyKind, key),
+			CallingObjects: []client.Object{&gateway},
+		}, string(name))
+	}
+}
+
+// envoyClientTrafficPolicy returns the Envoy Gateway ClientTrafficPolicy of the
+// Gateway with the settings of the Gateway policy.
+func envoyClientTrafficPolicy(policy GatewayPolicy, gatewayKey types.NamespacedName) unstructured.Unstructured {
+	spec := map[string]any{
+		"targetRefs": []any{map[string]any{
+			"group": gatewayv1.GroupName,
+			"kind":  "Gateway",
+			"name":  gatewayKey.Name,
+		}},
+	}
+	if policy.ProxyProtocol {
+		spec["enableProxyProtocol"] = true
+	}
+	if policy.TLSMinVersion != "" {
+		spec["tls"] = map[string]any{"minVersion": policy.TLSMinVersion}
+	}
+
+	clientTrafficPolicy := unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	clientTrafficPolicy.SetAPIVersion(envoyGatewayAPIVersion)
+	clientTrafficPolicy.SetKind(envoyClientTrafficPolicyKind)
+	clientTrafficPolicy.SetNamespace(gatewayKey.Namespace)
+	clientTrafficPolicy.SetName(gatewayKey.Name)
+	return clientTrafficPolicy
+}
+
+// envoySecurityPolicy returns the Envoy Gateway SecurityPolicy of the route with
+// the access control. Its authorization rules apply in order, the first
+// matching one deciding, so the denied ranges come before the allowed ones, and
+// the requests matching no rule are denied if only some ranges are allowed.
+func envoySecurityPolicy(accessControl AccessControl, routeKind string, routeKey types.NamespacedName) unstructured.Unstructured {
+	var rules []any
+	for _, rule := range []struct {
+		action string
+		cidrs  []string
+	}{{"Deny", accessControl.DeniedCIDRs}, {"Allow", accessControl.AllowedCIDRs}} {
+		if len(rule.cidrs) == 0 {
+			continue
+		}
+		cidrs := make([]any, 0, len(rule.cidrs))
+		for _, cidr := range rule.cidrs {
+			cidrs = append(cidrs, cidr)
+		}
+		rules = append(rules, map[string]any{
+			"action":    rule.action,
+			"principal": map[string]any{"clientCIDRs": cidrs},
+		})
+	}
+	defaultAction := "Allow"
+	if len(accessControl.AllowedCIDRs) > 0 {
+		defaultAction = "Deny"
+	}
+
+	securityPolicy := unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{
+		"targetRefs": []any{map[string]any{
+			"group": gatewayv1.GroupName,
+			"kind":  routeKind,
+			"name":  routeKey.Name,
+		}},
+		"authorization": map[string]any{
+			"defaultAction": defaultAction,
+			"rules":         rules,
+		},
+	}}}
+	securityPolicy.SetAPIVersion(envoyGatewayAPIVersion)
+	securityPolicy.SetKind(envoySecurityPolicyKind)
+	securityPolicy.SetNamespace(routeKey.Namespace)
+	securityPolicy.SetName(routeKey.Name)
+	return securityPolicy
+}
+
+// trafficPolicyRoute returns the HTTPRoute or GRPCRoute of the traffic policy,
+// and its kind.
+func trafficPolicyRoute(gatewayResources GatewayResources, key types.NamespacedName) (client.Object, string, bool) {
+	if route, ok := gatewayResources.HTTPRoutes[key]; ok {
+		return &route, "HTTPRoute", true
+	}
+	if route, ok := gatewayResources.GRPCRoutes[key]; ok {
+		return &route, "GRPCRoute", true
+	}
+	return nil, "", false
+}
+
+// envoyBackendTrafficPolicy returns the Envoy Gateway BackendTrafficPolicy of the
+// route with the settings of the traffic policy.
+func envoyBackendTrafficPolicy(policy TrafficPolicy, routeKind string, routeKey types.NamespacedName) unstructured.Unstructured {
+	spec := map[string]any{
+		"targetRefs": []any{map[string]any{
+			"group": gatewayv1.GroupName,
+			"kind":  routeKind,
+			"name":  routeKey.Name,
+		}},
+	}
+	if policy.RateLimit != nil {
+		spec["rateLimit"] = map[string]any{
+			"type": "Local",
+			"local": map[string]any{
+				"rules": []any{map[string]any{
+					"limit": map[string]any{"requests": policy.RateLimit.Requests, "unit": string(policy.RateLimit.Unit)},
+				}},
+			},
+		}
+	}
+	if policy.Retry != nil {
+		retry := map[string]any{"numRetries": int64(policy.Retry.Retries)}
+		retryOn := map[string]any{}
+		var triggers []any
+		if policy.Retry.OnConnectionFailure {
+			triggers = append(triggers, "connect-failure", "reset")
+		}
+		if len(policy.Retry.OnStatusCodes) > 0 {
+			triggers = append(triggers, "retriable-status-codes")
+			codes := make([]any, 0, len(policy.Retry.OnStatusCodes))
+			for _, code := range policy.Retry.OnStatusCodes {
+				codes = append(codes, int64(code))
+			}
+			retryOn["httpStatusCodes"] = codes
+		}
+		if len(triggers) > 0 {
+			retryOn["triggers"] = triggers
+			retry["retryOn"] = retryOn
+		}
+		spec["retry"] = retry
+	}
+	switch policy.LoadBalancer {
+	case RoundRobinLoadBalancer, LeastRequestLoadBalancer:
+		spec["loadBalancer"] = map[string]any{"type": string(policy.LoadBalancer)}
+	case SourceIPHashLoadBalancer:
+		spec["loadBalancer"] = map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{"type": "SourceIP"}}
+	case HeaderHashLoadBalancer:
+		spec["loadBalancer"] = map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{
+			"type":   "Header",
+			"header": map[string]any{"name": policy.LoadBalancerHashKey},
+		}}
+	case CookieHashLoadBalancer:
+		spec["loadBalancer"] = map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{
+			"type":   "Cookie",
+			"cookie": map[string]any{"name": policy.LoadBalancerHashKey},
+		}}
+	}
+	if policy.RequestBodyLimit != nil {
+		// The request bodies are buffered up to the limit, the larger requests
+		// being rejected with a 413 status code.
+		spec["requestBuffer"] = map[string]any{"limit": policy.RequestBodyLimit.String()}
+	} else if policy.RequestBuffering {
+		spec["requestBuffer"] = map[string]any{"limit": defaultRequestBufferLimit}
+	}
+
+	backendTrafficPolicy := unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	backendTrafficPolicy.SetAPIVersion(envoyGatewayAPIVersion)
+	backendTrafficPolicy.SetKind(envoyBackendTrafficPolicyKind)
+	backendTrafficPolicy.SetNamespace(routeKey.Namespace)
+	backendTrafficPolicy.SetName(routeKey.Name)
+	return backendTrafficPolicy
+}
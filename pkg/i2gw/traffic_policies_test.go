@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_generateImplementationPolicies(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "foo"}
+	trafficPolicyResources := func() map[ProviderName]GatewayResources {
+		return map[ProviderName]GatewayResources{
+			"test-provider": {
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+					routeKey: {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+				},
+				TrafficPolicies: map[types.NamespacedName]TrafficPolicy{
+					routeKey: {
+						RateLimit:        &RateLimit{Requests: 10, Unit: RateLimitPerSecond},
+						Retry:            &Retry{Retries: 2, OnConnectionFailure: true, OnStatusCodes: []int32{502, 503}},
+						LoadBalancer:     SourceIPHashLoadBalancer,
+						RequestBodyLimit: ptr.To(resource.MustParse("8Mi")),
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		targetImplementation string
+		trafficPolicy        *TrafficPolicy
+		expectedPolicies     map[PolicyKey]unstructured.Unstructured
+		expectedNotification notifications.MessageType
+	}{
+		{
+			name:                 "single BackendTrafficPolicy for Envoy Gateway",
+			targetImplementation: EnvoyGatewayImplementation,
+			expectedPolicies: map[PolicyKey]unstructured.Unstructured{
+				{Kind: "BackendTrafficPolicy", NamespacedName: routeKey}: {Object: map[string]any{
+					"apiVersion": "gateway.envoyproxy.io/v1alpha1",
+					"kind":       "BackendTrafficPolicy",
+					"metadata":   map[string]any{"namespace": "default", "name": "foo"},
+					"spec": map[string]any{
+						"targetRefs": []any{map[string]any{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "foo"}},
+						"rateLimit": map[string]any{
+							"type": "Local",
+							"local": map[string]any{"rules": []any{map[string]any{
+								"limit": map[string]any{"requests": int64(10), "unit": "Second"},
+							}}},
+						},
+						"retry": map[string]any{
+							"numRetries": int64(2),
+							"retryOn": map[string]any{
+								"triggers":        []any{"connect-failure", "reset", "retriable-status-codes"},
+								"httpStatusCodes": []any{int64(502), int64(503)},
+							},
+						},
+						"loadBalancer":  map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{"type": "SourceIP"}},
+						"requestBuffer": map[string]any{"limit": "8Mi"},
+					},
+				}},
+			},
+			expectedNotification: notifications.InfoNotification,
+		},
+		{
+			name:                 "request buffering without body limit for Envoy Gateway",
+			targetImplementation: EnvoyGatewayImplementation,
+			trafficPolicy:        &TrafficPolicy{RequestBuffering: true},
+			expectedPolicies: map[PolicyKey]unstructured.Unstructured{
+				{Kind: "BackendTrafficPolicy", NamespacedName: routeKey}: {Object: map[string]any{
+					"apiVersion": "gateway.envoyproxy.io/v1alpha1",
+					"kind":       "BackendTrafficPolicy",
+					"metadata":   map[string]any{"namespace": "default", "name": "foo"},
+					"spec": map[string]any{
+						"targetRefs":    []any{map[string]any{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "foo"}},
+						"requestBuffer": map[string]any{"limit": "1Mi"},
+					},
+				}},
+			},
+			expectedNotification: notifications.InfoNotification,
+		},
+		{
+			name:                 "no policy for Istio",
+			targetImplementation: IstioImplementation,
+			expectedNotification: notifications.WarningNotification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+			byProvider := trafficPolicyResources()
+			if tc.trafficPolicy != nil {
+				byProvider["test-provider"].TrafficPolicies[routeKey] = *tc.trafficPolicy
+			}
+
+			generateImplementationPolicies(byProvider, tc.targetImplementation)
+
+			if diff := cmp.Diff(tc.expectedPolicies, byProvider["test-provider"].ImplementationPolicies); diff != "" {
+				t.Errorf("Unexpected implementation policies (-want +got):\n%s", diff)
+			}
+			notifs := notifications.NotificationAggr.Notifications["test-provider"]
+			if len(notifs) != 1 || notifs[0].Type != tc.expectedNotification {
+				t.Errorf("Expected a single %s notification, got %+v", tc.expectedNotification, notifs)
+			}
+		})
+	}
+}
+
+func Test_envoySecurityPolicy(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "foo"}
+	testCases := []struct {
+		name          string
+		accessControl AccessControl
+		expectedSpec  map[string]any
+	}{
+		{
+			name:          "denied ranges before the allowed ones",
+			accessControl: AccessControl{AllowedCIDRs: []string{"10.0.0.0/8"}, DeniedCIDRs: []string{"10.0.0.1/32"}},
+			expectedSpec: map[string]any{
+				"targetRefs": []any{map[string]any{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "foo"}},
+				"authorization": map[string]any{
+					"defaultAction": "Deny",
+					"rules": []any{
+						map[string]any{"action": "Deny", "principal": map[string]any{"clientCIDRs": []any{"10.0.0.1/32"}}},
+						map[string]any{"action": "Allow", "principal": map[string]any{"clientCIDRs": []any{"10.0.0.0/8"}}},
+					},
+				},
+			},
+		},
+		{
+			name:          "only denied ranges",
+			accessControl: AccessControl{DeniedCIDRs: []string{"192.168.0.0/16"}},
+			expectedSpec: map[string]any{
+				"targetRefs": []any{map[string]any{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "foo"}},
+				"authorization": map[string]any{
+					"defaultAction": "Allow",
+					"rules": []any{
+						map[string]any{"action": "Deny", "principal": map[string]any{"clientCIDRs": []any{"192.168.0.0/16"}}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			securityPolicy := envoySecurityPolicy(tc.accessControl, "HTTPRoute", routeKey)
+			if securityPolicy.GetKind() != "SecurityPolicy" || securityPolicy.GetNamespace() != "default" || securityPolicy.GetName() != "foo" {
+				t.Errorf("Expected SecurityPolicy default/foo, got %s %s/%s", securityPolicy.GetKind(), securityPolicy.GetNamespace(), securityPolicy.GetName())
+			}
+			if diff := cmp.Diff(tc.expectedSpec, securityPolicy.Object["spec"]); diff != "" {
+				t.Errorf("Unexpected SecurityPolicy spec (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_envoyClientTrafficPolicy(t *testing.T) {
+	clientTrafficPolicy := envoyClientTrafficPolicy(GatewayPolicy{ProxyProtocol: true, TLSMinVersion: "1.2"}, types.NamespacedName{Namespace: "default", Name: "nginx"})
+	if clientTrafficPolicy.GetKind() != "ClientTrafficPolicy" || clientTrafficPolicy.GetNamespace() != "default" || clientTrafficPolicy.GetName() != "nginx" {
+		t.Errorf("Expected ClientTrafficPolicy default/nginx, got %s %s/%s", clientTrafficPolicy.GetKind(), clientTrafficPolicy.GetNamespace(), clientTrafficPolicy.GetName())
+	}
+	expectedSpec := map[string]any{
+		"targetRefs":          []any{map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "nginx"}},
+		"enableProxyProtocol": true,
+		"tls":                 map[string]any{"minVersion": "1.2"},
+	}
+	if diff := cmp.Diff(expectedSpec, clientTrafficPolicy.Object["spec"]); diff != "" {
+		t.Errorf("Unexpected ClientTrafficPolicy spec (-want +got):\n%s", diff)
+	}
+}
+
+func Test_envoyBackendTrafficPolicyConsistentHash(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "foo"}
+	testCases := []struct {
+		name                 string
+		policy               TrafficPolicy
+		expectedLoadBalancer map[string]any
+	}{
+		{
+			name:   "header",
+			policy: TrafficPolicy{LoadBalancer: HeaderHashLoadBalancer, LoadBalancerHashKey: "X-User"},
+			expectedLoadBalancer: map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{
+				"type":   "Header",
+				"header": map[string]any{"name": "X-User"},
+			}},
+		},
+		{
+			name:   "cookie",
+			policy: TrafficPolicy{LoadBalancer: CookieHashLoadBalancer, LoadBalancerHashKey: "session"},
+			expectedLoadBalancer: map[string]any{"type": "ConsistentHash", "consistentHash": map[string]any{
+				"type":   "Cookie",
+				"cookie": map[string]any{"name": "session"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backendTrafficPolicy := envoyBackendTrafficPolicy(tc.policy, "HTTPRoute", routeKey)
+			loadBalancer, _, _ := unstructured.NestedMap(backendTrafficPolicy.Object, "spec", "loadBalancer")
+			if diff := cmp.Diff(tc.expectedLoadBalancer, loadBalancer); diff != "" {
+				t.Errorf("Unexpected load balancer (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
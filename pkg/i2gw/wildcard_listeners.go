@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// collapseWildcardListeners removes the listeners of the Gateways whose
+// hostname is covered by a wildcard listener of the same Gateway, like
+// a.example.com by *.example.com, on the same port and protocol and with the
+// same TLS settings, so that the Gateway has no redundant listeners. The routes
+// of the removed listener attach to the wildcard listener by hostname. The
+// listeners with their own TLS certificates, or referenced by sectionName, are
+// kept. An Info notification is emitted for every Gateway with collapsed
+// listeners.
+func collapseWildcardListeners(gatewayResourcesByProvider map[ProviderName]GatewayResources) {
+	for _, providerName := range sortedProviderNames(gatewayResourcesByProvider) {
+		gatewayResources := gatewayResourcesByProvider[providerName]
+		keys := make([]types.NamespacedName, 0, len(gatewayResources.Gateways))
+		for key := range gatewayResources.Gateways {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			gateway := gatewayResources.Gateways[key]
+			var listeners []gatewayv1.Listener
+			var collapsed []string
+			for _, listener := range gateway.Spec.Listeners {
+				wildcard := coveringWildcardListener(gateway.Spec.Listeners, listener)
+				if wildcard == nil || isListenerReferencedByProviders(gatewayResourcesByProvider, key, listener.Name) {
+					listeners = append(listeners, listener)
+					continue
+				}
+				collapsed = append(collapsed, fmt.Sprintf("%s into %s", listener.Name, wildcard.Name))
+			}
+			if len(collapsed) == 0 {
+				continue
+			}
+			gateway.Spec.Listeners = listeners
+			gatewayResources.Gateways[key] = gateway
+			notifications.NotificationAggr.DispatchNotification(notifications.Notification{
+				Type:           notifications.InfoNotification,
+				Message:        fmt.Sprintf("the listeners of Gateway %s covered by a wildcard listener with the same settings were collapsed: %s. Their routes attach to the wildcard listener by hostname", key, strings.Join(collapsed, ", ")),
+				CallingObjects: []client.Object{&gateway},
+			}, string(providerName))
+		}
+	}
+}
+
+// coveringWildcardListener returns the wildcard listener covering the hostname
+// of the listener with the same port, protocol, TLS settings and allowed
+// routes, if any.
+func coveringWildcardListener(listeners []gatewayv1.Listener, listener gatewayv1.Listener) *gatewayv1.Listener {
+	if listener.Hostname == nil || strings.HasPrefix(string(*listener.Hostname), "*") {
+		return nil
+	}
+	for i, wildcard := range listeners {
+		if wildcard.Hostname == nil || !strings.HasPrefix(string(*wildcard.Hostname), "*") || !hostnameMatches(wildcard.Hostname, string(*listener.Hostname)) {
+			continue
+		}
+		if wildcard.Port == listener.Port && wildcard.Protocol == listener.Protocol &&
+			equality.Semantic.DeepEqual(wildcard.TLS, listener.TLS) && equality.Semantic.DeepEqual(wildcard.AllowedRoutes, listener.AllowedRoutes) {
+			return &listeners[i]
+		}
+	}
+	return nil
+}
+
+// isListenerReferencedByProviders returns whether a route of any provider
+// references the listener of the Gateway by name, as the Gateways merged from
+// several providers are held by one of them.
+func isListenerReferencedByProviders(gatewayResourcesByProvider map[ProviderName]GatewayResources, gatewayKey types.NamespacedName, listener gatewayv1.SectionName) bool {
+	for _, gatewayResources := range gatewayResourcesByProvider {
+		gatewayResources := gatewayResources
+		if isListenerReferenced(&gatewayResources, gatewayKey, listener) {
+			return true
+		}
+	}
+	return false
+}
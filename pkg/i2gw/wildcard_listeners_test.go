@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_collapseWildcardListeners(t *testing.T) {
+	notifications.NotificationAggr.Notifications = map[string][]notifications.Notification{}
+	wildcardTLS := &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "wildcard-example-com"}}}
+	listener := func(name, hostname string, port gatewayv1.PortNumber, tls *gatewayv1.GatewayTLSConfig) gatewayv1.Listener {
+		protocol := gatewayv1.HTTPProtocolType
+		if tls != nil {
+			protocol = gatewayv1.HTTPSProtocolType
+		}
+		return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Hostname: ptr.To(gatewayv1.Hostname(hostname)), Port: port, Protocol: protocol, TLS: tls}
+	}
+	gatewayKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+	gatewayResources := GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			gatewayKey: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+				Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+					listener("wildcard-example-com-http", "*.example.com", 80, nil),
+					listener("wildcard-example-com-https", "*.example.com", 443, wildcardTLS),
+					// a.example.com shares the certificate of the wildcard.
+					listener("a-example-com-http", "a.example.com", 80, nil),
+					listener("a-example-com-https", "a.example.com", 443, wildcardTLS),
+					// b.example.com has its own certificate.
+					listener("b-example-com-https", "b.example.com", 443, &gatewayv1.GatewayTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "b-example-com"}}}),
+					// c.example.com is referenced by name.
+					listener("c-example-com-http", "c.example.com", 80, nil),
+					listener("example-net-http", "example.net", 80, nil),
+				}},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "c"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "c"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "nginx", SectionName: ptr.To(gatewayv1.SectionName("c-example-com-http"))}}},
+					Hostnames:       []gatewayv1.Hostname{"c.example.com"},
+				},
+			},
+		},
+	}
+	gatewayResourcesByProvider := map[ProviderName]GatewayResources{"test-provider": gatewayResources}
+
+	collapseWildcardListeners(gatewayResourcesByProvider)
+
+	var names []gatewayv1.SectionName
+	for _, listener := range gatewayResourcesByProvider["test-provider"].Gateways[gatewayKey].Spec.Listeners {
+		names = append(names, listener.Name)
+	}
+	expectedNames := []gatewayv1.SectionName{"wildcard-example-com-http", "wildcard-example-com-https", "b-example-com-https", "c-example-com-http", "example-net-http"}
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+	}
+
+	var messages []string
+	for _, notification := range notifications.NotificationAggr.Notifications["test-provider"] {
+		messages = append(messages, string(notification.Type)+": "+notification.Message)
+	}
+	expectedMessages := []string{
+		"INFO: the listeners of Gateway default/nginx covered by a wildcard listener with the same settings were collapsed: a-example-com-http into wildcard-example-com-http, a-example-com-https into wildcard-example-com-https. Their routes attach to the wildcard listener by hostname",
+	}
+	if diff := cmp.Diff(expectedMessages, messages); diff != "" {
+		t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+	}
+}